@@ -118,6 +118,13 @@ func registerAgentConfig() {
 	registerBool(flags, name("debug"), defaults.Debug, "Enable verbose script output")
 	registerInt(flags, name("artificial-slots"), defaults.ArtificialSlots, "")
 	flags.Lookup("artificial-slots").Hidden = true
+	registerInt(flags, name("fake-gpus", "count"), defaults.FakeGPUs.Count,
+		"Number of fake GPUs to expose with the fake-gpu slot type")
+	flags.Lookup("fake-gpus-count").Hidden = true
+	registerBool(flags, name("fake-gpus", "nvidia-visible-devices"),
+		defaults.FakeGPUs.NvidiaVisibleDevices,
+		"Whether to set NVIDIA_VISIBLE_DEVICES to the fake GPUs in containers")
+	flags.Lookup("fake-gpus-nvidia-visible-devices").Hidden = true
 
 	// Endpoint TLS flags.
 	registerBool(flags, name("tls"), defaults.TLS, "Use TLS for the API server")
@@ -147,5 +154,5 @@ func registerAgentConfig() {
 		"Time between agent reconnect attempts")
 
 	registerString(flags, name("container-runtime"), defaults.ContainerRuntime,
-		"The container runtime to use")
+		"The container runtime to use (docker or podman)")
 }
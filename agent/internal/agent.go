@@ -19,13 +19,16 @@ import (
 	"github.com/determined-ai/determined/agent/internal/container"
 	"github.com/determined-ai/determined/agent/internal/containers"
 	"github.com/determined-ai/determined/agent/internal/detect"
+	"github.com/determined-ai/determined/agent/internal/health"
 	"github.com/determined-ai/determined/agent/internal/options"
 	"github.com/determined-ai/determined/agent/pkg/docker"
 	"github.com/determined-ai/determined/agent/pkg/events"
+	"github.com/determined-ai/determined/agent/pkg/podman"
 	"github.com/determined-ai/determined/master/pkg/aproto"
 	"github.com/determined-ai/determined/master/pkg/cproto"
 	"github.com/determined-ai/determined/master/pkg/device"
 	"github.com/determined-ai/determined/master/pkg/syncx/errgroupx"
+	"github.com/determined-ai/determined/master/pkg/syncx/waitgroupx"
 	"github.com/determined-ai/determined/master/pkg/ws"
 )
 
@@ -112,35 +115,41 @@ func (a *Agent) run(ctx context.Context) error {
 	}
 
 	a.log.Trace("detecting devices")
-	devices, err := detect.Detect(
-		a.opts.SlotType, a.opts.AgentID, a.opts.VisibleGPUs, a.opts.ArtificialSlots,
+	devices, fakeGPUs, err := detect.Detect(
+		a.opts.SlotType, a.opts.AgentID, a.opts.VisibleGPUs, a.opts.ArtificialSlots, a.opts.FakeGPUs,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to detect devices: %v", devices)
 	}
 
 	a.log.Tracef("setting up %s runtime", a.opts.ContainerRuntime)
-	if a.opts.ContainerRuntime != options.DockerContainerRuntime {
-		a.log.Error(a.opts.ContainerRuntime,
-			" container runtime is not supported, please update runtime config to use docker instead.")
+	var cruntime container.ContainerRuntime
+	switch a.opts.ContainerRuntime {
+	case options.PodmanContainerRuntime:
+		pcl, pErr := podman.NewClient(podman.DefaultBinary)
+		if pErr != nil {
+			return fmt.Errorf("failed to build podman client: %w", pErr)
+		}
+		cruntime = pcl
+	case options.DockerContainerRuntime:
+		dcl, dErr := dclient.NewClientWithOpts(dclient.WithAPIVersionNegotiation(), dclient.FromEnv)
+		if dErr != nil {
+			return fmt.Errorf("failed to build docker client: %w", dErr)
+		}
+		defer func() {
+			a.log.Trace("cleaning up docker client")
+			if cErr := dcl.Close(); cErr != nil {
+				a.log.WithError(cErr).Error("failed to close docker client")
+			}
+		}()
+		cruntime = docker.NewClient(dcl)
+	default:
 		return fmt.Errorf("container runtime not available: %s", a.opts.ContainerRuntime)
 	}
 
-	dcl, dErr := dclient.NewClientWithOpts(dclient.WithAPIVersionNegotiation(), dclient.FromEnv)
-	if dErr != nil {
-		return fmt.Errorf("failed to build docker client: %w", dErr)
-	}
-	defer func() {
-		a.log.Trace("cleaning up docker client")
-		if cErr := dcl.Close(); cErr != nil {
-			a.log.WithError(cErr).Error("failed to close docker client")
-		}
-	}()
-	cruntime := docker.NewClient(dcl)
-
 	a.log.Trace("setting up container manager")
 	outbox := make(chan *aproto.MasterMessage, eventChanSize) // covers many from socket lifetimes
-	manager, err := containers.New(a.opts, mopts, devices, cruntime, a.sender(outbox))
+	manager, err := containers.New(a.opts, mopts, devices, fakeGPUs, cruntime, a.sender(outbox))
 	if err != nil {
 		return fmt.Errorf("error initializing container manager: %w", err)
 	}
@@ -167,6 +176,15 @@ func (a *Agent) run(ctx context.Context) error {
 		return ctx.Err()
 	}
 
+	if a.opts.HealthCheck.Interval > 0 {
+		a.log.Trace("starting health checks")
+		checks := waitgroupx.WithContext(ctx)
+		defer checks.Close()
+		checks.Go(func(ctx context.Context) {
+			health.Run(ctx, a.opts.HealthCheck, devices, a.healthReporter(outbox))
+		})
+	}
+
 	a.log.Trace("watching for ws requests and system events")
 	inbox := socket.Inbox
 	for {
@@ -295,6 +313,19 @@ func (a *Agent) sender(out chan *aproto.MasterMessage) events.Publisher[containe
 	)
 }
 
+func (a *Agent) healthReporter(
+	out chan *aproto.MasterMessage,
+) func(context.Context, *aproto.AgentHealthReport) error {
+	return func(ctx context.Context, report *aproto.AgentHealthReport) error {
+		select {
+		case out <- &aproto.MasterMessage{AgentHealthReport: report}:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 func (a *Agent) enrichLog(log *aproto.ContainerLog) *aproto.ContainerLog {
 	log.AgentID = &a.opts.AgentID
 	if log.Source == nil {
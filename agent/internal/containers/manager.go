@@ -34,9 +34,10 @@ const (
 // state.
 type Manager struct {
 	// Configuration details. Set in initialization and never modified after.
-	opts    options.Options
-	mopts   aproto.MasterSetAgentOptions
-	devices []device.Device
+	opts     options.Options
+	mopts    aproto.MasterSetAgentOptions
+	devices  []device.Device
+	fakeGPUs []options.FakeGPUDevice
 
 	// System dependencies. Also set in initialization and never modified after.
 	log      *log.Entry
@@ -50,11 +51,12 @@ type Manager struct {
 	mu          sync.RWMutex
 }
 
-// New returns a new container manager.
+// New returns a new container manager. fakeGPUs configures the devices, if they are fake GPUs.
 func New(
 	opts options.Options,
 	mopts aproto.MasterSetAgentOptions,
 	devices []device.Device,
+	fakeGPUs []options.FakeGPUDevice,
 	cl container.ContainerRuntime,
 	pub events.Publisher[container.Event],
 ) (*Manager, error) {
@@ -62,6 +64,7 @@ func New(
 		opts:        opts,
 		mopts:       mopts,
 		devices:     devices,
+		fakeGPUs:    fakeGPUs,
 		log:         log.WithField("component", "container-manager"),
 		cruntime:    cl,
 		pub:         pub,
@@ -199,7 +202,7 @@ func (m *Manager) StartContainer(ctx context.Context, req aproto.StartContainer)
 		return fmt.Errorf("devices specified in container spec not found on agent")
 	}
 
-	spec, err := overwriteSpec(req.Spec, req.Container, m.opts, m.mopts)
+	spec, err := overwriteSpec(req.Spec, req.Container, m.opts, m.mopts, m.fakeGPUs)
 	if err != nil {
 		return fmt.Errorf("failed to overwrite spec: %w", err)
 	}
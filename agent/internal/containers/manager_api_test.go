@@ -53,7 +53,7 @@ func TestManager(t *testing.T) {
 	f := events.ChannelPublisher(evs)
 
 	t.Log("creating container manager")
-	m, err := containers.New(opts, mopts, nil, cl, f)
+	m, err := containers.New(opts, mopts, nil, nil, cl, f)
 	require.NoError(t, err)
 	defer m.Close()
 
@@ -292,7 +292,7 @@ func TestManagerReattach(t *testing.T) {
 	f := events.ChannelPublisher(evs)
 
 	t.Log("creating container manager")
-	m, err := containers.New(opts, mopts, nil, cl, f)
+	m, err := containers.New(opts, mopts, nil, nil, cl, f)
 	require.NoError(t, err)
 	defer m.Close()
 
@@ -6,6 +6,9 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/determined-ai/determined/agent/internal/options"
+	"github.com/determined-ai/determined/master/pkg/aproto"
+	"github.com/determined-ai/determined/master/pkg/cproto"
+	"github.com/determined-ai/determined/master/pkg/device"
 )
 
 func TestAddProxyInfo(t *testing.T) {
@@ -78,3 +81,44 @@ func TestAddProxyInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestOverwriteSpecFakeGPUs(t *testing.T) {
+	cont := cproto.Container{
+		ID: cproto.ID("container"),
+		Devices: []device.Device{
+			{ID: 0, UUID: "GPU-0", Type: device.CUDA},
+			{ID: 1, UUID: "GPU-1", Type: device.CUDA},
+		},
+	}
+
+	fakeGPUs := []options.FakeGPUDevice{
+		{UUID: "GPU-0", MemoryMB: 1024},
+		{UUID: "GPU-1", MemoryMB: 2048},
+	}
+	opts := options.Options{SlotType: options.FakeGPUSlotType}
+
+	spec, err := overwriteSpec(cproto.Spec{}, cont, opts, aproto.MasterSetAgentOptions{}, fakeGPUs)
+	require.NoError(t, err)
+	require.Empty(t, spec.RunSpec.HostConfig.DeviceRequests)
+	require.Subset(t, spec.RunSpec.ContainerConfig.Env, []string{
+		"DET_FAKE_GPU_MEMORY_MB=1024,2048",
+		"DET_SLOT_IDS=[0,1]",
+	})
+	for _, env := range spec.RunSpec.ContainerConfig.Env {
+		require.NotContains(t, env, "NVIDIA_VISIBLE_DEVICES")
+	}
+
+	opts.FakeGPUs.NvidiaVisibleDevices = true
+	spec, err = overwriteSpec(cproto.Spec{}, cont, opts, aproto.MasterSetAgentOptions{}, fakeGPUs)
+	require.NoError(t, err)
+	require.Subset(t, spec.RunSpec.ContainerConfig.Env, []string{
+		"NVIDIA_VISIBLE_DEVICES=GPU-0,GPU-1",
+		"NVIDIA_DRIVER_CAPABILITIES=compute,utility",
+	})
+
+	spec, err = overwriteSpec(cproto.Spec{}, cont, options.Options{SlotType: "cuda"},
+		aproto.MasterSetAgentOptions{}, nil)
+	require.NoError(t, err)
+	require.Len(t, spec.RunSpec.HostConfig.DeviceRequests, 1)
+	require.Equal(t, []string{"GPU-0", "GPU-1"}, spec.RunSpec.HostConfig.DeviceRequests[0].DeviceIDs)
+}
@@ -26,6 +26,7 @@ func overwriteSpec(
 	cont cproto.Container,
 	opts options.Options,
 	mopts aproto.MasterSetAgentOptions,
+	fakeGPUs []options.FakeGPUDevice,
 ) (cproto.Spec, error) {
 	spec.RunSpec.ContainerConfig.Env = addProxyInfo(spec.RunSpec.ContainerConfig.Env, opts)
 	spec.RunSpec.ContainerConfig.Env = append(
@@ -44,7 +45,11 @@ func overwriteSpec(
 		spec.RunSpec.ContainerConfig.Labels[k] = v
 	}
 
-	if len(cont.DeviceUUIDsByType(device.CUDA)) > 0 {
+	switch {
+	case opts.SlotType == options.FakeGPUSlotType:
+		spec.RunSpec.ContainerConfig.Env = append(
+			spec.RunSpec.ContainerConfig.Env, fakeGPUEnv(cont, fakeGPUs, opts.FakeGPUs.NvidiaVisibleDevices)...)
+	case len(cont.DeviceUUIDsByType(device.CUDA)) > 0:
 		spec.RunSpec.HostConfig.DeviceRequests = append(
 			spec.RunSpec.HostConfig.DeviceRequests, cudaDeviceRequests(cont)...)
 	}
@@ -96,6 +101,37 @@ func cudaDeviceRequests(cont cproto.Container) []dcontainer.DeviceRequest {
 	}
 }
 
+// fakeGPUEnv passes the memory of the fake devices of the container, for tests to check against.
+// If nvidiaVisibleDevices is set, it also returns the environment variables that the NVIDIA
+// container runtime would give a container with the devices, in place of the devices themselves,
+// which are fake; the NVIDIA container runtime would fail to find them, so it is opt-in.
+func fakeGPUEnv(
+	cont cproto.Container, fakeGPUs []options.FakeGPUDevice, nvidiaVisibleDevices bool,
+) []string {
+	uuids := cont.DeviceUUIDsByType(device.CUDA)
+	if len(uuids) == 0 {
+		return nil
+	}
+	var memory []string
+	for _, uuid := range uuids {
+		memoryMB := 0
+		for _, d := range fakeGPUs {
+			if d.UUID == uuid {
+				memoryMB = d.MemoryMB
+			}
+		}
+		memory = append(memory, strconv.Itoa(memoryMB))
+	}
+	env := []string{"DET_FAKE_GPU_MEMORY_MB=" + strings.Join(memory, ",")}
+	if nvidiaVisibleDevices {
+		env = append(env,
+			"NVIDIA_VISIBLE_DEVICES="+strings.Join(uuids, ","),
+			"NVIDIA_DRIVER_CAPABILITIES=compute,utility",
+		)
+	}
+	return env
+}
+
 func injectRocmDeviceRequests(cont cproto.Container, hostConfig *dcontainer.HostConfig) error {
 	// Docker args for "all rocm gpus":
 	//   --device=/dev/kfd --device=/dev/dri --security-opt seccomp=unconfined --group-add video
@@ -11,16 +11,22 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/determined-ai/determined/agent/internal/options"
 	"github.com/determined-ai/determined/master/pkg/device"
 )
 
 // Detect the devices available. If artificial devices are configured, prefers those, otherwise,
-// we detect cuda, rocm, cpu (or no) devices based on the configured slot type.
-func Detect(slotType, agentID, visibleGPUs string, artificialSlots int) ([]device.Device, error) {
+// we detect cuda, rocm, cpu (or no) devices, or synthesize fake cuda devices, based on the
+// configured slot type. With fake GPUs, it also returns the configuration of each detected one.
+func Detect(
+	slotType, agentID, visibleGPUs string,
+	artificialSlots int,
+	fakeGPUs options.FakeGPUOptions,
+) ([]device.Device, []options.FakeGPUDevice, error) {
 	// Log detected nvidia version.
 	v, err := getNvidiaVersion()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get nvidia version: %w", err)
+		return nil, nil, fmt.Errorf("failed to get nvidia version: %w", err)
 	} else if v != "" {
 		log.Infof("Nvidia driver version: %s", v)
 	}
@@ -28,39 +34,45 @@ func Detect(slotType, agentID, visibleGPUs string, artificialSlots int) ([]devic
 	// Log detected rocm version.
 	v, err = getRocmVersion()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get rocm version: %w", err)
+		return nil, nil, fmt.Errorf("failed to get rocm version: %w", err)
 	} else if v != "" {
 		log.Infof("Rocm driver version: %s", v)
 	}
 
 	// Detect devices available to the agent.
 	var detected []device.Device
+	var fakes []options.FakeGPUDevice
 	switch {
 	case artificialSlots > 0:
 		// Generate random UUIDs consistent across agent restarts as long as
 		// agentID is the same.
 		rnd, sErr := randFromString(agentID)
 		if sErr != nil {
-			return nil, sErr
+			return nil, nil, sErr
 		}
 
 		for i := 0; i < artificialSlots; i++ {
 			u, rErr := uuid.NewRandomFromReader(rnd)
 			if rErr != nil {
-				return nil, rErr
+				return nil, nil, rErr
 			}
 			id := u.String()
 			detected = append(detected, device.Device{
 				ID: device.ID(i), Brand: "Artificial", UUID: id, Type: device.CPU,
 			})
 		}
+	case slotType == options.FakeGPUSlotType:
+		detected, fakes, err = detectFakeGPUs(agentID, visibleGPUs, fakeGPUs)
+		if err != nil {
+			return nil, nil, fmt.Errorf("synthesizing fake GPUs: %w", err)
+		}
 	case slotType == "none":
 		detected = []device.Device{}
 	case slotType == "cuda" || slotType == "gpu":
 		// Support "gpu" for backwards compatibility.
 		detected, err = detectCudaGPUs(visibleGPUs)
 		if err != nil {
-			return nil, errors.Wrap(
+			return nil, nil, errors.Wrap(
 				err,
 				"error while gathering GPU info through nvidia-smi command",
 			)
@@ -68,17 +80,17 @@ func Detect(slotType, agentID, visibleGPUs string, artificialSlots int) ([]devic
 	case slotType == "rocm":
 		detected, err = detectRocmGPUs(visibleGPUs)
 		if err != nil {
-			return nil, errors.Wrap(err, "error while gathering GPU info through rocm-smi command")
+			return nil, nil, errors.Wrap(err, "error while gathering GPU info through rocm-smi command")
 		}
 	case slotType == "cpu":
 		detected, err = detectCPUs()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	case slotType == "auto":
 		detected, err = detectCudaGPUs(visibleGPUs)
 		if err != nil {
-			return nil, errors.Wrap(
+			return nil, nil, errors.Wrap(
 				err,
 				"error while gathering GPU info through nvidia-smi command",
 			)
@@ -86,7 +98,7 @@ func Detect(slotType, agentID, visibleGPUs string, artificialSlots int) ([]devic
 		if len(detected) == 0 {
 			detected, err = detectRocmGPUs(visibleGPUs)
 			if err != nil {
-				return nil, errors.Wrap(
+				return nil, nil, errors.Wrap(
 					err,
 					"error while gathering GPU info through rocm-smi command",
 				)
@@ -95,7 +107,7 @@ func Detect(slotType, agentID, visibleGPUs string, artificialSlots int) ([]devic
 		if len(detected) == 0 {
 			detected, err = detectCPUs()
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 		}
 	default:
@@ -107,7 +119,7 @@ func Detect(slotType, agentID, visibleGPUs string, artificialSlots int) ([]devic
 		log.Infof("\t%s", d.String())
 	}
 
-	return detected, nil
+	return detected, fakes, nil
 }
 
 // randFromString returns a random-number generated seeded from an input string.
@@ -0,0 +1,81 @@
+package detect
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/determined-ai/determined/agent/internal/options"
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+const (
+	defaultFakeGPUBrand    = "Fake GPU"
+	defaultFakeGPUMemoryMB = 16384
+)
+
+// detectFakeGPUs synthesizes CUDA devices as configured, so that GPU scheduling can be exercised on
+// hosts without GPUs. Default UUIDs are consistent across agent restarts as long as agentID is the
+// same. Like nvidia-smi, visibleGPUs selects devices by index or UUID. It returns the devices along
+// with the configuration of each, with defaults filled in.
+func detectFakeGPUs(
+	agentID string, visibleGPUs string, opts options.FakeGPUOptions,
+) ([]device.Device, []options.FakeGPUDevice, error) {
+	count := opts.Count
+	if count == 0 {
+		count = max(len(opts.Devices), 1)
+	}
+	if count < len(opts.Devices) {
+		return nil, nil, fmt.Errorf(
+			"fake_gpus.count %d is less than the %d listed devices", count, len(opts.Devices))
+	}
+
+	rnd, err := randFromString(agentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	fakes := make([]options.FakeGPUDevice, count)
+	copy(fakes, opts.Devices)
+	for i := range fakes {
+		// Always draw a UUID, so that default UUIDs don't change when others are configured.
+		u, rErr := uuid.NewRandomFromReader(rnd)
+		if rErr != nil {
+			return nil, nil, rErr
+		}
+		if fakes[i].UUID == "" {
+			fakes[i].UUID = "GPU-" + u.String()
+		}
+		if fakes[i].Brand == "" {
+			fakes[i].Brand = defaultFakeGPUBrand
+		}
+		if fakes[i].MemoryMB == 0 {
+			fakes[i].MemoryMB = defaultFakeGPUMemoryMB
+		}
+		if slices.ContainsFunc(fakes[:i], func(d options.FakeGPUDevice) bool {
+			return d.UUID == fakes[i].UUID
+		}) {
+			return nil, nil, fmt.Errorf("duplicate fake GPU UUID %s", fakes[i].UUID)
+		}
+	}
+
+	var visible []string
+	if visibleGPUs != "" {
+		visible = strings.Split(visibleGPUs, ",")
+	}
+	var detected []device.Device
+	var configs []options.FakeGPUDevice
+	for i, d := range fakes {
+		if visible != nil &&
+			!slices.Contains(visible, strconv.Itoa(i)) && !slices.Contains(visible, d.UUID) {
+			continue
+		}
+		configs = append(configs, d)
+		detected = append(detected, device.Device{
+			ID: device.ID(i), Brand: d.Brand, UUID: d.UUID, Type: device.CUDA,
+		})
+	}
+	return detected, configs, nil
+}
@@ -0,0 +1,55 @@
+package detect
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/agent/internal/options"
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+func TestDetectFakeGPUs(t *testing.T) {
+	opts := options.FakeGPUOptions{
+		Count: 3,
+		Devices: []options.FakeGPUDevice{
+			{UUID: "MIG-0a1b", Brand: "NVIDIA A100 MIG 1g.5gb", MemoryMB: 5120},
+		},
+	}
+	detected, fakes, err := Detect(options.FakeGPUSlotType, "agent-1", "", 0, opts)
+	assert.NilError(t, err)
+	assert.Equal(t, len(detected), 3)
+	assert.Equal(t, len(fakes), 3)
+	assert.DeepEqual(t, detected[0], device.Device{
+		ID: 0, Brand: "NVIDIA A100 MIG 1g.5gb", UUID: "MIG-0a1b", Type: device.CUDA,
+	})
+	assert.DeepEqual(t, fakes[0], opts.Devices[0])
+	for i, d := range detected[1:] {
+		assert.Equal(t, d.Type, device.CUDA)
+		assert.Equal(t, d.Brand, defaultFakeGPUBrand)
+		assert.DeepEqual(t, fakes[i+1], options.FakeGPUDevice{
+			UUID: d.UUID, Brand: defaultFakeGPUBrand, MemoryMB: defaultFakeGPUMemoryMB,
+		})
+	}
+
+	// Default UUIDs are stable across restarts of the same agent.
+	again, _, err := Detect(options.FakeGPUSlotType, "agent-1", "", 0, opts)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, again, detected)
+
+	// Visible GPUs select devices by index or UUID.
+	visible, visibleFakes, err := Detect(options.FakeGPUSlotType, "agent-1", "MIG-0a1b,2", 0, opts)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, visible, []device.Device{detected[0], detected[2]})
+	assert.DeepEqual(t, visibleFakes, []options.FakeGPUDevice{fakes[0], fakes[2]})
+
+	// With no configuration, a single device is synthesized.
+	single, _, err := Detect(options.FakeGPUSlotType, "agent-1", "", 0, options.FakeGPUOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(single), 1)
+
+	_, _, err = Detect(options.FakeGPUSlotType, "agent-1", "", 0, options.FakeGPUOptions{
+		Devices: []options.FakeGPUDevice{{UUID: "GPU-1"}, {UUID: "GPU-1"}},
+	})
+	assert.ErrorContains(t, err, "duplicate fake GPU UUID")
+}
@@ -0,0 +1,201 @@
+package health
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+// waitDelay bounds the wait for the output of a killed command, which its children may hold open.
+const waitDelay = time.Second
+
+// ScriptChecker runs a command as a health check. The command gets the UUIDs of the agent's
+// devices, comma-separated, in DET_AGENT_DEVICE_UUIDS. If it exits with zero, all devices are
+// healthy. Otherwise, each line of its output of the form "<device UUID> <reason>" marks a device
+// unhealthy; if no line names a device, all devices are unhealthy.
+type ScriptChecker struct {
+	Command []string
+}
+
+// Name implements Checker.
+func (s *ScriptChecker) Name() string {
+	return "script"
+}
+
+// Check implements Checker.
+func (s *ScriptChecker) Check(
+	ctx context.Context, devices []device.Device,
+) (map[device.ID]string, error) {
+	uuids := make([]string, 0, len(devices))
+	for _, d := range devices {
+		uuids = append(uuids, d.UUID)
+	}
+	// #nosec G204 // The command is configured by the agent's administrator.
+	cmd := exec.CommandContext(ctx, s.Command[0], s.Command[1:]...)
+	cmd.Env = append(os.Environ(), "DET_AGENT_DEVICE_UUIDS="+strings.Join(uuids, ","))
+	cmd.WaitDelay = waitDelay
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return nil, nil
+	case ctx.Err() != nil:
+		return nil, fmt.Errorf("running health check script: %w", ctx.Err())
+	case !errors.As(err, &exitErr):
+		return nil, fmt.Errorf("running health check script: %w", err)
+	}
+
+	unhealthy := map[device.ID]string{}
+	for scan := bufio.NewScanner(bytes.NewReader(out)); scan.Scan(); {
+		uuid, reason, _ := strings.Cut(strings.TrimSpace(scan.Text()), " ")
+		for _, d := range devices {
+			if d.UUID == uuid {
+				unhealthy[d.ID] = strings.TrimSpace(reason)
+			}
+		}
+	}
+	if len(unhealthy) == 0 {
+		return allUnhealthy(devices, exitReason("health check script", err, out)), nil
+	}
+	return unhealthy, nil
+}
+
+// NvidiaSMIChecker queries nvidia-smi for the agent's NVIDIA GPUs. GPUs that nvidia-smi does not
+// list, e.g., since they fell off the bus, and GPUs with uncorrected ECC errors are unhealthy. MIG
+// instances are not checked on their own.
+type NvidiaSMIChecker struct{}
+
+// Name implements Checker.
+func (n *NvidiaSMIChecker) Name() string {
+	return "nvidia-smi"
+}
+
+// Check implements Checker.
+func (n *NvidiaSMIChecker) Check(
+	ctx context.Context, devices []device.Device,
+) (map[device.ID]string, error) {
+	gpus := devicesOfType(devices, device.CUDA)
+	if len(gpus) == 0 {
+		return nil, nil
+	}
+	// #nosec G204
+	cmd := exec.CommandContext(ctx, "nvidia-smi",
+		"--query-gpu=uuid,ecc.errors.uncorrected.volatile.total", "--format=csv,noheader")
+	cmd.WaitDelay = waitDelay
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return parseNvidiaSMIHealth(out, gpus)
+	case ctx.Err() != nil:
+		return nil, fmt.Errorf("running nvidia-smi: %w", ctx.Err())
+	case errors.As(err, &exitErr):
+		// nvidia-smi fails outright when a GPU is lost, e.g., "Unable to determine the device
+		// handle for GPU 0000:3B:00.0: GPU is lost".
+		return allUnhealthy(gpus, exitReason("nvidia-smi", err, append(out, exitErr.Stderr...))), nil
+	default:
+		return nil, fmt.Errorf("running nvidia-smi: %w", err)
+	}
+}
+
+func parseNvidiaSMIHealth(out []byte, gpus []device.Device) (map[device.ID]string, error) {
+	uncorrected := map[string]string{}
+	r := csv.NewReader(bytes.NewReader(out))
+	r.TrimLeadingSpace = true
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("parsing output of nvidia-smi: %w", err)
+		}
+		if len(record) != 2 {
+			return nil, fmt.Errorf("parsing output of nvidia-smi: expected 2 fields, got %d",
+				len(record))
+		}
+		uncorrected[record[0]] = record[1]
+	}
+
+	unhealthy := map[device.ID]string{}
+	for _, gpu := range gpus {
+		if strings.HasPrefix(gpu.UUID, "MIG-") {
+			continue
+		}
+		errs, ok := uncorrected[gpu.UUID]
+		if !ok {
+			unhealthy[gpu.ID] = "GPU is not visible to nvidia-smi"
+			continue
+		}
+		// ECC counts are "[N/A]" on GPUs without ECC.
+		if n, err := strconv.Atoi(errs); err == nil && n > 0 {
+			unhealthy[gpu.ID] = fmt.Sprintf("GPU has %d uncorrected ECC errors", n)
+		}
+	}
+	return unhealthy, nil
+}
+
+// RocmSMIChecker queries rocm-smi for the agent's AMD GPUs. GPUs that rocm-smi does not list are
+// unhealthy.
+type RocmSMIChecker struct{}
+
+// Name implements Checker.
+func (r *RocmSMIChecker) Name() string {
+	return "rocm-smi"
+}
+
+// Check implements Checker.
+func (r *RocmSMIChecker) Check(
+	ctx context.Context, devices []device.Device,
+) (map[device.ID]string, error) {
+	gpus := devicesOfType(devices, device.ROCM)
+	if len(gpus) == 0 {
+		return nil, nil
+	}
+	cmd := exec.CommandContext(ctx, "rocm-smi", "--showuniqueid", "--json")
+	cmd.WaitDelay = waitDelay
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return parseRocmSMIHealth(out, gpus)
+	case ctx.Err() != nil:
+		return nil, fmt.Errorf("running rocm-smi: %w", ctx.Err())
+	case errors.As(err, &exitErr):
+		return allUnhealthy(gpus, exitReason("rocm-smi", err, append(out, exitErr.Stderr...))), nil
+	default:
+		return nil, fmt.Errorf("running rocm-smi: %w", err)
+	}
+}
+
+func parseRocmSMIHealth(out []byte, gpus []device.Device) (map[device.ID]string, error) {
+	cards := map[string]struct {
+		UUID string `json:"Unique ID"`
+	}{}
+	if err := json.Unmarshal(out, &cards); err != nil {
+		return nil, fmt.Errorf("parsing output of rocm-smi: %w", err)
+	}
+	visible := map[string]bool{}
+	for _, card := range cards {
+		visible[card.UUID] = true
+	}
+
+	unhealthy := map[device.ID]string{}
+	for _, gpu := range gpus {
+		if !visible[gpu.UUID] {
+			unhealthy[gpu.ID] = "GPU is not visible to rocm-smi"
+		}
+	}
+	return unhealthy, nil
+}
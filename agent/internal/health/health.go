@@ -0,0 +1,175 @@
+// Package health runs periodic health checks of the agent's devices, whose results the master uses
+// to disable the slots of unhealthy devices.
+package health
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/agent/internal/options"
+	"github.com/determined-ai/determined/master/pkg/aproto"
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+const defaultTimeout = 60 * time.Second
+
+// Checker checks the health of the agent's devices.
+type Checker interface {
+	// Name describes the check in logs.
+	Name() string
+	// Check returns the reasons that devices are unhealthy, by device ID. Errors mean that the
+	// check could not run, not that devices are unhealthy.
+	Check(ctx context.Context, devices []device.Device) (map[device.ID]string, error)
+}
+
+// Checkers returns the checkers configured by the options.
+func Checkers(opts options.HealthCheckOptions) []Checker {
+	var checkers []Checker
+	if len(opts.Command) > 0 {
+		checkers = append(checkers, &ScriptChecker{Command: opts.Command})
+	}
+	if opts.QueryGPUs {
+		checkers = append(checkers, &NvidiaSMIChecker{}, &RocmSMIChecker{})
+	}
+	return checkers
+}
+
+// Run runs the configured health checks right away and then every interval, until the context is
+// canceled, and reports the results of each round.
+func Run(
+	ctx context.Context,
+	opts options.HealthCheckOptions,
+	devices []device.Device,
+	report func(context.Context, *aproto.AgentHealthReport) error,
+) {
+	log := logrus.WithField("component", "health-checks")
+	checkers := Checkers(opts)
+	if opts.Interval <= 0 || len(checkers) == 0 {
+		log.Trace("no health checks configured")
+		return
+	}
+	timeout := defaultTimeout
+	if opts.Timeout > 0 {
+		timeout = time.Duration(opts.Timeout) * time.Second
+	}
+
+	ticker := time.NewTicker(time.Duration(opts.Interval) * time.Second)
+	defer ticker.Stop()
+	var last map[device.ID]string
+	for {
+		unhealthy := check(ctx, log, checkers, devices, timeout)
+		if !equal(unhealthy, last) {
+			if len(unhealthy) == 0 {
+				log.Info("all devices passed health checks")
+			}
+			for id, reason := range unhealthy {
+				log.Warnf("device %d failed health checks: %s", id, reason)
+			}
+			last = unhealthy
+		}
+
+		// Report every round, rather than only changes, so a restarted master learns the state.
+		if err := report(ctx, newReport(devices, unhealthy, opts.DrainAgent)); err != nil {
+			log.WithError(err).Debug("health check report was not sent")
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// check runs all checkers and merges the reasons devices are unhealthy.
+func check(
+	ctx context.Context,
+	log *logrus.Entry,
+	checkers []Checker,
+	devices []device.Device,
+	timeout time.Duration,
+) map[device.ID]string {
+	unhealthy := map[device.ID]string{}
+	for _, c := range checkers {
+		checkCtx, cancel := context.WithTimeout(ctx, timeout)
+		reasons, err := c.Check(checkCtx, devices)
+		cancel()
+		if err != nil {
+			log.WithError(err).Errorf("%s health check failed to run", c.Name())
+			continue
+		}
+		for id, reason := range reasons {
+			if prev, ok := unhealthy[id]; ok {
+				reason = prev + "; " + reason
+			}
+			unhealthy[id] = reason
+		}
+	}
+	return unhealthy
+}
+
+func newReport(
+	devices []device.Device, unhealthy map[device.ID]string, drain bool,
+) *aproto.AgentHealthReport {
+	report := &aproto.AgentHealthReport{DrainAgent: drain}
+	for _, d := range devices {
+		if reason, ok := unhealthy[d.ID]; ok {
+			report.UnhealthyDevices = append(report.UnhealthyDevices, aproto.UnhealthyDevice{
+				Device: d,
+				Reason: reason,
+			})
+		}
+	}
+	return report
+}
+
+func equal(a, b map[device.ID]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// allUnhealthy marks all of the devices unhealthy for the same reason.
+func allUnhealthy(devices []device.Device, reason string) map[device.ID]string {
+	unhealthy := make(map[device.ID]string, len(devices))
+	for _, d := range devices {
+		unhealthy[d.ID] = reason
+	}
+	return unhealthy
+}
+
+func devicesOfType(devices []device.Device, t device.Type) []device.Device {
+	var result []device.Device
+	for _, d := range devices {
+		if d.Type == t {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+func trimOutput(out []byte) string {
+	const maxLen = 256
+	s := strings.TrimSpace(string(out))
+	if len(s) > maxLen {
+		s = s[:maxLen] + "..."
+	}
+	return s
+}
+
+func exitReason(name string, err error, out []byte) string {
+	if msg := trimOutput(out); msg != "" {
+		return fmt.Sprintf("%s failed: %s", name, msg)
+	}
+	return fmt.Sprintf("%s failed: %s", name, err)
+}
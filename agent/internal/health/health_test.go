@@ -0,0 +1,138 @@
+package health
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/agent/internal/options"
+	"github.com/determined-ai/determined/master/pkg/aproto"
+	"github.com/determined-ai/determined/master/pkg/device"
+)
+
+var testDevices = []device.Device{
+	{ID: 0, UUID: "GPU-0", Type: device.CUDA},
+	{ID: 1, UUID: "GPU-1", Type: device.CUDA},
+	{ID: 2, UUID: "MIG-2", Type: device.CUDA},
+}
+
+func TestScriptChecker(t *testing.T) {
+	ctx := context.Background()
+	cases := []struct {
+		name     string
+		script   string
+		expected map[device.ID]string
+		err      bool
+	}{
+		{name: "healthy", script: "exit 0"},
+		{
+			name:     "device uuids",
+			script:   `test "$DET_AGENT_DEVICE_UUIDS" = GPU-0,GPU-1,MIG-2`,
+			expected: nil,
+		},
+		{
+			name:     "named devices",
+			script:   "echo 'GPU-1 row remapping failed'; echo 'GPU-9 unknown'; exit 1",
+			expected: map[device.ID]string{1: "row remapping failed"},
+		},
+		{
+			name:   "all devices",
+			script: "echo 'host is overheating'; exit 2",
+			expected: map[device.ID]string{
+				0: "health check script failed: host is overheating",
+				1: "health check script failed: host is overheating",
+				2: "health check script failed: host is overheating",
+			},
+		},
+		{name: "timeout", script: "sleep 10", err: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(ctx, time.Second)
+			defer cancel()
+			c := &ScriptChecker{Command: []string{"sh", "-c", tc.script}}
+			unhealthy, err := c.Check(ctx, testDevices)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tc.expected == nil {
+				require.Empty(t, unhealthy)
+			} else {
+				require.Equal(t, tc.expected, unhealthy)
+			}
+		})
+	}
+}
+
+func TestParseNvidiaSMIHealth(t *testing.T) {
+	out := []byte("GPU-0, 0\nGPU-3, 2\n")
+	unhealthy, err := parseNvidiaSMIHealth(out, testDevices)
+	require.NoError(t, err)
+	require.Equal(t, map[device.ID]string{1: "GPU is not visible to nvidia-smi"}, unhealthy)
+
+	out = []byte("GPU-0, [N/A]\nGPU-1, 4\n")
+	unhealthy, err = parseNvidiaSMIHealth(out, testDevices)
+	require.NoError(t, err)
+	require.Equal(t, map[device.ID]string{1: "GPU has 4 uncorrected ECC errors"}, unhealthy)
+
+	_, err = parseNvidiaSMIHealth([]byte("GPU-0\n"), testDevices)
+	require.Error(t, err)
+}
+
+func TestParseRocmSMIHealth(t *testing.T) {
+	gpus := []device.Device{
+		{ID: 0, UUID: "0x1234", Type: device.ROCM},
+		{ID: 1, UUID: "0x5678", Type: device.ROCM},
+	}
+	out := []byte(`{"card0": {"Unique ID": "0x1234"}}`)
+	unhealthy, err := parseRocmSMIHealth(out, gpus)
+	require.NoError(t, err)
+	require.Equal(t, map[device.ID]string{1: "GPU is not visible to rocm-smi"}, unhealthy)
+}
+
+func TestRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reports := make(chan *aproto.AgentHealthReport)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Run(ctx, options.HealthCheckOptions{
+			Interval:   1,
+			Command:    []string{"sh", "-c", "echo 'GPU-0 bad'; exit 1"},
+			DrainAgent: true,
+		}, testDevices, func(ctx context.Context, r *aproto.AgentHealthReport) error {
+			select {
+			case reports <- r:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	// Every round is reported, not just changes.
+	for i := 0; i < 2; i++ {
+		select {
+		case r := <-reports:
+			require.Equal(t, &aproto.AgentHealthReport{
+				UnhealthyDevices: []aproto.UnhealthyDevice{{Device: testDevices[0], Reason: "bad"}},
+				DrainAgent:       true,
+			}, r)
+		case <-time.After(10 * time.Second):
+			t.Fatal("no health report")
+		}
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("health checks did not stop")
+	}
+}
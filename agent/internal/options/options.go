@@ -19,6 +19,9 @@ const (
 	RocrVisibleDevices = "ROCR_VISIBLE_DEVICES"
 	// CudaVisibleDevices define the CUDA resources allocated by Slurm.
 	CudaVisibleDevices = "CUDA_VISIBLE_DEVICES"
+
+	// FakeGPUSlotType exposes synthesized CUDA devices, for testing GPU scheduling without GPUs.
+	FakeGPUSlotType = "fake-gpu"
 )
 
 // DefaultOptions returns the default configurable options for the Determined agent.
@@ -59,8 +62,9 @@ type Options struct {
 
 	Security SecurityOptions `json:"security"`
 
-	Debug           bool `json:"debug"`
-	ArtificialSlots int  `json:"artificial_slots"`
+	Debug           bool           `json:"debug"`
+	ArtificialSlots int            `json:"artificial_slots"`
+	FakeGPUs        FakeGPUOptions `json:"fake_gpus"`
 
 	TLS         bool   `json:"tls"`
 	TLSCertFile string `json:"tls_cert"`
@@ -86,6 +90,8 @@ type Options struct {
 
 	Hooks HooksOptions `json:"hooks"`
 
+	HealthCheck HealthCheckOptions `json:"health_check"`
+
 	// The Fluent docker image to use, deprecated.
 	Fluent FluentOptions `json:"fluent"`
 }
@@ -111,8 +117,14 @@ func (o Options) Deprecations() (errs []error) {
 func (o Options) Validate() []error {
 	return []error{
 		o.validateTLS(),
-		check.In(o.SlotType, []string{"gpu", "cuda", "rocm", "cpu", "auto", "none"}),
+		check.In(o.SlotType, []string{"gpu", "cuda", "rocm", "cpu", "auto", "none", FakeGPUSlotType}),
 		check.NotEmpty(o.MasterHost, "master host must be provided"),
+		check.In(o.ContainerRuntime, []string{DockerContainerRuntime, PodmanContainerRuntime}),
+		check.GreaterThanOrEqualTo(o.FakeGPUs.Count, 0, "fake_gpus.count must be non-negative"),
+		check.GreaterThanOrEqualTo(o.HealthCheck.Interval, 0,
+			"health_check.interval must be non-negative"),
+		check.GreaterThanOrEqualTo(o.HealthCheck.Timeout, 0,
+			"health_check.timeout must be non-negative"),
 	}
 }
 
@@ -207,12 +219,46 @@ type HooksOptions struct {
 	OnConnectionLost []string `json:"on_connection_lost"`
 }
 
+// HealthCheckOptions configures periodic health checks of the agent's devices. The master disables
+// the slots of devices that fail them.
+type HealthCheckOptions struct {
+	// Interval is the time between health checks, in seconds; 0 disables health checks.
+	Interval int `json:"interval"`
+	// Timeout is the time each health check may take, in seconds; 0 means 60 seconds.
+	Timeout int `json:"timeout"`
+	// Command is a script to run as a health check.
+	Command []string `json:"command"`
+	// QueryGPUs checks the agent's GPUs with nvidia-smi or rocm-smi.
+	QueryGPUs bool `json:"query_gpus"`
+	// DrainAgent asks the master to also drain the agent when a device is unhealthy.
+	DrainAgent bool `json:"drain_agent"`
+}
+
+// FakeGPUOptions configures the CUDA devices that the agent synthesizes with the fake-gpu slot type.
+type FakeGPUOptions struct {
+	// Count is the number of devices; it defaults to the number of listed devices, or 1.
+	Count int `json:"count"`
+	// Devices describes the first devices; the rest get defaults.
+	Devices []FakeGPUDevice `json:"devices"`
+	// NvidiaVisibleDevices sets NVIDIA_VISIBLE_DEVICES to the fake devices in containers. Hosts
+	// that run the NVIDIA container runtime fail to start containers with it set.
+	NvidiaVisibleDevices bool `json:"nvidia_visible_devices"`
+}
+
+// FakeGPUDevice describes a synthesized CUDA device. Unset fields get defaults.
+type FakeGPUDevice struct {
+	UUID     string `json:"uuid"`
+	Brand    string `json:"brand"`
+	MemoryMB int    `json:"memory_mb"`
+}
+
 // ContainerRuntime configures which container runtime to use.
 type ContainerRuntime string
 
 // Available container runtimes.
 const (
 	DockerContainerRuntime = "docker"
+	PodmanContainerRuntime = "podman"
 )
 
 // VisibleGPUsFromEnvironment returns GPU visibility information from the environment
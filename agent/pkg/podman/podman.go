@@ -0,0 +1,750 @@
+// Package podman implements a container runtime on top of the podman CLI, so that the agent can
+// run containers on hosts without a (root) Docker daemon.
+package podman
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/docker/distribution/reference"
+	"github.com/docker/docker/api/types"
+	dcontainer "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	typeReg "github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/docker/docker/registry"
+	"github.com/docker/go-connections/nat"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+
+	"github.com/determined-ai/determined/agent/pkg/cruntimes"
+	"github.com/determined-ai/determined/agent/pkg/docker"
+	"github.com/determined-ai/determined/agent/pkg/events"
+	"github.com/determined-ai/determined/master/pkg/aproto"
+	"github.com/determined-ai/determined/master/pkg/archive"
+	"github.com/determined-ai/determined/master/pkg/cproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+const (
+	// DefaultBinary is the podman binary that is used unless another one is configured.
+	DefaultBinary = "podman"
+
+	// autoRemoveLabel marks containers that we remove once we observe their exit.
+	autoRemoveLabel = "ai.determined.container.autoremove"
+)
+
+// command describes an invocation of the podman CLI.
+type command struct {
+	args   []string
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// runner runs a podman command to completion. Errors include the stderr of the command if it is
+// not captured by the caller.
+type runner func(ctx context.Context, cmd command) error
+
+// Client implements the agent container runtime with the podman CLI. It works with both rootful
+// and rootless podman, and only needs the podman binary on the host.
+type Client struct {
+	run runner
+	log *logrus.Entry
+}
+
+// NewClient returns a new Client that runs the given podman binary.
+func NewClient(binary string) (*Client, error) {
+	if binary == "" {
+		binary = DefaultBinary
+	}
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		return nil, fmt.Errorf("finding podman binary %s: %w", binary, err)
+	}
+	return newClient(execRunner(path)), nil
+}
+
+func newClient(run runner) *Client {
+	return &Client{run: run, log: logrus.WithField("component", "podman-client")}
+}
+
+func execRunner(path string) runner {
+	return func(ctx context.Context, c command) error {
+		cmd := exec.CommandContext(ctx, path, c.args...) // #nosec G204 args are under our control
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = c.stdin, c.stdout, c.stderr
+		var stderr bytes.Buffer
+		if cmd.Stderr == nil {
+			cmd.Stderr = &stderr
+		}
+		if err := cmd.Run(); err != nil {
+			if len(c.args) == 0 {
+				return err
+			}
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return fmt.Errorf("podman %s: %w: %s", c.args[0], err, msg)
+			}
+			return fmt.Errorf("podman %s: %w", c.args[0], err)
+		}
+		return nil
+	}
+}
+
+// output runs a podman command and returns its stdout.
+func (c *Client) output(ctx context.Context, args ...string) ([]byte, error) {
+	var stdout bytes.Buffer
+	if err := c.run(ctx, command{args: args, stdout: &stdout}); err != nil {
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+// ReattachContainer looks for a single running or terminated container with the given Determined
+// container ID and returns whether it can be reattached or has terminated.
+func (c *Client) ReattachContainer(
+	ctx context.Context,
+	id cproto.ID,
+) (*docker.Container, *aproto.ExitCode, error) {
+	containers, err := c.listContainers(
+		ctx, true, docker.LabelFilter(docker.ContainerIDLabel, id.String()))
+	if err != nil {
+		return nil, nil, fmt.Errorf("while reattaching container: %w", err)
+	}
+
+	switch len(containers) {
+	case 0:
+		return nil, nil, nil
+	case 1:
+	default:
+		return nil, nil, errors.New("reattach filters matched more than one container")
+	}
+
+	info, autoRemove, err := c.inspect(ctx, containers[0].ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("inspecting reattached container: %w", err)
+	}
+	if !info.State.Running {
+		if autoRemove {
+			if rErr := c.RemoveContainer(ctx, info.ID, true); rErr != nil {
+				c.log.WithError(rErr).Warnf("removing exited container %s", info.ID)
+			}
+		}
+		return nil, ptrs.Ptr(aproto.ExitCode(info.State.ExitCode)), nil
+	}
+	return &docker.Container{
+		ContainerInfo:   info,
+		ContainerWaiter: c.wait(ctx, info.ID, autoRemove),
+	}, nil, nil
+}
+
+// PullImage pulls an image according to the given request. Credentials are taken from the
+// request's registry auth if it matches the image's registry, and otherwise from podman's own
+// credentials, e.g., those from `podman login`. It takes a caller-provided channel on which events
+// are sent. Slow receivers will block the call.
+func (c *Client) PullImage(
+	ctx context.Context,
+	req docker.PullImage,
+	p events.Publisher[docker.Event],
+) (err error) {
+	ref, err := reference.ParseNormalizedNamed(req.Name)
+	if err != nil {
+		return fmt.Errorf("error parsing image name %s: %w", req.Name, err)
+	}
+	ref = reference.TagNameOnly(ref)
+
+	out, err := c.output(ctx, "image", "ls", "--quiet", ref.String())
+	switch present := len(bytes.TrimSpace(out)) > 0; {
+	case err != nil:
+		return fmt.Errorf("error checking if image exists %s: %w", ref.String(), err)
+	case present && req.ForcePull:
+		if err = p.Publish(ctx, docker.NewLogEvent(model.LogLevelInfo, fmt.Sprintf(
+			"image present, but force_pull_image is set; checking for updates: %s",
+			ref.String(),
+		))); err != nil {
+			return err
+		}
+	case !present:
+		if err = p.Publish(ctx, docker.NewLogEvent(model.LogLevelInfo, fmt.Sprintf(
+			"image not found, pulling image: %s", ref.String(),
+		))); err != nil {
+			return err
+		}
+	default:
+		return p.Publish(ctx, docker.NewLogEvent(model.LogLevelInfo, fmt.Sprintf(
+			"image already found, skipping pull phase: %s", ref.String(),
+		)))
+	}
+
+	if err = p.Publish(ctx, docker.NewBeginStatsEvent(docker.ImagePullStatsKind)); err != nil {
+		return err
+	}
+	defer func() {
+		if scErr := p.Publish(ctx, docker.NewEndStatsEvent(docker.ImagePullStatsKind)); scErr != nil {
+			c.log.WithError(scErr).Warn("did not send image pull done stats")
+		}
+	}()
+
+	args := []string{"pull"}
+	auth, err := registryAuth(ctx, ref, req.Registry, p)
+	if err != nil {
+		return fmt.Errorf("could not get registry authentication: %w", err)
+	}
+	if auth != nil {
+		authFile, cleanup, aErr := writeAuthFile(reference.Domain(ref), *auth)
+		if aErr != nil {
+			return fmt.Errorf("writing registry credentials: %w", aErr)
+		}
+		defer cleanup()
+		args = append(args, "--authfile", authFile)
+	}
+	args = append(args, ref.String())
+	if err = cruntimes.PprintCommand(ctx, DefaultBinary, args, p, c.log); err != nil {
+		return err
+	}
+
+	// Pull progress is written to stderr; ship it line by line as it comes.
+	pr, pw := io.Pipe()
+	shipped := make(chan struct{})
+	go func() {
+		defer close(shipped)
+		cruntimes.ShipContainerCommandLogs(ctx, pr, stdcopy.Stderr, p)
+		_, _ = io.Copy(io.Discard, pr) // Keep the command unblocked if shipping stops early.
+	}()
+	var stderr bytes.Buffer
+	err = c.run(ctx, command{args: args, stderr: io.MultiWriter(pw, &stderr)})
+	_ = pw.Close()
+	<-shipped
+	if err != nil {
+		return fmt.Errorf("error pulling image %s: %w: %s",
+			ref.String(), err, lastLine(stderr.String()))
+	}
+	return nil
+}
+
+// CreateContainer creates a container according to the given spec, returning a podman container ID
+// to start it. It takes a caller-provided channel on which events are sent. Slow receivers will
+// block the call.
+func (c *Client) CreateContainer(
+	ctx context.Context,
+	id cproto.ID,
+	req cproto.RunSpec,
+	p events.Publisher[docker.Event],
+) (string, error) {
+	envFile, err := os.CreateTemp("", "det-podman-env-*")
+	if err != nil {
+		return "", fmt.Errorf("creating container environment file: %w", err)
+	}
+	defer func() {
+		if rErr := os.Remove(envFile.Name()); rErr != nil {
+			c.log.WithError(rErr).Warn("removing container environment file")
+		}
+	}()
+	args, envLines := createArgs(req, envFile.Name())
+	_, err = envFile.WriteString(strings.Join(envLines, "\n"))
+	if cErr := envFile.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("writing container environment file: %w", err)
+	}
+
+	if err = cruntimes.PprintCommand(ctx, DefaultBinary, args, p, c.log); err != nil {
+		return "", err
+	}
+	out, err := c.output(ctx, args...)
+	if err != nil {
+		return "", fmt.Errorf("creating container: %w", err)
+	}
+	podmanID := lastLine(string(out))
+
+	for _, copyArx := range req.Archives {
+		if err = p.Publish(ctx, docker.NewLogEvent(model.LogLevelInfo, fmt.Sprintf(
+			"copying files to container: %s", copyArx.Path,
+		))); err != nil {
+			return "", err
+		}
+
+		files, cErr := archive.ToIOReader(copyArx.Archive)
+		if cErr != nil {
+			return "", fmt.Errorf("converting RunSpec Archive files to io.Reader: %w", cErr)
+		}
+		// Unlike `docker cp`, `podman cp` chowns files to the container user by default, so
+		// keep the ownership from the archive unless asked otherwise.
+		cpArgs := []string{
+			"cp", "--archive=" + strconv.FormatBool(copyArx.CopyOptions.CopyUIDGID),
+			"-", podmanID + ":" + copyArx.Path,
+		}
+		if err = c.run(ctx, command{args: cpArgs, stdin: files}); err != nil {
+			return "", fmt.Errorf("copying files to container: %w", err)
+		}
+	}
+	return podmanID, nil
+}
+
+// RunContainer runs a container by podman container ID. RunContainer takes two contexts: one to
+// govern cancellation of running the container, and another to govern the lifetime of the waiter
+// returned.
+// nolint: golint // Both contexts can't both be first.
+func (c *Client) RunContainer(
+	ctx context.Context,
+	waitCtx context.Context,
+	id string,
+	p events.Publisher[docker.Event],
+) (*docker.Container, error) {
+	if err := c.run(ctx, command{args: []string{"start", id}}); err != nil {
+		return nil, fmt.Errorf("starting container: %w", err)
+	}
+
+	// Podman keeps the exit code of stopped containers, since we remove them ourselves, so
+	// starting to wait after the start does not miss immediate exits.
+	info, autoRemove, err := c.inspect(ctx, id)
+	if err != nil {
+		if cErr := c.RemoveContainer(ctx, id, true); cErr != nil {
+			c.log.
+				WithError(cErr).
+				WithField("podman-container-id", id).
+				Errorf("removing container %s after inspect failure", id)
+		}
+		return nil, fmt.Errorf("inspecting, container may be orphaned: %w", err)
+	}
+	return &docker.Container{
+		ContainerInfo:   info,
+		ContainerWaiter: c.wait(waitCtx, id, autoRemove),
+	}, nil
+}
+
+// SignalContainer signals the container, by podman container ID, with the requested signal.
+func (c *Client) SignalContainer(ctx context.Context, id string, sig syscall.Signal) error {
+	return c.run(ctx, command{args: []string{"kill", "--signal", unix.SignalName(sig), id}})
+}
+
+// RemoveContainer removes a podman container by ID.
+func (c *Client) RemoveContainer(ctx context.Context, id string, force bool) error {
+	return c.run(ctx, command{args: []string{"rm", "--force=" + strconv.FormatBool(force), id}})
+}
+
+// ListRunningContainers lists running podman containers satisfying the given filters.
+func (c *Client) ListRunningContainers(ctx context.Context, fs filters.Args) (
+	map[cproto.ID]types.Container, error,
+) {
+	containers, err := c.listContainers(ctx, false, fs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[cproto.ID]types.Container, len(containers))
+	for _, cont := range containers {
+		containerID, ok := cont.Labels[docker.ContainerIDLabel]
+		if ok {
+			result[cproto.ID(containerID)] = cont
+		} else {
+			c.log.Warnf("container %v has agent label but no container ID", cont.ID)
+		}
+	}
+	return result, nil
+}
+
+// psEntry is the subset of `podman ps --format json` output that we use.
+type psEntry struct {
+	ID     string            `json:"Id"`
+	Names  []string          `json:"Names"`
+	Image  string            `json:"Image"`
+	Labels map[string]string `json:"Labels"`
+	State  string            `json:"State"`
+	Status string            `json:"Status"`
+}
+
+func (c *Client) listContainers(
+	ctx context.Context, all bool, fs filters.Args,
+) ([]types.Container, error) {
+	args := []string{"ps", "--format", "json", "--all=" + strconv.FormatBool(all)}
+	for _, key := range fs.Keys() {
+		for _, val := range fs.Get(key) {
+			args = append(args, "--filter", key+"="+val)
+		}
+	}
+	out, err := c.output(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing containers: %w", err)
+	}
+
+	var entries []psEntry
+	if len(bytes.TrimSpace(out)) > 0 {
+		if err := json.Unmarshal(out, &entries); err != nil {
+			return nil, fmt.Errorf("parsing container list: %w", err)
+		}
+	}
+	containers := make([]types.Container, 0, len(entries))
+	for _, e := range entries {
+		containers = append(containers, types.Container{
+			ID:     e.ID,
+			Names:  e.Names,
+			Image:  e.Image,
+			Labels: e.Labels,
+			State:  e.State,
+			Status: e.Status,
+		})
+	}
+	return containers, nil
+}
+
+// inspectEntry is the subset of `podman inspect` output that we use. Its fields are named like
+// Docker's, but podman's output is not close enough to decode into types.ContainerJSON directly.
+type inspectEntry struct {
+	ID      string `json:"Id"`
+	Name    string `json:"Name"`
+	Image   string `json:"Image"`
+	Created string `json:"Created"`
+	State   struct {
+		Status     string `json:"Status"`
+		Running    bool   `json:"Running"`
+		Paused     bool   `json:"Paused"`
+		OOMKilled  bool   `json:"OOMKilled"`
+		Dead       bool   `json:"Dead"`
+		Pid        int    `json:"Pid"`
+		ExitCode   int    `json:"ExitCode"`
+		Error      string `json:"Error"`
+		StartedAt  string `json:"StartedAt"`
+		FinishedAt string `json:"FinishedAt"`
+	} `json:"State"`
+	Config struct {
+		Labels       map[string]string   `json:"Labels"`
+		ExposedPorts map[string]struct{} `json:"ExposedPorts"`
+	} `json:"Config"`
+	HostConfig struct {
+		NetworkMode string `json:"NetworkMode"`
+	} `json:"HostConfig"`
+	NetworkSettings struct {
+		IPAddress string                               `json:"IPAddress"`
+		Ports     nat.PortMap                          `json:"Ports"`
+		Networks  map[string]*network.EndpointSettings `json:"Networks"`
+	} `json:"NetworkSettings"`
+}
+
+// inspect returns the details of a container and whether podman should remove it once it exits.
+func (c *Client) inspect(ctx context.Context, id string) (types.ContainerJSON, bool, error) {
+	out, err := c.output(ctx, "inspect", "--type", "container", id)
+	if err != nil {
+		return types.ContainerJSON{}, false, err
+	}
+	var entries []inspectEntry
+	if err := json.Unmarshal(out, &entries); err != nil {
+		return types.ContainerJSON{}, false, fmt.Errorf("parsing container details: %w", err)
+	}
+	if len(entries) != 1 {
+		return types.ContainerJSON{}, false, fmt.Errorf(
+			"expected details of one container, got %d", len(entries))
+	}
+	e := entries[0]
+	autoRemove := e.Config.Labels[autoRemoveLabel] == "true"
+
+	exposedPorts := nat.PortSet{}
+	for port := range e.Config.ExposedPorts {
+		exposedPorts[nat.Port(port)] = struct{}{}
+	}
+	// Older podman versions only report exposed ports as part of the network settings.
+	for port := range e.NetworkSettings.Ports {
+		exposedPorts[port] = struct{}{}
+	}
+	networks := e.NetworkSettings.Networks
+	if len(networks) == 0 && e.HostConfig.NetworkMode != "host" {
+		// Rootless networking (slirp4netns or pasta) attaches no named network, but the master
+		// still needs one to report the published ports.
+		networks = map[string]*network.EndpointSettings{
+			"podman": {IPAddress: e.NetworkSettings.IPAddress},
+		}
+	}
+
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			ID:      e.ID,
+			Name:    e.Name,
+			Image:   e.Image,
+			Created: e.Created,
+			State: &types.ContainerState{
+				Status:     e.State.Status,
+				Running:    e.State.Running,
+				Paused:     e.State.Paused,
+				OOMKilled:  e.State.OOMKilled,
+				Dead:       e.State.Dead,
+				Pid:        e.State.Pid,
+				ExitCode:   e.State.ExitCode,
+				Error:      e.State.Error,
+				StartedAt:  e.State.StartedAt,
+				FinishedAt: e.State.FinishedAt,
+			},
+			HostConfig: &dcontainer.HostConfig{
+				NetworkMode: dcontainer.NetworkMode(e.HostConfig.NetworkMode),
+				AutoRemove:  autoRemove,
+			},
+		},
+		Config: &dcontainer.Config{
+			Labels:       e.Config.Labels,
+			ExposedPorts: exposedPorts,
+		},
+		NetworkSettings: &types.NetworkSettings{
+			NetworkSettingsBase: types.NetworkSettingsBase{Ports: e.NetworkSettings.Ports},
+			Networks:            networks,
+		},
+	}, autoRemove, nil
+}
+
+// wait returns a waiter for the next exit of a container, which removes it afterwards if asked.
+func (c *Client) wait(ctx context.Context, id string, autoRemove bool) docker.ContainerWaiter {
+	waiter := make(chan dcontainer.WaitResponse, 1)
+	errs := make(chan error, 1)
+	go func() {
+		out, err := c.output(ctx, "wait", id)
+		if err != nil {
+			errs <- fmt.Errorf("waiting for container: %w", err)
+			return
+		}
+		code, err := strconv.ParseInt(lastLine(string(out)), 10, 64)
+		if err != nil {
+			errs <- fmt.Errorf("parsing exit code of container: %w", err)
+			return
+		}
+		if autoRemove {
+			// The exit is already observed, so the removal should outlive the waiter.
+			if rErr := c.RemoveContainer(context.WithoutCancel(ctx), id, true); rErr != nil {
+				c.log.WithError(rErr).Warnf("removing exited container %s", id)
+			}
+		}
+		waiter <- dcontainer.WaitResponse{StatusCode: code}
+	}()
+	return docker.ContainerWaiter{Waiter: waiter, Errs: errs}
+}
+
+// createArgs translates a RunSpec into `podman create` arguments. The environment is passed
+// through the given env file, so it does not show up in the process list; the lines of the env
+// file are returned alongside the arguments. Variables whose values span lines, which env files
+// cannot express, are passed as arguments instead.
+func createArgs(req cproto.RunSpec, envFile string) (args []string, envLines []string) {
+	cc, hc := req.ContainerConfig, req.HostConfig
+
+	// The image is pulled beforehand, with credentials that `podman create` does not have.
+	args = []string{"create", "--pull", "never", "--env-file", envFile}
+	for _, env := range cc.Env {
+		if strings.Contains(env, "\n") {
+			args = append(args, "--env", env)
+		} else {
+			envLines = append(envLines, env)
+		}
+	}
+	for _, k := range sortedKeys(cc.Labels) {
+		args = append(args, "--label", k+"="+cc.Labels[k])
+	}
+	if cc.User != "" {
+		args = append(args, "--user", cc.User)
+	}
+	if cc.WorkingDir != "" {
+		args = append(args, "--workdir", cc.WorkingDir)
+	}
+	if len(cc.Entrypoint) > 0 {
+		entrypoint, _ := json.Marshal([]string(cc.Entrypoint)) // Marshaling strings can't fail.
+		args = append(args, "--entrypoint", string(entrypoint))
+	}
+	for _, port := range sortedKeys(cc.ExposedPorts) {
+		args = append(args, "--expose", string(port))
+	}
+
+	switch hc.NetworkMode {
+	case "", "default", "bridge":
+		// Let podman pick its default, which differs between rootful and rootless podman.
+	default:
+		args = append(args, "--network", string(hc.NetworkMode))
+	}
+	if hc.PublishAllPorts {
+		args = append(args, "--publish-all")
+	}
+	for _, port := range sortedKeys(hc.PortBindings) {
+		for _, b := range hc.PortBindings[port] {
+			args = append(args, "--publish", fmt.Sprintf("%s:%s:%s", b.HostIP, b.HostPort, port))
+		}
+	}
+	for _, bind := range hc.Binds {
+		args = append(args, "--volume", bind)
+	}
+	for _, m := range hc.Mounts {
+		args = append(args, "--mount", mountArg(m))
+	}
+	if hc.ShmSize > 0 {
+		args = append(args, "--shm-size", strconv.FormatInt(hc.ShmSize, 10))
+	}
+	if hc.IpcMode != "" {
+		args = append(args, "--ipc", string(hc.IpcMode))
+	}
+	if hc.Privileged {
+		args = append(args, "--privileged")
+	}
+	for _, capability := range hc.CapAdd {
+		args = append(args, "--cap-add", capability)
+	}
+	for _, capability := range hc.CapDrop {
+		args = append(args, "--cap-drop", capability)
+	}
+	for _, opt := range hc.SecurityOpt {
+		args = append(args, "--security-opt", opt)
+	}
+	for _, group := range hc.GroupAdd {
+		args = append(args, "--group-add", group)
+	}
+	for _, u := range hc.Ulimits {
+		args = append(args, "--ulimit", fmt.Sprintf("%s=%d:%d", u.Name, u.Soft, u.Hard))
+	}
+	for _, d := range hc.Devices {
+		args = append(args, "--device", deviceArg(d))
+	}
+	for _, r := range hc.DeviceRequests {
+		// Podman exposes GPUs through CDI, e.g., generated by `nvidia-ctk cdi generate`.
+		if r.Driver != "nvidia" {
+			continue
+		}
+		if len(r.DeviceIDs) == 0 && r.Count != 0 {
+			args = append(args, "--device", "nvidia.com/gpu=all")
+		}
+		for _, id := range r.DeviceIDs {
+			args = append(args, "--device", "nvidia.com/gpu="+id)
+		}
+	}
+	if hc.AutoRemove {
+		// Not `--rm`, which would race with `podman wait` for containers that exit quickly;
+		// the label tells us to remove the container once we observe its exit.
+		args = append(args, "--label", autoRemoveLabel+"=true")
+	}
+
+	args = append(args, cc.Image)
+	args = append(args, cc.Cmd...)
+	return args, envLines
+}
+
+func mountArg(m mount.Mount) string {
+	typ := m.Type
+	if typ == "" {
+		typ = mount.TypeBind
+	}
+	parts := []string{"type=" + string(typ), "destination=" + m.Target}
+	if m.Source != "" {
+		parts = append(parts, "source="+m.Source)
+	}
+	if m.ReadOnly {
+		parts = append(parts, "readonly=true")
+	}
+	if m.BindOptions != nil && m.BindOptions.Propagation != "" {
+		parts = append(parts, "bind-propagation="+string(m.BindOptions.Propagation))
+	}
+	return strings.Join(parts, ",")
+}
+
+func deviceArg(d dcontainer.DeviceMapping) string {
+	arg := d.PathOnHost
+	if d.PathInContainer != "" {
+		arg += ":" + d.PathInContainer
+	}
+	if d.CgroupPermissions != "" {
+		arg += ":" + d.CgroupPermissions
+	}
+	return arg
+}
+
+// registryAuth returns the registry credentials from the request to use for an image, or nil to use
+// podman's own credentials.
+func registryAuth(
+	ctx context.Context,
+	image reference.Named,
+	userRegistry *typeReg.AuthConfig,
+	p events.Publisher[docker.Event],
+) (*typeReg.AuthConfig, error) {
+	if userRegistry == nil {
+		return nil, nil
+	}
+
+	// TODO: remove didNotPassServerAddress when it becomes required.
+	didNotPassServerAddress := userRegistry.ServerAddress == ""
+	if didNotPassServerAddress {
+		if err := p.Publish(ctx, docker.NewLogEvent(model.LogLevelWarning,
+			"setting registry_auth without registry_auth.serveraddress is deprecated "+
+				"and the latter will soon be required")); err != nil {
+			return nil, err
+		}
+	}
+
+	imageDomain := reference.Domain(image)
+	registryDomain := registry.ConvertToHostname(userRegistry.ServerAddress)
+	if registryDomain == imageDomain || didNotPassServerAddress {
+		return userRegistry, nil
+	}
+	if err := p.Publish(ctx, docker.NewLogEvent(model.LogLevelWarning, fmt.Sprintf(
+		"not using expconfig registry_auth since expconf "+
+			"registry_auth.serverAddress %s did not match the image serverAddress %s",
+		registryDomain, imageDomain,
+	))); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// writeAuthFile writes the credentials for a registry to a private auth file in the format of
+// containers-auth.json(5), so they do not show up in the process list, and returns its path.
+func writeAuthFile(domain string, auth typeReg.AuthConfig) (string, func(), error) {
+	encoded := auth.Auth
+	if encoded == "" {
+		encoded = base64.StdEncoding.EncodeToString([]byte(auth.Username + ":" + auth.Password))
+	}
+	entry := map[string]string{"auth": encoded}
+	if auth.IdentityToken != "" {
+		entry["identitytoken"] = auth.IdentityToken
+	}
+	bs, err := json.Marshal(map[string]any{"auths": map[string]any{domain: entry}})
+	if err != nil {
+		return "", nil, err
+	}
+
+	// CreateTemp creates the file with mode 0600.
+	f, err := os.CreateTemp("", "det-podman-auth-*.json")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() {
+		if rErr := os.Remove(f.Name()); rErr != nil {
+			logrus.WithError(rErr).Warn("removing registry auth file")
+		}
+	}
+	_, err = f.Write(bs)
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return f.Name(), cleanup, nil
+}
+
+func sortedKeys[K ~string, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
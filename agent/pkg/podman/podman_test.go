@@ -0,0 +1,363 @@
+package podman
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	dcontainer "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/go-connections/nat"
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/agent/pkg/docker"
+	"github.com/determined-ai/determined/agent/pkg/events"
+	"github.com/determined-ai/determined/master/pkg/archive"
+	"github.com/determined-ai/determined/master/pkg/cproto"
+)
+
+type fakeContainer struct {
+	labels  map[string]string
+	running bool
+	exit    chan int
+	files   []byte
+}
+
+// fakePodman fakes the podman CLI, keeping just enough state to back the client.
+type fakePodman struct {
+	mu         sync.Mutex
+	images     map[string]bool
+	containers map[string]*fakeContainer
+	calls      [][]string
+	authFile   string
+	envFile    string
+}
+
+func newFakePodman() *fakePodman {
+	return &fakePodman{images: map[string]bool{}, containers: map[string]*fakeContainer{}}
+}
+
+func (f *fakePodman) container(id string) (*fakeContainer, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c, ok := f.containers[id]
+	if !ok {
+		return nil, fmt.Errorf("no such container %s", id)
+	}
+	return c, nil
+}
+
+func (f *fakePodman) run(ctx context.Context, cmd command) error {
+	f.mu.Lock()
+	f.calls = append(f.calls, cmd.args)
+	f.mu.Unlock()
+
+	args := cmd.args
+	flag := func(name string) string {
+		for i, arg := range args {
+			if arg == name && i+1 < len(args) {
+				return args[i+1]
+			}
+		}
+		return ""
+	}
+	switch args[0] {
+	case "image":
+		if f.images[args[len(args)-1]] {
+			_, _ = io.WriteString(cmd.stdout, "sha256:abc\n")
+		}
+	case "pull":
+		if authFile := flag("--authfile"); authFile != "" {
+			bs, err := os.ReadFile(authFile)
+			if err != nil {
+				return err
+			}
+			f.authFile = string(bs)
+		}
+		_, _ = io.WriteString(cmd.stderr, "Copying blob 1234 done\n")
+		f.images[args[len(args)-1]] = true
+	case "create":
+		bs, err := os.ReadFile(flag("--env-file"))
+		if err != nil {
+			return err
+		}
+		f.envFile = string(bs)
+		labels := map[string]string{}
+		for i, arg := range args {
+			if arg == "--label" {
+				kv := strings.SplitN(args[i+1], "=", 2)
+				labels[kv[0]] = kv[1]
+			}
+		}
+		f.mu.Lock()
+		id := fmt.Sprintf("podman-%d", len(f.containers))
+		f.containers[id] = &fakeContainer{labels: labels, exit: make(chan int, 1)}
+		f.mu.Unlock()
+		_, _ = io.WriteString(cmd.stdout, id+"\n")
+	case "cp":
+		c, err := f.container(strings.Split(args[len(args)-1], ":")[0])
+		if err != nil {
+			return err
+		}
+		c.files, err = io.ReadAll(cmd.stdin)
+		return err
+	case "start":
+		c, err := f.container(args[1])
+		if err != nil {
+			return err
+		}
+		c.running = true
+	case "kill":
+		c, err := f.container(args[len(args)-1])
+		if err != nil {
+			return err
+		}
+		c.running = false
+		c.exit <- 137
+	case "rm":
+		f.mu.Lock()
+		delete(f.containers, args[len(args)-1])
+		f.mu.Unlock()
+	case "wait":
+		c, err := f.container(args[1])
+		if err != nil {
+			return err
+		}
+		select {
+		case code := <-c.exit:
+			_, _ = fmt.Fprintf(cmd.stdout, "%d\n", code)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	case "inspect":
+		id := args[len(args)-1]
+		c, err := f.container(id)
+		if err != nil {
+			return err
+		}
+		exitCode := 0
+		if !c.running {
+			exitCode = <-c.exit
+		}
+		return json.NewEncoder(cmd.stdout).Encode([]map[string]any{{
+			"Id":     id,
+			"State":  map[string]any{"Running": c.running, "ExitCode": exitCode},
+			"Config": map[string]any{"Labels": c.labels},
+			"NetworkSettings": map[string]any{
+				"Ports": map[string]any{"8080/tcp": []map[string]string{{"HostPort": "40000"}}},
+			},
+		}})
+	case "ps":
+		var entries []psEntry
+		f.mu.Lock()
+		for id, c := range f.containers {
+			if !c.running && !slices.Contains(args, "--all=true") {
+				continue
+			}
+			if label := flag("--filter"); label != "" {
+				kv := strings.SplitN(strings.TrimPrefix(label, "label="), "=", 2)
+				if c.labels[kv[0]] != kv[1] {
+					continue
+				}
+			}
+			entries = append(entries, psEntry{ID: id, Labels: c.labels, State: "running"})
+		}
+		f.mu.Unlock()
+		return json.NewEncoder(cmd.stdout).Encode(entries)
+	default:
+		return fmt.Errorf("unexpected podman command %v", args)
+	}
+	return nil
+}
+
+func (f *fakePodman) called(name string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, call := range f.calls {
+		if call[0] == name {
+			return true
+		}
+	}
+	return false
+}
+
+func TestPullImage(t *testing.T) {
+	ctx := context.Background()
+	p := events.NilPublisher[docker.Event]{}
+	fake := newFakePodman()
+	cl := newClient(fake.run)
+
+	err := cl.PullImage(ctx, docker.PullImage{
+		Name: "registry.example.com/team/image:1",
+		Registry: &registry.AuthConfig{
+			Username:      "user",
+			Password:      "pass",
+			ServerAddress: "https://registry.example.com",
+		},
+	}, p)
+	require.NoError(t, err)
+	require.JSONEq(t,
+		`{"auths": {"registry.example.com": {"auth": "dXNlcjpwYXNz"}}}`, fake.authFile)
+	require.True(t, fake.images["registry.example.com/team/image:1"])
+
+	// The registry auth is only used for images from its registry.
+	fake.authFile = ""
+	err = cl.PullImage(ctx, docker.PullImage{
+		Name:     "python",
+		Registry: &registry.AuthConfig{Auth: "token", ServerAddress: "registry.example.com"},
+	}, p)
+	require.NoError(t, err)
+	require.Empty(t, fake.authFile)
+	require.True(t, fake.images["docker.io/library/python:latest"])
+
+	// Present images are only pulled again if asked to.
+	fake.calls = nil
+	require.NoError(t, cl.PullImage(ctx, docker.PullImage{Name: "python"}, p))
+	require.False(t, fake.called("pull"))
+	require.NoError(t, cl.PullImage(ctx, docker.PullImage{Name: "python", ForcePull: true}, p))
+	require.True(t, fake.called("pull"))
+}
+
+func TestContainerLifecycle(t *testing.T) {
+	ctx := context.Background()
+	p := events.NilPublisher[docker.Event]{}
+	fake := newFakePodman()
+	cl := newClient(fake.run)
+
+	spec := cproto.RunSpec{
+		ContainerConfig: dcontainer.Config{
+			Image:  "python:3.8",
+			Cmd:    []string{"echo", "hello"},
+			Env:    []string{"DET_SESSION_TOKEN=secret", "MULTILINE=a\nb"},
+			Labels: map[string]string{docker.ContainerIDLabel: "container-1"},
+		},
+		HostConfig: dcontainer.HostConfig{AutoRemove: true},
+		Archives: []cproto.RunArchive{{
+			Path:    "/run/determined",
+			Archive: archive.Archive{archive.RootItem("file", []byte("contents"), 0o600, 0)},
+		}},
+	}
+	id, err := cl.CreateContainer(ctx, cproto.ID("container-1"), spec, p)
+	require.NoError(t, err)
+	require.Equal(t, "DET_SESSION_TOKEN=secret", fake.envFile)
+	c, err := fake.container(id)
+	require.NoError(t, err)
+	require.NotEmpty(t, c.files)
+	require.Equal(t, "true", c.labels[autoRemoveLabel])
+
+	dc, err := cl.RunContainer(ctx, ctx, id, p)
+	require.NoError(t, err)
+	require.Equal(t, id, dc.ContainerInfo.ID)
+	require.True(t, dc.ContainerInfo.State.Running)
+	require.Contains(t, dc.ContainerInfo.Config.ExposedPorts, nat.Port("8080/tcp"))
+	require.Len(t, dc.ContainerInfo.NetworkSettings.Networks, 1)
+
+	running, err := cl.ListRunningContainers(ctx, docker.LabelFilter(docker.ContainerIDLabel,
+		"container-1"))
+	require.NoError(t, err)
+	require.Contains(t, running, cproto.ID("container-1"))
+
+	require.NoError(t, cl.SignalContainer(ctx, id, syscall.SIGKILL))
+	select {
+	case exit := <-dc.ContainerWaiter.Waiter:
+		require.Equal(t, int64(137), exit.StatusCode)
+	case err := <-dc.ContainerWaiter.Errs:
+		t.Fatalf("waiting for container: %s", err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("container did not exit")
+	}
+	_, err = fake.container(id)
+	require.Error(t, err, "container should be removed after it exits")
+}
+
+func TestReattachContainer(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakePodman()
+	cl := newClient(fake.run)
+
+	dc, exitCode, err := cl.ReattachContainer(ctx, cproto.ID("missing"))
+	require.NoError(t, err)
+	require.Nil(t, dc)
+	require.Nil(t, exitCode)
+
+	fake.containers["running"] = &fakeContainer{
+		labels:  map[string]string{docker.ContainerIDLabel: "running"},
+		running: true,
+		exit:    make(chan int, 1),
+	}
+	dc, exitCode, err = cl.ReattachContainer(ctx, cproto.ID("running"))
+	require.NoError(t, err)
+	require.Nil(t, exitCode)
+	require.Equal(t, "running", dc.ContainerInfo.ID)
+
+	exited := &fakeContainer{
+		labels: map[string]string{docker.ContainerIDLabel: "exited", autoRemoveLabel: "true"},
+		exit:   make(chan int, 1),
+	}
+	exited.exit <- 3
+	fake.containers["exited"] = exited
+	dc, exitCode, err = cl.ReattachContainer(ctx, cproto.ID("exited"))
+	require.NoError(t, err)
+	require.Nil(t, dc)
+	require.EqualValues(t, 3, *exitCode)
+	_, err = fake.container("exited")
+	require.Error(t, err, "exited container should be removed")
+}
+
+func TestCreateArgs(t *testing.T) {
+	args, env := createArgs(cproto.RunSpec{
+		ContainerConfig: dcontainer.Config{
+			Image:        "image",
+			Cmd:          []string{"cmd"},
+			User:         "1000:1000",
+			ExposedPorts: nat.PortSet{"1734/tcp": {}},
+		},
+		HostConfig: dcontainer.HostConfig{
+			NetworkMode:     "bridge",
+			PublishAllPorts: true,
+			ShmSize:         4096,
+			Mounts: []mount.Mount{{
+				Type: mount.TypeBind, Source: "/data", Target: "/data", ReadOnly: true,
+			}},
+			Resources: dcontainer.Resources{
+				Devices: []dcontainer.DeviceMapping{{PathOnHost: "/dev/fuse"}},
+				DeviceRequests: []dcontainer.DeviceRequest{
+					{Driver: "nvidia", DeviceIDs: []string{"GPU-1"}},
+				},
+			},
+		},
+	}, "/tmp/env")
+	require.Empty(t, env)
+	require.Equal(t, []string{
+		"create", "--pull", "never", "--env-file", "/tmp/env",
+		"--user", "1000:1000",
+		"--expose", "1734/tcp",
+		"--publish-all",
+		"--mount", "type=bind,destination=/data,source=/data,readonly=true",
+		"--shm-size", "4096",
+		"--device", "/dev/fuse",
+		"--device", "nvidia.com/gpu=GPU-1",
+		"image", "cmd",
+	}, args)
+
+	args, _ = createArgs(cproto.RunSpec{
+		HostConfig: dcontainer.HostConfig{NetworkMode: "host"},
+	}, "/tmp/env")
+	require.Contains(t, strings.Join(args, " "), "--network host")
+}
+
+func TestExecRunnerError(t *testing.T) {
+	run := execRunner("false")
+	err := run(context.Background(), command{args: []string{"ps"}})
+	require.Error(t, err)
+	require.True(t, strings.HasPrefix(err.Error(), "podman ps"))
+}
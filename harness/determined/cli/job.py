@@ -1,5 +1,6 @@
 import argparse
 import datetime
+import json
 from typing import Any, List, Union
 
 from determined import cli
@@ -85,6 +86,26 @@ def ls(args: argparse.Namespace) -> None:
     render.tabulate_or_csv(headers, values, as_csv=args.csv)
 
 
+def watch(args: argparse.Namespace) -> None:
+    sess = cli.setup_session(args)
+    for resp in bindings.get_StreamJobQueue(sess, resourcePool=args.resource_pool):
+        for event in resp.events:
+            j = None
+            if event.job is not None:
+                j = event.job.full if event.job.full is not None else event.job.limited
+            if args.json:
+                print(json.dumps(event.to_json()), flush=True)
+                continue
+
+            line = f"{event.type.name:<16} {event.jobId}"
+            if j is not None and j.summary is not None:
+                ahead = j.summary.jobsAhead if j.summary.jobsAhead > -1 else "N/A"
+                line += f" state={j.summary.state.value} ahead={ahead}"
+            if j is not None:
+                line += f" slots={j.allocatedSlots}/{j.requestedSlots}"
+            print(line, flush=True)
+
+
 def update(args: argparse.Namespace) -> None:
     sess = cli.setup_session(args)
     update = bindings.v1QueueControl(
@@ -187,6 +208,24 @@ args_description = [
                 ],
                 is_default=True,
             ),
+            cli.Cmd(
+                "w|atch",
+                watch,
+                "follow insertions, position changes, state changes and removals in a job queue",
+                [
+                    cli.Arg(
+                        "-p",
+                        "--resource-pool",
+                        type=str,
+                        help="The target resource pool, if any.",
+                    ),
+                    cli.Arg(
+                        "--json",
+                        action="store_true",
+                        help="Print each event as a line of JSON.",
+                    ),
+                ],
+            ),
             cli.Cmd(
                 "u|pdate",
                 update,
@@ -6,4 +6,7 @@ from determined.common.streams._client import (
     Stream,
     Sync,
     ProjectSpec,
+    ExperimentSpec,
+    TrialSpec,
+    TrialMetricSummarySpec,
 )
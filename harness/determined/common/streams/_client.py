@@ -156,6 +156,66 @@ class ModelVersionSpec:
         ).to_json()
 
 
+class ExperimentSpec:
+    def __init__(
+        self,
+        workspace_id: Optional[Union[int, Sequence[int]]] = None,
+        project_id: Optional[Union[int, Sequence[int]]] = None,
+        experiment_id: Optional[Union[int, Sequence[int]]] = None,
+    ) -> None:
+        self.workspace_id = workspace_id
+        self.project_id = project_id
+        self.experiment_id = experiment_id
+
+    def _copy(self) -> "ExperimentSpec":
+        return ExperimentSpec(self.workspace_id, self.project_id, self.experiment_id)
+
+    def _to_wire(self) -> Dict[str, Any]:
+        return wire.ExperimentSubscriptionSpec(
+            workspace_ids=int_or_list(self.workspace_id),
+            project_ids=int_or_list(self.project_id),
+            experiment_ids=int_or_list(self.experiment_id),
+        ).to_json()
+
+
+class TrialSpec:
+    def __init__(
+        self,
+        trial_id: Optional[Union[int, Sequence[int]]] = None,
+        experiment_id: Optional[Union[int, Sequence[int]]] = None,
+    ) -> None:
+        self.trial_id = trial_id
+        self.experiment_id = experiment_id
+
+    def _copy(self) -> "TrialSpec":
+        return TrialSpec(self.trial_id, self.experiment_id)
+
+    def _to_wire(self) -> Dict[str, Any]:
+        return wire.TrialSubscriptionSpec(
+            trial_ids=int_or_list(self.trial_id),
+            experiment_ids=int_or_list(self.experiment_id),
+        ).to_json()
+
+
+class TrialMetricSummarySpec:
+    def __init__(
+        self,
+        trial_id: Optional[Union[int, Sequence[int]]] = None,
+        experiment_id: Optional[Union[int, Sequence[int]]] = None,
+    ) -> None:
+        self.trial_id = trial_id
+        self.experiment_id = experiment_id
+
+    def _copy(self) -> "TrialMetricSummarySpec":
+        return TrialMetricSummarySpec(self.trial_id, self.experiment_id)
+
+    def _to_wire(self) -> Dict[str, Any]:
+        return wire.TrialMetricSummarySubscriptionSpec(
+            trial_ids=int_or_list(self.trial_id),
+            experiment_ids=int_or_list(self.experiment_id),
+        ).to_json()
+
+
 class Sync:
     def __init__(self, sync_id: Any, complete: bool) -> None:
         self.sync_id = sync_id
@@ -274,6 +334,9 @@ class Stream:
         self._projects = KeyCache()
         self._models = KeyCache()
         self._model_versions = KeyCache()
+        self._experiments = KeyCache()
+        self._trials = KeyCache()
+        self._trial_metric_summaries = KeyCache()
         # The websocket events.  We'll connect (and reconnect) lazily.
         self._ws_iter: Optional[Iterable] = None
         self._closed = False
@@ -313,6 +376,18 @@ class Stream:
             "modelversions_deleted": self._make_deletion_handler(
                 wire.ModelVersionMsg, self._model_versions
             ),
+            "experiment": self._make_upsertion_handler(wire.ExperimentMsg, self._experiments),
+            "experiments_deleted": self._make_deletion_handler(
+                wire.ExperimentsDeleted, self._experiments
+            ),
+            "trial": self._make_upsertion_handler(wire.TrialMsg, self._trials),
+            "trials_deleted": self._make_deletion_handler(wire.TrialsDeleted, self._trials),
+            "trialmetricsummary": self._make_upsertion_handler(
+                wire.TrialMetricSummaryMsg, self._trial_metric_summaries
+            ),
+            "trialmetricsummaries_deleted": self._make_deletion_handler(
+                wire.TrialMetricSummariesDeleted, self._trial_metric_summaries
+            ),
         }
 
         self._retries = 0
@@ -351,6 +426,9 @@ class Stream:
             "projects": self._projects.maxseq,
             "models": self._models.maxseq,
             "modelversions": self._model_versions.maxseq,
+            "experiments": self._experiments.maxseq,
+            "trials": self._trials.maxseq,
+            "trialmetricsummaries": self._trial_metric_summaries.maxseq,
         }
         subscribe = {k: v._to_wire() for k, v in spec.items()}
         # add since info to our initial subscriptions
@@ -373,7 +451,10 @@ class Stream:
                 for k, v in {
                     "projects": self._projects.known(),
                     "models": self._models.known(),
-                    "modelversions": self._models.known(),
+                    "modelversions": self._model_versions.known(),
+                    "experiments": self._experiments.known(),
+                    "trials": self._trials.known(),
+                    "trialmetricsummaries": self._trial_metric_summaries.known(),
                 }.items()
                 if v
             },
@@ -524,6 +605,9 @@ class Stream:
         projects: Optional[ProjectSpec] = None,
         models: Optional[ModelSpec] = None,
         model_versions: Optional[ModelVersionSpec] = None,
+        experiments: Optional[ExperimentSpec] = None,
+        trials: Optional[TrialSpec] = None,
+        trial_metric_summaries: Optional[TrialMetricSummarySpec] = None,
     ) -> "Stream":
         # Capture what the user asked for immediately, but we won't fill since or known values until
         # we send it.
@@ -534,6 +618,12 @@ class Stream:
             spec["models"] = models._copy()
         if model_versions:
             spec["modelversions"] = model_versions._copy()
+        if experiments:
+            spec["experiments"] = experiments._copy()
+        if trials:
+            spec["trials"] = trials._copy()
+        if trial_metric_summaries:
+            spec["trialmetricsummaries"] = trial_metric_summaries._copy()
         self._specs.append((sync_id, spec))
         # Adding a spec can trigger sending a subscription.
         self._advance_subscription()
@@ -36,6 +36,10 @@ const (
 	requestID      streamType = "model.RequestID"
 	requestIDPtr   streamType = "*model.RequestID"
 	workspaceState streamType = "model.WorkspaceState"
+	state          streamType = "model.State"
+	textPtr        streamType = "*string"
+	intPtr         streamType = "*int"
+	floatPtr       streamType = "*float64"
 )
 
 const (
@@ -266,6 +270,14 @@ func (b *Builder) String() string {
 	return b.builder.String()
 }
 
+// pluralize returns the streamable id of an entity, which is also its subscription key.
+func pluralize(entity string) string {
+	if strings.HasSuffix(entity, "y") {
+		return strings.TrimSuffix(entity, "y") + "ies"
+	}
+	return entity + "s"
+}
+
 func genTypescript(streamables []Streamable) ([]byte, error) {
 	b := Builder{}
 	typeAnno := func(f Field) ([2]string, error) {
@@ -283,6 +295,10 @@ func genTypescript(streamables []Streamable) ([]byte, error) {
 			requestID:      {"number", "0"},
 			requestIDPtr:   {"number | undefined", "undefined"},
 			workspaceState: {"types.WorkspaceState", "types.WorkspaceState.Unspecified"},
+			state:          {"string", ""},
+			textPtr:        {"string | undefined", "undefined"},
+			intPtr:         {"number | undefined", "undefined"},
+			floatPtr:       {"number | undefined", "undefined"},
 		}
 		out, ok := x[f.Type]
 		if !ok {
@@ -317,7 +333,7 @@ func genTypescript(streamables []Streamable) ([]byte, error) {
 				}
 			}
 			b.Writef("export class %vSpec extends StreamSpec {\n", caser.String(entity))
-			b.Writef("  readonly #id: Streamable = '%vs';\n", entity)
+			b.Writef("  readonly #id: Streamable = '%v';\n", pluralize(entity))
 			for _, f := range s.Fields {
 				anno, _ := typeAnno(f)
 				b.Writef("  #%v: %v;\n", f.JSONTag, anno[0])
@@ -385,6 +401,10 @@ func genPython(streamables []Streamable) ([]byte, error) {
 			requestID:      "int",
 			requestIDPtr:   "typing.Optional[int]",
 			workspaceState: "str",
+			state:          "str",
+			textPtr:        "typing.Optional[str]",
+			intPtr:         "typing.Optional[int]",
+			floatPtr:       "typing.Optional[float]",
 		}
 		out, ok := x[f.Type]
 		if !ok {
@@ -2,6 +2,7 @@ package internal
 
 import (
 	"context"
+	"time"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -11,6 +12,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/api"
 	"github.com/determined-ai/determined/master/internal/authz"
 	"github.com/determined-ai/determined/master/internal/cluster"
+	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
 	"github.com/determined-ai/determined/master/internal/rm/rmerrors"
 	"github.com/determined-ai/determined/master/pkg/model"
@@ -137,6 +139,32 @@ func (a *apiServer) GetSlot(
 	return resp, nil
 }
 
+func (a *apiServer) GetAgentHealthEvents(
+	ctx context.Context, req *apiv1.GetAgentHealthEventsRequest,
+) (*apiv1.GetAgentHealthEventsResponse, error) {
+	user, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	permErr, err := cluster.AuthZProvider.Get().CanGetSensitiveAgentInfo(ctx, user)
+	switch {
+	case err != nil:
+		return nil, err
+	case permErr != nil:
+		return nil, status.Error(codes.PermissionDenied, permErr.Error())
+	}
+
+	events, pagination, err := db.AgentHealthEvents(ctx, req.AgentId, int(req.Offset), int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetAgentHealthEventsResponse{Pagination: pagination}
+	for _, e := range events {
+		resp.Events = append(resp.Events, e.ToProto())
+	}
+	return resp, nil
+}
+
 func (a *apiServer) canUpdateAgents(ctx context.Context) error {
 	user, _, err := grpcutil.GetUser(ctx)
 	if err != nil {
@@ -205,3 +233,50 @@ func (a *apiServer) DisableSlot(
 		return resp, nil
 	}
 }
+
+func (a *apiServer) ScheduleAgentMaintenance(
+	ctx context.Context, req *apiv1.ScheduleAgentMaintenanceRequest,
+) (*apiv1.ScheduleAgentMaintenanceResponse, error) {
+	if err := a.canUpdateAgents(ctx); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case req.StartTime == nil || req.EndTime == nil:
+		return nil, status.Error(codes.InvalidArgument, "start_time and end_time are required")
+	case !req.EndTime.AsTime().After(req.StartTime.AsTime()):
+		return nil, status.Error(codes.InvalidArgument, "end_time must be after start_time")
+	case !req.EndTime.AsTime().After(time.Now()):
+		return nil, status.Error(codes.InvalidArgument, "end_time must be in the future")
+	case req.CheckpointLeadSeconds != nil && *req.CheckpointLeadSeconds < 0:
+		return nil, status.Error(codes.InvalidArgument, "checkpoint_lead_seconds must be non-negative")
+	}
+
+	resp, err := a.m.rm.ScheduleAgentMaintenance(req)
+	switch {
+	case errors.Is(err, rmerrors.ErrNotSupported):
+		return resp, status.Error(codes.Unimplemented, err.Error())
+	case err != nil:
+		return nil, err
+	default:
+		return resp, nil
+	}
+}
+
+func (a *apiServer) CancelAgentMaintenance(
+	ctx context.Context, req *apiv1.CancelAgentMaintenanceRequest,
+) (*apiv1.CancelAgentMaintenanceResponse, error) {
+	if err := a.canUpdateAgents(ctx); err != nil {
+		return nil, err
+	}
+
+	resp, err := a.m.rm.CancelAgentMaintenance(req)
+	switch {
+	case errors.Is(err, rmerrors.ErrNotSupported):
+		return resp, status.Error(codes.Unimplemented, err.Error())
+	case err != nil:
+		return nil, err
+	default:
+		return resp, nil
+	}
+}
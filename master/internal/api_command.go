@@ -26,6 +26,8 @@ import (
 	"github.com/determined-ai/determined/master/internal/configpolicy"
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/job"
+	"github.com/determined-ai/determined/master/internal/job/dependency"
 	"github.com/determined-ai/determined/master/internal/rbac/audit"
 	"github.com/determined-ai/determined/master/internal/templates"
 	"github.com/determined-ai/determined/master/internal/user"
@@ -163,6 +165,11 @@ func (a *apiServer) getCommandLaunchParams(ctx context.Context, req *protoComman
 	if err != nil {
 		return nil, nil, status.Errorf(codes.InvalidArgument, "failed constraint check: %v", err)
 	}
+	err = configpolicy.CheckQuotas(ctx, int(cmdSpec.Metadata.WorkspaceID), userModel.ID,
+		model.NTSCType, config.Resources.Slots)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.ResourceExhausted, "failed quota check: %v", err)
+	}
 
 	token, err := getTaskSessionToken(ctx, userModel)
 	if err != nil {
@@ -354,6 +361,11 @@ func (a *apiServer) LaunchCommand(
 		return nil, err
 	}
 
+	launchReq.DependsOn, err = dependency.FromProto(ctx, *user, job.AuthZProvider.Get(), req.DependsOn)
+	if err != nil {
+		return nil, err
+	}
+
 	// Postprocess the launchReq.Spec.
 	if launchReq.Spec.Config.Description == "" {
 		launchReq.Spec.Config.Description = fmt.Sprintf(
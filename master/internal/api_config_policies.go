@@ -255,6 +255,42 @@ func (a *apiServer) GetGlobalConfigPolicies(
 	return &apiv1.GetGlobalConfigPoliciesResponse{ConfigPolicies: resp}, nil
 }
 
+// Get the status of the quotas that apply to the current user's workloads in a workspace.
+func (a *apiServer) GetQuotaStatus(
+	ctx context.Context, req *apiv1.GetQuotaStatusRequest,
+) (*apiv1.GetQuotaStatusResponse, error) {
+	license.RequireLicense("manage config policies")
+
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, err.Error())
+	}
+
+	if _, err := a.GetWorkspaceByID(ctx, req.WorkspaceId, *curUser, false); err != nil {
+		return nil, status.Errorf(codes.NotFound, err.Error())
+	}
+
+	if !configpolicy.ValidWorkloadType(req.WorkloadType) {
+		errMessage := fmt.Sprintf(invalidWorkloadTypeErr+": %s.", req.WorkloadType)
+		if len(req.WorkloadType) == 0 {
+			errMessage = noWorkloadErr
+		}
+		return nil, status.Errorf(codes.InvalidArgument, errMessage)
+	}
+
+	statuses, err := configpolicy.GetQuotaStatus(ctx, int(req.WorkspaceId), curUser.ID,
+		req.WorkloadType)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	resp := &apiv1.GetQuotaStatusResponse{Quotas: []*apiv1.QuotaStatus{}}
+	for _, s := range statuses {
+		resp.Quotas = append(resp.Quotas, s.Proto())
+	}
+	return resp, nil
+}
+
 func (*apiServer) getConfigPolicies(
 	ctx context.Context, workspaceID *int, workloadType string,
 ) (*structpb.Struct, error) {
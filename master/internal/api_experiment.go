@@ -35,6 +35,8 @@ import (
 	"github.com/determined-ai/determined/master/internal/db/bunutils"
 	"github.com/determined-ai/determined/master/internal/experiment"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/job"
+	"github.com/determined-ai/determined/master/internal/job/dependency"
 	"github.com/determined-ai/determined/master/internal/job/jobservice"
 	"github.com/determined-ai/determined/master/internal/prom"
 	"github.com/determined-ai/determined/master/internal/rm"
@@ -72,6 +74,13 @@ var SummaryMetricStatistics = []string{"last", "max", "mean", "min"}
 
 const maxConcurrentDeletes = 10
 
+// The number of times PreviewHPSearch simulates a search against a metric model by default, and
+// the most it will.
+const (
+	defaultNumSearchSimulations = 100
+	maxNumSearchSimulations     = 1000
+)
+
 func (a *apiServer) enrichExperimentState(experiments ...*experimentv1.Experiment) error {
 	return a.enrichExperimentStateTx(context.Background(), db.Bun(), experiments...)
 }
@@ -838,10 +847,32 @@ func (a *apiServer) PreviewHPSearch(
 	if err != nil {
 		return nil, err
 	}
-
-	return &apiv1.PreviewHPSearchResponse{
+	resp := &apiv1.PreviewHPSearchResponse{
 		Summary: sim.Proto(),
-	}, nil
+	}
+
+	if req.MetricModel != nil {
+		numSimulations := int(req.NumSimulations)
+		switch {
+		case numSimulations == 0:
+			numSimulations = defaultNumSearchSimulations
+		case numSimulations < 0 || numSimulations > maxNumSearchSimulations:
+			return nil, status.Errorf(codes.InvalidArgument,
+				"num_simulations must be between 1 and %d", maxNumSearchSimulations)
+		}
+		slotsPerTrial := 1
+		if config.RawResources != nil {
+			slotsPerTrial = schemas.WithDefaults(*config.RawResources).SlotsPerTrial()
+		}
+
+		curves, err := searcher.SimulateLearningCurves(sc, hc,
+			searcher.LearningCurveModelFromProto(req.MetricModel), slotsPerTrial, numSimulations, req.Seed)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "unable to simulate search: %s", err)
+		}
+		resp.Simulation = curves.Proto()
+	}
+	return resp, nil
 }
 
 func (a *apiServer) ActivateExperiment(
@@ -1474,7 +1505,7 @@ func (a *apiServer) ContinueExperiment(
 	dbExp.ID = int(req.Id)
 	dbExp.JobID = origExperiment.JobID // Revive job.
 
-	e, launchWarnings, err := newExperiment(a.m, dbExp, modelDef, activeConfig, taskSpec)
+	e, launchWarnings, err := newExperiment(a.m, dbExp, modelDef, activeConfig, taskSpec, nil)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to create experiment: %s", err)
 	}
@@ -1651,6 +1682,11 @@ func (a *apiServer) CreateExperiment(
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, err.Error())
 	}
+	err = configpolicy.CheckQuotas(ctx, int(wkspIDs[0]), user.ID, model.ExperimentType,
+		activeConfig.Resources().SlotsPerTrial())
+	if err != nil {
+		return nil, status.Errorf(codes.ResourceExhausted, err.Error())
+	}
 
 	if req.ValidateOnly {
 		return &apiv1.CreateExperimentResponse{
@@ -1659,8 +1695,15 @@ func (a *apiServer) CreateExperiment(
 	}
 
 	if req.Unmanaged != nil && *req.Unmanaged {
+		if len(req.DependsOn) > 0 {
+			return nil, status.Error(codes.InvalidArgument, "unmanaged experiments cannot depend on other jobs")
+		}
 		return a.createUnmanagedExperimentTx(ctx, db.Bun(), dbExp, modelDef, activeConfig, user)
 	}
+	deps, err := dependency.FromProto(ctx, *user, job.AuthZProvider.Get(), req.DependsOn)
+	if err != nil {
+		return nil, err
+	}
 	// Check user has permission for what they are trying to do
 	// before actually saving the experiment.
 	if req.Activate {
@@ -1669,7 +1712,7 @@ func (a *apiServer) CreateExperiment(
 		}
 	}
 
-	e, launchWarnings, err := newExperiment(a.m, dbExp, modelDef, activeConfig, taskSpec)
+	e, launchWarnings, err := newExperiment(a.m, dbExp, modelDef, activeConfig, taskSpec, deps)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to create experiment: %s", err)
 	}
@@ -2297,6 +2340,49 @@ func (a *apiServer) GetBestSearcherValidationMetric(
 	}, nil
 }
 
+func (a *apiServer) GetSearcherParetoFront(
+	ctx context.Context, req *apiv1.GetSearcherParetoFrontRequest,
+) (*apiv1.GetSearcherParetoFrontResponse, error) {
+	if _, _, err := a.getExperimentAndCheckCanDoActions(ctx, int(req.ExperimentId),
+		experiment.AuthZProvider.Get().CanGetExperimentArtifacts); err != nil {
+		return nil, err
+	}
+
+	objectives, validations, err := db.ExperimentSearcherObjectiveValidations(ctx, int(req.ExperimentId))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &apiv1.GetSearcherParetoFrontResponse{
+		Validations: []*apiv1.SearcherParetoFrontValidation{},
+	}
+	for _, objective := range objectives {
+		resp.Metrics = append(resp.Metrics, objective.Name())
+		resp.SmallerIsBetter = append(resp.SmallerIsBetter, objective.SmallerIsBetter())
+	}
+
+	// Rank the validations with every objective oriented so that smaller is better.
+	points := make([][]float64, 0, len(validations))
+	for _, v := range validations {
+		point := make([]float64, 0, len(v.Values))
+		for i, value := range v.Values {
+			if !objectives[i].SmallerIsBetter() {
+				value *= -1
+			}
+			point = append(point, value)
+		}
+		points = append(points, point)
+	}
+	for _, i := range searcher.ParetoFront(points) {
+		resp.Validations = append(resp.Validations, &apiv1.SearcherParetoFrontValidation{
+			TrialId:      int32(validations[i].TrialID),
+			TotalBatches: int32(validations[i].TotalBatches),
+			Values:       validations[i].Values,
+		})
+	}
+	return resp, nil
+}
+
 func (a *apiServer) GetModelDef(
 	ctx context.Context, req *apiv1.GetModelDefRequest,
 ) (*apiv1.GetModelDefResponse, error) {
@@ -2653,6 +2739,7 @@ func (a *apiServer) SearchExperiments(
 		ColumnExpr("proto_time(trials.end_time) AS end_time").
 		Column("trials.restarts").
 		ColumnExpr("new_ckpt.uuid AS warm_start_checkpoint_uuid").
+		Column("trials.parent_trial_id").
 		ColumnExpr("trials.checkpoint_size AS total_checkpoint_size").
 		ColumnExpr(bunutils.ProtoStateDBCaseString(trialv1.State_value, "trials.state", "state",
 			"STATE_")).
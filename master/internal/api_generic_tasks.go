@@ -19,8 +19,11 @@ import (
 	"github.com/determined-ai/determined/master/internal/authz"
 	"github.com/determined-ai/determined/master/internal/command"
 	masterConfig "github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/configpolicy"
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/job"
+	"github.com/determined-ai/determined/master/internal/job/dependency"
 	"github.com/determined-ai/determined/master/internal/job/jobservice"
 	"github.com/determined-ai/determined/master/internal/project"
 	"github.com/determined-ai/determined/master/internal/rm/tasklist"
@@ -284,6 +287,17 @@ func (a *apiServer) CreateGenericTask(
 			err.Error(),
 		)
 	}
+	err = configpolicy.CheckQuotas(ctx, genericTaskSpec.WorkspaceID, genericTaskSpec.Base.Owner.ID,
+		model.NTSCType, *genericTaskSpec.GenericTaskConfig.Resources.Slots())
+	if err != nil {
+		return nil, status.Errorf(codes.ResourceExhausted, "failed quota check: %v", err)
+	}
+
+	deps, err := dependency.FromProto(ctx, *genericTaskSpec.Base.Owner, job.AuthZProvider.Get(),
+		req.DependsOn)
+	if err != nil {
+		return nil, err
+	}
 
 	// Persist the task.
 	taskID := model.NewTaskID()
@@ -297,6 +311,9 @@ func (a *apiServer) CreateGenericTask(
 		}); err != nil {
 			return fmt.Errorf("persisting job %v: %w", taskID, err)
 		}
+		if err := dependency.AddDependenciesTx(ctx, tx, jobID, deps); err != nil {
+			return err
+		}
 
 		genericTaskSpec.RegisteredTime = startTime
 		genericTaskSpec.JobID = jobID
@@ -350,34 +367,28 @@ func (a *apiServer) CreateGenericTask(
 	}
 
 	onAllocationExit := getGenericTaskOnAllocationExit(ctx, taskID, jobID, logCtx)
+	startAllocation := getGenericTaskStartAllocation(
+		a.m.db, a.m.rm, taskID, jobID, genericTaskSpec, logCtx, onAllocationExit,
+	)
 
-	allocationID := model.AllocationID(fmt.Sprintf("%s.%d", taskID, 1))
-	isSingleNode := genericTaskSpec.GenericTaskConfig.Resources.IsSingleNode() != nil &&
-		*genericTaskSpec.GenericTaskConfig.Resources.IsSingleNode()
-	err = task.DefaultService.StartAllocation(logCtx, sproto.AllocateRequest{
-		AllocationID:      allocationID,
-		TaskID:            taskID,
-		JobID:             jobID,
-		JobSubmissionTime: startTime,
-		IsUserVisible:     true,
-		Name:              fmt.Sprintf("Generic Task %s", taskID),
-
-		SlotsNeeded:  *genericTaskSpec.GenericTaskConfig.Resources.Slots(),
-		ResourcePool: genericTaskSpec.GenericTaskConfig.Resources.ResourcePool(),
-		FittingRequirements: sproto.FittingRequirements{
-			SingleAgent: isSingleNode,
-		},
-
-		Restore: false,
-	}, a.m.db, a.m.rm, genericTaskSpec, onAllocationExit)
-	if err != nil {
-		return nil, err
+	if len(deps) > 0 {
+		// Persist the spec without an allocation, so the task waits again if the master restarts.
+		if err := persistGenericTaskSpec(ctx, taskID, *genericTaskSpec, ""); err != nil {
+			return nil, err
+		}
 	}
-
-	err = persistGenericTaskSpec(ctx, taskID, *genericTaskSpec, allocationID)
+	waiting, err := dependency.DefaultService.Wait(
+		ctx, jobID, genericTaskSpec.GenericTaskConfig.Resources.ResourcePool(),
+		getGenericTaskOnDependenciesResolved(taskID, jobID, logCtx, startAllocation),
+	)
 	if err != nil {
 		return nil, err
 	}
+	if !waiting {
+		if err := startAllocation(ctx); err != nil {
+			return nil, err
+		}
+	}
 
 	jobservice.DefaultService.RegisterJob(jobID, genericTaskSpec)
 
@@ -528,6 +539,10 @@ func (a *apiServer) KillGenericTask(
 	}
 	for _, childTask := range tasksToDelete {
 		if childTask.State == nil || *childTask.State != model.TaskStateCanceled {
+			if childTask.JobID != nil &&
+				dependency.DefaultService.Cancel(*childTask.JobID, "user requested task kill") {
+				continue
+			}
 			allocationID, err := getAllocationFromTaskID(ctx, childTask.TaskID)
 			if err != nil {
 				return nil, err
@@ -656,6 +671,11 @@ func (a *apiServer) UnpauseGenericTask(
 		resumingAllocationID := model.AllocationID(fmt.Sprintf("%s.%d", resumingTask.TaskID, allocationSpecifier+1))
 		isSingleNode := genericTaskSpec.GenericTaskConfig.Resources.IsSingleNode() != nil &&
 			*genericTaskSpec.GenericTaskConfig.Resources.IsSingleNode()
+		err = task.InsertNTSCAllocationWorkspaceRecord(
+			ctx, resumingAllocationID, genericTaskSpec.WorkspaceID, genericTaskSpec.Base.Workspace)
+		if err != nil {
+			return nil, err
+		}
 		err = task.DefaultService.StartAllocation(
 			logCtx, sproto.AllocateRequest{
 				AllocationID:      resumingAllocationID,
@@ -665,6 +685,8 @@ func (a *apiServer) UnpauseGenericTask(
 				RequestTime:       time.Now().UTC(),
 				IsUserVisible:     true,
 				Name:              fmt.Sprintf("Generic Task %s", resumingTask.TaskID),
+				Workspace:         genericTaskSpec.Base.Workspace,
+				Username:          genericTaskSpec.Base.OwnerUsername(),
 				SlotsNeeded:       *genericTaskSpec.GenericTaskConfig.Resources.Slots(),
 				ResourcePool:      genericTaskSpec.GenericTaskConfig.Resources.ResourcePool(),
 				FittingRequirements: sproto.FittingRequirements{
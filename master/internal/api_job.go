@@ -3,6 +3,9 @@ package internal
 import (
 	"context"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	"github.com/determined-ai/determined/master/internal/api"
 	"github.com/determined-ai/determined/master/internal/job/jobservice"
 	"github.com/determined-ai/determined/master/internal/rm"
@@ -10,6 +13,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/authz"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
 	"github.com/determined-ai/determined/master/internal/job"
+	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/proto/pkg/apiv1"
 	"github.com/determined-ai/determined/proto/pkg/jobv1"
 )
@@ -61,8 +65,21 @@ func (a *apiServer) GetJobsV2(
 	if err != nil {
 		return nil, err
 	}
-	resp = &apiv1.GetJobsV2Response{Jobs: make([]*jobv1.RBACJob, 0)}
+	rbacJobs, err := toRBACJobs(ctx, curUser, jobs)
+	if err != nil {
+		return nil, err
+	}
+	resp = &apiv1.GetJobsV2Response{Jobs: rbacJobs}
+
+	if req.Limit == 0 {
+		req.Limit = 100
+	}
 
+	return resp, api.Paginate(&resp.Pagination, &resp.Jobs, req.Offset, req.Limit)
+}
+
+// toRBACJobs returns the jobs in full where the user may view them and obfuscated elsewhere.
+func toRBACJobs(ctx context.Context, curUser *model.User, jobs []*jobv1.Job) ([]*jobv1.RBACJob, error) {
 	okJobs, err := job.AuthZProvider.Get().FilterJobs(ctx, *curUser, jobs)
 	if err != nil {
 		return nil, err
@@ -72,6 +89,7 @@ func (a *apiServer) GetJobsV2(
 		okJobsMap[j.JobId] = true
 	}
 
+	rbacJobs := make([]*jobv1.RBACJob, 0, len(jobs))
 	for _, job := range jobs {
 		j := jobv1.RBACJob{}
 		if ok := okJobsMap[job.JobId]; ok {
@@ -84,14 +102,61 @@ func (a *apiServer) GetJobsV2(
 				Limited: &limitedJob,
 			}
 		}
-		resp.Jobs = append(resp.Jobs, &j)
+		rbacJobs = append(rbacJobs, &j)
 	}
+	return rbacJobs, nil
+}
 
-	if req.Limit == 0 {
-		req.Limit = 100
+// StreamJobQueue streams changes to the job queue of a resource pool.
+func (a *apiServer) StreamJobQueue(
+	req *apiv1.StreamJobQueueRequest, resp apiv1.Determined_StreamJobQueueServer,
+) error {
+	ctx := resp.Context()
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return err
 	}
 
-	return resp, api.Paginate(&resp.Pagination, &resp.Jobs, req.Offset, req.Limit)
+	sub, err := jobservice.DefaultService.SubscribeJobQueue(rm.ResourcePoolName(req.ResourcePool))
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case events, ok := <-sub.C:
+			if !ok {
+				return status.Errorf(codes.Unavailable,
+					"job queue stream for resource pool %q was interrupted", req.ResourcePool)
+			}
+
+			var jobs []*jobv1.Job
+			for _, e := range events {
+				if e.Job != nil {
+					jobs = append(jobs, e.Job)
+				}
+			}
+			rbacJobs, err := toRBACJobs(ctx, curUser, jobs)
+			if err != nil {
+				return err
+			}
+
+			batch := &apiv1.StreamJobQueueResponse{Events: make([]*jobv1.JobQueueEvent, 0, len(events))}
+			for _, e := range events {
+				ev := &jobv1.JobQueueEvent{Type: e.Type, JobId: e.JobID}
+				if e.Job != nil {
+					ev.Job, rbacJobs = rbacJobs[0], rbacJobs[1:]
+				}
+				batch.Events = append(batch.Events, ev)
+			}
+			if err := resp.Send(batch); err != nil {
+				return err
+			}
+		}
+	}
 }
 
 // GetJobQueueStats retrieves job queue stats for a set of resource pools.
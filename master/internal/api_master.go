@@ -254,6 +254,21 @@ func (a *apiServer) ResourceAllocationAggregated(
 	return a.m.fetchAggregatedResourceAllocation(req)
 }
 
+func (a *apiServer) ResourceCostAggregated(
+	ctx context.Context,
+	req *apiv1.ResourceCostAggregatedRequest,
+) (*apiv1.ResourceCostAggregatedResponse, error) {
+	u, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.m.canGetUsageDetails(ctx, u); err != nil {
+		return nil, err
+	}
+
+	return a.m.fetchAggregatedResourceCost(ctx, req)
+}
+
 func (a *apiServer) GetClusterMessage(
 	ctx context.Context,
 	req *apiv1.GetClusterMessageRequest,
@@ -0,0 +1,305 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/authz"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/scheduledjob"
+	"github.com/determined-ai/determined/master/internal/user"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/jobv1"
+	"github.com/determined-ai/determined/proto/pkg/scheduledjobv1"
+)
+
+// scheduledJobLauncher launches the jobs of scheduled jobs through the API as their owners, so that
+// they go through the same validation and RBAC checks as jobs that the owners launch themselves.
+type scheduledJobLauncher struct {
+	a *apiServer
+}
+
+func (l *scheduledJobLauncher) ownerContext(
+	ctx context.Context, j *scheduledjob.ScheduledJob,
+) (context.Context, error) {
+	owner, err := user.ByID(ctx, j.OwnerID)
+	if err != nil {
+		return nil, fmt.Errorf("getting owner of scheduled job %d: %w", j.ID, err)
+	}
+	if !owner.Active {
+		return nil, fmt.Errorf("owner %s of scheduled job %d is inactive", owner.Username, j.ID)
+	}
+	return grpcutil.WithUser(ctx, ptrs.Ptr(owner.ToUser())), nil
+}
+
+func (l *scheduledJobLauncher) Launch(
+	ctx context.Context, j *scheduledjob.ScheduledJob, dependsOn []model.JobID,
+) (*scheduledjob.Run, error) {
+	ctx, err := l.ownerContext(ctx, j)
+	if err != nil {
+		return nil, err
+	}
+	var deps []*jobv1.JobDependency
+	for _, jobID := range dependsOn {
+		deps = append(deps, &jobv1.JobDependency{
+			JobId:     string(jobID),
+			Condition: jobv1.DependencyCondition_DEPENDENCY_CONDITION_ANY_EXIT,
+		})
+	}
+
+	switch j.Type {
+	case scheduledjob.TypeExperiment:
+		req, err := j.ExperimentRequest()
+		if err != nil {
+			return nil, err
+		}
+		req.DependsOn = deps
+		resp, err := l.a.CreateExperiment(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		expID := int(resp.Experiment.Id)
+		jobID := model.JobID(resp.Experiment.JobId)
+		return &scheduledjob.Run{JobID: &jobID, ExperimentID: &expID}, nil
+	case scheduledjob.TypeCommand:
+		req, err := j.CommandRequest()
+		if err != nil {
+			return nil, err
+		}
+		req.DependsOn = deps
+		resp, err := l.a.LaunchCommand(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		taskID := model.TaskID(resp.Command.Id)
+		jobID := model.JobID(resp.Command.JobId)
+		return &scheduledjob.Run{JobID: &jobID, TaskID: &taskID}, nil
+	default:
+		return nil, fmt.Errorf("scheduled job %d has unknown type %s", j.ID, j.Type)
+	}
+}
+
+func (l *scheduledJobLauncher) Kill(
+	ctx context.Context, j *scheduledjob.ScheduledJob, r *scheduledjob.Run,
+) error {
+	ctx, err := l.ownerContext(ctx, j)
+	if err != nil {
+		return err
+	}
+	switch {
+	case r.ExperimentID != nil:
+		_, err = l.a.KillExperiment(ctx, &apiv1.KillExperimentRequest{Id: int32(*r.ExperimentID)})
+	case r.TaskID != nil:
+		_, err = l.a.KillCommand(ctx, &apiv1.KillCommandRequest{CommandId: string(*r.TaskID)})
+	}
+	return err
+}
+
+// getScheduledJobForUser returns a scheduled job that the user can view, and checks that the user
+// can edit it if edit is set.
+func getScheduledJobForUser(
+	ctx context.Context, id int32, edit bool,
+) (*scheduledjob.ScheduledJob, error) {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	notFound := api.NotFoundErrs("scheduled job", strconv.Itoa(int(id)), true)
+	j, err := scheduledjob.GetScheduledJob(ctx, int(id))
+	if errors.Is(err, db.ErrNotFound) {
+		return nil, notFound
+	} else if err != nil {
+		return nil, err
+	}
+	if err := scheduledjob.AuthZProvider.Get().CanGetScheduledJob(ctx, *curUser, j); err != nil {
+		return nil, authz.SubIfUnauthorized(err, notFound)
+	}
+	if edit {
+		if err := scheduledjob.AuthZProvider.Get().CanEditScheduledJob(ctx, *curUser, j); err != nil {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+	}
+	return j, nil
+}
+
+func (a *apiServer) PostScheduledJob(
+	ctx context.Context, req *apiv1.PostScheduledJobRequest,
+) (*apiv1.PostScheduledJobResponse, error) {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+	if _, err := scheduledjob.ParseCronSpec(req.CronSpec); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid cron spec %q: %s", req.CronSpec, err)
+	}
+	if (req.Experiment == nil) == (req.Command == nil) {
+		return nil, status.Error(codes.InvalidArgument, "exactly one of experiment and command must be set")
+	}
+
+	j := &scheduledjob.ScheduledJob{
+		Name:          req.Name,
+		CronSpec:      req.CronSpec,
+		OverlapPolicy: scheduledjob.OverlapPolicyFromProto(req.OverlapPolicy),
+		OwnerID:       curUser.ID,
+		Username:      curUser.Username,
+	}
+	var launchReq proto.Message
+	if req.Experiment != nil {
+		expReq := req.Experiment
+		switch {
+		case expReq.Unmanaged != nil && *expReq.Unmanaged:
+			return nil, status.Error(codes.InvalidArgument, "scheduled experiments cannot be unmanaged")
+		case len(expReq.DependsOn) > 0:
+			return nil, status.Error(codes.InvalidArgument,
+				"scheduled experiments cannot depend on other jobs, use an overlap policy instead")
+		}
+		expReq.Activate = true
+		expReq.ValidateOnly = false
+
+		// Check that the owner can create the experiment now, as it is checked again at every run.
+		_, _, _, p, _, err := a.m.parseCreateExperiment(ctx, expReq, curUser)
+		if err != nil {
+			return nil, err
+		}
+		if err = experiment.AuthZProvider.Get().CanCreateExperiment(ctx, *curUser, p); err != nil {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		j.Type = scheduledjob.TypeExperiment
+		j.WorkspaceID = int(p.WorkspaceId)
+		launchReq = expReq
+	} else {
+		cmdReq := req.Command
+		if len(cmdReq.DependsOn) > 0 {
+			return nil, status.Error(codes.InvalidArgument,
+				"scheduled commands cannot depend on other jobs, use an overlap policy instead")
+		}
+		launchParams, _, err := a.getCommandLaunchParams(ctx, &protoCommandParams{
+			TemplateName: cmdReq.TemplateName,
+			WorkspaceID:  cmdReq.WorkspaceId,
+			Config:       cmdReq.Config,
+			Files:        cmdReq.Files,
+		}, curUser)
+		if err != nil {
+			return nil, api.WrapWithFallbackCode(err, codes.InvalidArgument,
+				"failed to prepare launch params")
+		}
+		if err = a.isNTSCPermittedToLaunch(ctx, launchParams.Spec, curUser); err != nil {
+			return nil, err
+		}
+		j.Type = scheduledjob.TypeCommand
+		j.WorkspaceID = int(launchParams.Spec.Metadata.WorkspaceID)
+		launchReq = cmdReq
+	}
+
+	request, err := protojson.Marshal(launchReq)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling the request of the scheduled job: %w", err)
+	}
+	j.Request = string(request)
+	if err := scheduledjob.DefaultService.Add(ctx, j); err != nil {
+		return nil, err
+	}
+	return &apiv1.PostScheduledJobResponse{ScheduledJob: j.Proto()}, nil
+}
+
+func (a *apiServer) GetScheduledJobs(
+	ctx context.Context, req *apiv1.GetScheduledJobsRequest,
+) (*apiv1.GetScheduledJobsResponse, error) {
+	curUser, _, err := grpcutil.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := scheduledjob.GetScheduledJobs(ctx, req.WorkspaceId)
+	if err != nil {
+		return nil, err
+	}
+	if jobs, err = scheduledjob.AuthZProvider.Get().FilterScheduledJobs(ctx, *curUser, jobs); err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetScheduledJobsResponse{ScheduledJobs: []*scheduledjobv1.ScheduledJob{}}
+	for _, j := range jobs {
+		resp.ScheduledJobs = append(resp.ScheduledJobs, j.Proto())
+	}
+	return resp, nil
+}
+
+func (a *apiServer) GetScheduledJob(
+	ctx context.Context, req *apiv1.GetScheduledJobRequest,
+) (*apiv1.GetScheduledJobResponse, error) {
+	j, err := getScheduledJobForUser(ctx, req.Id, false)
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.GetScheduledJobResponse{ScheduledJob: j.Proto()}, nil
+}
+
+func (a *apiServer) DeleteScheduledJob(
+	ctx context.Context, req *apiv1.DeleteScheduledJobRequest,
+) (*apiv1.DeleteScheduledJobResponse, error) {
+	if _, err := getScheduledJobForUser(ctx, req.Id, true); err != nil {
+		return nil, err
+	}
+	if err := scheduledjob.DefaultService.Delete(ctx, int(req.Id)); err != nil {
+		return nil, err
+	}
+	return &apiv1.DeleteScheduledJobResponse{}, nil
+}
+
+func (a *apiServer) PauseScheduledJob(
+	ctx context.Context, req *apiv1.PauseScheduledJobRequest,
+) (*apiv1.PauseScheduledJobResponse, error) {
+	if _, err := getScheduledJobForUser(ctx, req.Id, true); err != nil {
+		return nil, err
+	}
+	if err := scheduledjob.DefaultService.SetPaused(ctx, int(req.Id), true); err != nil {
+		return nil, err
+	}
+	return &apiv1.PauseScheduledJobResponse{}, nil
+}
+
+func (a *apiServer) ResumeScheduledJob(
+	ctx context.Context, req *apiv1.ResumeScheduledJobRequest,
+) (*apiv1.ResumeScheduledJobResponse, error) {
+	if _, err := getScheduledJobForUser(ctx, req.Id, true); err != nil {
+		return nil, err
+	}
+	if err := scheduledjob.DefaultService.SetPaused(ctx, int(req.Id), false); err != nil {
+		return nil, err
+	}
+	return &apiv1.ResumeScheduledJobResponse{}, nil
+}
+
+func (a *apiServer) GetScheduledJobRuns(
+	ctx context.Context, req *apiv1.GetScheduledJobRunsRequest,
+) (*apiv1.GetScheduledJobRunsResponse, error) {
+	if _, err := getScheduledJobForUser(ctx, req.Id, false); err != nil {
+		return nil, err
+	}
+	runs, pagination, err := scheduledjob.GetRuns(ctx, int(req.Id), int(req.Offset), int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+	resp := &apiv1.GetScheduledJobRunsResponse{
+		Runs:       []*scheduledjobv1.ScheduledJobRun{},
+		Pagination: pagination,
+	}
+	for _, r := range runs {
+		resp.Runs = append(resp.Runs, r.Proto())
+	}
+	return resp, nil
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/internal/task"
 	"github.com/determined-ai/determined/master/internal/trials"
+	"github.com/determined-ai/determined/master/internal/webhooks"
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/master/pkg/protoutils"
 	"github.com/determined-ai/determined/master/pkg/protoutils/protoconverter"
@@ -1472,6 +1473,13 @@ func (a *apiServer) ReportTrialMetrics(
 	if err := a.m.db.AddTrialMetrics(ctx, req.Metrics, metricGroup); err != nil {
 		return nil, err
 	}
+
+	switch err := webhooks.ReportTrialMetrics(ctx, req.Metrics, metricGroup); {
+	case err != nil && errors.Is(err, context.Canceled):
+		return nil, err
+	case err != nil:
+		log.Errorf("scanning metrics for webhook triggers: %v", err)
+	}
 	return &apiv1.ReportTrialMetricsResponse{}, nil
 }
 
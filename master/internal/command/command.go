@@ -14,6 +14,7 @@ import (
 
 	"github.com/determined-ai/determined/master/internal/configpolicy"
 	internaldb "github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/job/dependency"
 	"github.com/determined-ai/determined/master/internal/job/jobservice"
 	"github.com/determined-ai/determined/master/internal/rm"
 	"github.com/determined-ai/determined/master/internal/rm/rmerrors"
@@ -64,6 +65,8 @@ type Command struct {
 	exitStatus       *task.AllocationExited
 	restored         bool
 	contextDirectory []byte // Don't rely on this being set outsides of PreStart non restore case.
+	dependsOn        []model.JobDependency
+	waiting          bool
 
 	logCtx logger.Context
 	syslog *logrus.Entry
@@ -73,6 +76,8 @@ type Command struct {
 type CreateGeneric struct {
 	ContextDirectory []byte
 	Spec             *tasks.GenericCommandSpec
+	// DependsOn are the jobs that must finish before the command starts.
+	DependsOn []model.JobDependency
 }
 
 func commandFromSnapshot(
@@ -100,13 +105,15 @@ func commandFromSnapshot(
 		jobType:            snapshot.Task.Job.JobType,
 		jobID:              jobID,
 		restored:           true,
+		waiting:            snapshot.AllocationID == "",
 		logCtx:             logCtx,
 		syslog:             logrus.WithFields(logrus.Fields{"component": "command"}).WithFields(logCtx.Fields()),
 	}
 	return cmd, cmd.Start(context.TODO())
 }
 
-// Start starts the command & its respective allocation. Once started, it persists to the db.
+// Start starts the command & its respective allocation, or holds it back until the jobs it depends
+// on finish. Once started, it persists to the db.
 func (c *Command) Start(ctx context.Context) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -147,6 +154,30 @@ func (c *Command) Start(ctx context.Context) error {
 		}
 	}
 
+	// Commands restored while they waited on their dependencies have no allocation to restore, so
+	// they wait again.
+	restore := c.restored && !c.waiting
+	if !restore {
+		waiting, err := dependency.DefaultService.Wait(
+			ctx, c.jobID, c.Config.Resources.ResourcePool, c.dependenciesResolved,
+		)
+		if err != nil {
+			return err
+		}
+		c.waiting = waiting
+		if waiting {
+			jobservice.DefaultService.RegisterJob(c.jobID, c)
+			if err := c.persist(); err != nil {
+				c.syslog.WithError(err).Warnf("command persist failure")
+			}
+			return nil
+		}
+	}
+	return c.startAllocation(restore)
+}
+
+// startAllocation starts the allocation of the command, or restores it, and persists the command.
+func (c *Command) startAllocation(restore bool) error {
 	var idleWatcherConfig *sproto.IdleTimeoutConfig
 	if c.Config.IdleTimeout != nil && (c.WatchProxyIdleTimeout || c.WatchRunnerIdleTimeout) {
 		idleWatcherConfig = &sproto.IdleTimeoutConfig{
@@ -158,6 +189,11 @@ func (c *Command) Start(ctx context.Context) error {
 		}
 	}
 
+	var timeLimit time.Duration
+	if c.Config.Resources.TimeLimit != nil {
+		timeLimit = time.Duration(*c.Config.Resources.TimeLimit) * time.Second
+	}
+
 	err := task.DefaultService.StartAllocation(c.logCtx,
 		sproto.AllocateRequest{
 			AllocationID:        c.allocationID,
@@ -166,13 +202,16 @@ func (c *Command) Start(ctx context.Context) error {
 			JobSubmissionTime:   c.registeredTime,
 			IsUserVisible:       true,
 			Name:                c.Config.Description,
+			Workspace:           c.Base.Workspace,
+			Username:            c.Base.OwnerUsername(),
 			SlotsNeeded:         c.Config.Resources.Slots,
 			ResourcePool:        c.Config.Resources.ResourcePool,
 			FittingRequirements: sproto.FittingRequirements{SingleAgent: true},
 			ProxyPorts:          sproto.NewProxyPortConfig(c.GenericCommandSpec.ProxyPorts(), c.taskID),
 			IdleTimeout:         idleWatcherConfig,
-			Restore:             c.restored,
+			Restore:             restore,
 			ProxyTLS:            c.TaskType == model.TaskTypeNotebook,
+			TimeLimit:           timeLimit,
 		}, c.db, c.rm, c.GenericCommandSpec, c.OnExit)
 	if err != nil {
 		return err
@@ -197,6 +236,9 @@ func (c *Command) registerJobAndTask(ctx context.Context, tx bun.Tx) error {
 	}); err != nil {
 		return fmt.Errorf("persisting job %v: %w", c.taskID, err)
 	}
+	if err := dependency.AddDependenciesTx(ctx, tx, c.jobID, c.dependsOn); err != nil {
+		return err
+	}
 
 	if err := internaldb.AddTaskTx(ctx, tx, &model.Task{
 		TaskID:     c.taskID,
@@ -228,23 +270,53 @@ func (c *Command) persist() error {
 		AllocationID:       c.allocationID,
 		GenericCommandSpec: c.GenericCommandSpec,
 	}
+	if c.waiting {
+		snapshot.AllocationID = ""
+	}
 	_, err := internaldb.Bun().NewInsert().Model(snapshot).
 		On("CONFLICT (task_id) DO UPDATE").
 		Exec(context.TODO())
 	return err
 }
 
+// dependenciesResolved starts the allocation once the jobs that the command depends on have
+// finished, or exits the command if one of them did not finish as required.
+func (c *Command) dependenciesResolved(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.exitStatus != nil {
+		return
+	}
+	c.waiting = false
+	if err == nil {
+		if err = c.startAllocation(false); err == nil {
+			return
+		}
+	}
+	c.syslog.WithError(err).Info("command exited before its allocation started")
+	c.onExit(&task.AllocationExited{
+		Err:        err,
+		FinalState: task.AllocationState{State: model.AllocationStateTerminated},
+	})
+}
+
 // OnExit runs when an command's allocation exits. It marks the command task as complete, and unregisters where needed.
 // OnExit locks ahead of gc -> unregisterCommand.
 func (c *Command) OnExit(ae *task.AllocationExited) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	c.onExit(ae)
+}
+
+func (c *Command) onExit(ae *task.AllocationExited) {
 	c.exitStatus = ae
 
 	if err := internaldb.CompleteTask(context.TODO(), c.taskID, time.Now().UTC()); err != nil {
 		c.syslog.WithError(err).Error("marking task complete")
 	}
+	dependency.DefaultService.JobFinished(c.jobID)
 	if err := user.DeleteSessionByToken(context.TODO(), c.GenericCommandSpec.Base.UserSessionToken); err != nil {
 		c.syslog.WithError(err).Errorf(
 			"failure to delete user session for task: %v", c.taskID)
@@ -314,6 +386,9 @@ func (c *Command) DeleteIfInWorkspace(req *apiv1.DeleteWorkspaceRequest) {
 	defer c.mu.Unlock()
 
 	if c.Metadata.WorkspaceID == model.AccessScopeID(req.Id) {
+		if dependency.DefaultService.Cancel(c.jobID, "user requested workspace delete") {
+			return
+		}
 		err := task.DefaultService.Signal(
 			c.allocationID,
 			task.KillAllocation,
@@ -434,6 +509,9 @@ func (c *Command) refreshAllocationState() task.AllocationState {
 	if c.exitStatus != nil {
 		return c.exitStatus.FinalState
 	}
+	if c.waiting {
+		return task.AllocationState{State: model.AllocationStateWaiting}
+	}
 
 	state, err := task.DefaultService.State(c.allocationID)
 	if err != nil {
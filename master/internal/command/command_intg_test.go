@@ -4,8 +4,10 @@
 package command
 
 import (
+	"context"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/determined-ai/determined/master/internal/user"
 
@@ -14,8 +16,10 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/job/dependency"
 	"github.com/determined-ai/determined/master/internal/job/jobservice"
 	"github.com/determined-ai/determined/master/internal/mocks"
+	"github.com/determined-ai/determined/master/internal/rm/tasklist"
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/master/pkg/syncx/queue"
@@ -62,6 +66,49 @@ func TestCommandManagerLifecycle(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestRestoreCommandWaitingOnDependency(t *testing.T) {
+	ctx := context.Background()
+	pgDB := setupTest(t)
+
+	upstream := launchCommand(t, pgDB)
+	upstreamID := model.TaskID(upstream.Id)
+	upstreamJobID := DefaultCmdService.commands[upstreamID].jobID
+
+	req := CreateMockGenericReq(t, pgDB)
+	req.DependsOn = []model.JobDependency{{
+		DependsOn: upstreamJobID,
+		Condition: model.JobDependencySuccess,
+	}}
+	cmd, err := DefaultCmdService.LaunchGenericCommand(model.TaskTypeCommand, model.JobTypeCommand, req)
+	require.NoError(t, err)
+	require.True(t, dependency.DefaultService.IsWaiting(cmd.jobID))
+
+	// Restart the master, which rebuilds the command service and the waiters from the database.
+	dependency.DefaultService.Forget(cmd.jobID)
+	require.NoError(t, tasklist.GroupPriorityChangeRegistry.Delete(cmd.jobID))
+	setupTest(t)
+	require.NoError(t, dependency.EndUnrestorableTasks(ctx))
+	require.NoError(t, DefaultCmdService.RestoreAllCommands(ctx))
+
+	restored, ok := DefaultCmdService.commands[cmd.taskID]
+	require.True(t, ok, "the waiting command should be restored")
+	require.True(t, dependency.DefaultService.IsWaiting(cmd.jobID))
+	require.Equal(t, taskv1.State_STATE_WAITING, restored.ToV1Command().State)
+	outcomes, err := dependency.JobOutcomes(ctx, []model.JobID{cmd.jobID})
+	require.NoError(t, err)
+	require.Equal(t, model.JobOutcomeRunning, outcomes[cmd.jobID], "the command should not be ended")
+
+	// Once the upstream command succeeds, the restored command starts.
+	require.NoError(t, db.CompleteTask(ctx, upstreamID, time.Now().UTC()))
+	dependency.DefaultService.JobFinished(upstreamJobID)
+	require.Eventually(t, func() bool {
+		restored.mu.Lock()
+		defer restored.mu.Unlock()
+		return !restored.waiting
+	}, 10*time.Second, 10*time.Millisecond)
+	require.Nil(t, restored.exitStatus)
+}
+
 func TestNotebookManagerLifecycle(t *testing.T) {
 	db := setupTest(t)
 	user.InitService(db, &model.ExternalSessions{})
@@ -10,6 +10,7 @@ import (
 
 	"github.com/determined-ai/determined/master/internal/api"
 	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/job/dependency"
 	"github.com/determined-ai/determined/master/internal/rm"
 	"github.com/determined-ai/determined/master/internal/task"
 	"github.com/determined-ai/determined/master/pkg/logger"
@@ -51,7 +52,8 @@ func SetDefaultService(cs *CommandService) {
 	DefaultCmdService = cs
 }
 
-// RestoreAllCommands restores all terminated commands whose end time isn't set.
+// RestoreAllCommands restores all terminated commands whose end time isn't set, and the commands
+// that were waiting on their dependencies.
 func (cs *CommandService) RestoreAllCommands(
 	ctx context.Context,
 ) error {
@@ -63,8 +65,9 @@ func (cs *CommandService) RestoreAllCommands(
 		Relation("Allocation").
 		Relation("Task").
 		Relation("Task.Job").
-		Where("allocation.end_time IS NULL").
-		Where("allocation.state != ?", model.AllocationStateTerminated).
+		// Commands that were waiting on their dependencies have no allocation.
+		Where(`(command_snapshot.allocation_id IS NULL AND task.end_time IS NULL) OR
+			(allocation.end_time IS NULL AND allocation.state != ?)`, model.AllocationStateTerminated).
 		Where("task.task_id = command_snapshot.task_id").
 		Where("command_snapshot.generic_task_spec IS NULL").
 		Scan(ctx)
@@ -116,6 +119,7 @@ func (cs *CommandService) LaunchGenericCommand(
 		jobType:          jobType,
 		jobID:            jobID,
 		contextDirectory: req.ContextDirectory,
+		dependsOn:        req.DependsOn,
 		logCtx:           logCtx,
 		syslog:           logrus.WithFields(logrus.Fields{"component": "command"}).WithFields(logCtx.Fields()),
 	}
@@ -283,7 +287,7 @@ func (cs *CommandService) KillNTSC(id string, taskType model.TaskType) (*Command
 		return nil, err
 	}
 
-	if !completed {
+	if !completed && !dependency.DefaultService.Cancel(c.jobID, "user requested kill") {
 		err = task.DefaultService.Signal(c.allocationID, task.KillAllocation, "user requested kill")
 		if err != nil && !strings.Contains(err.Error(), "not found") {
 			return nil, fmt.Errorf("failed to kill allocation: %w", err)
@@ -18,7 +18,8 @@ type CommandSnapshot struct {
 	// taskType can be obtained from related task.
 	// jobType can be obtained from task -> job_id -> job_type
 	// jobId can be obtained from task -> job_id
-	AllocationID model.AllocationID `bun:"allocation_id"`
+	// AllocationID is empty for commands that wait on their dependencies.
+	AllocationID model.AllocationID `bun:"allocation_id,nullzero"`
 
 	// GenericCommandSpec
 	GenericCommandSpec tasks.GenericCommandSpec `bun:"generic_command_spec"`
@@ -50,6 +50,12 @@ const (
 	// MaxAllowedTokenLifespanDays is the max allowed lifespan for tokens.
 	// This is the maximum number of days a go duration can represent.
 	MaxAllowedTokenLifespanDays = 106751
+	// DefaultWebhookDeliveryRetentionDays is how many days webhook deliveries are kept by default.
+	DefaultWebhookDeliveryRetentionDays = 30
+	// DefaultAgentHealthEventRetentionDays is how many days agent health events are kept by default.
+	DefaultAgentHealthEventRetentionDays = 30
+	// DefaultJobQueueRefreshInterval is how often watched job queues are re-read by default.
+	DefaultJobQueueRefreshInterval = model.Duration(time.Second)
 )
 
 const (
@@ -98,6 +104,48 @@ type DBConfig struct {
 type WebhooksConfig struct {
 	BaseURL    string `json:"base_url"`
 	SigningKey string `json:"signing_key"`
+	// DeliveryRetentionDays is how many days webhook deliveries, including dead-lettered events, are
+	// kept, or -1 to keep them forever.
+	DeliveryRetentionDays int `json:"delivery_retention_days"`
+}
+
+// Validate implements the check.Validatable interface for the WebhooksConfig.
+func (w *WebhooksConfig) Validate() []error {
+	if w.DeliveryRetentionDays < -1 {
+		return []error{errors.New("webhook delivery retention must be at least 0 days, unless" +
+			" set to -1 to keep deliveries forever")}
+	}
+	return nil
+}
+
+// AgentHealthEventsConfig hosts configuration fields for the health events that agents report.
+type AgentHealthEventsConfig struct {
+	// RetentionDays is how many days agent health events are kept, or -1 to keep them forever.
+	RetentionDays int `json:"retention_days"`
+}
+
+// Validate implements the check.Validatable interface for the AgentHealthEventsConfig.
+func (a *AgentHealthEventsConfig) Validate() []error {
+	if a.RetentionDays < -1 {
+		return []error{errors.New("agent health event retention must be at least 0 days, unless" +
+			" set to -1 to keep events forever")}
+	}
+	return nil
+}
+
+// JobQueueConfig hosts configuration fields for streaming changes to job queues.
+type JobQueueConfig struct {
+	// RefreshInterval is how often a watched job queue is re-read from the resource manager, to
+	// pick up changes from scheduling passes, or 0 to only re-read it when jobs change.
+	RefreshInterval model.Duration `json:"refresh_interval"`
+}
+
+// Validate implements the check.Validatable interface for the JobQueueConfig.
+func (j *JobQueueConfig) Validate() []error {
+	if j.RefreshInterval < 0 {
+		return []error{errors.New("job queue refresh interval must not be negative")}
+	}
+	return nil
 }
 
 // IntegrationsConfig stores configs related to integrations like pachyderm.
@@ -158,6 +206,15 @@ func DefaultConfig() *Config {
 		Observability: ObservabilityConfig{
 			EnablePrometheus: true,
 		},
+		Webhooks: WebhooksConfig{
+			DeliveryRetentionDays: DefaultWebhookDeliveryRetentionDays,
+		},
+		AgentHealthEvents: AgentHealthEventsConfig{
+			RetentionDays: DefaultAgentHealthEventRetentionDays,
+		},
+		JobQueue: JobQueueConfig{
+			RefreshInterval: DefaultJobQueueRefreshInterval,
+		},
 		OIDC: OIDCConfig{
 			AuthenticationClaim:         "email",
 			SCIMAuthenticationAttribute: "userName",
@@ -191,6 +248,8 @@ type Config struct {
 	Observability         ObservabilityConfig               `json:"observability"`
 	Cache                 CacheConfig                       `json:"cache"`
 	Webhooks              WebhooksConfig                    `json:"webhooks"`
+	AgentHealthEvents     AgentHealthEventsConfig           `json:"agent_health_events"`
+	JobQueue              JobQueueConfig                    `json:"job_queue"`
 	FeatureSwitches       []string                          `json:"feature_switches"`
 	ReservedPorts         []int                             `json:"reserved_ports"`
 	ResourceConfig
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"encoding/json"
+
+	"github.com/determined-ai/determined/master/pkg/check"
+)
+
+// DefaultPricingCurrency is the default currency of resource pool prices.
+const DefaultPricingCurrency = "USD"
+
+// PricingConfig configures the price of the slots of a resource pool, which is used to attribute
+// costs to allocations.
+type PricingConfig struct {
+	// SlotHourPrice is the price of a slot-hour.
+	SlotHourPrice *float64 `json:"slot_hour_price"`
+	// InstanceHourPrices are the prices of an instance-hour by instance type, for resource pools
+	// with dynamic agents. They take precedence over SlotHourPrice.
+	InstanceHourPrices map[string]float64 `json:"instance_hour_prices"`
+	// SpotPrice records the spot market price of the instances of resource pools with AWS spot
+	// instances, as it changes.
+	SpotPrice bool   `json:"spot_price"`
+	Currency  string `json:"currency"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (p *PricingConfig) UnmarshalJSON(data []byte) error {
+	p.Currency = DefaultPricingCurrency
+	type DefaultParser *PricingConfig
+	return json.Unmarshal(data, DefaultParser(p))
+}
+
+// Validate implements the check.Validatable interface.
+func (p PricingConfig) Validate() []error {
+	errs := []error{
+		check.True(p.SlotHourPrice != nil || len(p.InstanceHourPrices) > 0 || p.SpotPrice,
+			"pricing must set a slot hour price, instance hour prices or spot price"),
+		check.NotEmpty(p.Currency, "pricing currency must be non-empty"),
+	}
+	if p.SlotHourPrice != nil {
+		errs = append(errs, check.GreaterThanOrEqualTo(*p.SlotHourPrice, 0.0,
+			"slot hour price must be >= 0"))
+	}
+	for instanceType, price := range p.InstanceHourPrices {
+		errs = append(errs, check.GreaterThanOrEqualTo(price, 0.0,
+			"instance hour price of %s must be >= 0", instanceType))
+	}
+	return errs
+}
@@ -0,0 +1,91 @@
+package provconfig
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+
+	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// WarmPoolConfig keeps a minimum number of instances around for a period of time that starts on
+// a cron schedule, e.g., during working hours, so that tasks do not wait for instances to boot.
+type WarmPoolConfig struct {
+	// Schedule is a standard cron expression for when the warm pool starts.
+	Schedule string `json:"schedule"`
+	// Duration is how long the warm pool lasts after it starts.
+	Duration     model.Duration `json:"duration"`
+	MinInstances int            `json:"min_instances"`
+}
+
+// Validate implements the check.Validatable interface.
+func (c WarmPoolConfig) Validate() []error {
+	var scheduleErr error
+	if _, err := cron.ParseStandard(c.Schedule); err != nil {
+		scheduleErr = errors.Wrapf(err, "cannot parse warm pool schedule %q", c.Schedule)
+	}
+	return []error{
+		scheduleErr,
+		check.GreaterThan(int64(c.Duration), int64(0), "warm pool duration must be greater than 0"),
+		check.GreaterThanOrEqualTo(c.MinInstances, 0, "warm pool min instances must be >= 0"),
+	}
+}
+
+// Active returns whether the warm pool is active at the given time, i.e., whether the schedule
+// has started the warm pool within the last duration.
+func (c WarmPoolConfig) Active(t time.Time) bool {
+	schedule, err := cron.ParseStandard(c.Schedule)
+	if err != nil {
+		return false
+	}
+	return !schedule.Next(t.Add(-time.Duration(c.Duration))).After(t)
+}
+
+// ForecastConfig configures launching instances ahead of demand that is forecast from the queue
+// history of the resource pool. The forecast demand is the average of the peak number of slots that
+// were demanded at once within the next horizon at the same point of each of the past periods in
+// the lookback window, e.g., at the same time of day over the past week.
+type ForecastConfig struct {
+	Lookback model.Duration `json:"lookback"`
+	Period   model.Duration `json:"period"`
+	Horizon  model.Duration `json:"horizon"`
+}
+
+// DefaultForecastConfig returns the default configuration of the demand forecast.
+func DefaultForecastConfig() *ForecastConfig {
+	return &ForecastConfig{
+		Lookback: model.Duration(7 * 24 * time.Hour),
+		Period:   model.Duration(24 * time.Hour),
+		Horizon:  model.Duration(30 * time.Minute),
+	}
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *ForecastConfig) UnmarshalJSON(data []byte) error {
+	*c = *DefaultForecastConfig()
+	type DefaultParser *ForecastConfig
+	return json.Unmarshal(data, DefaultParser(c))
+}
+
+// Validate implements the check.Validatable interface.
+func (c ForecastConfig) Validate() []error {
+	return []error{
+		check.GreaterThan(int64(c.Period), int64(0), "forecast period must be greater than 0"),
+		check.GreaterThanOrEqualTo(int64(c.Lookback), int64(c.Period),
+			"forecast lookback must be greater than or equal to the forecast period"),
+		check.GreaterThan(int64(c.Horizon), int64(0), "forecast horizon must be greater than 0"),
+		check.LessThanOrEqualTo(int64(c.Horizon), int64(c.Period),
+			"forecast horizon must be less than or equal to the forecast period"),
+	}
+}
+
+// Periods returns the number of past periods that the forecast averages over.
+func (c ForecastConfig) Periods() int {
+	if c.Period <= 0 {
+		return 0
+	}
+	return int(time.Duration(c.Lookback) / time.Duration(c.Period))
+}
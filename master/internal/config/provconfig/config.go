@@ -28,19 +28,22 @@ type Config struct {
 	AgentDockerRuntime     string `json:"agent_docker_runtime"`
 	AgentDockerImage       string `json:"agent_docker_image"`
 	// deprecated, no longer in use.
-	AgentFluentImage        string            `json:"agent_fluent_image"`
-	AgentReconnectAttempts  int               `json:"agent_reconnect_attempts"`
-	AgentReconnectBackoff   int               `json:"agent_reconnect_backoff"`
-	AgentConfigFileContents json.RawMessage   `json:"agent_config_file_contents"`
-	AWS                     *AWSClusterConfig `union:"type,aws" json:"-"`
-	GCP                     *GCPClusterConfig `union:"type,gcp" json:"-"`
-	HPC                     *HpcClusterConfig `union:"type,hpc" json:"-"`
-	MaxIdleAgentPeriod      model.Duration    `json:"max_idle_agent_period"`
-	MaxAgentStartingPeriod  model.Duration    `json:"max_agent_starting_period"`
-	MinInstances            int               `json:"min_instances"`
-	MaxInstances            int               `json:"max_instances"`
-	LaunchErrorTimeout      *model.Duration   `json:"launch_error_timeout"`
-	LaunchErrorRetries      int               `json:"launch_error_retries"`
+	AgentFluentImage        string                `json:"agent_fluent_image"`
+	AgentReconnectAttempts  int                   `json:"agent_reconnect_attempts"`
+	AgentReconnectBackoff   int                   `json:"agent_reconnect_backoff"`
+	AgentConfigFileContents json.RawMessage       `json:"agent_config_file_contents"`
+	AWS                     *AWSClusterConfig     `union:"type,aws" json:"-"`
+	GCP                     *GCPClusterConfig     `union:"type,gcp" json:"-"`
+	HPC                     *HpcClusterConfig     `union:"type,hpc" json:"-"`
+	Webhook                 *WebhookClusterConfig `union:"type,webhook" json:"-"`
+	MaxIdleAgentPeriod      model.Duration        `json:"max_idle_agent_period"`
+	MaxAgentStartingPeriod  model.Duration        `json:"max_agent_starting_period"`
+	MinInstances            int                   `json:"min_instances"`
+	MaxInstances            int                   `json:"max_instances"`
+	LaunchErrorTimeout      *model.Duration       `json:"launch_error_timeout"`
+	LaunchErrorRetries      int                   `json:"launch_error_retries"`
+	WarmPools               []WarmPoolConfig      `json:"warm_pools"`
+	Forecast                *ForecastConfig       `json:"forecast"`
 }
 
 // HpcClusterConfig describes the configuration for a HPC cluster managed by Determined.
@@ -95,9 +98,9 @@ func (c Config) Validate() []error {
 	errs = append(errs, []error{
 		masterURLErr,
 		check.NotEmpty(c.AgentDockerImage, "must configure an agent docker image"),
-		check.False(c.AWS != nil && c.GCP != nil, "must configure only one cluster"),
-		check.False(c.AWS == nil && c.GCP == nil && c.HPC == nil,
-			"must configure aws or gcp or hpc cluster"),
+		check.True(c.numClusters() <= 1, "must configure only one cluster"),
+		check.True(c.numClusters() == 1 || c.HPC != nil,
+			"must configure aws or gcp or webhook or hpc cluster"),
 		check.GreaterThan(
 			int64(c.MaxIdleAgentPeriod), int64(0), "max idle agent period must be greater than 0"),
 		check.GreaterThan(
@@ -108,9 +111,53 @@ func (c Config) Validate() []error {
 		check.GreaterThanOrEqualTo(int64(c.MaxInstances), int64(c.MinInstances),
 			"max instance must be greater than or equal to min instance"),
 	}...)
+	for _, warmPool := range c.WarmPools {
+		errs = append(errs, check.GreaterThanOrEqualTo(int64(c.MaxInstances), int64(warmPool.MinInstances),
+			"max instance must be greater than or equal to warm pool min instances"))
+	}
 	return errs
 }
 
+// InstanceType returns the type of the instances of the configured instance provider, or nil if
+// there is none.
+func (c Config) InstanceType() model.InstanceType {
+	switch {
+	case c.AWS != nil:
+		return c.AWS.InstanceType
+	case c.GCP != nil:
+		return c.GCP.InstanceType
+	case c.Webhook != nil:
+		return c.Webhook.InstanceType
+	default:
+		return nil
+	}
+}
+
+// SlotsPerInstance returns the number of slots per instance of the configured instance provider.
+func (c Config) SlotsPerInstance() int {
+	switch {
+	case c.AWS != nil:
+		return c.AWS.SlotsPerInstance()
+	case c.GCP != nil:
+		return c.GCP.SlotsPerInstance()
+	case c.Webhook != nil:
+		return c.Webhook.SlotsPerInstance()
+	default:
+		return 0
+	}
+}
+
+// numClusters returns the number of instance providers that are configured.
+func (c Config) numClusters() int {
+	n := 0
+	for _, configured := range []bool{c.AWS != nil, c.GCP != nil, c.Webhook != nil} {
+		if configured {
+			n++
+		}
+	}
+	return n
+}
+
 func (c Config) mustParseMasterURL() url.URL {
 	masterURL, err := url.Parse(c.MasterURL)
 	if err != nil {
@@ -163,6 +210,14 @@ func (c Config) Printable() Config {
 	if len(c.ContainerStartupScript) > 0 {
 		c.ContainerStartupScript = hiddenValue
 	}
+	if c.Webhook != nil && len(c.Webhook.Headers) > 0 {
+		webhook := *c.Webhook
+		webhook.Headers = make(map[string]string, len(c.Webhook.Headers))
+		for k := range c.Webhook.Headers {
+			webhook.Headers[k] = hiddenValue
+		}
+		c.Webhook = &webhook
+	}
 
 	return c
 }
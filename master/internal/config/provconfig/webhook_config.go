@@ -0,0 +1,104 @@
+package provconfig
+
+import (
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/pkg/check"
+	"github.com/determined-ai/determined/master/pkg/device"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// WebhookClusterConfig describes the configuration for a cluster whose instances are listed,
+// launched and terminated through a user-provided HTTP API. This allows the provisioner to manage
+// instances on infrastructure that Determined has no built-in support for, such as on-premise or
+// OpenStack fleets.
+type WebhookClusterConfig struct {
+	// URL is the base URL of the API.
+	URL string `json:"url"`
+	// Headers are sent with every request to the API, e.g., to authenticate.
+	Headers map[string]string `json:"headers"`
+	// Timeout is how long a request to the API may take.
+	Timeout model.Duration `json:"timeout"`
+
+	// AgentID is a shell expression that is evaluated on a launched instance to get the ID of its
+	// agent. It must match the agent names that the API lists for the instances.
+	AgentID string `json:"agent_id"`
+	// Metadata is passed along with launch requests, so the API can tell how to launch instances.
+	Metadata map[string]string `json:"metadata"`
+
+	InstanceType    WebhookInstanceType `json:"instance_type"`
+	CPUSlotsAllowed bool                `json:"cpu_slots_allowed"`
+}
+
+// WebhookInstanceType describes the instances that the API launches.
+type WebhookInstanceType struct {
+	MachineType string `json:"machine_type"`
+	SlotNum     int    `json:"slots"`
+}
+
+// Name implements the model.InstanceType interface.
+func (t WebhookInstanceType) Name() string {
+	return t.MachineType
+}
+
+// Slots implements the model.InstanceType interface.
+func (t WebhookInstanceType) Slots() int {
+	return t.SlotNum
+}
+
+// DefaultWebhookClusterConfig returns the default configuration of the webhook cluster.
+func DefaultWebhookClusterConfig() *WebhookClusterConfig {
+	return &WebhookClusterConfig{
+		Timeout: model.Duration(30 * time.Second),
+		AgentID: "$(hostname)",
+	}
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *WebhookClusterConfig) UnmarshalJSON(data []byte) error {
+	*c = *DefaultWebhookClusterConfig()
+	type DefaultParser *WebhookClusterConfig
+	return json.Unmarshal(data, DefaultParser(c))
+}
+
+// Validate implements the check.Validatable interface.
+func (c WebhookClusterConfig) Validate() []error {
+	var urlErr error
+	if u, err := url.Parse(c.URL); err != nil {
+		urlErr = errors.Wrap(err, "cannot parse webhook url")
+	} else {
+		urlErr = check.In(u.Scheme, []string{"http", "https"}, "webhook url scheme must be within [http, https]")
+	}
+	return []error{
+		urlErr,
+		check.GreaterThan(int64(c.Timeout), int64(0), "webhook timeout must be greater than 0"),
+		check.NotEmpty(c.AgentID, "webhook agent id must be non-empty"),
+		check.GreaterThanOrEqualTo(c.InstanceType.SlotNum, 0, "webhook instance slots must be >= 0"),
+	}
+}
+
+// SlotsPerInstance returns the number of slots per instance.
+func (c WebhookClusterConfig) SlotsPerInstance() int {
+	slots := c.InstanceType.Slots()
+	if slots == 0 && c.CPUSlotsAllowed {
+		slots = 1
+	}
+
+	return slots
+}
+
+// SlotType returns the type of the slot.
+func (c WebhookClusterConfig) SlotType() device.Type {
+	slots := c.InstanceType.Slots()
+	if slots > 0 {
+		return device.CUDA
+	}
+	if c.CPUSlotsAllowed {
+		return device.CPU
+	}
+	return device.ZeroSlot
+}
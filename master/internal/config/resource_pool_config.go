@@ -32,6 +32,12 @@ type ResourcePoolConfig struct {
 	// AgentReconnectWait define the time master will wait for agent
 	// before abandoning it.
 	AgentReconnectWait model.Duration `json:"agent_reconnect_wait"`
+	// MaintenanceSchedulingHorizon is how long before a maintenance window of an agent only jobs
+	// whose time limits end before the window are scheduled on it. Unset uses a day.
+	MaintenanceSchedulingHorizon model.Duration `json:"maintenance_scheduling_horizon"`
+
+	// Pricing configures the price of the slots of the resource pool.
+	Pricing *PricingConfig `json:"pricing"`
 
 	// Deprecated: Use MaxAuxContainersPerAgent instead.
 	MaxCPUContainersPerAgent int `json:"max_cpu_containers_per_agent,omitempty"`
@@ -56,11 +62,44 @@ func (r *ResourcePoolConfig) UnmarshalJSON(data []byte) error {
 
 // Validate implements the check.Validatable interface.
 func (r ResourcePoolConfig) Validate() []error {
-	return []error{
+	errs := []error{
 		check.True(len(r.PoolName) != 0, "resource pool name cannot be empty"),
 		check.True(r.MaxAuxContainersPerAgent >= 0,
 			"resource pool max cpu containers per agent should be >= 0"),
+		check.True(r.MaintenanceSchedulingHorizon >= 0,
+			"resource pool maintenance scheduling horizon should be >= 0"),
+	}
+	if r.Pricing != nil {
+		errs = append(errs,
+			check.True(len(r.Pricing.InstanceHourPrices) == 0 || r.Provider != nil,
+				"instance hour prices require dynamic agents"),
+			check.True(!r.Pricing.SpotPrice || (r.Provider != nil && r.Provider.AWS != nil &&
+				r.Provider.AWS.SpotEnabled),
+				"spot price requires AWS spot instances"),
+		)
+	}
+	return errs
+}
+
+// SlotHourPrice returns the configured price of a slot-hour of the resource pool. For resource
+// pools with dynamic agents, the price of an instance-hour of their instance type is split across
+// the slots of the instance. It returns false if the resource pool has no configured price.
+func (r ResourcePoolConfig) SlotHourPrice() (float64, bool) {
+	if r.Pricing == nil {
+		return 0, false
+	}
+	if r.Provider != nil {
+		if instanceType := r.Provider.InstanceType(); instanceType != nil {
+			price, ok := r.Pricing.InstanceHourPrices[instanceType.Name()]
+			if slots := r.Provider.SlotsPerInstance(); ok && slots > 0 {
+				return price / float64(slots), true
+			}
+		}
+	}
+	if r.Pricing.SlotHourPrice != nil {
+		return *r.Pricing.SlotHourPrice, true
 	}
+	return 0, false
 }
 
 // Printable returns a printable object.
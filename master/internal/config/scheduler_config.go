@@ -2,6 +2,8 @@ package config
 
 import (
 	"encoding/json"
+	"sort"
+	"time"
 
 	"github.com/determined-ai/determined/master/pkg/check"
 	"github.com/determined-ai/determined/master/pkg/model"
@@ -18,6 +20,11 @@ const (
 	PriorityScheduling = "priority"
 	// RoundRobinScheduling schedules tasks based on the order in which they arrive.
 	RoundRobinScheduling = "round_robin"
+	// ExternalScheduling delegates scheduling decisions to an external service.
+	ExternalScheduling = "external"
+
+	// DefaultExternalSchedulerTimeout is how long the external scheduler is waited for by default.
+	DefaultExternalSchedulerTimeout = model.Duration(2 * time.Second)
 
 	best             = "best"
 	worst            = "worst"
@@ -41,8 +48,10 @@ type SchedulerConfig struct {
 	FairShare              *FairShareSchedulerConfig  `union:"type,fair_share" json:"-"`
 	Priority               *PrioritySchedulerConfig   `union:"type,priority" json:"-"`
 	RoundRobin             *RoundRobinSchedulerConfig `union:"type,round_robin" json:"-"`
+	External               *ExternalSchedulerConfig   `union:"type,external" json:"-"`
 	FittingPolicy          string                     `json:"fitting_policy"`
 	AllowHeterogeneousFits bool                       `json:"allow_heterogeneous_fits"`
+	Reservation            *ReservationConfig         `json:"reservation,omitempty"`
 }
 
 // MarshalJSON implements the json.Marshaler interface.
@@ -62,12 +71,20 @@ func (s *SchedulerConfig) UnmarshalJSON(data []byte) error {
 	}
 
 	// Fill in the default
-	if s.FairShare == nil && s.Priority == nil && s.RoundRobin == nil {
+	if s.FairShare == nil && s.Priority == nil && s.RoundRobin == nil && s.External == nil {
 		s.Priority = &PrioritySchedulerConfig{}
 	}
-	if s.Priority != nil && s.Priority.DefaultPriority == nil {
+	if s.External != nil {
+		if s.External.Fallback == nil {
+			s.External.Fallback = &PrioritySchedulerConfig{}
+		}
+		if s.External.Timeout == 0 {
+			s.External.Timeout = DefaultExternalSchedulerTimeout
+		}
+	}
+	if p := s.PriorityConfig(); p != nil && p.DefaultPriority == nil {
 		defaultPriority := DefaultSchedulingPriority
-		s.Priority.DefaultPriority = &defaultPriority
+		p.DefaultPriority = &defaultPriority
 	}
 	if s.FittingPolicy == "" {
 		s.FittingPolicy = best
@@ -94,6 +111,8 @@ func (s *SchedulerConfig) GetType() string {
 		return PriorityScheduling
 	case s.RoundRobin != nil:
 		return RoundRobinScheduling
+	case s.External != nil:
+		return ExternalScheduling
 	default:
 		panic("neither scheduler type configured")
 	}
@@ -109,23 +128,141 @@ func (s *SchedulerConfig) GetPreemption() bool {
 		preemptionEnabled = s.Priority.Preemption
 	case s.RoundRobin != nil:
 		preemptionEnabled = false
+	case s.External != nil:
+		preemptionEnabled = s.External.Fallback != nil && s.External.Fallback.Preemption
 	}
 	return preemptionEnabled
 }
 
+// PriorityConfig returns the configuration of the priority scheduling the pool uses, either as
+// its scheduler or as the fallback of an external scheduler, or nil if it does not use
+// priorities.
+func (s *SchedulerConfig) PriorityConfig() *PrioritySchedulerConfig {
+	switch {
+	case s.Priority != nil:
+		return s.Priority
+	case s.External != nil:
+		return s.External.Fallback
+	default:
+		return nil
+	}
+}
+
+// ReservationConfig configures agent reservations for tasks that wait too long to be scheduled.
+// Once the task at the head of the queue has waited longer than WaitThreshold, the resource pool
+// holds enough agents for it as they drain, instead of giving their slots to other tasks.
+type ReservationConfig struct {
+	WaitThreshold model.Duration `json:"wait_threshold"`
+}
+
+// Validate implements the check.Validatable interface.
+func (r ReservationConfig) Validate() []error {
+	return []error{
+		check.GreaterThan(int64(r.WaitThreshold), int64(0), "wait_threshold must be positive"),
+	}
+}
+
 // FairShareSchedulerConfig holds configurations for the fair share scheduler.
-type FairShareSchedulerConfig struct{}
+type FairShareSchedulerConfig struct {
+	Hierarchical *HierarchicalFairShareConfig `json:"hierarchical,omitempty"`
+}
+
+// HierarchicalFairShareConfig configures hierarchical fair share. When it is set, the fair share
+// scheduler divides the slots of a resource pool across workspaces first, then across the users
+// of each workspace, and finally across the jobs of each user. Workspaces and users that are not
+// listed have a weight of 1; jobs keep their own weights.
+type HierarchicalFairShareConfig struct {
+	WorkspaceWeights map[string]float64 `json:"workspace_weights,omitempty"`
+	UserWeights      map[string]float64 `json:"user_weights,omitempty"`
+}
+
+// WorkspaceWeight returns the weight of the workspace.
+func (h HierarchicalFairShareConfig) WorkspaceWeight(workspace string) float64 {
+	if w, ok := h.WorkspaceWeights[workspace]; ok {
+		return w
+	}
+	return 1
+}
+
+// UserWeight returns the weight of the user.
+func (h HierarchicalFairShareConfig) UserWeight(username string) float64 {
+	if w, ok := h.UserWeights[username]; ok {
+		return w
+	}
+	return 1
+}
+
+// Validate implements the check.Validatable interface.
+func (h HierarchicalFairShareConfig) Validate() []error {
+	return append(
+		validateFairShareWeights("workspace_weights", h.WorkspaceWeights),
+		validateFairShareWeights("user_weights", h.UserWeights)...,
+	)
+}
+
+func validateFairShareWeights(field string, weights map[string]float64) []error {
+	names := make([]string, 0, len(weights))
+	for name := range weights {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		errs = append(errs, check.GreaterThan(weights[name], 0.0, "%s.%s must be positive", field, name))
+	}
+	return errs
+}
 
 // PrioritySchedulerConfig holds the configurations for the priority scheduler.
 type PrioritySchedulerConfig struct {
-	Preemption      bool `json:"preemption"`
-	DefaultPriority *int `json:"default_priority"`
+	Preemption      bool            `json:"preemption"`
+	DefaultPriority *int            `json:"default_priority"`
+	Backfill        *BackfillConfig `json:"backfill,omitempty"`
+}
+
+// BackfillConfig configures backfill scheduling for the priority scheduler. When it is set, the
+// highest-priority task that cannot be scheduled holds a reservation for the earliest time it is
+// estimated to fit, and lower-priority tasks are only started if they do not delay it.
+type BackfillConfig struct {
+	// DefaultTimeLimit is the run time assumed for allocations of tasks that do not set a time
+	// limit. If it is unset, such allocations are assumed to run forever.
+	DefaultTimeLimit *model.Duration `json:"default_time_limit,omitempty"`
 }
 
 // RoundRobinSchedulerConfig holds the configurations for the round robing scheduler.
 type RoundRobinSchedulerConfig struct{}
 
+// ExternalSchedulerConfig holds the configurations for the external scheduler, which delegates
+// scheduling decisions to a gRPC service implementing determined.scheduler.v1.ExternalScheduler.
+// If the service does not answer within Timeout, or fails, the resource pool schedules with the
+// priority scheduler configured by Fallback instead.
+type ExternalSchedulerConfig struct {
+	Endpoint string                   `json:"endpoint"`
+	Timeout  model.Duration           `json:"timeout"`
+	TLS      bool                     `json:"tls"`
+	Fallback *PrioritySchedulerConfig `json:"fallback"`
+}
+
+// Validate implements the check.Validatable interface.
+func (e ExternalSchedulerConfig) Validate() []error {
+	return []error{
+		check.NotEmpty(e.Endpoint, "endpoint must be set"),
+		check.GreaterThan(int64(e.Timeout), int64(0), "timeout must be positive"),
+	}
+}
+
 // Validate implements the check.Validatable interface.
 func (p PrioritySchedulerConfig) Validate() []error {
 	return model.ValidatePrioritySetting(p.DefaultPriority)
 }
+
+// Validate implements the check.Validatable interface.
+func (b BackfillConfig) Validate() []error {
+	if b.DefaultTimeLimit == nil {
+		return nil
+	}
+	return []error{
+		check.GreaterThan(int64(*b.DefaultTimeLimit), int64(0), "default_time_limit must be positive"),
+	}
+}
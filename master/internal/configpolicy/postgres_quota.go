@@ -0,0 +1,129 @@
+package configpolicy
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// quotaUsageFilter selects the allocations that count against a quota.
+type quotaUsageFilter struct {
+	workloadType string
+	// userID and workspaceID restrict the allocations to those of a user or a workspace, if set.
+	userID      *model.UserID
+	workspaceID *int
+	// window is how far back slot-hours are counted.
+	window time.Duration
+}
+
+// quotaUsageKey identifies the allocations that a quota usage filter selects, regardless of the
+// window. Quotas with the same key limit the same concurrent slots.
+type quotaUsageKey struct {
+	workloadType string
+	userID       model.UserID
+	workspaceID  int
+}
+
+func (f quotaUsageFilter) key() quotaUsageKey {
+	key := quotaUsageKey{workloadType: f.workloadType}
+	if f.userID != nil {
+		key.userID = *f.userID
+	}
+	if f.workspaceID != nil {
+		key.workspaceID = *f.workspaceID
+	}
+	return key
+}
+
+// pendingAllocation is an allocation that is waiting for resources, along with who it is for.
+type pendingAllocation struct {
+	AllocationID model.AllocationID `bun:"allocation_id"`
+	Slots        int                `bun:"slots"`
+	TaskType     model.TaskType     `bun:"task_type"`
+	OwnerID      model.UserID       `bun:"owner_id"`
+	WorkspaceID  *int               `bun:"workspace_id"`
+}
+
+// workloadTaskTypes returns the task types whose usage counts against the quotas of the workload
+// type.
+func workloadTaskTypes(workloadType string) []model.TaskType {
+	switch workloadType {
+	case model.ExperimentType:
+		return []model.TaskType{model.TaskTypeTrial}
+	case model.NTSCType:
+		return []model.TaskType{
+			model.TaskTypeNotebook, model.TaskTypeTensorboard, model.TaskTypeShell, model.TaskTypeCommand,
+			model.TaskTypeGeneric,
+		}
+	default:
+		return nil
+	}
+}
+
+// taskWorkloadType returns the workload type whose quotas the usage of the task type counts
+// against, if any.
+func taskWorkloadType(taskType model.TaskType) (string, bool) {
+	for _, workloadType := range []string{model.ExperimentType, model.NTSCType} {
+		if slices.Contains(workloadTaskTypes(workloadType), taskType) {
+			return workloadType, true
+		}
+	}
+	return "", false
+}
+
+// getQuotaUsage returns the slot-hours used within the window, and the slots in use, by the
+// allocations selected by the filter.
+func getQuotaUsage(ctx context.Context, filter quotaUsageFilter) (QuotaUsage, error) {
+	since := time.Now().UTC().Add(-filter.window)
+
+	var usage QuotaUsage
+	q := db.Bun().NewSelect().
+		TableExpr("allocations a").
+		Join("JOIN tasks t ON a.task_id = t.task_id").
+		Join("JOIN jobs j ON t.job_id = j.job_id").
+		Join("LEFT JOIN allocation_workspace_info awi ON a.allocation_id = awi.allocation_id").
+		ColumnExpr(`(coalesce(sum(extract(epoch FROM
+			greatest(coalesce(a.end_time, now()), a.start_time) - greatest(a.start_time, ?::timestamptz)
+		) * a.slots), 0) / 3600.0)::float8 AS slot_hours`, since).
+		ColumnExpr("coalesce(sum(a.slots) FILTER (WHERE a.end_time IS NULL), 0) AS concurrent_slots").
+		Where("a.start_time IS NOT NULL").
+		Where("a.end_time IS NULL OR a.end_time > ?", since).
+		Where("t.task_type IN (?)", bun.In(workloadTaskTypes(filter.workloadType)))
+	if filter.userID != nil {
+		q = q.Where("j.owner_id = ?", *filter.userID)
+	}
+	if filter.workspaceID != nil {
+		q = q.Where("awi.workspace_id = ?", *filter.workspaceID)
+	}
+	if err := q.Scan(ctx, &usage); err != nil {
+		return QuotaUsage{}, fmt.Errorf("error computing quota usage: %w", err)
+	}
+	return usage, nil
+}
+
+// getPendingAllocations returns the allocations that have not started yet, earliest submitted
+// first.
+func getPendingAllocations(ctx context.Context) ([]pendingAllocation, error) {
+	var pending []pendingAllocation
+	err := db.Bun().NewSelect().
+		TableExpr("allocations a").
+		Join("JOIN tasks t ON a.task_id = t.task_id").
+		Join("JOIN jobs j ON t.job_id = j.job_id").
+		Join("LEFT JOIN allocation_workspace_info awi ON a.allocation_id = awi.allocation_id").
+		ColumnExpr("a.allocation_id, a.slots, t.task_type, j.owner_id, awi.workspace_id").
+		Where("a.start_time IS NULL").
+		Where("a.end_time IS NULL").
+		Where("a.slots > 0").
+		OrderExpr("t.start_time, a.allocation_id").
+		Scan(ctx, &pending)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving pending allocations: %w", err)
+	}
+	return pending, nil
+}
@@ -0,0 +1,162 @@
+//go:build integration
+// +build integration
+
+package configpolicy
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+func addQuotaAllocation(
+	t *testing.T, pgDB *db.PgDB, userID model.UserID, workspaceID *int, taskType model.TaskType,
+	slots int, start, end *time.Time,
+) model.AllocationID {
+	ctx := context.Background()
+	jobID := db.RequireMockJob(t, pgDB, &userID)
+	task := &model.Task{
+		TaskID:    model.NewTaskID(),
+		JobID:     &jobID,
+		TaskType:  taskType,
+		StartTime: time.Now().UTC(),
+	}
+	require.NoError(t, db.AddTask(ctx, task))
+
+	allocationID := model.AllocationID(fmt.Sprintf("%s.1", task.TaskID))
+	require.NoError(t, db.AddAllocation(ctx, &model.Allocation{
+		AllocationID: allocationID,
+		TaskID:       task.TaskID,
+		Slots:        slots,
+		ResourcePool: "default",
+		StartTime:    start,
+		EndTime:      end,
+	}))
+	if workspaceID != nil {
+		_, err := db.Bun().NewInsert().Model(&model.AllocationWorkspaceRecord{
+			AllocationID:  allocationID,
+			WorkspaceID:   *workspaceID,
+			WorkspaceName: fmt.Sprint(*workspaceID),
+		}).Exec(ctx)
+		require.NoError(t, err)
+	}
+	return allocationID
+}
+
+func TestGetQuotaUsage(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, etc.SetRootPath(db.RootFromDB))
+	pgDB, cleanup := db.MustResolveNewPostgresDatabase(t)
+	defer cleanup()
+	db.MustMigrateTestPostgres(t, pgDB, db.MigrationsFromDB)
+
+	user := db.RequireMockUser(t, pgDB)
+	otherUser := db.RequireMockUser(t, pgDB)
+	workspaceID, _ := db.RequireMockWorkspaceID(t, pgDB, "")
+	otherWorkspaceID, _ := db.RequireMockWorkspaceID(t, pgDB, "")
+	now := time.Now().UTC()
+	ago := func(hours int) *time.Time { return ptrs.Ptr(now.Add(-time.Duration(hours) * time.Hour)) }
+
+	// Within the last 2 hours, the user runs a command on 2 slots throughout and a generic task on
+	// 4 slots for an hour, while another user runs a notebook on 1 slot for an hour.
+	addQuotaAllocation(t, pgDB, user.ID, &workspaceID, model.TaskTypeCommand, 2, ago(2), nil)
+	addQuotaAllocation(t, pgDB, user.ID, &workspaceID, model.TaskTypeGeneric, 4, ago(3), ago(1))
+	addQuotaAllocation(t, pgDB, otherUser.ID, &workspaceID, model.TaskTypeNotebook, 1, ago(1), nil)
+	// These do not count against the NTSC quotas of the workspace in the window: one ended before
+	// it, one is a trial, one is in another workspace, and one has not started.
+	addQuotaAllocation(t, pgDB, user.ID, &workspaceID, model.TaskTypeShell, 8, ago(5), ago(4))
+	addQuotaAllocation(t, pgDB, user.ID, &workspaceID, model.TaskTypeTrial, 16, ago(1), nil)
+	addQuotaAllocation(t, pgDB, user.ID, &otherWorkspaceID, model.TaskTypeCommand, 1, ago(1), nil)
+	addQuotaAllocation(t, pgDB, user.ID, &workspaceID, model.TaskTypeCommand, 32, nil, nil)
+
+	cases := []struct {
+		name            string
+		filter          quotaUsageFilter
+		slotHours       float64
+		concurrentSlots int
+	}{
+		{
+			name: "workspace user",
+			filter: quotaUsageFilter{
+				workloadType: model.NTSCType, userID: &user.ID, workspaceID: &workspaceID,
+			},
+			slotHours:       8,
+			concurrentSlots: 2,
+		},
+		{
+			name:            "global user",
+			filter:          quotaUsageFilter{workloadType: model.NTSCType, userID: &user.ID},
+			slotHours:       9,
+			concurrentSlots: 3,
+		},
+		{
+			name:            "workspace",
+			filter:          quotaUsageFilter{workloadType: model.NTSCType, workspaceID: &workspaceID},
+			slotHours:       9,
+			concurrentSlots: 3,
+		},
+		{
+			name: "experiments",
+			filter: quotaUsageFilter{
+				workloadType: model.ExperimentType, userID: &user.ID, workspaceID: &workspaceID,
+			},
+			slotHours:       16,
+			concurrentSlots: 16,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.filter.window = 2 * time.Hour
+			usage, err := getQuotaUsage(ctx, tc.filter)
+			require.NoError(t, err)
+			require.InDelta(t, tc.slotHours, usage.SlotHours, 0.01)
+			require.Equal(t, tc.concurrentSlots, usage.ConcurrentSlots)
+		})
+	}
+}
+
+func TestGetPendingAllocations(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, etc.SetRootPath(db.RootFromDB))
+	pgDB, cleanup := db.MustResolveNewPostgresDatabase(t)
+	defer cleanup()
+	db.MustMigrateTestPostgres(t, pgDB, db.MigrationsFromDB)
+
+	user := db.RequireMockUser(t, pgDB)
+	workspaceID, _ := db.RequireMockWorkspaceID(t, pgDB, "")
+	now := time.Now().UTC()
+
+	generic := addQuotaAllocation(t, pgDB, user.ID, &workspaceID, model.TaskTypeGeneric, 2, nil, nil)
+	trial := addQuotaAllocation(t, pgDB, user.ID, nil, model.TaskTypeTrial, 4, nil, nil)
+	// Allocations that started, ended without starting, or need no slots are not pending.
+	addQuotaAllocation(t, pgDB, user.ID, &workspaceID, model.TaskTypeCommand, 1, &now, nil)
+	addQuotaAllocation(t, pgDB, user.ID, &workspaceID, model.TaskTypeCommand, 1, nil, &now)
+	addQuotaAllocation(t, pgDB, user.ID, &workspaceID, model.TaskTypeCommand, 0, nil, nil)
+
+	pending, err := getPendingAllocations(ctx)
+	require.NoError(t, err)
+	var ours []pendingAllocation
+	for _, alloc := range pending {
+		if alloc.OwnerID == user.ID {
+			ours = append(ours, alloc)
+		}
+	}
+	require.Equal(t, []pendingAllocation{
+		{
+			AllocationID: generic,
+			Slots:        2,
+			TaskType:     model.TaskTypeGeneric,
+			OwnerID:      user.ID,
+			WorkspaceID:  &workspaceID,
+		},
+		{AllocationID: trial, Slots: 4, TaskType: model.TaskTypeTrial, OwnerID: user.ID},
+	}, ours)
+}
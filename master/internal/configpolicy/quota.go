@@ -0,0 +1,291 @@
+package configpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/master/internal/rm/tasklist"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+const (
+	quotaScopeGlobal      = "global"
+	quotaScopeWorkspace   = "workspace"
+	quotaSubjectUser      = "user"
+	quotaSubjectWorkspace = "workspace"
+
+	// quotaEnforcementInterval is how often pending allocations are checked against quotas.
+	quotaEnforcementInterval = 30 * time.Second
+)
+
+var errQuotaExhausted = errors.New("submitted workload exceeds a quota")
+
+// QuotaUsage is the slot usage that counts against a quota.
+type QuotaUsage struct {
+	// SlotHours is the number of slot-hours used within the window of the quota.
+	SlotHours float64 `bun:"slot_hours"`
+	// ConcurrentSlots is the number of slots in use.
+	ConcurrentSlots int `bun:"concurrent_slots"`
+}
+
+// QuotaStatus is the usage and the remaining budget of a quota that applies to a workload.
+type QuotaStatus struct {
+	// Scope is the scope of the config policies that set the quota: global or workspace.
+	Scope string
+	// Subject is whose workloads the quota limits: user or workspace.
+	Subject string
+	Limits  model.QuotaLimits
+	Usage   QuotaUsage
+
+	// filter selects the allocations the usage is of.
+	filter quotaUsageFilter
+}
+
+func (s QuotaStatus) String() string {
+	return fmt.Sprintf("%s quota of the %s config policies", s.Subject, s.Scope)
+}
+
+// window returns the length of the rolling window slot-hours are counted in.
+func (s QuotaStatus) window() time.Duration {
+	if s.Limits.SlotHours == nil || s.Limits.Window == nil {
+		return 0
+	}
+	return time.Duration(*s.Limits.Window)
+}
+
+// SlotHoursRemaining returns the slot-hours left within the window, or nil if slot-hours are not
+// limited.
+func (s QuotaStatus) SlotHoursRemaining() *float64 {
+	if s.Limits.SlotHours == nil {
+		return nil
+	}
+	return ptrs.Ptr(math.Max(*s.Limits.SlotHours-s.Usage.SlotHours, 0))
+}
+
+// Exhausted returns whether the slot-hours of the quota are used up.
+func (s QuotaStatus) Exhausted() bool {
+	return s.Limits.SlotHours != nil && s.Usage.SlotHours >= *s.Limits.SlotHours
+}
+
+// checkSubmission returns an error if a workload that needs the given number of slots must not be
+// submitted because of the quota.
+func (s QuotaStatus) checkSubmission(slots int) error {
+	if s.Exhausted() {
+		return fmt.Errorf("%s is exhausted: %.2f of %.2f slot-hours used in the last %s: %w",
+			s, s.Usage.SlotHours, *s.Limits.SlotHours, s.window(), errQuotaExhausted)
+	}
+	if maxSlots := s.Limits.MaxConcurrentSlots; maxSlots != nil && slots > *maxSlots {
+		return fmt.Errorf("requested slots [%d] exceed the %s of %d concurrent slots: %w",
+			slots, s, *maxSlots, errQuotaExhausted)
+	}
+	return nil
+}
+
+// holdReason returns why an allocation that needs the given number of slots must wait for quota,
+// and whether it must wait.
+func (s QuotaStatus) holdReason(slots int) (string, bool) {
+	if s.Exhausted() {
+		return fmt.Sprintf("%s is exhausted", s), true
+	}
+	maxSlots := s.Limits.MaxConcurrentSlots
+	if maxSlots != nil && s.Usage.ConcurrentSlots+slots > *maxSlots {
+		return fmt.Sprintf("%s allows %d concurrent slots and %d are in use", s, *maxSlots,
+			s.Usage.ConcurrentSlots), true
+	}
+	return "", false
+}
+
+// Proto returns the quota status as a protobuf message.
+func (s QuotaStatus) Proto() *apiv1.QuotaStatus {
+	pb := &apiv1.QuotaStatus{
+		Scope:              s.Scope,
+		Subject:            s.Subject,
+		SlotHoursLimit:     s.Limits.SlotHours,
+		SlotHoursUsed:      s.Usage.SlotHours,
+		SlotHoursRemaining: s.SlotHoursRemaining(),
+		ConcurrentSlots:    int32(s.Usage.ConcurrentSlots),
+		Exhausted:          s.Exhausted(),
+	}
+	if s.Limits.Window != nil {
+		pb.Window = ptrs.Ptr(time.Duration(*s.Limits.Window).String())
+	}
+	if s.Limits.MaxConcurrentSlots != nil {
+		pb.MaxConcurrentSlots = ptrs.Ptr(int32(*s.Limits.MaxConcurrentSlots))
+	}
+	return pb
+}
+
+// GetQuotaStatus returns the status of the quotas that apply to the workloads of the given type
+// that the user runs in the workspace. Global quotas are listed before workspace quotas; all of
+// them must be satisfied.
+func GetQuotaStatus(
+	ctx context.Context, workspaceID int, userID model.UserID, workloadType string,
+) ([]QuotaStatus, error) {
+	var statuses []QuotaStatus
+	for _, scope := range []*int{nil, &workspaceID} {
+		configPolicies, err := GetTaskConfigPolicies(ctx, scope, workloadType)
+		if err != nil {
+			return nil, err
+		}
+		if configPolicies.Constraints == nil {
+			continue
+		}
+		var constraints model.Constraints
+		if err := json.Unmarshal([]byte(*configPolicies.Constraints), &constraints); err != nil {
+			return nil, fmt.Errorf("unable to unmarshal task config policies: %w", err)
+		}
+		quotas := constraints.QuotaConstraints
+		if quotas == nil {
+			continue
+		}
+
+		scopeName := quotaScopeGlobal
+		if scope != nil {
+			scopeName = quotaScopeWorkspace
+		}
+		// A global user quota counts the usage of the user in every workspace, whereas a
+		// workspace user quota only counts the usage in that workspace.
+		if quotas.User != nil {
+			status, err := getQuotaStatus(ctx, scopeName, quotaSubjectUser, *quotas.User,
+				quotaUsageFilter{workloadType: workloadType, userID: &userID, workspaceID: scope})
+			if err != nil {
+				return nil, err
+			}
+			statuses = append(statuses, status)
+		}
+		if quotas.Workspace != nil {
+			status, err := getQuotaStatus(ctx, scopeName, quotaSubjectWorkspace, *quotas.Workspace,
+				quotaUsageFilter{workloadType: workloadType, workspaceID: &workspaceID})
+			if err != nil {
+				return nil, err
+			}
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses, nil
+}
+
+func getQuotaStatus(
+	ctx context.Context, scope, subject string, limits model.QuotaLimits, filter quotaUsageFilter,
+) (QuotaStatus, error) {
+	status := QuotaStatus{Scope: scope, Subject: subject, Limits: limits}
+	filter.window = status.window()
+	status.filter = filter
+	usage, err := getQuotaUsage(ctx, filter)
+	if err != nil {
+		return QuotaStatus{}, err
+	}
+	status.Usage = usage
+	return status, nil
+}
+
+// CheckQuotas returns an error if the user's workload of the given type, which needs the given
+// number of slots, must not be submitted to the workspace because of a quota.
+func CheckQuotas(
+	ctx context.Context, workspaceID int, userID model.UserID, workloadType string, slots int,
+) error {
+	statuses, err := GetQuotaStatus(ctx, workspaceID, userID, workloadType)
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		if err := status.checkSubmission(slots); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EnforceQuotas periodically finds the pending allocations that would exceed a quota and holds
+// them in the queues of the resource managers until enough budget is available.
+func EnforceQuotas(ctx context.Context) {
+	t := time.NewTicker(quotaEnforcementInterval)
+	defer t.Stop()
+	for {
+		held, err := findQuotaHolds(ctx)
+		if err != nil {
+			logrus.WithField("component", "task configuration & constraints policy").
+				WithError(err).Error("error enforcing quotas")
+		} else {
+			tasklist.QuotaHolds.Set(held)
+		}
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// quotaKey identifies the workloads that the same quotas apply to.
+type quotaKey struct {
+	workspaceID  int
+	userID       model.UserID
+	workloadType string
+}
+
+// findQuotaHolds returns the pending allocations that would exceed a quota, and why.
+func findQuotaHolds(ctx context.Context) (map[model.AllocationID]string, error) {
+	pending, err := getPendingAllocations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return holdPendingAllocations(pending, func(key quotaKey) ([]QuotaStatus, error) {
+		return GetQuotaStatus(ctx, key.workspaceID, key.userID, key.workloadType)
+	})
+}
+
+// holdPendingAllocations returns the pending allocations that would exceed one of the quotas that
+// getStatuses returns for them, and why. The slots of the allocations that are let through count
+// against the quotas of the later ones, so that together they do not exceed a quota either.
+func holdPendingAllocations(
+	pending []pendingAllocation, getStatuses func(quotaKey) ([]QuotaStatus, error),
+) (map[model.AllocationID]string, error) {
+	held := make(map[model.AllocationID]string)
+	statuses := make(map[quotaKey][]QuotaStatus)
+	admittedSlots := make(map[quotaUsageKey]int)
+	for _, alloc := range pending {
+		workloadType, ok := taskWorkloadType(alloc.TaskType)
+		if !ok || alloc.WorkspaceID == nil {
+			continue
+		}
+
+		key := quotaKey{workspaceID: *alloc.WorkspaceID, userID: alloc.OwnerID, workloadType: workloadType}
+		if _, ok := statuses[key]; !ok {
+			keyStatuses, err := getStatuses(key)
+			if err != nil {
+				return nil, err
+			}
+			statuses[key] = keyStatuses
+		}
+		for _, status := range statuses[key] {
+			status.Usage.ConcurrentSlots += admittedSlots[status.filter.key()]
+			if reason, ok := status.holdReason(alloc.Slots); ok {
+				held[alloc.AllocationID] = reason
+				break
+			}
+		}
+		if _, ok := held[alloc.AllocationID]; ok {
+			continue
+		}
+		// Quotas with the same usage, like the global and workspace quotas of a workspace, only
+		// count the allocation once.
+		counted := make(map[quotaUsageKey]bool)
+		for _, status := range statuses[key] {
+			if usageKey := status.filter.key(); !counted[usageKey] {
+				counted[usageKey] = true
+				admittedSlots[usageKey] += alloc.Slots
+			}
+		}
+	}
+	return held, nil
+}
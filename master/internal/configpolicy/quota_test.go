@@ -0,0 +1,168 @@
+package configpolicy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+func TestQuotaStatus(t *testing.T) {
+	s := QuotaStatus{
+		Scope:   quotaScopeWorkspace,
+		Subject: quotaSubjectUser,
+		Limits: model.QuotaLimits{
+			SlotHours:          ptrs.Ptr(100.0),
+			Window:             ptrs.Ptr(model.Duration(7 * 24 * time.Hour)),
+			MaxConcurrentSlots: ptrs.Ptr(8),
+		},
+		Usage: QuotaUsage{SlotHours: 60, ConcurrentSlots: 6},
+	}
+
+	t.Run("within budget", func(t *testing.T) {
+		require.False(t, s.Exhausted())
+		require.Equal(t, 40.0, *s.SlotHoursRemaining())
+		require.NoError(t, s.checkSubmission(8))
+		_, held := s.holdReason(2)
+		require.False(t, held)
+	})
+
+	t.Run("too many concurrent slots", func(t *testing.T) {
+		// A workload that needs more slots than the quota allows can never run.
+		require.ErrorIs(t, s.checkSubmission(9), errQuotaExhausted)
+		// One that fits is submitted, but waits while other workloads use the slots.
+		require.NoError(t, s.checkSubmission(4))
+		reason, held := s.holdReason(4)
+		require.True(t, held)
+		require.Equal(t,
+			"user quota of the workspace config policies allows 8 concurrent slots and 6 are in use",
+			reason)
+	})
+
+	t.Run("slot-hours exhausted", func(t *testing.T) {
+		s := s
+		s.Usage.SlotHours = 120
+		require.True(t, s.Exhausted())
+		require.Equal(t, 0.0, *s.SlotHoursRemaining())
+		require.ErrorIs(t, s.checkSubmission(1), errQuotaExhausted)
+		_, held := s.holdReason(1)
+		require.True(t, held)
+
+		pb := s.Proto()
+		require.True(t, pb.Exhausted)
+		require.Equal(t, "168h0m0s", *pb.Window)
+		require.Equal(t, int32(8), *pb.MaxConcurrentSlots)
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		s := QuotaStatus{Usage: QuotaUsage{SlotHours: 1000, ConcurrentSlots: 1000}}
+		require.False(t, s.Exhausted())
+		require.Nil(t, s.SlotHoursRemaining())
+		require.NoError(t, s.checkSubmission(1000))
+		_, held := s.holdReason(1000)
+		require.False(t, held)
+	})
+}
+
+func TestCheckQuotaConstraints(t *testing.T) {
+	window := ptrs.Ptr(model.Duration(24 * time.Hour))
+	testCases := []struct {
+		name   string
+		quotas *model.QuotaConstraints
+		err    string
+	}{
+		{"no quotas", nil, ""},
+		{
+			"valid", &model.QuotaConstraints{
+				User:      &model.QuotaLimits{SlotHours: ptrs.Ptr(10.0), Window: window},
+				Workspace: &model.QuotaLimits{MaxConcurrentSlots: ptrs.Ptr(16)},
+			}, "",
+		},
+		{
+			"slot-hours without window", &model.QuotaConstraints{
+				User: &model.QuotaLimits{SlotHours: ptrs.Ptr(10.0)},
+			}, "quotas.user.window must be a positive duration when slot_hours is set",
+		},
+		{
+			"negative slot-hours", &model.QuotaConstraints{
+				Workspace: &model.QuotaLimits{SlotHours: ptrs.Ptr(-1.0), Window: window},
+			}, "quotas.workspace.slot_hours must be positive",
+		},
+		{
+			"zero concurrent slots", &model.QuotaConstraints{
+				Workspace: &model.QuotaLimits{MaxConcurrentSlots: ptrs.Ptr(0)},
+			}, "quotas.workspace.max_concurrent_slots must be positive",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkQuotaConstraints(&model.Constraints{QuotaConstraints: tt.quotas})
+			if tt.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.err)
+			}
+		})
+	}
+
+	t.Run("yaml", func(t *testing.T) {
+		err := ValidateExperimentConfig(nil, `
+constraints:
+  quotas:
+    user:
+      slot_hours: 100
+      window: 168h
+      max_concurrent_slots: 8
+`, nil)
+		require.NoError(t, err)
+
+		err = ValidateNTSCConfig(nil, `
+constraints:
+  quotas:
+    workspace:
+      slot_hours: 100
+`, nil)
+		require.ErrorContains(t, err, "quotas.workspace.window must be a positive duration")
+	})
+}
+
+func TestHoldPendingAllocations(t *testing.T) {
+	workspaceID := 1
+	userA, userB := model.UserID(1), model.UserID(2)
+	workspaceLimits := model.QuotaLimits{MaxConcurrentSlots: ptrs.Ptr(8)}
+	getStatuses := func(key quotaKey) ([]QuotaStatus, error) {
+		filter := quotaUsageFilter{workloadType: key.workloadType, workspaceID: &key.workspaceID}
+		return []QuotaStatus{
+			{
+				Scope: quotaScopeGlobal, Subject: quotaSubjectWorkspace, Limits: workspaceLimits,
+				Usage: QuotaUsage{ConcurrentSlots: 2}, filter: filter,
+			},
+			{
+				Scope: quotaScopeWorkspace, Subject: quotaSubjectWorkspace, Limits: workspaceLimits,
+				Usage: QuotaUsage{ConcurrentSlots: 2}, filter: filter,
+			},
+		}, nil
+	}
+	pending := []pendingAllocation{
+		{AllocationID: "a.1", Slots: 4, TaskType: model.TaskTypeCommand, OwnerID: userA},
+		{AllocationID: "b.1", Slots: 2, TaskType: model.TaskTypeGeneric, OwnerID: userB},
+		{AllocationID: "c.1", Slots: 4, TaskType: model.TaskTypeNotebook, OwnerID: userA},
+		{AllocationID: "d.1", Slots: 4, TaskType: model.TaskTypeTrial, OwnerID: userA},
+	}
+	for i := range pending {
+		pending[i].WorkspaceID = &workspaceID
+	}
+
+	held, err := holdPendingAllocations(pending, getStatuses)
+	require.NoError(t, err)
+	// The first two fit in the workspace's 6 free slots together, even though each quota is
+	// checked against the same usage; the third would exceed them. The trial counts against the
+	// experiment quotas, which have their own usage.
+	require.Equal(t, map[model.AllocationID]string{
+		"c.1": "workspace quota of the global config policies allows 8 concurrent slots and 8 are in use",
+	}, held)
+}
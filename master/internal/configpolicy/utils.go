@@ -143,6 +143,9 @@ func ValidateExperimentConfig(
 	if cp.Constraints != nil {
 		checkAgainstGlobalPriority(priorityEnabledErr, cp.Constraints.PriorityLimit)
 	}
+	if err := checkQuotaConstraints(cp.Constraints); err != nil {
+		return status.Errorf(codes.InvalidArgument, fmt.Sprintf(InvalidExperimentConfigPolicyErr+": %s.", err))
+	}
 
 	if cp.InvariantConfig != nil {
 		if cp.InvariantConfig.RawResources != nil {
@@ -205,6 +208,9 @@ func ValidateNTSCConfig(
 	if cp.Constraints != nil {
 		checkAgainstGlobalPriority(priorityEnabledErr, cp.Constraints.PriorityLimit)
 	}
+	if err := checkQuotaConstraints(cp.Constraints); err != nil {
+		return status.Errorf(codes.InvalidArgument, fmt.Sprintf(InvalidNTSCConfigPolicyErr+": %s.", err))
+	}
 
 	if cp.InvariantConfig != nil {
 		if cp.InvariantConfig.Resources.Priority != nil {
@@ -263,6 +269,35 @@ func checkConstraintConflicts(constraints *model.Constraints, maxSlots, slots, p
 	return nil
 }
 
+func checkQuotaConstraints(constraints *model.Constraints) error {
+	if constraints == nil || constraints.QuotaConstraints == nil {
+		return nil
+	}
+	quotas := constraints.QuotaConstraints
+	for _, subject := range []string{quotaSubjectUser, quotaSubjectWorkspace} {
+		limits := quotas.User
+		if subject == quotaSubjectWorkspace {
+			limits = quotas.Workspace
+		}
+		if limits == nil {
+			continue
+		}
+		if limits.SlotHours != nil {
+			if *limits.SlotHours <= 0 {
+				return fmt.Errorf("quotas.%s.slot_hours must be positive", subject)
+			}
+			if limits.Window == nil || *limits.Window <= 0 {
+				return fmt.Errorf("quotas.%s.window must be a positive duration when slot_hours is set",
+					subject)
+			}
+		}
+		if limits.MaxConcurrentSlots != nil && *limits.MaxConcurrentSlots <= 0 {
+			return fmt.Errorf("quotas.%s.max_concurrent_slots must be positive", subject)
+		}
+	}
+	return nil
+}
+
 // configPolicyOverlap compares two different configurations and warns the user when both
 // configurations define the same field.
 func configPolicyOverlap(config1, config2 interface{}) {
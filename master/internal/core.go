@@ -43,11 +43,13 @@ import (
 	"github.com/determined-ai/determined/master/internal/cluster"
 	"github.com/determined-ai/determined/master/internal/command"
 	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/configpolicy"
 	"github.com/determined-ai/determined/master/internal/connsave"
 	detContext "github.com/determined-ai/determined/master/internal/context"
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/elastic"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/job/dependency"
 	"github.com/determined-ai/determined/master/internal/job/jobservice"
 	"github.com/determined-ai/determined/master/internal/license"
 	"github.com/determined-ai/determined/master/internal/logpattern"
@@ -63,6 +65,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/rm/multirm"
 	"github.com/determined-ai/determined/master/internal/rm/tasklist"
 	"github.com/determined-ai/determined/master/internal/saas/saasprovisioner"
+	"github.com/determined-ai/determined/master/internal/scheduledjob"
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/internal/stream"
 	"github.com/determined-ai/determined/master/internal/task"
@@ -86,6 +89,9 @@ import (
 const (
 	maxConcurrentRestores = 10
 	webuiBaseRoute        = "/det"
+	// agentHealthEventCleanupInterval is how often agent health events past their retention are
+	// deleted.
+	agentHealthEventCleanupInterval = time.Hour
 )
 
 // staticWebDirectoryPaths are the locations of static files that comprise the webui.
@@ -883,8 +889,9 @@ func (m *Master) restoreGenericTasks(ctx context.Context) error {
 		Relation("Allocation").
 		Relation("Task").
 		Relation("Task.Job").
-		Where("allocation.end_time IS NULL").
-		Where("allocation.state != ?", model.AllocationStateTerminated).
+		// Tasks that were waiting on their dependencies have no allocation.
+		Where(`(command_snapshot.allocation_id IS NULL AND task.end_time IS NULL) OR
+			(allocation.end_time IS NULL AND allocation.state != ?)`, model.AllocationStateTerminated).
 		Where("task.task_id = command_snapshot.task_id").
 		Where("task.task_type = ?", model.TaskTypeGeneric).
 		Where("command_snapshot.generic_task_spec IS NOT NULL").
@@ -917,6 +924,15 @@ func (m *Master) restoreGenericTasks(ctx context.Context) error {
 
 		onAllocationExit := getGenericTaskOnAllocationExit(ctx, taskID, *jobID, logCtx)
 
+		if snapshots[i].AllocationID == "" {
+			if err := m.restoreWaitingGenericTask(
+				ctx, taskID, *jobID, snapshots[i].GenericTaskSpec, logCtx, onAllocationExit,
+			); err != nil {
+				return err
+			}
+			continue
+		}
+
 		isSingleNode := snapshots[i].GenericTaskSpec.GenericTaskConfig.Resources.IsSingleNode() != nil &&
 			*snapshots[i].GenericTaskSpec.GenericTaskConfig.Resources.IsSingleNode()
 
@@ -954,6 +970,35 @@ func (m *Master) restoreGenericTasks(ctx context.Context) error {
 	return nil
 }
 
+// restoreWaitingGenericTask holds a generic task that was waiting on its dependencies back again,
+// or starts it if they are gone.
+func (m *Master) restoreWaitingGenericTask(
+	ctx context.Context,
+	taskID model.TaskID,
+	jobID model.JobID,
+	spec *tasks.GenericTaskSpec,
+	logCtx logger.Context,
+	onAllocationExit func(*task.AllocationExited),
+) error {
+	startAllocation := getGenericTaskStartAllocation(
+		m.db, m.rm, taskID, jobID, spec, logCtx, onAllocationExit,
+	)
+	waiting, err := dependency.DefaultService.Wait(
+		ctx, jobID, spec.GenericTaskConfig.Resources.ResourcePool(),
+		getGenericTaskOnDependenciesResolved(taskID, jobID, logCtx, startAllocation),
+	)
+	if err != nil {
+		return err
+	}
+	if !waiting {
+		if err := startAllocation(ctx); err != nil {
+			return err
+		}
+	}
+	jobservice.DefaultService.RegisterJob(jobID, spec)
+	return nil
+}
+
 func (m *Master) closeOpenAllocations(ctx context.Context) error {
 	allocationIds := task.DefaultService.GetAllAllocationIDs()
 	if err := db.CloseOpenAllocations(ctx, allocationIds); err != nil {
@@ -1003,6 +1048,29 @@ func updateClusterHeartbeat(ctx context.Context, db *db.PgDB) {
 	}
 }
 
+// cleanUpAgentHealthEvents periodically deletes the agent health events that are older than the
+// retention of the master config.
+func cleanUpAgentHealthEvents(ctx context.Context) {
+	t := time.NewTicker(agentHealthEventCleanupInterval)
+	defer t.Stop()
+	for {
+		if days := config.GetMasterConfig().AgentHealthEvents.RetentionDays; days >= 0 {
+			count, err := db.DeleteExpiredAgentHealthEvents(ctx, time.Now().AddDate(0, 0, -days))
+			if err != nil {
+				log.WithError(err).Error("failed to delete expired agent health events")
+			} else if count > 0 {
+				log.WithField("count", count).Info("deleted expired agent health events")
+			}
+		}
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 func (m *Master) checkIfRMDefaultsAreUnbound(rmConfig *config.ResourceManagerConfig) error {
 	if rmConfig.AgentRM != nil {
 		err := db.CheckIfRPUnbound(rmConfig.AgentRM.DefaultComputeResourcePool)
@@ -1418,6 +1486,10 @@ func (m *Master) Run(ctx context.Context, gRPCLogInitDone chan struct{}) error {
 		return err
 	}
 
+	if err = dependency.EndUnrestorableTasks(ctx); err != nil {
+		return err
+	}
+
 	if err = m.closeOpenAllocations(ctx); err != nil {
 		return err
 	}
@@ -1426,11 +1498,28 @@ func (m *Master) Run(ctx context.Context, gRPCLogInitDone chan struct{}) error {
 		return err
 	}
 
+	// Scheduled jobs launch experiments and commands, so they start once those are restored.
+	sjs, err := scheduledjob.NewService(&scheduledJobLauncher{a: &apiServer{m: m}})
+	if err != nil {
+		return fmt.Errorf("initializing scheduled jobs: %w", err)
+	}
+	scheduledjob.SetDefaultService(sjs)
+	if err = sjs.Start(ctx); err != nil {
+		return fmt.Errorf("starting scheduled jobs: %w", err)
+	}
+	defer func() {
+		if err := sjs.Shutdown(); err != nil {
+			log.WithError(err).Warn("shutting down scheduled jobs")
+		}
+	}()
+
 	// The below function call is intentionally made after the call to CloseOpenAllocations.
 	// This ensures that in the scenario where a cluster fails all open allocations are
 	// set to the last cluster heartbeat when the cluster was running.
 	go updateClusterHeartbeat(ctx, m.db)
+	go cleanUpAgentHealthEvents(ctx)
 	go trials.MarkLostTrialsWorker(ctx)
+	go configpolicy.EnforceQuotas(ctx)
 
 	// Docs and WebUI.
 	webuiRoot := filepath.Join(m.config.Root, "webui")
@@ -1516,6 +1605,7 @@ func (m *Master) Run(ctx context.Context, gRPCLogInitDone chan struct{}) error {
 	resourcesGroup.GET("/allocation/raw", m.getRawResourceAllocation)
 	resourcesGroup.GET("/allocation/allocations-csv", m.getResourceAllocations)
 	resourcesGroup.GET("/allocation/aggregated", m.getAggregatedResourceAllocation)
+	resourcesGroup.GET("/cost/aggregated", m.getAggregatedResourceCost)
 
 	m.echo.POST("/task-logs", api.Route(m.postTaskLogs))
 
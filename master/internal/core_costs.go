@@ -0,0 +1,220 @@
+package internal
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/masterv1"
+)
+
+// resourceCostKeys are the SQL expressions of the keys by which resource costs are aggregated.
+var resourceCostKeys = map[masterv1.ResourceCostGroupBy]string{
+	masterv1.ResourceCostGroupBy_RESOURCE_COST_GROUP_BY_ALLOCATION:    "a.allocation_id",
+	masterv1.ResourceCostGroupBy_RESOURCE_COST_GROUP_BY_EXPERIMENT:    "coalesce(awi.experiment_id::text, '')",
+	masterv1.ResourceCostGroupBy_RESOURCE_COST_GROUP_BY_USER:          "coalesce(u.username, '')",
+	masterv1.ResourceCostGroupBy_RESOURCE_COST_GROUP_BY_WORKSPACE:     "coalesce(awi.workspace_name, '')",
+	masterv1.ResourceCostGroupBy_RESOURCE_COST_GROUP_BY_PROJECT:       "coalesce(w.name || '/' || p.name, '')",
+	masterv1.ResourceCostGroupBy_RESOURCE_COST_GROUP_BY_RESOURCE_POOL: "a.resource_pool",
+}
+
+// resourceCostRange returns the start and exclusive end of the periods of the request, and the
+// length of each period as a Postgres interval.
+func resourceCostRange(
+	req *apiv1.ResourceCostAggregatedRequest,
+) (start, end time.Time, interval string, err error) {
+	var layout string
+	switch req.Period {
+	case masterv1.ResourceAllocationAggregationPeriod_RESOURCE_ALLOCATION_AGGREGATION_PERIOD_DAILY:
+		layout, interval = "2006-01-02", "1 day"
+	case masterv1.ResourceAllocationAggregationPeriod_RESOURCE_ALLOCATION_AGGREGATION_PERIOD_MONTHLY:
+		layout, interval = "2006-01", "1 month"
+	default:
+		return start, end, "", status.Error(codes.InvalidArgument, "no aggregation period specified")
+	}
+
+	start, err = time.Parse(layout, req.StartDate)
+	if err != nil {
+		return start, end, "", status.Errorf(codes.InvalidArgument, "invalid start date %s", err.Error())
+	}
+	end, err = time.Parse(layout, req.EndDate)
+	if err != nil {
+		return start, end, "", status.Errorf(codes.InvalidArgument, "invalid end date %s", err.Error())
+	}
+	if start.After(end) {
+		return start, end, "", status.Error(codes.InvalidArgument, "start date cannot be after end date")
+	}
+
+	// The end date is inclusive.
+	if interval == "1 day" {
+		end = end.AddDate(0, 0, 1)
+	} else {
+		end = end.AddDate(0, 1, 0)
+	}
+	return start.UTC(), end.UTC(), interval, nil
+}
+
+// fetchAggregatedResourceCost aggregates the cost of allocations by period and by the key of the
+// request. The slot-hours of an allocation are priced at the slot-hour price of its resource pool
+// that was in effect when the allocation started, or the earliest price recorded after that if
+// the allocation predates the pricing of its pool.
+func (m *Master) fetchAggregatedResourceCost(
+	ctx context.Context, req *apiv1.ResourceCostAggregatedRequest,
+) (*apiv1.ResourceCostAggregatedResponse, error) {
+	start, end, interval, err := resourceCostRange(req)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := resourceCostKeys[req.GroupBy]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "no aggregation key specified")
+	}
+
+	periods := db.Bun().NewSelect().
+		ColumnExpr("ps AS period_start").
+		ColumnExpr("ps + ?::interval AS period_end", interval).
+		TableExpr("generate_series(?::timestamptz, ?::timestamptz - ?::interval, ?::interval) ps",
+			start, end, interval, interval)
+
+	pricedAllocations := db.Bun().NewSelect().
+		ColumnExpr("a.allocation_id").
+		ColumnExpr("a.task_id").
+		ColumnExpr("a.resource_pool").
+		ColumnExpr("per.period_start").
+		ColumnExpr("extract(epoch FROM (" +
+			"LEAST(GREATEST(coalesce(a.end_time, now()), a.start_time), per.period_end) - " +
+			"GREATEST(a.start_time, per.period_start))) * a.slots / 3600.0 AS slot_hours").
+		ColumnExpr("price.slot_hour_price").
+		ColumnExpr("price.currency").
+		TableExpr("allocations a").
+		Join("INNER JOIN periods per ON a.start_time < per.period_end " +
+			"AND coalesce(a.end_time, now()) > per.period_start").
+		Join(`LEFT JOIN LATERAL (
+			SELECT rpp.slot_hour_price, rpp.currency
+			FROM resource_pool_prices rpp
+			WHERE rpp.resource_pool = a.resource_pool
+			ORDER BY rpp.effective_from > a.start_time,
+				abs(extract(epoch FROM rpp.effective_from - a.start_time))
+			LIMIT 1
+		) price ON true`).
+		Where("a.start_time IS NOT NULL")
+
+	var rows []struct {
+		PeriodStart time.Time
+		Key         string
+		Currency    string
+		SlotHours   float64
+		Cost        float64
+	}
+	if err := db.Bun().NewSelect().
+		With("periods", periods).
+		With("priced_allocations", pricedAllocations).
+		ColumnExpr("a.period_start").
+		ColumnExpr(key+" AS key").
+		ColumnExpr("coalesce(a.currency, '') AS currency").
+		ColumnExpr("sum(a.slot_hours) AS slot_hours").
+		ColumnExpr("sum(a.slot_hours * coalesce(a.slot_hour_price, 0)) AS cost").
+		TableExpr("priced_allocations a").
+		Join("LEFT JOIN tasks t ON a.task_id = t.task_id").
+		Join("LEFT JOIN jobs j ON t.job_id = j.job_id").
+		Join("LEFT JOIN users u ON j.owner_id = u.id").
+		Join("LEFT JOIN allocation_workspace_info awi ON a.allocation_id = awi.allocation_id").
+		Join("LEFT JOIN experiments e ON awi.experiment_id = e.id").
+		Join("LEFT JOIN projects p ON e.project_id = p.id").
+		Join("LEFT JOIN workspaces w ON p.workspace_id = w.id").
+		GroupExpr("1, 2, 3").
+		OrderExpr("1, 2, 3").
+		Scan(ctx, &rows); err != nil {
+		return nil, errors.Wrap(err, "error fetching aggregated resource cost data")
+	}
+
+	layout := "2006-01-02"
+	if interval == "1 month" {
+		layout = "2006-01"
+	}
+	resp := &apiv1.ResourceCostAggregatedResponse{Entries: []*masterv1.ResourceCostEntry{}}
+	for _, row := range rows {
+		resp.Entries = append(resp.Entries, &masterv1.ResourceCostEntry{
+			PeriodStart: row.PeriodStart.UTC().Format(layout),
+			Key:         row.Key,
+			Currency:    row.Currency,
+			SlotHours:   row.SlotHours,
+			Cost:        row.Cost,
+		})
+	}
+	return resp, nil
+}
+
+//	@Summary	Get an aggregated view of the cost of resources during the given time period (CSV).
+//	@Tags		Cluster
+//	@ID			get-aggregated-resource-cost-csv
+//	@Produce	text/csv
+//	@Param		start_date	query	string	true	"Start time to get costs for (YYYY-MM-DD format for daily, YYYY-MM format for monthly)"
+//	@Param		end_date	query	string	true	"End time to get costs for (YYYY-MM-DD format for daily, YYYY-MM format for monthly)"
+//
+// nolint:lll
+//
+//	@Param		period		query	string	true	"Period to aggregate over (RESOURCE_ALLOCATION_AGGREGATION_PERIOD_DAILY or RESOURCE_ALLOCATION_AGGREGATION_PERIOD_MONTHLY)"
+//
+// nolint:lll
+//
+//	@Param		group_by	query	string	true	"Key to aggregate by (RESOURCE_COST_GROUP_BY_ALLOCATION, RESOURCE_COST_GROUP_BY_EXPERIMENT, RESOURCE_COST_GROUP_BY_USER, RESOURCE_COST_GROUP_BY_WORKSPACE, RESOURCE_COST_GROUP_BY_PROJECT or RESOURCE_COST_GROUP_BY_RESOURCE_POOL)"
+//	@Success	200			{}		string	"period_start,key,currency,slot_hours,cost"
+//	@Router		/resources/cost/aggregated [get]
+//
+// nolint:lll
+// To make both gofmt and swag fmt happy we need an unindented comment matched with the swagger
+// comment indented with tabs. https://github.com/swaggo/swag/pull/1386#issuecomment-1359242144
+func (m *Master) getAggregatedResourceCost(c echo.Context) error {
+	args := struct {
+		Start   string `query:"start_date"`
+		End     string `query:"end_date"`
+		Period  string `query:"period"`
+		GroupBy string `query:"group_by"`
+	}{}
+	if err := api.BindArgs(&args, c); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	resp, err := m.fetchAggregatedResourceCost(ctx, &apiv1.ResourceCostAggregatedRequest{
+		StartDate: args.Start,
+		EndDate:   args.End,
+		Period: masterv1.ResourceAllocationAggregationPeriod(
+			masterv1.ResourceAllocationAggregationPeriod_value[args.Period],
+		),
+		GroupBy: masterv1.ResourceCostGroupBy(masterv1.ResourceCostGroupBy_value[args.GroupBy]),
+	})
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("Content-Type", "text/csv")
+
+	csvWriter := csv.NewWriter(c.Response())
+	if err = csvWriter.Write([]string{"period_start", "key", "currency", "slot_hours", "cost"}); err != nil {
+		return err
+	}
+	for _, entry := range resp.Entries {
+		if err = csvWriter.Write([]string{
+			entry.PeriodStart,
+			entry.Key,
+			entry.Currency,
+			fmt.Sprintf("%f", entry.SlotHours),
+			fmt.Sprintf("%f", entry.Cost),
+		}); err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
@@ -0,0 +1,97 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/masterv1"
+)
+
+func TestFetchAggregatedResourceCost(t *testing.T) {
+	require.NoError(t, etc.SetRootPath("../static/srv"))
+	pgDB, cleanup := db.MustResolveNewPostgresDatabase(t)
+	defer cleanup()
+	db.MustMigrateTestPostgres(t, pgDB, "file://../static/migrations")
+	api, _, ctx := setupAPITest(t, pgDB)
+
+	at := func(day, hour int) time.Time { return time.Date(2021, 3, day, hour, 0, 0, 0, time.UTC) }
+	pricedPool, unpricedPool := uuid.NewString(), uuid.NewString()
+
+	// The priced pool costs 2.0 per slot-hour from March 1 until its price is removed on March 3.
+	for _, p := range []model.ResourcePoolPrice{
+		{SlotHourPrice: ptrs.Ptr(2.0), Currency: "USD", EffectiveFrom: at(1, 0)},
+		{EffectiveFrom: at(3, 0)},
+	} {
+		p.ResourcePool, p.Source = pricedPool, model.PriceSourceConfig
+		_, err := db.Bun().NewInsert().Model(&p).Exec(ctx)
+		require.NoError(t, err)
+	}
+
+	task := db.RequireMockTask(t, pgDB, nil)
+	for i, a := range []struct {
+		pool       string
+		slots      int
+		start, end time.Time
+	}{
+		// Predates the first price, so it is priced at it.
+		{pricedPool, 1, at(0, 23), at(1, 1)},
+		{pricedPool, 2, at(1, 12), at(2, 0)},
+		// Spans two days.
+		{pricedPool, 1, at(1, 22), at(2, 2)},
+		// Runs after the price was removed, so it is free.
+		{pricedPool, 1, at(3, 6), at(3, 8)},
+		{unpricedPool, 4, at(2, 0), at(2, 1)},
+	} {
+		require.NoError(t, db.AddAllocation(ctx, &model.Allocation{
+			AllocationID: model.AllocationID(fmt.Sprintf("%s.%d", task.TaskID, i)),
+			TaskID:       task.TaskID,
+			Slots:        a.slots,
+			ResourcePool: a.pool,
+			StartTime:    ptrs.Ptr(a.start),
+			EndTime:      ptrs.Ptr(a.end),
+		}))
+	}
+
+	resp, err := api.m.fetchAggregatedResourceCost(ctx, &apiv1.ResourceCostAggregatedRequest{
+		StartDate: "2021-03-01",
+		EndDate:   "2021-03-03",
+		Period:    masterv1.ResourceAllocationAggregationPeriod_RESOURCE_ALLOCATION_AGGREGATION_PERIOD_DAILY,
+		GroupBy:   masterv1.ResourceCostGroupBy_RESOURCE_COST_GROUP_BY_RESOURCE_POOL,
+	})
+	require.NoError(t, err)
+	type entry struct {
+		currency        string
+		slotHours, cost float64
+	}
+	entries := map[string]entry{}
+	for _, e := range resp.Entries {
+		if e.Key == pricedPool || e.Key == unpricedPool {
+			entries[e.PeriodStart+" "+e.Key] = entry{e.Currency, e.SlotHours, e.Cost}
+		}
+	}
+	expected := map[string]entry{
+		"2021-03-01 " + pricedPool:   {"USD", 1 + 24 + 2, 2 * (1 + 24 + 2)},
+		"2021-03-02 " + pricedPool:   {"USD", 2, 2 * 2},
+		"2021-03-02 " + unpricedPool: {"", 4, 0},
+		"2021-03-03 " + pricedPool:   {"", 2, 0},
+	}
+	require.Len(t, entries, len(expected))
+	for k, e := range expected {
+		require.Contains(t, entries, k)
+		require.Equal(t, e.currency, entries[k].currency, k)
+		require.InDelta(t, e.slotHours, entries[k].slotHours, 1e-6, k)
+		require.InDelta(t, e.cost, entries[k].cost, 1e-6, k)
+	}
+}
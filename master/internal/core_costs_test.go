@@ -0,0 +1,45 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/masterv1"
+)
+
+func TestResourceCostRange(t *testing.T) {
+	daily := masterv1.ResourceAllocationAggregationPeriod_RESOURCE_ALLOCATION_AGGREGATION_PERIOD_DAILY
+	monthly := masterv1.ResourceAllocationAggregationPeriod_RESOURCE_ALLOCATION_AGGREGATION_PERIOD_MONTHLY
+
+	start, end, interval, err := resourceCostRange(&apiv1.ResourceCostAggregatedRequest{
+		StartDate: "2024-01-30",
+		EndDate:   "2024-01-31",
+		Period:    daily,
+	})
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 1, 30, 0, 0, 0, 0, time.UTC), start)
+	require.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), end)
+	require.Equal(t, "1 day", interval)
+
+	start, end, interval, err = resourceCostRange(&apiv1.ResourceCostAggregatedRequest{
+		StartDate: "2024-01",
+		EndDate:   "2024-12",
+		Period:    monthly,
+	})
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), start)
+	require.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), end)
+	require.Equal(t, "1 month", interval)
+
+	for _, req := range []*apiv1.ResourceCostAggregatedRequest{
+		{StartDate: "2024-01-01", EndDate: "2024-01-02"},
+		{StartDate: "2024-01", EndDate: "2024-01-02", Period: monthly},
+		{StartDate: "2024-02-01", EndDate: "2024-01-01", Period: daily},
+	} {
+		_, _, _, err := resourceCostRange(req)
+		require.Error(t, err)
+	}
+}
@@ -3,10 +3,13 @@ package db
 import (
 	"context"
 	"fmt"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/determined-ai/determined/master/internal/db/bunutils"
 	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
 )
 
 // RecordAgentStats insert a record of instance start time if instance has not been
@@ -44,3 +47,81 @@ UPDATE agent_stats SET end_time = greatest(cluster_heartbeat, start_time) FROM c
 WHERE end_time IS NULL`)
 	return err
 }
+
+// AddAgentHealthEvents records changes in the health of agents' devices.
+func AddAgentHealthEvents(ctx context.Context, events []*model.AgentHealthEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	if _, err := Bun().NewInsert().Model(&events).Exec(ctx); err != nil {
+		return fmt.Errorf("adding agent health events: %w", err)
+	}
+	return nil
+}
+
+// AgentHealthEvents returns a page of the health events of an agent, most recent first.
+func AgentHealthEvents(
+	ctx context.Context, agentID string, offset, limit int,
+) ([]*model.AgentHealthEvent, *apiv1.Pagination, error) {
+	var events []*model.AgentHealthEvent
+	q := Bun().NewSelect().Model(&events).
+		Where("agent_id = ?", agentID).
+		Order("time DESC", "id DESC")
+	q, pagination, err := bunutils.Paginate(ctx, q, offset, limit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("paginating health events of agent %s: %w", agentID, err)
+	}
+	if err := q.Scan(ctx); err != nil {
+		return nil, nil, fmt.Errorf("getting health events of agent %s: %w", agentID, err)
+	}
+	return events, pagination, nil
+}
+
+// DeleteExpiredAgentHealthEvents deletes the agent health events from before the given time and
+// returns how many were deleted.
+func DeleteExpiredAgentHealthEvents(ctx context.Context, before time.Time) (int64, error) {
+	res, err := Bun().NewDelete().Model((*model.AgentHealthEvent)(nil)).
+		Where("time < ?", before).
+		Exec(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("deleting agent health events before %s: %w", before, err)
+	}
+	return res.RowsAffected()
+}
+
+// AddAgentMaintenanceWindow records a maintenance window of an agent and sets its ID.
+func AddAgentMaintenanceWindow(ctx context.Context, w *model.AgentMaintenanceWindow) error {
+	if _, err := Bun().NewInsert().Model(w).Returning("id").Exec(ctx); err != nil {
+		return fmt.Errorf("adding maintenance window of agent %s: %w", w.AgentID, err)
+	}
+	return nil
+}
+
+// DeleteAgentMaintenanceWindow deletes a maintenance window of an agent.
+func DeleteAgentMaintenanceWindow(ctx context.Context, agentID string, id int) error {
+	res, err := Bun().NewDelete().Model((*model.AgentMaintenanceWindow)(nil)).
+		Where("id = ?", id).
+		Where("agent_id = ?", agentID).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("deleting maintenance window %d of agent %s: %w", id, agentID, err)
+	}
+	return MustHaveAffectedRows(res, ErrNotFound)
+}
+
+// AgentMaintenanceWindows returns the maintenance windows of an agent that end after the given
+// time, earliest first.
+func AgentMaintenanceWindows(
+	ctx context.Context, agentID string, after time.Time,
+) ([]model.AgentMaintenanceWindow, error) {
+	var windows []model.AgentMaintenanceWindow
+	err := Bun().NewSelect().Model(&windows).
+		Where("agent_id = ?", agentID).
+		Where("end_time > ?", after).
+		Order("start_time", "id").
+		Scan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting maintenance windows of agent %s: %w", agentID, err)
+	}
+	return windows, nil
+}
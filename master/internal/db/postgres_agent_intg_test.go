@@ -139,3 +139,43 @@ func TestEndAllAgentStats(t *testing.T) {
 		[]*time.Time{&heartBeatTime, &a1Start, &a2End},
 		[]*time.Time{stats[0].EndTime, stats[1].EndTime, stats[2].EndTime})
 }
+
+func TestAgentHealthEvents(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, etc.SetRootPath(RootFromDB))
+	db, closeDB := MustResolveTestPostgres(t)
+	defer closeDB()
+	MustMigrateTestPostgres(t, db, MigrationsFromDB)
+
+	agentID := uuid.New().String()
+	now := time.Now()
+	var events []*model.AgentHealthEvent
+	for i := 0; i < 3; i++ {
+		events = append(events, &model.AgentHealthEvent{
+			AgentID:  agentID,
+			Time:     now.AddDate(0, 0, -2*i),
+			DeviceID: 0,
+			Healthy:  i%2 == 0,
+		})
+	}
+	require.NoError(t, AddAgentHealthEvents(ctx, events))
+
+	all, pagination, err := AgentHealthEvents(ctx, agentID, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	require.Equal(t, int32(3), pagination.Total)
+
+	page, pagination, err := AgentHealthEvents(ctx, agentID, 1, 1)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	require.Equal(t, all[1].ID, page[0].ID)
+	require.Equal(t, int32(3), pagination.Total)
+
+	deleted, err := DeleteExpiredAgentHealthEvents(ctx, now.AddDate(0, 0, -1))
+	require.NoError(t, err)
+	require.Equal(t, int64(2), deleted)
+	remaining, _, err := AgentHealthEvents(ctx, agentID, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+	require.Equal(t, all[0].ID, remaining[0].ID)
+}
@@ -387,6 +387,59 @@ LIMIT 1`, metricOrdering), exp.Config.Searcher.Metric, id).Scan(ctx, &metric); e
 	return metric, nil
 }
 
+// SearcherObjectiveValidation is a validation with the value it reported for each objective of the
+// experiment's searcher.
+type SearcherObjectiveValidation struct {
+	TrialID      int
+	TotalBatches int
+	Values       []float64
+}
+
+// ExperimentSearcherObjectiveValidations returns the objectives of an experiment's searcher and
+// every validation of the experiment that reported a value for all of them.
+func ExperimentSearcherObjectiveValidations(
+	ctx context.Context, id int,
+) ([]expconf.SearcherMetric, []SearcherObjectiveValidation, error) {
+	exp, err := ExperimentByID(ctx, id)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get experiment config")
+	}
+	objectives := exp.Config.Searcher.Objectives()
+
+	var rows []struct {
+		TrialID      int
+		TotalBatches int
+		Metrics      model.JSONObj
+	}
+	if err := Bun().NewRaw(`
+SELECT v.trial_id, v.total_batches, v.metrics->'validation_metrics' AS metrics
+FROM validations v, trials t
+WHERE v.trial_id = t.id
+  AND t.experiment_id = ?
+ORDER BY v.trial_id, v.total_batches`, id).Scan(ctx, &rows); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get validations")
+	}
+
+	validations := make([]SearcherObjectiveValidation, 0, len(rows))
+	for _, row := range rows {
+		values := make([]float64, 0, len(objectives))
+		for _, objective := range objectives {
+			if value, ok := row.Metrics[objective.Name()].(float64); ok {
+				values = append(values, value)
+			}
+		}
+		if len(values) < len(objectives) {
+			continue
+		}
+		validations = append(validations, SearcherObjectiveValidation{
+			TrialID:      row.TrialID,
+			TotalBatches: row.TotalBatches,
+			Values:       values,
+		})
+	}
+	return objectives, validations, nil
+}
+
 // ExperimentConfigRaw returns the full config object for an experiment as a JSON string.
 func (db *PgDB) ExperimentConfigRaw(id int) ([]byte, error) {
 	return db.rawQuery(`
@@ -419,6 +419,42 @@ func TestExperimentBestSearcherValidation(t *testing.T) {
 	require.InEpsilon(t, float32(5.0), val, 0.01)
 }
 
+func TestExperimentSearcherObjectiveValidations(t *testing.T) {
+	ctx := context.Background()
+
+	require.NoError(t, etc.SetRootPath(RootFromDB))
+	db, closeDB := MustResolveTestPostgres(t)
+	defer closeDB()
+	MustMigrateTestPostgres(t, db, MigrationsFromDB)
+	user := RequireMockUser(t, db)
+
+	exp := RequireMockExperiment(t, db, user)
+	_, err := Bun().NewUpdate().Table("experiments").
+		Set(`config = jsonb_set(config, '{searcher,metrics}',
+			'[{"name": "accuracy", "smaller_is_better": false}, {"name": "latency"}]'::jsonb)`).
+		Where("id = ?", exp.ID).
+		Exec(ctx)
+	require.NoError(t, err)
+
+	t0 := RequireMockTrialID(t, db, exp)
+	addMetrics(ctx, t, db, t0, `[]`,
+		`[{"accuracy": 0.5, "latency": 10.0}, {"accuracy": 0.9, "latency": 20.0}]`, false)
+	// Validations without every objective are left out.
+	t1 := RequireMockTrialID(t, db, exp)
+	addMetrics(ctx, t, db, t1, `[]`, `[{"accuracy": 0.7}]`, false)
+
+	objectives, validations, err := ExperimentSearcherObjectiveValidations(ctx, exp.ID)
+	require.NoError(t, err)
+	require.Equal(t, []expconf.SearcherMetric{
+		{RawName: "accuracy", RawSmallerIsBetter: ptrs.Ptr(false)},
+		{RawName: "latency", RawSmallerIsBetter: ptrs.Ptr(true)},
+	}, objectives)
+	require.Equal(t, []SearcherObjectiveValidation{
+		{TrialID: t0, TotalBatches: 0, Values: []float64{0.5, 10}},
+		{TrialID: t0, TotalBatches: 1, Values: []float64{0.9, 20}},
+	}, validations)
+}
+
 func TestActiveLogPatternPolicies(t *testing.T) {
 	ctx := context.Background()
 	require.NoError(t, etc.SetRootPath(RootFromDB))
@@ -0,0 +1,35 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// RecordResourcePoolPrice records a new price of a resource pool, unless it is the same as the
+// latest recorded price of the resource pool. A removed price, without a slot-hour price, is only
+// recorded for resource pools that have a price.
+func RecordResourcePoolPrice(ctx context.Context, price *model.ResourcePoolPrice) error {
+	if _, err := Bun().NewRaw(`
+WITH latest AS (
+	SELECT slot_hour_price, currency, source FROM resource_pool_prices
+	WHERE resource_pool = ?0
+	ORDER BY effective_from DESC
+	LIMIT 1
+)
+INSERT INTO resource_pool_prices (resource_pool, slot_hour_price, currency, source, effective_from)
+SELECT ?0, ?1, ?2, ?3, ?4
+WHERE NOT EXISTS (
+	SELECT 1 FROM latest
+	WHERE latest.slot_hour_price IS NOT DISTINCT FROM ?1::float8
+		AND latest.currency = ?2 AND latest.source = ?3
+) AND (?1::float8 IS NOT NULL OR EXISTS (
+	SELECT 1 FROM latest WHERE latest.slot_hour_price IS NOT NULL
+))`,
+		price.ResourcePool, price.SlotHourPrice, price.Currency, price.Source, price.EffectiveFrom,
+	).Exec(ctx); err != nil {
+		return fmt.Errorf("recording price of resource pool %s: %w", price.ResourcePool, err)
+	}
+	return nil
+}
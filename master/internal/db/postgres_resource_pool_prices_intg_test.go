@@ -0,0 +1,65 @@
+//go:build integration
+// +build integration
+
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+func TestRecordResourcePoolPrice(t *testing.T) {
+	require.NoError(t, etc.SetRootPath(RootFromDB))
+	db, closeDB := MustResolveTestPostgres(t)
+	defer closeDB()
+	MustMigrateTestPostgres(t, db, MigrationsFromDB)
+	ctx := context.Background()
+
+	pool := uuid.NewString()
+	record := func(price *float64) {
+		p := &model.ResourcePoolPrice{
+			ResourcePool:  pool,
+			SlotHourPrice: price,
+			Source:        model.PriceSourceConfig,
+			EffectiveFrom: time.Now(),
+		}
+		if price != nil {
+			p.Currency = "USD"
+		}
+		require.NoError(t, RecordResourcePoolPrice(ctx, p))
+	}
+	prices := func() []*float64 {
+		var ps []model.ResourcePoolPrice
+		require.NoError(t, Bun().NewSelect().Model(&ps).
+			Where("resource_pool = ?", pool).
+			Order("effective_from").
+			Scan(ctx))
+		var res []*float64
+		for _, p := range ps {
+			res = append(res, p.SlotHourPrice)
+		}
+		return res
+	}
+
+	record(nil)
+	require.Empty(t, prices(), "pools that never had a price record no removal")
+
+	record(ptrs.Ptr(2.0))
+	record(ptrs.Ptr(2.0))
+	require.Equal(t, []*float64{ptrs.Ptr(2.0)}, prices(), "unchanged prices are recorded once")
+
+	record(nil)
+	record(nil)
+	require.Equal(t, []*float64{ptrs.Ptr(2.0), nil}, prices(), "removed prices are recorded once")
+
+	record(ptrs.Ptr(3.0))
+	require.Equal(t, []*float64{ptrs.Ptr(2.0), nil, ptrs.Ptr(3.0)}, prices())
+}
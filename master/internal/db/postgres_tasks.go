@@ -12,6 +12,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
 
 	"github.com/determined-ai/determined/master/internal/api"
 	"github.com/determined-ai/determined/master/pkg/model"
@@ -183,6 +184,20 @@ func SetErrorState(taskID model.TaskID, endTime time.Time) error {
 	return nil
 }
 
+// SetCanceledState sets given task to a CANCELED state.
+func SetCanceledState(taskID model.TaskID, endTime time.Time) error {
+	_, err := Bun().NewUpdate().
+		Table("tasks").
+		Set("task_state = ?", model.TaskStateCanceled).
+		Set("end_time = ?", endTime).
+		Where("task_id = ?", taskID).
+		Exec(context.Background())
+	if err != nil {
+		return errors.Wrap(err, "setting canceled task state")
+	}
+	return nil
+}
+
 // AddAllocation upserts the existence of an allocation. Allocation IDs may conflict in the event
 // the master restarts and the trial run ID increment is not persisted, but it is the same
 // allocation so this is OK.
@@ -368,6 +383,66 @@ func RecordTaskEndStats(ctx context.Context, stats *model.TaskStats) error {
 	return nil
 }
 
+// PeakSlotDemand returns, for each window of the given length starting at one of starts, the
+// peak number of slots that were demanded at once by the allocations of a resource pool. An
+// allocation demands its slots from when it is queued until it ends. Allocations that were queued
+// before the earliest window are not counted.
+func PeakSlotDemand(
+	ctx context.Context, resourcePool string, starts []time.Time, length time.Duration,
+) ([]int, error) {
+	if len(starts) == 0 {
+		return nil, nil
+	}
+	from, to := starts[0], starts[0]
+	for _, start := range starts {
+		if start.Before(from) {
+			from = start
+		}
+		if start.After(to) {
+			to = start
+		}
+	}
+
+	windows := Bun().NewSelect().
+		ColumnExpr("w.i").
+		ColumnExpr("w.window_start").
+		ColumnExpr("w.window_start + make_interval(secs => ?) AS window_end", length.Seconds()).
+		TableExpr("unnest(?::timestamptz[]) WITH ORDINALITY AS w(window_start, i)", pgdialect.Array(starts))
+	demand := Bun().NewSelect().
+		ColumnExpr("ts.start_time AS demand_start").
+		ColumnExpr("coalesce(a.end_time, now()) AS demand_end").
+		ColumnExpr("a.slots").
+		TableExpr("task_stats ts").
+		Join("INNER JOIN allocations a ON a.allocation_id = ts.allocation_id").
+		Where("ts.event_type = ?", "QUEUED").
+		Where("ts.start_time >= ? AND ts.start_time < ?", from, to.Add(length)).
+		Where("a.resource_pool = ?", resourcePool)
+	// The demand within a window peaks at its start or when an allocation is queued.
+	points := Bun().NewSelect().
+		ColumnExpr("w.i, w.window_start AS t").
+		TableExpr("windows w").
+		UnionAll(Bun().NewSelect().
+			ColumnExpr("w.i, d.demand_start AS t").
+			TableExpr("windows w").
+			Join("INNER JOIN demand d ON d.demand_start > w.window_start AND d.demand_start < w.window_end"))
+
+	var slots []int
+	if err := Bun().NewSelect().
+		With("windows", windows).
+		With("demand", demand).
+		With("points", points).
+		ColumnExpr(`max((
+			SELECT coalesce(sum(d.slots), 0) FROM demand d WHERE d.demand_start <= p.t AND d.demand_end > p.t
+		))`).
+		TableExpr("points p").
+		GroupExpr("p.i").
+		OrderExpr("p.i").
+		Scan(ctx, &slots); err != nil {
+		return nil, fmt.Errorf("fetching slot demand of resource pool %s: %w", resourcePool, err)
+	}
+	return slots, nil
+}
+
 // EndAllTaskStats called at master starts, in case master previously crashed.
 func EndAllTaskStats(ctx context.Context) error {
 	_, err := Bun().NewRaw(`UPDATE task_stats 
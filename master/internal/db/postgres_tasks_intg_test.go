@@ -179,6 +179,60 @@ func TestRecordAndEndTaskStats(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestPeakSlotDemand(t *testing.T) {
+	ctx := context.Background()
+	pgDB, closeDB := MustResolveTestPostgres(t)
+	defer closeDB()
+	MustMigrateTestPostgres(t, pgDB, MigrationsFromDB)
+
+	pool, otherPool := uuid.NewString(), uuid.NewString()
+	base := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	queue := func(pool string, slots int, queued, end time.Duration, running bool) {
+		tID := model.NewTaskID()
+		require.NoError(t, AddTask(ctx, &model.Task{
+			TaskID:    tID,
+			TaskType:  model.TaskTypeCommand,
+			StartTime: base.Add(queued),
+		}))
+		allocationID := model.AllocationID(tID + ".1")
+		alloc := &model.Allocation{
+			TaskID:       tID,
+			AllocationID: allocationID,
+			ResourcePool: pool,
+			Slots:        slots,
+			StartTime:    ptrs.Ptr(base.Add(queued)),
+		}
+		if !running {
+			alloc.EndTime = ptrs.Ptr(base.Add(end))
+		}
+		require.NoError(t, AddAllocation(ctx, alloc))
+		require.NoError(t, RecordTaskStats(ctx, &model.TaskStats{
+			AllocationID: allocationID,
+			EventType:    "QUEUED",
+			StartTime:    ptrs.Ptr(base.Add(queued)),
+		}))
+	}
+
+	// Queued before the earliest window, so it is not counted.
+	queue(pool, 4, -10*time.Minute, 30*time.Minute, false)
+	// In the first window, these overlap for 5 slots and then 4 slots.
+	queue(pool, 2, 10*time.Minute, 40*time.Minute, false)
+	queue(pool, 3, 20*time.Minute, 50*time.Minute, false)
+	queue(pool, 1, 45*time.Minute, 2*time.Hour, false)
+	// In the second window, an allocation that is still running overlaps another for 3 slots.
+	queue(pool, 2, 23*time.Hour+30*time.Minute, 0, true)
+	queue(pool, 1, 24*time.Hour+10*time.Minute, 24*time.Hour+20*time.Minute, false)
+	queue(otherPool, 10, 24*time.Hour+15*time.Minute, 25*time.Hour, false)
+
+	demand, err := PeakSlotDemand(ctx, pool, []time.Time{base.Add(24 * time.Hour), base}, time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, []int{3, 5}, demand)
+
+	demand, err = PeakSlotDemand(ctx, uuid.NewString(), []time.Time{base}, time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, []int{0}, demand)
+}
+
 func TestNonExperimentTasksContextDirectory(t *testing.T) {
 	ctx := context.Background()
 	pgDB, closeDB := MustResolveTestPostgres(t)
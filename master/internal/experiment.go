@@ -22,6 +22,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/configpolicy"
 	internaldb "github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/experiment"
+	"github.com/determined-ai/determined/master/internal/job/dependency"
 	"github.com/determined-ai/determined/master/internal/job/jobservice"
 	"github.com/determined-ai/determined/master/internal/rm"
 	"github.com/determined-ai/determined/master/internal/rm/rmerrors"
@@ -87,14 +88,15 @@ func resolveWorkspaceID(workspace *model.Workspace) int {
 }
 
 // Create a new experiment object from the given model experiment object, along with its searcher
-// and log. If the input object has no ID set, also create a new experiment in the database and set
-// the returned object's ID appropriately.
+// and log. If the input object has no ID set, also create a new experiment in the database, along
+// with its dependencies, and set the returned object's ID appropriately.
 func newExperiment(
 	m *Master,
 	expModel *model.Experiment,
 	modelDef []byte,
 	activeConfig expconf.ExperimentConfig,
 	taskSpec *tasks.TaskSpec,
+	deps []model.JobDependency,
 ) (*internalExperiment, []command.LaunchWarning, error) {
 	if len(modelDef) > 0 && expModel.ID != 0 {
 		return nil, nil, fmt.Errorf("experiments restoring should not provide a model def")
@@ -143,7 +145,12 @@ func newExperiment(
 	}
 
 	if expModel.ID == 0 {
-		if err = m.db.AddExperiment(expModel, modelDef, activeConfig); err != nil {
+		if err = internaldb.Bun().RunInTx(context.TODO(), nil, func(ctx context.Context, tx bun.Tx) error {
+			if err := internaldb.AddExperimentTx(ctx, tx, expModel, modelDef, activeConfig, false); err != nil {
+				return err
+			}
+			return dependency.AddDependenciesTx(ctx, tx, expModel.JobID, deps)
+		}); err != nil {
 			return nil, launchWarnings, err
 		}
 		telemetry.ReportExperimentCreated(expModel.ID, activeConfig)
@@ -298,9 +305,44 @@ func (e *internalExperiment) start() error {
 				InformationalReason: "resending stopping state signal on restore",
 			})
 		}
+
+		// An experiment that was waiting on its dependencies has no trials yet and waits again.
+		if model.StoppingStates[e.State] {
+			return nil
+		}
+		hasTrials, err := internaldb.Bun().NewSelect().Table("trials").
+			Where("experiment_id = ?", e.ID).
+			Exists(context.TODO())
+		if err != nil {
+			return fmt.Errorf("checking whether experiment %d has trials: %w", e.ID, err)
+		}
+		if !hasTrials {
+			if _, err := dependency.DefaultService.Wait(
+				context.TODO(), e.JobID, e.activeConfig.Resources().ResourcePool(), e.dependenciesResolved,
+			); err != nil {
+				return err
+			}
+		}
 		return nil
 	}
 
+	waiting, err := dependency.DefaultService.Wait(
+		context.TODO(), e.JobID, e.activeConfig.Resources().ResourcePool(), e.dependenciesResolved,
+	)
+	if err != nil {
+		e.updateState(model.StateWithReason{
+			State:               model.StoppingErrorState,
+			InformationalReason: err.Error(),
+		})
+		return err
+	}
+	if waiting {
+		return nil
+	}
+	return e.createInitialTrials()
+}
+
+func (e *internalExperiment) createInitialTrials() error {
 	creates, err := e.searcher.InitialTrials()
 	if err != nil {
 		err = errors.Wrap(err, "failed to generate initial operations")
@@ -315,6 +357,27 @@ func (e *internalExperiment) start() error {
 	return nil
 }
 
+// dependenciesResolved creates the initial trials once the jobs that the experiment depends on
+// have finished, or cancels the experiment if one of them did not finish as required.
+func (e *internalExperiment) dependenciesResolved(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if model.StoppingStates[e.State] || model.TerminalStates[e.State] {
+		return
+	}
+	if err != nil {
+		e.updateState(model.StateWithReason{
+			State:               model.StoppingCanceledState,
+			InformationalReason: err.Error(),
+		})
+		return
+	}
+	if err := e.createInitialTrials(); err != nil {
+		e.syslog.WithError(err).Error("failed to create initial trials after dependencies were met")
+	}
+}
+
 func (e *internalExperiment) TrialReportProgress(requestID model.RequestID, msg experiment.TrialReportProgress) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
@@ -404,6 +467,7 @@ func (e *internalExperiment) SetGroupPriority(priority int) error {
 
 func (e *internalExperiment) stop() error {
 	e.unregister()
+	dependency.DefaultService.Forget(e.JobID)
 
 	if err := tasklist.GroupPriorityChangeRegistry.Delete(e.JobID); err != nil {
 		e.syslog.WithError(err).Error("failed to remove priority change registry")
@@ -434,6 +498,7 @@ func (e *internalExperiment) stop() error {
 		return err
 	}
 	e.syslog.Infof("PostStop state changed to %s", e.State)
+	dependency.DefaultService.JobFinished(e.JobID)
 
 	taskSpec, err := e.taskSpec.Clone()
 	if err != nil {
@@ -608,7 +673,16 @@ func (e *internalExperiment) trialCreated(t *trial) {
 // last experiment checkpoint.
 func (e *internalExperiment) restoreTrials() {
 	for _, state := range e.TrialSearcherState {
-		e.restoreTrial(e.warmStartCheckpoint, state)
+		ckpt, err := e.searcherWarmStartCheckpoint(state.Create)
+		if err != nil {
+			e.syslog.WithField("request-id", state.Create.RequestID).WithError(err).
+				Error("failed restoring trial, aborting restore")
+			if !e.searcher.TrialIsClosed(state.Create.RequestID) {
+				e.trialExited(state.Create.RequestID, ptrs.Ptr(model.Errored))
+			}
+			continue
+		}
+		e.restoreTrial(ckpt, state)
 	}
 }
 
@@ -658,10 +732,24 @@ func (e *internalExperiment) handleSearcherActions(
 				e.trialExited(action.RequestID, ptrs.Ptr(model.Errored))
 				continue
 			}
+			ckpt, err := e.searcherWarmStartCheckpoint(action)
+			if err != nil {
+				e.syslog.WithError(err).Error("failed to create trial")
+				e.trialExited(action.RequestID, ptrs.Ptr(model.Errored))
+				continue
+			}
+			if action.Checkpoint != nil {
+				// Record the checkpoint copied, so that the trial restores from it.
+				state.Create.Checkpoint = &searcher.Checkpoint{
+					RequestID: action.Checkpoint.RequestID,
+					UUID:      ckpt.UUID,
+				}
+				e.TrialSearcherState[action.RequestID] = state
+			}
 
 			t, err := newTrial(
 				e.logCtx, trialTaskID(e.ID, action.RequestID), e.JobID, e.StartTime, e.ID, e.State,
-				state, e.rm, e.db, config, e.warmStartCheckpoint, clonedSpec, e.generatedKeys, false,
+				state, e.rm, e.db, config, ckpt, clonedSpec, e.generatedKeys, false,
 				nil, continueFromTrialID, e.TrialExited,
 			)
 			if err != nil {
@@ -837,6 +925,43 @@ func (e *internalExperiment) restore(experimentSnapshot json.RawMessage) error {
 	return nil
 }
 
+// searcherWarmStartCheckpoint returns the checkpoint a trial created by the searcher starts from.
+// If the searcher asked for the trial to continue from another trial, such as when population based
+// training copies a better trial, that is the latest checkpoint of the other trial, and it is an
+// error if there is none, since starting over would not copy the other trial. Once the trial has
+// been created, it is the checkpoint recorded then, so a restored trial does not pick up a later
+// checkpoint of the other trial. Otherwise it is the experiment's warm start checkpoint.
+func (e *internalExperiment) searcherWarmStartCheckpoint(
+	create searcher.Create,
+) (*model.Checkpoint, error) {
+	if create.Checkpoint == nil {
+		return e.warmStartCheckpoint, nil
+	}
+	if create.Checkpoint.UUID != nil {
+		checkpoint, err := checkpoints.CheckpointByUUID(context.TODO(), *create.Checkpoint.UUID)
+		switch {
+		case err != nil:
+			return nil, errors.Wrapf(err, "failed to find checkpoint %s to warm start from", *create.Checkpoint.UUID)
+		case checkpoint == nil:
+			return nil, errors.Errorf("checkpoint %s to warm start from not found", *create.Checkpoint.UUID)
+		}
+		return checkpoint, nil
+	}
+
+	parent, err := internaldb.TrialByExperimentAndRequestID(context.TODO(), e.ID, create.Checkpoint.RequestID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find trial %s to warm start from", create.Checkpoint.RequestID)
+	}
+	checkpoint, err := e.db.LatestCheckpointForTrial(parent.ID)
+	switch {
+	case err != nil:
+		return nil, errors.Wrapf(err, "failed to find a checkpoint of trial %d to warm start from", parent.ID)
+	case checkpoint == nil:
+		return nil, errors.Errorf("trial %d has no checkpoint to warm start from", parent.ID)
+	}
+	return checkpoint, nil
+}
+
 func checkpointFromTrialIDOrUUID(
 	db *internaldb.PgDB, trialID *int, checkpointUUIDStr *string,
 ) (*model.Checkpoint, error) {
@@ -0,0 +1,115 @@
+//go:build integration
+// +build integration
+
+package internal
+
+import (
+	"context"
+	"crypto/rand"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+	"github.com/determined-ai/determined/master/pkg/searcher"
+	"github.com/determined-ai/determined/master/pkg/tasks"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/utilv1"
+)
+
+func TestSearcherWarmStartCheckpoint(t *testing.T) {
+	require.NoError(t, etc.SetRootPath("../static/srv"))
+	pgDB, cleanup := db.MustResolveNewPostgresDatabase(t)
+	defer cleanup()
+	db.MustMigrateTestPostgres(t, pgDB, "file://../static/migrations")
+
+	user := db.RequireMockUser(t, pgDB)
+	exp := db.RequireMockExperiment(t, pgDB, user)
+	//nolint:exhaustruct
+	e := &internalExperiment{
+		Experiment:          exp,
+		db:                  pgDB,
+		warmStartCheckpoint: &model.Checkpoint{ID: 1},
+	}
+
+	// Trials that do not copy another trial start from the experiment's warm start checkpoint.
+	ckpt, err := e.searcherWarmStartCheckpoint(searcher.Create{})
+	require.NoError(t, err)
+	require.Equal(t, e.warmStartCheckpoint, ckpt)
+
+	_, err = e.searcherWarmStartCheckpoint(searcher.Create{
+		Checkpoint: &searcher.Checkpoint{RequestID: model.NewRequestID(rand.Reader)},
+	})
+	require.ErrorContains(t, err, "failed to find trial")
+
+	// A copy of a trial without a checkpoint cannot start over instead.
+	parent, task := db.RequireMockTrial(t, pgDB, exp)
+	create := searcher.Create{Checkpoint: &searcher.Checkpoint{RequestID: *parent.RequestID}}
+	_, err = e.searcherWarmStartCheckpoint(create)
+	require.ErrorContains(t, err, "has no checkpoint to warm start from")
+
+	checkpoint := db.MockModelCheckpoint(uuid.New(), db.RequireMockAllocation(t, pgDB, task.TaskID))
+	require.NoError(t, db.AddCheckpointMetadata(context.Background(), &checkpoint, parent.ID))
+	ckpt, err = e.searcherWarmStartCheckpoint(create)
+	require.NoError(t, err)
+	require.Equal(t, checkpoint.UUID, *ckpt.UUID)
+
+	// Once created, a copy restores from the checkpoint it copied, not a later one.
+	create.Checkpoint.UUID = &checkpoint.UUID
+	later := db.MockModelCheckpoint(uuid.New(), db.RequireMockAllocation(t, pgDB, task.TaskID))
+	require.NoError(t, db.AddCheckpointMetadata(context.Background(), &later, parent.ID))
+	ckpt, err = e.searcherWarmStartCheckpoint(create)
+	require.NoError(t, err)
+	require.Equal(t, checkpoint.UUID, *ckpt.UUID)
+
+	_, err = e.searcherWarmStartCheckpoint(searcher.Create{
+		Checkpoint: &searcher.Checkpoint{RequestID: *parent.RequestID, UUID: ptrs.Ptr(uuid.New())},
+	})
+	require.ErrorContains(t, err, "to warm start from not found")
+}
+
+func TestNewExperimentDependencies(t *testing.T) {
+	api, curUser, ctx := setupAPITest(t, nil)
+	upstream := createTestExp(t, api, curUser)
+
+	parse := func() (*model.Experiment, []byte, expconf.ExperimentConfig, *tasks.TaskSpec) {
+		dbExp, modelDef, activeConfig, _, taskSpec, err := api.m.parseCreateExperiment(ctx,
+			&apiv1.CreateExperimentRequest{
+				ModelDefinition: []*utilv1.File{{Content: []byte{1}}},
+				Config:          minExpConfToYaml(t),
+				ProjectId:       1,
+			}, &curUser)
+		require.NoError(t, err)
+		return dbExp, modelDef, activeConfig, taskSpec
+	}
+	jobExists := func(jobID model.JobID) bool {
+		exists, err := db.Bun().NewSelect().Table("jobs").Where("job_id = ?", jobID).Exists(ctx)
+		require.NoError(t, err)
+		return exists
+	}
+
+	// The dependencies are written with the experiment.
+	dbExp, modelDef, activeConfig, taskSpec := parse()
+	deps := []model.JobDependency{{DependsOn: upstream.JobID, Condition: model.JobDependencySuccess}}
+	_, _, err := newExperiment(api.m, dbExp, modelDef, activeConfig, taskSpec, deps)
+	require.NoError(t, err)
+	var written []model.JobDependency
+	require.NoError(t, db.Bun().NewSelect().Model(&written).Where("job_id = ?", dbExp.JobID).Scan(ctx))
+	require.Equal(t, []model.JobDependency{{
+		JobID: dbExp.JobID, DependsOn: upstream.JobID, Condition: model.JobDependencySuccess,
+	}}, written)
+
+	// If they cannot be written, neither are the experiment and its job.
+	dbExp, modelDef, activeConfig, taskSpec = parse()
+	_, _, err = newExperiment(api.m, dbExp, modelDef, activeConfig, taskSpec, []model.JobDependency{
+		{DependsOn: upstream.JobID, Condition: model.JobDependencySuccess},
+		{DependsOn: upstream.JobID, Condition: model.JobDependencyAnyExit},
+	})
+	require.Error(t, err)
+	require.False(t, jobExists(dbExp.JobID))
+}
@@ -110,6 +110,12 @@ func getAllocationSessionBun(ctx context.Context) (*model.AllocationSession, err
 	}
 }
 
+// WithUser returns a context in which GetUser returns the given user without a session. It is for
+// requests that the master makes on behalf of a user, such as launching scheduled jobs.
+func WithUser(ctx context.Context, user *model.User) context.Context {
+	return context.WithValue(ctx, userContextKey{}, user)
+}
+
 // GetUser returns the currently logged in user.
 func GetUser(ctx context.Context) (*model.User, *model.UserSession, error) {
 	if user, ok := ctx.Value(userContextKey{}).(*model.User); ok {
@@ -0,0 +1,433 @@
+// Package dependency holds jobs back from the resource manager until the jobs that they depend on
+// have finished.
+package dependency
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/uptrace/bun"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/jobv1"
+)
+
+// Service holds jobs that wait on their dependencies. Jobs are checked when they start to wait and
+// whenever a job that they depend on finishes.
+type Service struct {
+	mu      sync.Mutex
+	waiters map[model.JobID]*waiter
+	syslog  *logrus.Entry
+
+	// outcomes looks up the outcomes of jobs. Jobs that do not exist are left out.
+	outcomes func(ctx context.Context, jobIDs []model.JobID) (map[model.JobID]model.JobOutcome, error)
+}
+
+type waiter struct {
+	resourcePool string
+	deps         []model.JobDependency
+	since        time.Time
+	onResolve    func(error)
+}
+
+// DefaultService is the global singleton dependency service.
+var DefaultService = NewService()
+
+// NewService returns a new dependency service that looks up jobs in the database.
+func NewService() *Service {
+	return &Service{
+		waiters:  make(map[model.JobID]*waiter),
+		syslog:   logrus.WithField("component", "job-dependencies"),
+		outcomes: JobOutcomes,
+	}
+}
+
+// JobFilter filters jobs down to the ones that a user can view. It is implemented by job.JobAuthZ,
+// which this package cannot import.
+type JobFilter interface {
+	FilterJobs(ctx context.Context, curUser model.User, jobs []*jobv1.Job) ([]*jobv1.Job, error)
+}
+
+// FromProto returns the dependencies of a new job from their proto representation, after checking
+// that the jobs it depends on exist and that the user can view them. Jobs that the user cannot
+// view are reported as not found, the same as jobs that do not exist. Errors are InvalidArgument.
+func FromProto(
+	ctx context.Context, curUser model.User, filter JobFilter, deps []*jobv1.JobDependency,
+) ([]model.JobDependency, error) {
+	if len(deps) == 0 {
+		return nil, nil
+	}
+
+	res := make([]model.JobDependency, 0, len(deps))
+	upstream := make([]model.JobID, 0, len(deps))
+	for _, dep := range deps {
+		dependsOn := model.JobID(dep.JobId)
+		switch {
+		case dependsOn == "":
+			return nil, status.Error(codes.InvalidArgument, "job dependency must set a job ID")
+		case slices.Contains(upstream, dependsOn):
+			return nil, status.Errorf(codes.InvalidArgument, "job %s is a dependency more than once", dependsOn)
+		}
+		upstream = append(upstream, dependsOn)
+		res = append(res, model.JobDependency{
+			DependsOn: dependsOn,
+			Condition: model.JobDependencyConditionFromProto(dep.Condition),
+		})
+	}
+
+	jobs, err := upstreamJobs(ctx, upstream)
+	if err != nil {
+		return nil, err
+	}
+	if jobs, err = filter.FilterJobs(ctx, curUser, jobs); err != nil {
+		return nil, fmt.Errorf("filtering the jobs to depend on: %w", err)
+	}
+	for _, dependsOn := range upstream {
+		if !slices.ContainsFunc(jobs, func(j *jobv1.Job) bool { return j.JobId == string(dependsOn) }) {
+			return nil, status.Errorf(codes.InvalidArgument, "job %s to depend on not found", dependsOn)
+		}
+	}
+	return res, nil
+}
+
+// upstreamJobs returns the jobs with the given IDs that exist, with the type and workspace that
+// authorization needs. Experiments are in the workspace of their project, and commands and generic
+// tasks in the workspace of their spec.
+func upstreamJobs(ctx context.Context, jobIDs []model.JobID) ([]*jobv1.Job, error) {
+	var rows []struct {
+		JobID       model.JobID
+		JobType     model.JobType
+		WorkspaceID *int32
+	}
+	if err := db.Bun().NewSelect().
+		TableExpr("jobs AS j").
+		ColumnExpr("j.job_id, j.job_type").
+		ColumnExpr(`COALESCE(p.workspace_id,
+			(c.generic_command_spec->'Metadata'->>'workspace_id')::int,
+			(c.generic_task_spec->>'WorkspaceID')::int) AS workspace_id`).
+		Join("LEFT JOIN experiments AS e ON e.job_id = j.job_id").
+		Join("LEFT JOIN projects AS p ON p.id = e.project_id").
+		Join(`LEFT JOIN LATERAL (
+			SELECT c.generic_command_spec, c.generic_task_spec
+			FROM command_state AS c JOIN tasks AS t ON t.task_id = c.task_id
+			WHERE t.job_id = j.job_id
+			LIMIT 1
+		) AS c ON true`).
+		Where("j.job_id IN (?)", bun.In(jobIDs)).
+		Scan(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("looking up the jobs to depend on: %w", err)
+	}
+
+	jobs := make([]*jobv1.Job, 0, len(rows))
+	for _, r := range rows {
+		j := &jobv1.Job{JobId: string(r.JobID), Type: r.JobType.Proto()}
+		if r.WorkspaceID != nil {
+			j.WorkspaceId = *r.WorkspaceID
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs, nil
+}
+
+// AddDependenciesTx persists the dependencies of a job.
+func AddDependenciesTx(
+	ctx context.Context, idb bun.IDB, jobID model.JobID, deps []model.JobDependency,
+) error {
+	if len(deps) == 0 {
+		return nil
+	}
+	for i := range deps {
+		deps[i].JobID = jobID
+	}
+	if _, err := idb.NewInsert().Model(&deps).Exec(ctx); err != nil {
+		return fmt.Errorf("adding job dependencies: %w", err)
+	}
+	return nil
+}
+
+// EndUnrestorableTasks ends the commands and generic tasks that were waiting on their dependencies
+// when the master stopped, but were not persisted to wait again, e.g., since an older master
+// started them. Waiting tasks that were persisted are restored and wait again.
+func EndUnrestorableTasks(ctx context.Context) error {
+	res, err := db.Bun().NewUpdate().Table("tasks").
+		Set("end_time = ?", time.Now().UTC()).
+		Set("task_state = (CASE WHEN task_type = ? THEN ?::task_state ELSE task_state END)",
+			model.TaskTypeGeneric, model.TaskStateCanceled).
+		Where("end_time IS NULL").
+		Where("task_type NOT IN (?)", bun.In([]model.TaskType{
+			model.TaskTypeTrial, model.TaskTypeCheckpointGC,
+		})).
+		Where("job_id IN (SELECT job_id FROM job_dependencies)").
+		Where("NOT EXISTS (SELECT 1 FROM allocations a WHERE a.task_id = tasks.task_id)").
+		Where("NOT EXISTS (SELECT 1 FROM command_state c WHERE c.task_id = tasks.task_id)").
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("ending tasks that were waiting on dependencies: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n > 0 {
+		logrus.Infof("ended %d tasks that were waiting on dependencies and cannot be restored", n)
+	}
+	return nil
+}
+
+// Wait holds the job back if it has dependencies and returns whether it does. Once the
+// dependencies are met, onResolve is called from another goroutine with a nil error. If one of
+// them can no longer be met, or the wait is canceled, it is called with an error that says why.
+func (s *Service) Wait(
+	ctx context.Context, jobID model.JobID, resourcePool string, onResolve func(error),
+) (bool, error) {
+	var deps []model.JobDependency
+	if err := db.Bun().NewSelect().Model(&deps).
+		Where("job_id = ?", jobID).
+		Scan(ctx); err != nil {
+		return false, fmt.Errorf("getting dependencies of job %s: %w", jobID, err)
+	}
+	if len(deps) == 0 {
+		return false, nil
+	}
+
+	s.hold(jobID, resourcePool, deps, onResolve)
+	return true, nil
+}
+
+func (s *Service) hold(
+	jobID model.JobID, resourcePool string, deps []model.JobDependency, onResolve func(error),
+) {
+	s.mu.Lock()
+	s.waiters[jobID] = &waiter{
+		resourcePool: resourcePool,
+		deps:         deps,
+		since:        time.Now(),
+		onResolve:    onResolve,
+	}
+	s.mu.Unlock()
+
+	s.syslog.WithField("job-id", jobID).Infof("waiting on %d dependencies", len(deps))
+	go s.check(jobID)
+}
+
+// JobFinished checks the jobs that depend on a job that has finished.
+func (s *Service) JobFinished(jobID model.JobID) {
+	s.mu.Lock()
+	var dependents []model.JobID
+	for id, w := range s.waiters {
+		for _, dep := range w.deps {
+			if dep.DependsOn == jobID {
+				dependents = append(dependents, id)
+				break
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	if len(dependents) > 0 {
+		go s.check(dependents...)
+	}
+}
+
+// Cancel stops holding the job back and calls its callback with an error with the reason. It
+// returns whether the job was waiting.
+func (s *Service) Cancel(jobID model.JobID, reason string) bool {
+	w := s.release(jobID, nil)
+	if w == nil {
+		return false
+	}
+	go w.onResolve(fmt.Errorf("%s while waiting on dependencies", reason))
+	return true
+}
+
+// Forget stops holding the job back without calling its callback.
+func (s *Service) Forget(jobID model.JobID) {
+	s.release(jobID, nil)
+}
+
+// IsWaiting returns whether the job waits on its dependencies.
+func (s *Service) IsWaiting(jobID model.JobID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.waiters[jobID]
+	return ok
+}
+
+// Waiting returns the jobs of a resource pool that wait on their dependencies, in the order in
+// which they started to wait.
+func (s *Service) Waiting(resourcePool string) []model.JobID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var jobIDs []model.JobID
+	for jobID, w := range s.waiters {
+		if w.resourcePool == resourcePool {
+			jobIDs = append(jobIDs, jobID)
+		}
+	}
+	sort.Slice(jobIDs, func(i, j int) bool {
+		return s.waiters[jobIDs[i]].since.Before(s.waiters[jobIDs[j]].since)
+	})
+	return jobIDs
+}
+
+// release removes the waiter of the job, if it is still the given waiter or the given waiter is
+// nil, and returns it.
+func (s *Service) release(jobID model.JobID, expected *waiter) *waiter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, ok := s.waiters[jobID]
+	if !ok || (expected != nil && w != expected) {
+		return nil
+	}
+	delete(s.waiters, jobID)
+	return w
+}
+
+func (s *Service) check(jobIDs ...model.JobID) {
+	for _, jobID := range jobIDs {
+		s.mu.Lock()
+		w, ok := s.waiters[jobID]
+		s.mu.Unlock()
+		if !ok {
+			continue
+		}
+
+		upstream := make([]model.JobID, 0, len(w.deps))
+		for _, dep := range w.deps {
+			upstream = append(upstream, dep.DependsOn)
+		}
+		outcomes, err := s.outcomes(context.TODO(), upstream)
+		if err != nil {
+			s.syslog.WithError(err).WithField("job-id", jobID).Error("checking dependencies")
+			continue
+		}
+
+		met, err := resolve(w.deps, outcomes)
+		if !met && err == nil {
+			continue
+		}
+		if s.release(jobID, w) == nil {
+			continue
+		}
+		if err != nil {
+			s.syslog.WithField("job-id", jobID).WithError(err).Info("dependencies can no longer be met")
+		} else {
+			s.syslog.WithField("job-id", jobID).Info("dependencies met")
+		}
+		w.onResolve(err)
+	}
+}
+
+// resolve returns whether all the dependencies are met, or an error if one of them can no longer
+// be met.
+func resolve(deps []model.JobDependency, outcomes map[model.JobID]model.JobOutcome) (bool, error) {
+	met := true
+	for _, dep := range deps {
+		outcome, ok := outcomes[dep.DependsOn]
+		switch {
+		case !ok:
+			return false, fmt.Errorf("job %s that this job depends on no longer exists", dep.DependsOn)
+		case outcome == model.JobOutcomeRunning:
+			met = false
+		case outcome == model.JobOutcomeFailed && dep.Condition != model.JobDependencyAnyExit:
+			return false, fmt.Errorf("job %s that this job depends on did not succeed", dep.DependsOn)
+		}
+	}
+	return met, nil
+}
+
+// JobOutcomes looks up the outcomes of experiments, and of commands and generic tasks, by job.
+func JobOutcomes(
+	ctx context.Context, jobIDs []model.JobID,
+) (map[model.JobID]model.JobOutcome, error) {
+	outcomes := make(map[model.JobID]model.JobOutcome, len(jobIDs))
+
+	var experiments []struct {
+		JobID model.JobID
+		State model.State
+	}
+	if err := db.Bun().NewSelect().Table("experiments").
+		Column("job_id", "state").
+		Where("job_id IN (?)", bun.In(jobIDs)).
+		Scan(ctx, &experiments); err != nil {
+		return nil, fmt.Errorf("getting experiment states: %w", err)
+	}
+	for _, e := range experiments {
+		outcomes[e.JobID] = experimentOutcome(e.State)
+	}
+
+	var tasks []struct {
+		JobID        model.JobID
+		EndTime      *time.Time
+		TaskState    *model.TaskState
+		AllocationID *model.AllocationID
+		ExitErr      *string
+	}
+	if err := db.Bun().NewSelect().TableExpr("tasks t").
+		ColumnExpr("t.job_id").
+		ColumnExpr("t.end_time").
+		ColumnExpr("t.task_state").
+		ColumnExpr("a.allocation_id").
+		ColumnExpr("a.exit_error AS exit_err").
+		Join(`LEFT JOIN LATERAL (
+			SELECT allocation_id, exit_error FROM allocations
+			WHERE task_id = t.task_id
+			ORDER BY start_time DESC NULLS LAST
+			LIMIT 1
+		) a ON true`).
+		Where("t.job_id IN (?)", bun.In(jobIDs)).
+		Where("t.task_type NOT IN (?)", bun.In([]model.TaskType{
+			model.TaskTypeTrial, model.TaskTypeCheckpointGC,
+		})).
+		Scan(ctx, &tasks); err != nil {
+		return nil, fmt.Errorf("getting task states: %w", err)
+	}
+	for _, t := range tasks {
+		if _, ok := outcomes[t.JobID]; !ok {
+			outcomes[t.JobID] = taskOutcome(t.EndTime != nil, t.TaskState, t.AllocationID != nil, t.ExitErr)
+		}
+	}
+	return outcomes, nil
+}
+
+func experimentOutcome(state model.State) model.JobOutcome {
+	switch {
+	case state == model.CompletedState:
+		return model.JobOutcomeSucceeded
+	case model.TerminalStates[state], state == model.DeletingState, state == model.DeleteFailedState:
+		return model.JobOutcomeFailed
+	default:
+		return model.JobOutcomeRunning
+	}
+}
+
+// taskOutcome returns the outcome of a generic task from its state, or of a command from whether
+// its allocation exited with an error. A command that ended before it was allocated failed.
+func taskOutcome(
+	ended bool, state *model.TaskState, allocated bool, exitErr *string,
+) model.JobOutcome {
+	if state != nil {
+		switch *state {
+		case model.TaskStateCompleted:
+			return model.JobOutcomeSucceeded
+		case model.TaskStateError, model.TaskStateCanceled:
+			return model.JobOutcomeFailed
+		default:
+			return model.JobOutcomeRunning
+		}
+	}
+	switch {
+	case !ended:
+		return model.JobOutcomeRunning
+	case !allocated, exitErr != nil:
+		return model.JobOutcomeFailed
+	default:
+		return model.JobOutcomeSucceeded
+	}
+}
@@ -0,0 +1,68 @@
+//go:build integration
+// +build integration
+
+package dependency
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/jobv1"
+)
+
+// workspaceFilter lets users view the jobs in its workspaces only.
+type workspaceFilter map[int32]bool
+
+func (f workspaceFilter) FilterJobs(
+	ctx context.Context, curUser model.User, jobs []*jobv1.Job,
+) ([]*jobv1.Job, error) {
+	var viewable []*jobv1.Job
+	for _, j := range jobs {
+		if f[j.WorkspaceId] {
+			viewable = append(viewable, j)
+		}
+	}
+	return viewable, nil
+}
+
+func TestFromProto(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, etc.SetRootPath(db.RootFromDB))
+	pgDB, cleanup := db.MustResolveNewPostgresDatabase(t)
+	defer cleanup()
+	db.MustMigrateTestPostgres(t, pgDB, db.MigrationsFromDB)
+
+	user := db.RequireMockUser(t, pgDB)
+	viewableWorkspaceID, _ := db.RequireMockWorkspaceID(t, pgDB, "")
+	viewableProjectID, _ := db.RequireMockProjectID(t, pgDB, viewableWorkspaceID, false)
+	hiddenWorkspaceID, _ := db.RequireMockWorkspaceID(t, pgDB, "")
+	hiddenProjectID, _ := db.RequireMockProjectID(t, pgDB, hiddenWorkspaceID, false)
+	viewable := db.RequireMockExperimentProject(t, pgDB, user, viewableProjectID)
+	hidden := db.RequireMockExperimentProject(t, pgDB, user, hiddenProjectID)
+	filter := workspaceFilter{int32(viewableWorkspaceID): true}
+
+	deps, err := FromProto(ctx, user, filter, []*jobv1.JobDependency{{JobId: string(viewable.JobID)}})
+	require.NoError(t, err)
+	require.Equal(t, []model.JobDependency{
+		{DependsOn: viewable.JobID, Condition: model.JobDependencySuccess},
+	}, deps)
+
+	// A job in a workspace that the user cannot view is indistinguishable from one that does not
+	// exist.
+	for _, jobID := range []model.JobID{hidden.JobID, model.NewJobID()} {
+		_, err = FromProto(ctx, user, filter, []*jobv1.JobDependency{
+			{JobId: string(viewable.JobID)}, {JobId: string(jobID)},
+		})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+		require.Equal(t, fmt.Sprintf("job %s to depend on not found", jobID),
+			status.Convert(err).Message())
+	}
+}
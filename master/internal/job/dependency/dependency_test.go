@@ -0,0 +1,187 @@
+package dependency
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+func TestResolve(t *testing.T) {
+	deps := []model.JobDependency{
+		{DependsOn: "train", Condition: model.JobDependencySuccess},
+		{DependsOn: "prep", Condition: model.JobDependencyAnyExit},
+	}
+
+	cases := []struct {
+		name     string
+		outcomes map[model.JobID]model.JobOutcome
+		met      bool
+		err      bool
+	}{
+		{
+			name: "running",
+			outcomes: map[model.JobID]model.JobOutcome{
+				"train": model.JobOutcomeRunning,
+				"prep":  model.JobOutcomeSucceeded,
+			},
+		},
+		{
+			name: "all succeeded",
+			outcomes: map[model.JobID]model.JobOutcome{
+				"train": model.JobOutcomeSucceeded,
+				"prep":  model.JobOutcomeSucceeded,
+			},
+			met: true,
+		},
+		{
+			name: "any exit allows failure",
+			outcomes: map[model.JobID]model.JobOutcome{
+				"train": model.JobOutcomeSucceeded,
+				"prep":  model.JobOutcomeFailed,
+			},
+			met: true,
+		},
+		{
+			name: "success required",
+			outcomes: map[model.JobID]model.JobOutcome{
+				"train": model.JobOutcomeFailed,
+				"prep":  model.JobOutcomeRunning,
+			},
+			err: true,
+		},
+		{
+			name: "missing job",
+			outcomes: map[model.JobID]model.JobOutcome{
+				"prep": model.JobOutcomeSucceeded,
+			},
+			err: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			met, err := resolve(deps, tc.outcomes)
+			require.Equal(t, tc.met, met)
+			require.Equal(t, tc.err, err != nil)
+		})
+	}
+}
+
+func TestOutcomes(t *testing.T) {
+	require.Equal(t, model.JobOutcomeRunning, experimentOutcome(model.ActiveState))
+	require.Equal(t, model.JobOutcomeRunning, experimentOutcome(model.StoppingCompletedState))
+	require.Equal(t, model.JobOutcomeSucceeded, experimentOutcome(model.CompletedState))
+	require.Equal(t, model.JobOutcomeFailed, experimentOutcome(model.CanceledState))
+	require.Equal(t, model.JobOutcomeFailed, experimentOutcome(model.ErrorState))
+
+	require.Equal(t, model.JobOutcomeRunning,
+		taskOutcome(false, ptrs.Ptr(model.TaskStateActive), true, nil))
+	require.Equal(t, model.JobOutcomeSucceeded,
+		taskOutcome(true, ptrs.Ptr(model.TaskStateCompleted), true, nil))
+	require.Equal(t, model.JobOutcomeFailed,
+		taskOutcome(true, ptrs.Ptr(model.TaskStateCanceled), false, nil))
+
+	require.Equal(t, model.JobOutcomeRunning, taskOutcome(false, nil, true, nil))
+	require.Equal(t, model.JobOutcomeSucceeded, taskOutcome(true, nil, true, nil))
+	require.Equal(t, model.JobOutcomeFailed, taskOutcome(true, nil, true, ptrs.Ptr("exit code 1")))
+	require.Equal(t, model.JobOutcomeFailed, taskOutcome(true, nil, false, nil))
+}
+
+type fakeOutcomes struct {
+	mu       sync.Mutex
+	outcomes map[model.JobID]model.JobOutcome
+}
+
+func (f *fakeOutcomes) set(jobID model.JobID, outcome model.JobOutcome) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.outcomes[jobID] = outcome
+}
+
+func (f *fakeOutcomes) get(
+	_ context.Context, jobIDs []model.JobID,
+) (map[model.JobID]model.JobOutcome, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	res := make(map[model.JobID]model.JobOutcome)
+	for _, jobID := range jobIDs {
+		if outcome, ok := f.outcomes[jobID]; ok {
+			res[jobID] = outcome
+		}
+	}
+	return res, nil
+}
+
+func newTestService() (*Service, *fakeOutcomes) {
+	fake := &fakeOutcomes{outcomes: map[model.JobID]model.JobOutcome{}}
+	s := NewService()
+	s.outcomes = fake.get
+	return s, fake
+}
+
+func waitResolved(t *testing.T, resolved chan error) error {
+	select {
+	case err := <-resolved:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("dependencies were not resolved")
+		return nil
+	}
+}
+
+func TestServiceJobFinished(t *testing.T) {
+	s, fake := newTestService()
+	fake.set("train", model.JobOutcomeRunning)
+
+	resolved := make(chan error, 1)
+	s.hold("eval", "default", []model.JobDependency{
+		{DependsOn: "train", Condition: model.JobDependencySuccess},
+	}, func(err error) { resolved <- err })
+
+	require.True(t, s.IsWaiting("eval"))
+	require.Equal(t, []model.JobID{"eval"}, s.Waiting("default"))
+	require.Empty(t, s.Waiting("other"))
+
+	fake.set("train", model.JobOutcomeSucceeded)
+	s.JobFinished("train")
+	require.NoError(t, waitResolved(t, resolved))
+	require.False(t, s.IsWaiting("eval"))
+}
+
+func TestServiceUpstreamFailed(t *testing.T) {
+	s, fake := newTestService()
+	fake.set("train", model.JobOutcomeFailed)
+
+	resolved := make(chan error, 1)
+	s.hold("eval", "default", []model.JobDependency{
+		{DependsOn: "train", Condition: model.JobDependencySuccess},
+	}, func(err error) { resolved <- err })
+
+	require.ErrorContains(t, waitResolved(t, resolved), "did not succeed")
+	require.False(t, s.IsWaiting("eval"))
+}
+
+func TestServiceCancel(t *testing.T) {
+	s, fake := newTestService()
+	fake.set("train", model.JobOutcomeRunning)
+
+	resolved := make(chan error, 1)
+	s.hold("eval", "default", []model.JobDependency{
+		{DependsOn: "train", Condition: model.JobDependencyAnyExit},
+	}, func(err error) { resolved <- err })
+
+	require.True(t, s.Cancel("eval", "user requested kill"))
+	require.ErrorContains(t, waitResolved(t, resolved), "user requested kill")
+	require.False(t, s.Cancel("eval", "user requested kill"))
+
+	s.hold("eval", "default", []model.JobDependency{
+		{DependsOn: "train", Condition: model.JobDependencyAnyExit},
+	}, func(err error) { resolved <- err })
+	s.Forget("eval")
+	require.False(t, s.IsWaiting("eval"))
+}
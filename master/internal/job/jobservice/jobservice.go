@@ -11,6 +11,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"golang.org/x/exp/slices"
 
+	"github.com/determined-ai/determined/master/internal/job/dependency"
 	"github.com/determined-ai/determined/master/internal/rm"
 	"github.com/determined-ai/determined/master/internal/rm/rmerrors"
 	"github.com/determined-ai/determined/master/internal/sproto"
@@ -34,6 +35,9 @@ type Service struct {
 	rm      rm.ResourceManager
 	jobByID map[model.JobID]Job
 	syslog  *logrus.Entry
+
+	queueMu       sync.Mutex
+	queueWatchers map[rm.ResourcePoolName]*queueWatcher
 }
 
 // DefaultService is the global singleton job service.
@@ -58,17 +62,19 @@ func SetDefaultService(rm rm.ResourceManager) {
 // and registers it with the job manager's jobByID map.
 func (s *Service) RegisterJob(jobID model.JobID, j Job) {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-
 	s.jobByID[jobID] = j
+	s.mu.Unlock()
+
+	s.notifyQueueWatchers()
 }
 
 // UnregisterJob deletes a job from the jobByID map.
 func (s *Service) UnregisterJob(jobID model.JobID) {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-
 	delete(s.jobByID, jobID)
+	s.mu.Unlock()
+
+	s.notifyQueueWatchers()
 }
 
 func (s *Service) jobQRefs(jobQ map[model.JobID]*sproto.RMJobInfo) (map[model.JobID]*jobv1.Job, error) {
@@ -153,6 +159,24 @@ func (s *Service) GetJobs(
 		return jobsInRM[i].JobId < jobsInRM[j].JobId
 	})
 
+	// Jobs that wait on their dependencies are not in the RM yet, so they go after queued jobs.
+	for _, jID := range dependency.DefaultService.Waiting(string(resourcePool)) {
+		jobRef, ok := s.jobByID[jID]
+		if !ok {
+			continue
+		}
+		v1Job, err := jobRef.ToV1Job()
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		v1Job.Summary = &jobv1.JobSummary{State: jobv1.State_STATE_WAITING_ON_DEPENDENCY}
+		if states == nil || slices.Contains(states, v1Job.Summary.State) {
+			jobsInRM = append(jobsInRM, v1Job)
+		}
+	}
+
 	// Append any External jobs to the bottom of the list.
 	jobsInRM = append(jobsInRM, externalJobs...)
 
@@ -171,12 +195,17 @@ func (s *Service) GetJobSummary(id model.JobID, resourcePool rm.ResourcePoolName
 	}
 	jobInfo, ok := jobQ[id]
 	if !ok || jobInfo == nil {
+		if dependency.DefaultService.IsWaiting(id) {
+			return &jobv1.JobSummary{State: jobv1.State_STATE_WAITING_ON_DEPENDENCY}, nil
+		}
 		// job is not active.
 		return nil, sproto.ErrJobNotFound(id)
 	}
 	return &jobv1.JobSummary{
-		State:     jobInfo.State.Proto(),
-		JobsAhead: int32(jobInfo.JobsAhead),
+		State:       jobInfo.State.Proto(),
+		JobsAhead:   int32(jobInfo.JobsAhead),
+		Reservation: jobInfo.Reservation.Proto(),
+		FairShare:   jobInfo.FairShare.Proto(),
 	}, nil
 }
 
@@ -213,6 +242,7 @@ func (s *Service) applyUpdate(update *jobv1.QueueControl) error {
 
 // UpdateJobQueue sends queue control updates to specific jobs.
 func (s *Service) UpdateJobQueue(updates []*jobv1.QueueControl) error {
+	defer s.notifyQueueWatchers()
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -253,4 +283,6 @@ func updateJobQInfo(job *jobv1.Job, rmInfo *sproto.RMJobInfo) {
 	}
 	job.Summary.State = rmInfo.State.Proto()
 	job.Summary.JobsAhead = int32(rmInfo.JobsAhead)
+	job.Summary.Reservation = rmInfo.Reservation.Proto()
+	job.Summary.FairShare = rmInfo.FairShare.Proto()
 }
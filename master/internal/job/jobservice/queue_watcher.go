@@ -0,0 +1,236 @@
+package jobservice
+
+import (
+	"sort"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/rm"
+	"github.com/determined-ai/determined/proto/pkg/jobv1"
+)
+
+const (
+	// queueSubscriberBuffer is how many batches of events a subscriber may fall behind by before
+	// it is dropped.
+	queueSubscriberBuffer = 64
+)
+
+// JobQueueEvent is a single change to a job queue. Job is nil for removals.
+type JobQueueEvent struct {
+	Type  jobv1.JobQueueEventType
+	JobID string
+	Job   *jobv1.Job
+}
+
+// JobQueueSubscription receives batches of changes to the job queue of one resource pool.
+type JobQueueSubscription struct {
+	// C receives batches of events. The first batch inserts every job already in the queue. It is
+	// closed if the subscriber falls too far behind or the resource pool can no longer be read.
+	C <-chan []JobQueueEvent
+
+	c       chan []JobQueueEvent
+	watcher *queueWatcher
+}
+
+// Close unsubscribes from the job queue. It is safe to call more than once.
+func (sub *JobQueueSubscription) Close() {
+	sub.watcher.unsubscribe(sub)
+}
+
+// queueWatcher reads the job queue of a single resource pool while it has subscribers and fans
+// out the differences between consecutive reads. Its subscribers and snapshot are guarded by the
+// service's queueMu.
+type queueWatcher struct {
+	s    *Service
+	pool rm.ResourcePoolName
+	wake chan struct{}
+	done chan struct{}
+
+	subs map[*JobQueueSubscription]bool
+	last map[string]*jobv1.Job
+}
+
+// SubscribeJobQueue subscribes to changes to the job queue of a resource pool.
+func (s *Service) SubscribeJobQueue(resourcePool rm.ResourcePoolName) (*JobQueueSubscription, error) {
+	// Read the pool up front, so that an unknown resource pool is an error for the caller.
+	jobs, err := s.GetJobs(resourcePool, false, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+
+	if s.queueWatchers == nil {
+		s.queueWatchers = map[rm.ResourcePoolName]*queueWatcher{}
+	}
+	w, ok := s.queueWatchers[resourcePool]
+	if !ok {
+		w = &queueWatcher{
+			s:    s,
+			pool: resourcePool,
+			wake: make(chan struct{}, 1),
+			done: make(chan struct{}),
+			subs: map[*JobQueueSubscription]bool{},
+			last: jobsByID(jobs),
+		}
+		s.queueWatchers[resourcePool] = w
+		go w.run()
+	}
+
+	c := make(chan []JobQueueEvent, queueSubscriberBuffer)
+	sub := &JobQueueSubscription{C: c, c: c, watcher: w}
+	// Start the subscriber from the watcher's snapshot, so that later diffs apply on top of it.
+	c <- diffJobQueue(nil, w.last)
+	w.subs[sub] = true
+	return sub, nil
+}
+
+// notifyQueueWatchers asks every queue watcher to refresh as soon as possible.
+func (s *Service) notifyQueueWatchers() {
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+
+	for _, w := range s.queueWatchers {
+		select {
+		case w.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (w *queueWatcher) unsubscribe(sub *JobQueueSubscription) {
+	w.s.queueMu.Lock()
+	defer w.s.queueMu.Unlock()
+
+	if !w.subs[sub] {
+		return
+	}
+	w.drop(sub)
+}
+
+// drop closes a subscription and stops the watcher once it has none left. The caller must hold
+// the service's queueMu.
+func (w *queueWatcher) drop(sub *JobQueueSubscription) {
+	delete(w.subs, sub)
+	close(sub.c)
+	if len(w.subs) > 0 {
+		return
+	}
+	delete(w.s.queueWatchers, w.pool)
+	close(w.done)
+}
+
+// run re-reads the job queue whenever the watcher is woken by a change to the jobs and, since
+// queue positions change on scheduling passes that the job service is not told about, every
+// refresh interval of the master config.
+func (w *queueWatcher) run() {
+	var refresh <-chan time.Time
+	if interval := time.Duration(config.GetMasterConfig().JobQueue.RefreshInterval); interval > 0 {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		refresh = t.C
+	}
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-w.wake:
+		case <-refresh:
+		}
+
+		jobs, err := w.s.GetJobs(w.pool, false, nil)
+		if err != nil {
+			w.s.syslog.WithError(err).Warnf("refreshing job queue for resource pool %q", w.pool)
+		}
+		w.publish(jobs, err)
+	}
+}
+
+// publish sends the changes since the last read to every subscriber, dropping subscribers that
+// have fallen behind. If the read failed, every subscriber is dropped.
+func (w *queueWatcher) publish(jobs []*jobv1.Job, err error) {
+	w.s.queueMu.Lock()
+	defer w.s.queueMu.Unlock()
+
+	select {
+	case <-w.done:
+		return
+	default:
+	}
+
+	if err != nil {
+		for sub := range w.subs {
+			w.drop(sub)
+		}
+		return
+	}
+
+	next := jobsByID(jobs)
+	events := diffJobQueue(w.last, next)
+	w.last = next
+	if len(events) == 0 {
+		return
+	}
+	for sub := range w.subs {
+		select {
+		case sub.c <- events:
+		default:
+			w.s.syslog.Warnf("dropping job queue subscriber for resource pool %q that fell behind", w.pool)
+			w.drop(sub)
+		}
+	}
+}
+
+func jobsByID(jobs []*jobv1.Job) map[string]*jobv1.Job {
+	byID := make(map[string]*jobv1.Job, len(jobs))
+	for _, j := range jobs {
+		byID[j.JobId] = j
+	}
+	return byID
+}
+
+// diffJobQueue returns the events that turn the prev queue into the next one, ordered by queue
+// position with removals first.
+func diffJobQueue(prev, next map[string]*jobv1.Job) []JobQueueEvent {
+	var removed, changed []JobQueueEvent
+	for id := range prev {
+		if _, ok := next[id]; !ok {
+			removed = append(removed, JobQueueEvent{
+				Type:  jobv1.JobQueueEventType_JOB_QUEUE_EVENT_TYPE_REMOVED,
+				JobID: id,
+			})
+		}
+	}
+
+	for id, j := range next {
+		old, ok := prev[id]
+		var t jobv1.JobQueueEventType
+		switch {
+		case !ok:
+			t = jobv1.JobQueueEventType_JOB_QUEUE_EVENT_TYPE_INSERTED
+		case old.GetSummary().GetState() != j.GetSummary().GetState():
+			t = jobv1.JobQueueEventType_JOB_QUEUE_EVENT_TYPE_STATE_CHANGED
+		case old.GetSummary().GetJobsAhead() != j.GetSummary().GetJobsAhead():
+			t = jobv1.JobQueueEventType_JOB_QUEUE_EVENT_TYPE_POSITION_CHANGED
+		case !proto.Equal(old, j):
+			t = jobv1.JobQueueEventType_JOB_QUEUE_EVENT_TYPE_UPDATED
+		default:
+			continue
+		}
+		changed = append(changed, JobQueueEvent{Type: t, JobID: id, Job: j})
+	}
+
+	sort.Slice(removed, func(i, j int) bool { return removed[i].JobID < removed[j].JobID })
+	sort.SliceStable(changed, func(i, j int) bool {
+		ai, aj := changed[i].Job.GetSummary().GetJobsAhead(), changed[j].Job.GetSummary().GetJobsAhead()
+		if ai != aj {
+			return ai < aj
+		}
+		return changed[i].JobID < changed[j].JobID
+	})
+	return append(removed, changed...)
+}
@@ -0,0 +1,59 @@
+package jobservice
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/proto/pkg/jobv1"
+)
+
+func testJob(id string, state jobv1.State, jobsAhead int32, priority int32) *jobv1.Job {
+	return &jobv1.Job{
+		JobId:    id,
+		Priority: priority,
+		Summary:  &jobv1.JobSummary{State: state, JobsAhead: jobsAhead},
+	}
+}
+
+func TestDiffJobQueue(t *testing.T) {
+	prev := jobsByID([]*jobv1.Job{
+		testJob("a", jobv1.State_STATE_SCHEDULED, 0, 42),
+		testJob("b", jobv1.State_STATE_QUEUED, 1, 42),
+		testJob("c", jobv1.State_STATE_QUEUED, 2, 42),
+		testJob("d", jobv1.State_STATE_QUEUED, 3, 42),
+		testJob("e", jobv1.State_STATE_QUEUED, 4, 42),
+	})
+	next := jobsByID([]*jobv1.Job{
+		testJob("b", jobv1.State_STATE_SCHEDULED, 0, 42),
+		testJob("c", jobv1.State_STATE_QUEUED, 1, 42),
+		testJob("d", jobv1.State_STATE_QUEUED, 3, 10),
+		testJob("e", jobv1.State_STATE_QUEUED, 4, 42),
+		testJob("f", jobv1.State_STATE_QUEUED, 2, 42),
+	})
+
+	type event struct {
+		Type  jobv1.JobQueueEventType
+		JobID string
+	}
+	var actual []event
+	for _, e := range diffJobQueue(prev, next) {
+		require.Equal(t, e.Type == jobv1.JobQueueEventType_JOB_QUEUE_EVENT_TYPE_REMOVED, e.Job == nil)
+		actual = append(actual, event{e.Type, e.JobID})
+	}
+	require.Equal(t, []event{
+		{jobv1.JobQueueEventType_JOB_QUEUE_EVENT_TYPE_REMOVED, "a"},
+		{jobv1.JobQueueEventType_JOB_QUEUE_EVENT_TYPE_STATE_CHANGED, "b"},
+		{jobv1.JobQueueEventType_JOB_QUEUE_EVENT_TYPE_POSITION_CHANGED, "c"},
+		{jobv1.JobQueueEventType_JOB_QUEUE_EVENT_TYPE_INSERTED, "f"},
+		{jobv1.JobQueueEventType_JOB_QUEUE_EVENT_TYPE_UPDATED, "d"},
+	}, actual)
+
+	require.Empty(t, diffJobQueue(next, next))
+
+	inserted := diffJobQueue(nil, next)
+	require.Len(t, inserted, len(next))
+	for _, e := range inserted {
+		require.Equal(t, jobv1.JobQueueEventType_JOB_QUEUE_EVENT_TYPE_INSERTED, e.Type)
+	}
+}
@@ -116,7 +116,7 @@ func (m *Master) restoreExperiment(expModel *model.Experiment) error {
 	if err != nil {
 		return errors.Wrapf(err, "failed to restore experiment %d", expModel.ID)
 	}
-	e, _, err := newExperiment(m, expModel, nil, activeConfig, &taskSpec)
+	e, _, err := newExperiment(m, expModel, nil, activeConfig, &taskSpec, nil)
 	if err != nil {
 		return errors.Wrapf(err, "failed to create experiment %d from model", expModel.ID)
 	}
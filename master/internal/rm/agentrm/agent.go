@@ -6,6 +6,7 @@ import (
 	"net"
 	"os"
 	"reflect"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -15,6 +16,8 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/exp/maps"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/determined-ai/determined/master/internal/config"
 	"github.com/determined-ai/determined/master/internal/db"
@@ -34,6 +37,7 @@ import (
 var (
 	errRecovering                = errors.New("agent disconnected, wait for recovery")
 	errWebsocketAlreadyConnected = errors.New("websocket already connected")
+	errInMaintenance             = status.Error(codes.FailedPrecondition, "agent in maintenance")
 )
 
 type (
@@ -82,6 +86,15 @@ type (
 		preDisconnectEnabled  bool
 		preDisconnectDraining bool
 
+		// maintenanceWindows are the agent's current and upcoming maintenance windows, earliest
+		// first. maintenanceHorizon is how long before a window only jobs whose time limits end
+		// before it are scheduled on the agent. checkpointedWindow is the last window that
+		// preemptible allocations were checkpointed for.
+		maintenanceWindows []model.AgentMaintenanceWindow
+		maintenanceHorizon time.Duration
+		maintenanceTimer   *time.Timer
+		checkpointedWindow int
+
 		// opts are additional agent options the master sends to the agent.
 		opts *aproto.MasterSetAgentOptions
 
@@ -135,10 +148,14 @@ func newAgent(
 		resourcePoolName:      resourcePoolName,
 		maxZeroSlotContainers: rpConfig.MaxAuxContainersPerAgent,
 		agentReconnectWait:    time.Duration(rpConfig.AgentReconnectWait),
+		maintenanceHorizon:    time.Duration(rpConfig.MaintenanceSchedulingHorizon),
 		opts:                  opts,
 		agentState:            restoredAgentState,
 		unregister:            unregister,
 	}
+	if a.maintenanceHorizon == 0 {
+		a.maintenanceHorizon = DefaultMaintenanceSchedulingHorizon
+	}
 
 	if restoring := a.agentState != nil; restoring {
 		a.started = true
@@ -156,6 +173,7 @@ func newAgent(
 		}
 		a.notifyListeners()
 		a.socketDisconnected()
+		a.loadMaintenanceWindows()
 	}
 
 	return a
@@ -251,6 +269,10 @@ func (a *agent) killTaskContainer(msg sproto.KillTaskContainer) {
 func (a *agent) stop(cause error) {
 	defer a.unregister()
 
+	if a.maintenanceTimer != nil {
+		a.maintenanceTimer.Stop()
+	}
+
 	if cause != nil {
 		a.syslog.WithError(cause).WithFields(logrus.Fields{
 			"address": a.address,
@@ -425,6 +447,7 @@ func (a *agent) handleWebsocketConnection(msg webSocketRequest) error {
 			}
 		}
 		a.reconnectBacklog = nil
+		a.updateMaintenance(time.Now())
 		a.notifyListeners()
 	}
 	return nil
@@ -498,6 +521,10 @@ func (a *agent) EnableAgent(msg *apiv1.EnableAgentRequest) (*apiv1.EnableAgentRe
 		return nil, errors.New("can't enable agent: agent not started")
 	}
 
+	if a.agentState.inMaintenance {
+		return nil, errInMaintenance
+	}
+
 	a.agentState.enable()
 	a.agentState.patchAllSlotsState(patchAllSlotsState{
 		enabled: &a.agentState.enabled,
@@ -519,6 +546,13 @@ func (a *agent) DisableAgent(msg *apiv1.DisableAgentRequest) (*apiv1.DisableAgen
 		return nil, errors.New("can't disable agent: agent not started")
 	}
 
+	if a.agentState.inMaintenance {
+		// The agent is already disabled; keep it so after the window instead.
+		a.agentState.preMaintenanceEnabled = false
+		a.agentState.preMaintenanceDraining = msg.Drain
+		return &apiv1.DisableAgentResponse{Agent: a.summarize().ToProto()}, nil
+	}
+
 	// Mark current agent as disabled with RP.
 	a.agentState.disable(msg.Drain)
 	// Update individual slot state.
@@ -637,12 +671,16 @@ func (a *agent) HandleIncomingWebsocketMessage(msg *aproto.MasterMessage) {
 			a.agentStarted(msg.AgentStarted)
 		}
 
+		firstStart := !a.started
 		a.started = true
 
 		if err := a.handleContainersReattached(msg.AgentStarted); err != nil {
 			a.syslog.WithError(err).
 				Error("failure in handleContainersReattached")
 		}
+		if firstStart {
+			a.loadMaintenanceWindows()
+		}
 	case msg.ContainerStateChanged != nil:
 		a.containerStateChanged(*msg.ContainerStateChanged)
 	case msg.ContainerLog != nil:
@@ -664,6 +702,8 @@ func (a *agent) HandleIncomingWebsocketMessage(msg *aproto.MasterMessage) {
 			Source:      msg.ContainerLog.Source,
 			AgentID:     msg.ContainerLog.AgentID,
 		})
+	case msg.AgentHealthReport != nil:
+		a.healthReported(*msg.AgentHealthReport)
 	case msg.ContainerStatsRecord != nil:
 		if a.taskNeedsRecording(msg.ContainerStatsRecord) {
 			var err error
@@ -686,6 +726,48 @@ func (a *agent) taskNeedsRecording(record *aproto.ContainerStatsRecord) bool {
 	return record.TaskType == model.TaskTypeTrial
 }
 
+// healthReported disables or re-enables slots as the agent's health checks report, records the
+// changes as health events and, if asked to, drains the agent when any device is unhealthy.
+func (a *agent) healthReported(report aproto.AgentHealthReport) {
+	if !a.started {
+		a.syslog.Debug("received health report on non-started agent")
+		return
+	}
+
+	changed := a.agentState.healthReported(report)
+	if len(changed) == 0 {
+		return
+	}
+
+	now := time.Now().UTC()
+	events := make([]*model.AgentHealthEvent, 0, len(changed))
+	becameUnhealthy := false
+	for _, s := range changed {
+		becameUnhealthy = becameUnhealthy || s.enabled.unhealthyReason != ""
+		events = append(events, &model.AgentHealthEvent{
+			AgentID:    string(a.id),
+			Time:       now,
+			DeviceID:   s.device.ID,
+			DeviceUUID: s.device.UUID,
+			Healthy:    s.enabled.unhealthyReason == "",
+			Reason:     s.enabled.unhealthyReason,
+		})
+	}
+	if err := db.AddAgentHealthEvents(context.TODO(), events); err != nil {
+		a.syslog.WithError(err).Error("failed to record agent health events")
+	}
+
+	// Draining is left for an admin to undo, even once the devices recover.
+	if report.DrainAgent && becameUnhealthy && a.agentState.enabled {
+		a.agentState.disable(true)
+		a.agentState.patchAllSlotsState(patchAllSlotsState{
+			enabled: &a.agentState.enabled,
+			drain:   &a.agentState.draining,
+		})
+	}
+	a.notifyListeners()
+}
+
 func (a *agent) agentStarted(agentStarted *aproto.AgentStarted) {
 	a.agentState = newAgentState(a.id, a.maxZeroSlotContainers)
 	a.agentState.handler = a
@@ -758,6 +840,8 @@ func (a *agent) summarize() model.AgentSummary {
 		result.Draining = a.agentState.draining
 		result.NumContainers = len(a.agentState.containerAllocation)
 	}
+	result.MaintenanceWindows = slices.Clone(a.maintenanceWindows)
+	result.InMaintenance = a.agentState != nil && a.agentState.inMaintenance
 
 	return result
 }
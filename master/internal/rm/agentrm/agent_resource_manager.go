@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"fmt"
 	"maps"
+	"net/url"
 	"slices"
 	"strconv"
 	"time"
@@ -14,6 +15,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/determined-ai/determined/master/internal/api"
 	"github.com/determined-ai/determined/master/internal/config"
@@ -148,6 +150,28 @@ func (a *ResourceManager) DisableAgent(msg *apiv1.DisableAgentRequest) (*apiv1.D
 	return agent.DisableAgent(msg)
 }
 
+// ScheduleAgentMaintenance implements rm.ResourceManager.
+func (a *ResourceManager) ScheduleAgentMaintenance(
+	req *apiv1.ScheduleAgentMaintenanceRequest,
+) (*apiv1.ScheduleAgentMaintenanceResponse, error) {
+	agent, ok := a.agentService.get(aproto.ID(req.AgentId))
+	if !ok {
+		return nil, api.NotFoundErrs("agent", req.AgentId, true)
+	}
+	return agent.ScheduleMaintenance(req)
+}
+
+// CancelAgentMaintenance implements rm.ResourceManager.
+func (a *ResourceManager) CancelAgentMaintenance(
+	req *apiv1.CancelAgentMaintenanceRequest,
+) (*apiv1.CancelAgentMaintenanceResponse, error) {
+	agent, ok := a.agentService.get(aproto.ID(req.AgentId))
+	if !ok {
+		return nil, api.NotFoundErrs("agent", req.AgentId, true)
+	}
+	return agent.CancelMaintenance(req)
+}
+
 // HealthCheck always returns healthy for agentrm.
 func (a *ResourceManager) HealthCheck() []model.ResourceManagerHealth {
 	return []model.ResourceManagerHealth{
@@ -621,6 +645,10 @@ func (a *ResourceManager) createResourcePool(
 		a.syslog.Infof("pool %s using global scheduling config", config.PoolName)
 	}
 
+	if err := rm.RecordResourcePoolPrice(context.TODO(), config); err != nil {
+		return nil, err
+	}
+
 	scheduler, err := MakeScheduler(config.Scheduler)
 	if err != nil {
 		return nil, err
@@ -709,6 +737,15 @@ func (a *ResourceManager) createResourcePoolSummary(
 				accelerator = pool.Provider.GCP.Accelerator()
 			}
 		}
+		if pool.Provider.Webhook != nil {
+			poolType = resourcepoolv1.ResourcePoolType_RESOURCE_POOL_TYPE_WEBHOOK
+			if u, err := url.Parse(pool.Provider.Webhook.URL); err == nil {
+				location = u.Hostname()
+			}
+			instanceType = pool.Provider.Webhook.InstanceType.MachineType
+			slotsPerAgent = pool.Provider.Webhook.SlotsPerInstance()
+			slotType = pool.Provider.Webhook.SlotType()
+		}
 	}
 
 	var schedulerType resourcepoolv1.SchedulerType
@@ -730,6 +767,9 @@ func (a *ResourceManager) createResourcePoolSummary(
 	if pool.Scheduler.RoundRobin != nil {
 		schedulerType = resourcepoolv1.SchedulerType_SCHEDULER_TYPE_ROUND_ROBIN
 	}
+	if pool.Scheduler.External != nil {
+		schedulerType = resourcepoolv1.SchedulerType_SCHEDULER_TYPE_EXTERNAL
+	}
 
 	resp := &resourcepoolv1.ResourcePool{
 		Name:                         pool.PoolName,
@@ -830,10 +870,10 @@ func (a *ResourceManager) createResourcePoolSummary(
 		}
 	}
 
-	if schedulerType == resourcepoolv1.SchedulerType_SCHEDULER_TYPE_PRIORITY {
+	if priority := pool.Scheduler.PriorityConfig(); priority != nil {
 		resp.Details.PriorityScheduler = &resourcepoolv1.ResourcePoolPrioritySchedulerDetail{
-			Preemption:      pool.Scheduler.Priority.Preemption,
-			DefaultPriority: int32(*pool.Scheduler.Priority.DefaultPriority),
+			Preemption:      priority.Preemption,
+			DefaultPriority: int32(*priority.DefaultPriority),
 		}
 	}
 
@@ -851,6 +891,23 @@ func (a *ResourceManager) createResourcePoolSummary(
 	if pool.Provider == nil && resp.NumAgents > 0 {
 		resp.SlotType = resourceSummary.slotType.Proto()
 	}
+	if decision := rp.LaunchDecision(); decision != nil {
+		resp.LaunchDecision = &resourcepoolv1.ResourcePoolLaunchDecision{
+			Time:                      timestamppb.New(decision.Time),
+			Instances:                 int32(decision.Instances),
+			RecentlyLaunchedInstances: int32(decision.RecentlyLaunched),
+			DesiredNewInstances:       int32(decision.DesiredNewInstances),
+			MinInstances:              int32(decision.MinInstances),
+			WarmPoolMinInstances:      int32(decision.WarmPoolMinInstances),
+			ActiveWarmPools:           decision.ActiveWarmPools,
+			ForecastSlots:             decision.ForecastSlots,
+			ForecastMinInstances:      int32(decision.ForecastMinInstances),
+			TargetMinInstances:        int32(decision.TargetMinInstances),
+			MaxInstances:              int32(decision.MaxInstances),
+			InstancesToLaunch:         int32(decision.InstancesToLaunch),
+			Reason:                    decision.Reason,
+		}
+	}
 
 	return resp, nil
 }
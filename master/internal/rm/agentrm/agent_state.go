@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
@@ -27,10 +28,12 @@ type slotEnabled struct {
 	agentEnabled bool
 	userEnabled  bool
 	draining     bool
+	// unhealthyReason is set while the agent's health checks find the device unhealthy.
+	unhealthyReason string
 }
 
 func (s slotEnabled) enabled() bool {
-	return s.agentEnabled && s.userEnabled
+	return s.agentEnabled && s.userEnabled && s.unhealthyReason == ""
 }
 
 type slot struct {
@@ -57,6 +60,19 @@ type agentState struct {
 	slotStates          map[device.ID]*slot
 	containerAllocation map[cproto.ID]model.AllocationID
 	containerState      map[cproto.ID]*cproto.Container
+
+	// reservedFor is the job the resource pool holds this agent's slots for, if any. It is only
+	// set on the scheduler's copies of the agent states.
+	reservedFor model.JobID
+	// maintenanceStart is when the agent's next maintenance window starts, or zero if it has none
+	// or it is not near yet.
+	maintenanceStart time.Time
+	// inMaintenance is whether a maintenance window of the agent is current, which disables the
+	// agent. Its state from before the window is kept in preMaintenanceEnabled et al. and is what
+	// snapshots save, so the agent is enabled again after a window the master restarted during.
+	inMaintenance          bool
+	preMaintenanceEnabled  bool
+	preMaintenanceDraining bool
 }
 
 // newAgentState returns a new agent empty agent state backed by the handler.
@@ -208,6 +224,8 @@ func (a *agentState) deepCopy() *agentState {
 		// TODO(ilia): Deepcopy of `slotStates` may be necessary one day.
 		slotStates:       a.slotStates,
 		resourcePoolName: a.resourcePoolName,
+		reservedFor:      a.reservedFor,
+		maintenanceStart: a.maintenanceStart,
 	}
 
 	return copiedAgent
@@ -399,6 +417,8 @@ func (a *agentState) getSlotSummary(deviceID device.ID) model.SlotSummary {
 		Enabled:   s.enabled.enabled(),
 		Container: container,
 		Draining:  s.enabled.draining,
+
+		UnhealthyReason: s.enabled.unhealthyReason,
 	}
 }
 
@@ -432,6 +452,36 @@ func (a *agentState) updateSlotDeviceView(deviceID device.ID) {
 	}
 }
 
+// healthReported disables the slots of the devices that the agent's health checks found unhealthy,
+// enables those of devices that recovered, and returns the slots whose health changed.
+func (a *agentState) healthReported(report aproto.AgentHealthReport) []*slot {
+	reasons := make(map[device.ID]string, len(report.UnhealthyDevices))
+	for _, d := range report.UnhealthyDevices {
+		reason := d.Reason
+		if reason == "" {
+			reason = "failed health check"
+		}
+		reasons[d.Device.ID] = reason
+	}
+
+	var changed []*slot
+	for id, s := range a.slotStates {
+		if s.enabled.unhealthyReason == reasons[id] {
+			continue
+		}
+		if reasons[id] != "" {
+			a.syslog.Warnf("disabling unhealthy device: %s (%s): %s",
+				s.device.String(), a.string(), reasons[id])
+		} else {
+			a.syslog.Infof("device is healthy again: %s (%s)", s.device.String(), a.string())
+		}
+		s.enabled.unhealthyReason = reasons[id]
+		a.updateSlotDeviceView(id)
+		changed = append(changed, s)
+	}
+	return changed
+}
+
 func (a *agentState) patchSlotStateInner(
 	msg patchSlotState, slotState *slot,
 ) model.SlotSummary {
@@ -487,14 +537,19 @@ func (a *agentState) snapshot() *agentSnapshot {
 
 	containerIds := maps.Keys(a.containerState)
 
+	userEnabled, userDraining := a.enabled, a.draining
+	if a.inMaintenance {
+		userEnabled, userDraining = a.preMaintenanceEnabled, a.preMaintenanceDraining
+	}
+
 	s := agentSnapshot{
 		AgentID:          a.agentID(),
 		UUID:             a.uuid.String(),
 		ResourcePoolName: a.resourcePoolName,
 		// TODO(ilia): we need to disambiguate user setting (which needs to be saved)
 		// vs current state.
-		UserEnabled:           a.enabled,
-		UserDraining:          a.draining,
+		UserEnabled:           userEnabled,
+		UserDraining:          userDraining,
 		MaxZeroSlotContainers: a.maxZeroSlotContainers,
 		Slots:                 slots,
 		Containers:            containerIds,
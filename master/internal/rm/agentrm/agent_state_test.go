@@ -448,3 +448,41 @@ func TestSlotStates(t *testing.T) {
 	state.enable()
 	require.Equal(t, 2, state.numSlots())
 }
+
+func TestSlotHealth(t *testing.T) {
+	state := newAgentState(aproto.ID(uuid.NewString()), 64)
+	state.handler = &agent{}
+	state.resourcePoolName = "test"
+	devices := []device.Device{
+		{ID: 0, Brand: "nvda", UUID: uuid.NewString(), Type: "3090"},
+		{ID: 1, Brand: "nvda", UUID: uuid.NewString(), Type: "3090"},
+	}
+	state.agentStarted(&aproto.AgentStarted{
+		Devices:              devices,
+		ContainersReattached: []aproto.ContainerReattachAck{},
+		ResourcePoolName:     defaultResourcePoolName,
+	})
+	require.Equal(t, 2, state.numSlots())
+
+	report := aproto.AgentHealthReport{
+		UnhealthyDevices: []aproto.UnhealthyDevice{{Device: devices[1], Reason: "fell off the bus"}},
+	}
+	changed := state.healthReported(report)
+	require.Len(t, changed, 1)
+	require.Equal(t, device.ID(1), changed[0].device.ID)
+	require.Equal(t, 1, state.numSlots())
+	slot := state.getSlotSummary(1)
+	require.False(t, slot.Enabled)
+	require.Equal(t, "fell off the bus", slot.UnhealthyReason)
+
+	// Repeated reports change nothing, and users cannot enable unhealthy slots.
+	require.Empty(t, state.healthReported(report))
+	_, err := state.patchSlotState(patchSlotState{id: 1, enabled: ptrs.Ptr(true)})
+	require.NoError(t, err)
+	require.Equal(t, 1, state.numSlots())
+
+	changed = state.healthReported(aproto.AgentHealthReport{})
+	require.Len(t, changed, 1)
+	require.Equal(t, 2, state.numSlots())
+	require.Empty(t, state.getSlotSummary(1).UnhealthyReason)
+}
@@ -0,0 +1,231 @@
+package agentrm
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/rm/tasklist"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/schedulerv1"
+)
+
+type externalScheduler struct {
+	client  schedulerv1.ExternalSchedulerClient
+	timeout time.Duration
+	// fallback schedules the resource pool when the external scheduler fails or times out. It also
+	// orders the job queue.
+	fallback Scheduler
+
+	mu sync.Mutex
+	// calling is whether a call to the external scheduler is in progress.
+	calling bool
+	// stale is whether the resource pool was scheduled again during the call, so that its state
+	// may have changed since the snapshot that the call sent.
+	stale bool
+	// result is the result of the last call, if it finished and was not applied yet.
+	result *externalScheduleResult
+}
+
+type externalScheduleResult struct {
+	resp *schedulerv1.ScheduleResponse
+	err  error
+}
+
+// NewExternalScheduler creates a new scheduler that sends a snapshot of the resource pool to an
+// external gRPC service and applies the decisions it returns. If the service does not answer in
+// time, or fails, the resource pool is scheduled by the priority scheduler instead.
+func NewExternalScheduler(conf *config.SchedulerConfig) (Scheduler, error) {
+	creds := insecure.NewCredentials()
+	if conf.External.TLS {
+		creds = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	}
+	conn, err := grpc.NewClient(conf.External.Endpoint, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("creating client for external scheduler %s: %w", conf.External.Endpoint, err)
+	}
+	return &externalScheduler{
+		client:   schedulerv1.NewExternalSchedulerClient(conn),
+		timeout:  time.Duration(conf.External.Timeout),
+		fallback: NewPriorityScheduler(conf),
+	}, nil
+}
+
+// Schedule applies the decisions of the last call to the external scheduler, if one finished, and
+// otherwise starts a call with a snapshot of the resource pool. The call is made without holding
+// the lock of the resource pool, which is scheduled again once the call finishes; until then,
+// nothing is scheduled. Decisions are validated against the state of the resource pool when they
+// are applied, since it may have changed during the call.
+func (e *externalScheduler) Schedule(rp *resourcePool) ([]*sproto.AllocateRequest, []model.AllocationID) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	switch {
+	case e.calling:
+		e.stale = true
+		return nil, nil
+	case e.result != nil:
+		result := e.result
+		e.result = nil
+		if e.stale {
+			// Make another call with the current state once these decisions are applied.
+			e.stale = false
+			go rp.requestReschedule()
+		}
+		if result.err != nil {
+			rp.syslog.WithError(result.err).Warn("external scheduler failed, falling back to the priority scheduler")
+			return e.fallback.Schedule(rp)
+		}
+		return rp.validateExternalDecisions(result.resp)
+	}
+
+	e.calling = true
+	req := externalScheduleRequest(rp)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+		defer cancel()
+		resp, err := e.client.Schedule(ctx, req)
+
+		e.mu.Lock()
+		e.calling = false
+		e.result = &externalScheduleResult{resp: resp, err: err}
+		e.mu.Unlock()
+		rp.requestReschedule()
+	}()
+	return nil, nil
+}
+
+func (e *externalScheduler) JobQInfo(rp *resourcePool) map[model.JobID]*sproto.RMJobInfo {
+	return e.fallback.JobQInfo(rp)
+}
+
+// externalScheduleRequest returns a snapshot of the task list, groups, queue positions and agents
+// of the resource pool.
+func externalScheduleRequest(rp *resourcePool) *schedulerv1.ScheduleRequest {
+	req := &schedulerv1.ScheduleRequest{ResourcePool: rp.config.PoolName}
+
+	for it := rp.taskList.Iterator(); it.Next(); {
+		task := it.Value()
+		alloc := &schedulerv1.SchedulingAllocation{
+			AllocationId:      string(task.AllocationID),
+			TaskId:            string(task.TaskID),
+			JobId:             string(task.JobID),
+			Name:              task.Name,
+			SlotsNeeded:       int32(task.SlotsNeeded),
+			SingleAgent:       task.FittingRequirements.SingleAgent,
+			Preemptible:       task.Preemption.Preemptible,
+			Scheduled:         rp.taskList.IsScheduled(task.AllocationID),
+			BlockedAgentIds:   task.BlockedNodes,
+			JobSubmissionTime: timestamppb.New(task.JobSubmissionTime),
+			TimeLimitSeconds:  int64(task.TimeLimit / time.Second),
+			Workspace:         task.Workspace,
+			Username:          task.Username,
+		}
+		if allocated := rp.taskList.Allocation(task.AllocationID); allocated != nil {
+			for _, r := range allocated.Resources {
+				if cr, ok := r.(*containerResources); ok {
+					alloc.AgentIds = append(alloc.AgentIds, string(cr.agent.id))
+				}
+			}
+			sort.Strings(alloc.AgentIds)
+		}
+		if reason, ok := tasklist.QuotaHolds.Reason(task.AllocationID); ok {
+			alloc.QuotaHold = reason
+		}
+		req.Allocations = append(req.Allocations, alloc)
+	}
+
+	for _, g := range rp.groups {
+		group := &schedulerv1.SchedulingGroup{JobId: string(g.JobID), Weight: g.Weight}
+		if g.Priority != nil {
+			group.Priority = ptrs.Ptr(int32(*g.Priority))
+		}
+		if g.MaxSlots != nil {
+			group.MaxSlots = ptrs.Ptr(int32(*g.MaxSlots))
+		}
+		if position, ok := rp.queuePositions[g.JobID]; ok {
+			group.QueuePosition = position.String()
+		}
+		req.Groups = append(req.Groups, group)
+	}
+	sort.Slice(req.Groups, func(i, j int) bool { return req.Groups[i].JobId < req.Groups[j].JobId })
+
+	for _, agent := range rp.agentStatesCache {
+		req.Agents = append(req.Agents, &schedulerv1.SchedulingAgent{
+			Id:                    string(agent.id),
+			Slots:                 int32(agent.numSlots()),
+			UsedSlots:             int32(agent.numUsedSlots()),
+			MaxZeroSlotContainers: int32(agent.maxZeroSlotContainers),
+			ZeroSlotContainers:    int32(agent.numUsedZeroSlots()),
+			Enabled:               agent.enabled,
+			Draining:              agent.draining,
+		})
+	}
+	sort.Slice(req.Agents, func(i, j int) bool { return req.Agents[i].Id < req.Agents[j].Id })
+
+	return req
+}
+
+// validateExternalDecisions returns the decisions of the external scheduler that the resource pool
+// can apply. Only pending allocations that are not held by a quota are started, and only
+// scheduled, preemptible allocations are released; other decisions are logged and dropped.
+// Allocations that do not fit on the agents are skipped when they are allocated.
+func (rp *resourcePool) validateExternalDecisions(
+	resp *schedulerv1.ScheduleResponse,
+) ([]*sproto.AllocateRequest, []model.AllocationID) {
+	toAllocate := make([]*sproto.AllocateRequest, 0, len(resp.Allocate))
+	seen := make(map[model.AllocationID]bool)
+	for _, id := range resp.Allocate {
+		allocationID := model.AllocationID(id)
+		if seen[allocationID] {
+			continue
+		}
+		seen[allocationID] = true
+
+		req, ok := rp.taskList.TaskByID(allocationID)
+		switch {
+		case !ok:
+			rp.syslog.Warnf("external scheduler allocated unknown allocation %s", id)
+		case rp.taskList.IsScheduled(allocationID):
+			rp.syslog.Warnf("external scheduler allocated scheduled allocation %s", id)
+		case tasklist.QuotaHolds.Held(allocationID):
+			rp.syslog.Warnf("external scheduler allocated allocation %s, which is held by a quota", id)
+		default:
+			toAllocate = append(toAllocate, req)
+		}
+	}
+
+	toRelease := make([]model.AllocationID, 0, len(resp.Release))
+	seen = make(map[model.AllocationID]bool)
+	for _, id := range resp.Release {
+		allocationID := model.AllocationID(id)
+		if seen[allocationID] {
+			continue
+		}
+		seen[allocationID] = true
+
+		req, ok := rp.taskList.TaskByID(allocationID)
+		switch {
+		case !ok:
+			rp.syslog.Warnf("external scheduler released unknown allocation %s", id)
+		case !rp.taskList.IsScheduled(allocationID):
+			rp.syslog.Warnf("external scheduler released pending allocation %s", id)
+		case !req.Preemption.Preemptible:
+			rp.syslog.Warnf("external scheduler released allocation %s, which is not preemptible", id)
+		default:
+			toRelease = append(toRelease, allocationID)
+		}
+	}
+	return toAllocate, toRelease
+}
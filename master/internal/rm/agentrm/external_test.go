@@ -0,0 +1,204 @@
+package agentrm
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/rm/tasklist"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/schedulerv1"
+)
+
+type mockExternalScheduler struct {
+	schedulerv1.UnimplementedExternalSchedulerServer
+	delay    time.Duration
+	resp     *schedulerv1.ScheduleResponse
+	received *schedulerv1.ScheduleRequest
+}
+
+func (m *mockExternalScheduler) Schedule(
+	ctx context.Context, req *schedulerv1.ScheduleRequest,
+) (*schedulerv1.ScheduleResponse, error) {
+	m.received = req
+	select {
+	case <-time.After(m.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return m.resp, nil
+}
+
+func setupExternalPool(t *testing.T, server *mockExternalScheduler) *resourcePool {
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	schedulerv1.RegisterExternalSchedulerServer(s, server)
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, conn.Close())
+	})
+
+	priority := 42
+	conf := &config.ResourcePoolConfig{
+		PoolName: "pool",
+		Scheduler: &config.SchedulerConfig{
+			External: &config.ExternalSchedulerConfig{
+				Endpoint: "bufnet",
+				Timeout:  model.Duration(time.Second),
+				Fallback: &config.PrioritySchedulerConfig{DefaultPriority: &priority},
+			},
+			FittingPolicy: best,
+		},
+	}
+	agents := []*MockAgent{
+		{ID: "agent1", Slots: 4},
+		{ID: "agent2", Slots: 4},
+	}
+	low, high := 50, 10
+	groups := []*MockGroup{
+		{ID: "running", Priority: &priority, Weight: 1},
+		{ID: "pinned", Priority: &priority, Weight: 1},
+		{ID: "low", Priority: &low, Weight: 1},
+		{ID: "high", Priority: &high, Weight: 2},
+	}
+	tasks := []*MockTask{
+		{ID: "running", Group: groups[0], SlotsNeeded: 2, AllocatedAgent: agents[0], ContainerStarted: true},
+		{
+			ID: "pinned", Group: groups[1], SlotsNeeded: 2, AllocatedAgent: agents[0], ContainerStarted: true,
+			NonPreemptible: true,
+		},
+		{ID: "low", Group: groups[2], SlotsNeeded: 4, Workspace: "ws", Username: "alice"},
+		{ID: "high", Group: groups[3], SlotsNeeded: 4},
+	}
+
+	rp := &resourcePool{
+		syslog:         logrus.WithField("component", "resource-pool"),
+		config:         conf,
+		fittingMethod:  BestFit,
+		queuePositions: tasklist.InitializeJobSortState(false),
+		scheduler: &externalScheduler{
+			client:   schedulerv1.NewExternalSchedulerClient(conn),
+			timeout:  time.Duration(conf.Scheduler.External.Timeout),
+			fallback: NewPriorityScheduler(conf.Scheduler),
+		},
+	}
+	rp.taskList, rp.groups, rp.agentStatesCache = setupSchedulerStates(t, tasks, groups, agents)
+	return rp
+}
+
+// scheduleExternal schedules the resource pool once the call to the external scheduler that the
+// first attempt starts has finished.
+func scheduleExternal(t *testing.T, rp *resourcePool) ([]*sproto.AllocateRequest, []model.AllocationID) {
+	toAllocate, toRelease := rp.scheduler.Schedule(rp)
+	require.Empty(t, toAllocate)
+	require.Empty(t, toRelease)
+	require.Eventually(t, func() bool {
+		rp.mu.Lock()
+		defer rp.mu.Unlock()
+		return rp.reschedule
+	}, 5*time.Second, 10*time.Millisecond)
+	rp.reschedule = false
+	return rp.scheduler.Schedule(rp)
+}
+
+func TestExternalSchedulerAppliesValidDecisions(t *testing.T) {
+	server := &mockExternalScheduler{resp: &schedulerv1.ScheduleResponse{
+		// The external policy prefers the low-priority job; unknown, scheduled and duplicate
+		// allocations are dropped.
+		Allocate: []string{"low", "unknown", "running", "low"},
+		// Pending and non-preemptible allocations cannot be released.
+		Release: []string{"running", "high", "pinned"},
+	}}
+	rp := setupExternalPool(t, server)
+
+	toAllocate, toRelease := scheduleExternal(t, rp)
+	require.Len(t, toAllocate, 1)
+	require.Equal(t, model.AllocationID("low"), toAllocate[0].AllocationID)
+	require.Equal(t, []model.AllocationID{"running"}, toRelease)
+
+	req := server.received
+	require.Equal(t, "pool", req.ResourcePool)
+	require.Len(t, req.Allocations, 4)
+	byID := make(map[string]*schedulerv1.SchedulingAllocation)
+	for _, alloc := range req.Allocations {
+		byID[alloc.AllocationId] = alloc
+	}
+	require.True(t, byID["running"].Scheduled)
+	require.Equal(t, []string{"agent1"}, byID["running"].AgentIds)
+	require.False(t, byID["pinned"].Preemptible)
+	require.False(t, byID["low"].Scheduled)
+	require.Equal(t, "alice", byID["low"].Username)
+
+	require.Len(t, req.Groups, 4)
+	require.Equal(t, "high", req.Groups[0].JobId)
+	require.Equal(t, int32(10), *req.Groups[0].Priority)
+	require.Equal(t, 2.0, req.Groups[0].Weight)
+
+	require.Len(t, req.Agents, 2)
+	require.Equal(t, "agent1", req.Agents[0].Id)
+	require.Equal(t, int32(4), req.Agents[0].UsedSlots)
+	require.Equal(t, int32(0), req.Agents[1].UsedSlots)
+}
+
+func TestExternalSchedulerFallsBackOnTimeout(t *testing.T) {
+	server := &mockExternalScheduler{
+		delay: time.Minute,
+		resp:  &schedulerv1.ScheduleResponse{Allocate: []string{"low"}},
+	}
+	rp := setupExternalPool(t, server)
+	rp.scheduler.(*externalScheduler).timeout = 50 * time.Millisecond
+
+	// The priority scheduler starts the high-priority job on the free agent instead.
+	toAllocate, toRelease := scheduleExternal(t, rp)
+	require.Len(t, toAllocate, 1)
+	require.Equal(t, model.AllocationID("high"), toAllocate[0].AllocationID)
+	require.Empty(t, toRelease)
+}
+
+func TestExternalSchedulerDoesNotBlock(t *testing.T) {
+	server := &mockExternalScheduler{
+		delay: 200 * time.Millisecond,
+		resp:  &schedulerv1.ScheduleResponse{Allocate: []string{"low"}},
+	}
+	rp := setupExternalPool(t, server)
+
+	// Nothing is scheduled while the external scheduler is called, and scheduling again during the
+	// call does not wait for it or make another call.
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		toAllocate, toRelease := rp.scheduler.Schedule(rp)
+		require.Empty(t, toAllocate)
+		require.Empty(t, toRelease)
+	}
+	require.Less(t, time.Since(start), server.delay)
+
+	// The decisions are applied once the call finishes, and since the resource pool was scheduled
+	// during the call, it is scheduled again to make another call with its current state.
+	toAllocate, _ := scheduleExternal(t, rp)
+	require.Len(t, toAllocate, 1)
+	require.Eventually(t, func() bool {
+		rp.mu.Lock()
+		defer rp.mu.Unlock()
+		return rp.reschedule
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -5,6 +5,7 @@ import (
 	"sort"
 	"time"
 
+	"github.com/determined-ai/determined/master/internal/config"
 	"github.com/determined-ai/determined/master/internal/rm/tasklist"
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/pkg/aproto"
@@ -13,13 +14,23 @@ import (
 	"github.com/determined-ai/determined/master/pkg/model"
 )
 
-type fairShare struct{}
+type fairShare struct {
+	hierarchical *config.HierarchicalFairShareConfig
+	// shares are the slots offered to each job, its user and its workspace by the last
+	// hierarchical scheduling pass.
+	shares map[model.JobID]*sproto.JobFairShare
+}
 
 // NewFairShareScheduler creates a new scheduler that schedules tasks according to the max-min
 // fairness of groups. For groups that are above their fair share, the scheduler requests
-// them to terminate their idle tasks until they have achieved their fair share.
-func NewFairShareScheduler() Scheduler {
-	return &fairShare{}
+// them to terminate their idle tasks until they have achieved their fair share. If the config
+// enables hierarchical fair share, slots are shared across workspaces and users before groups.
+func NewFairShareScheduler(conf *config.FairShareSchedulerConfig) Scheduler {
+	f := &fairShare{}
+	if conf != nil {
+		f.hierarchical = conf.Hierarchical
+	}
+	return f
 }
 
 type groupState struct {
@@ -50,13 +61,16 @@ func (g *groupState) String() string {
 }
 
 func (f *fairShare) Schedule(rp *resourcePool) ([]*sproto.AllocateRequest, []model.AllocationID) {
-	return fairshareSchedule(
+	toAllocate, toRelease, shares := hierarchicalFairshareSchedule(
 		rp.taskList,
 		rp.groups,
 		rp.agentStatesCache,
 		rp.fittingMethod,
 		rp.config.Scheduler.AllowHeterogeneousFits,
+		f.hierarchical,
 	)
+	f.shares = shares
+	return toAllocate, toRelease
 }
 
 func (f *fairShare) createJobQInfo(
@@ -76,6 +90,11 @@ func (f *fairShare) createJobQInfo(
 
 func (f *fairShare) JobQInfo(rp *resourcePool) map[model.JobID]*sproto.RMJobInfo {
 	jobQ := f.createJobQInfo(rp.taskList)
+	if f.hierarchical != nil {
+		for jobID, info := range jobQ {
+			info.FairShare = f.shares[jobID]
+		}
+	}
 	return jobQ
 }
 
@@ -86,6 +105,23 @@ func fairshareSchedule(
 	fittingMethod SoftConstraint,
 	allowHeterogeneousAgentFits bool,
 ) ([]*sproto.AllocateRequest, []model.AllocationID) {
+	toAllocate, toRelease, _ := hierarchicalFairshareSchedule(
+		taskList, groups, agents, fittingMethod, allowHeterogeneousAgentFits, nil,
+	)
+	return toAllocate, toRelease
+}
+
+// hierarchicalFairshareSchedule schedules tasks like fairshareSchedule, but if hierarchical is set,
+// the slots are shared across workspaces, then users, then groups. It also returns the slots
+// offered to each job, its user and its workspace in that case.
+func hierarchicalFairshareSchedule(
+	taskList *tasklist.TaskList,
+	groups map[model.JobID]*tasklist.Group,
+	agents map[aproto.ID]*agentState,
+	fittingMethod SoftConstraint,
+	allowHeterogeneousAgentFits bool,
+	hierarchical *config.HierarchicalFairShareConfig,
+) ([]*sproto.AllocateRequest, []model.AllocationID, map[model.JobID]*sproto.JobFairShare) {
 	allToAllocate := make([]*sproto.AllocateRequest, 0)
 	allToRelease := make([]model.AllocationID, 0)
 
@@ -121,7 +157,12 @@ func fairshareSchedule(
 		taskList, groups, capacity, agents, fittingMethod, allowHeterogeneousAgentFits,
 	)
 
-	allocateSlotOffers(groupStates, capacity)
+	var shares map[model.JobID]*sproto.JobFairShare
+	if hierarchical != nil {
+		shares = allocateHierarchicalSlotOffers(groupStates, capacity, *hierarchical)
+	} else {
+		allocateSlotOffers(groupStates, capacity)
+	}
 	toAllocate, toRelease := assignTasks(
 		agents,
 		groupStates,
@@ -131,7 +172,7 @@ func fairshareSchedule(
 	allToAllocate = append(allToAllocate, toAllocate...)
 	allToRelease = append(allToRelease, toRelease...)
 
-	return allToAllocate, allToRelease
+	return allToAllocate, allToRelease, shares
 }
 
 func totalCapacity(agents map[aproto.ID]*agentState) int {
@@ -162,11 +203,15 @@ func calculateGroupStates(
 			continue
 		}
 
-		// Remove any tasks that cannot be scheduled from consideration.
+		// Remove any tasks that cannot be scheduled, or that are held by a quota, from
+		// consideration.
 		// This is more than a performance optimization. This is needed to
 		// prevent tasks that will fail a hard constraint from "wasting" offered slots
 		// and potentially preventing progress from being made.
 		if taskList.Allocation(req.AllocationID) == nil {
+			if tasklist.QuotaHolds.Held(req.AllocationID) {
+				continue
+			}
 			if fits := findFits(
 				req,
 				agents,
@@ -349,6 +394,93 @@ func allocateSlotOffers(states []*groupState, capacity int) {
 	}
 }
 
+// fairShareNode is a workspace or a user that slots are shared across in hierarchical fair share.
+// Its state sums up the states of the groups that belong to it.
+type fairShareNode struct {
+	name     string
+	state    *groupState
+	children []*groupState
+}
+
+// capacity returns the number of slots to share across the children of the node.
+func (n *fairShareNode) capacity() int {
+	return mathx.Max(n.state.offered, n.state.presubscribedSlots)
+}
+
+// groupFairShareNodes partitions the group states into nodes by the given key of their requests,
+// in order of first appearance.
+func groupFairShareNodes(
+	states []*groupState, key func(*sproto.AllocateRequest) string, weight func(string) float64,
+) []*fairShareNode {
+	var nodes []*fairShareNode
+	byName := make(map[string]*fairShareNode)
+	for _, state := range states {
+		name := key(state.reqs[0])
+		node, ok := byName[name]
+		if !ok {
+			node = &fairShareNode{
+				name: name,
+				state: &groupState{
+					Group:          &tasklist.Group{Weight: weight(name)},
+					registeredTime: state.registeredTime,
+				},
+			}
+			nodes = append(nodes, node)
+			byName[name] = node
+		}
+		node.children = append(node.children, state)
+		node.state.slotDemand += state.slotDemand
+		node.state.activeSlots += state.activeSlots
+		node.state.presubscribedSlots += state.presubscribedSlots
+		node.state.pendingReqs = append(node.state.pendingReqs, state.pendingReqs...)
+		if state.registeredTime.Before(node.state.registeredTime) {
+			node.state.registeredTime = state.registeredTime
+		}
+	}
+	return nodes
+}
+
+func fairShareNodeStates(nodes []*fairShareNode) []*groupState {
+	states := make([]*groupState, 0, len(nodes))
+	for _, node := range nodes {
+		states = append(states, node.state)
+	}
+	return states
+}
+
+// allocateHierarchicalSlotOffers offers slots to groups level by level: the capacity is shared
+// across workspaces, the slots offered to each workspace are shared across its users, and the
+// slots offered to each user are shared across their groups. Each level uses the same max-min
+// fairness as allocateSlotOffers, so a workspace with many groups gets no more slots than one
+// with a single group of the same weight. It returns the slots offered to each job, its user and
+// its workspace.
+func allocateHierarchicalSlotOffers(
+	states []*groupState, capacity int, conf config.HierarchicalFairShareConfig,
+) map[model.JobID]*sproto.JobFairShare {
+	shares := make(map[model.JobID]*sproto.JobFairShare)
+	workspaces := groupFairShareNodes(states,
+		func(req *sproto.AllocateRequest) string { return req.Workspace }, conf.WorkspaceWeight)
+	allocateSlotOffers(fairShareNodeStates(workspaces), capacity)
+	for _, workspace := range workspaces {
+		users := groupFairShareNodes(workspace.children,
+			func(req *sproto.AllocateRequest) string { return req.Username }, conf.UserWeight)
+		allocateSlotOffers(fairShareNodeStates(users), workspace.capacity())
+		for _, user := range users {
+			allocateSlotOffers(user.children, user.capacity())
+			for _, state := range user.children {
+				shares[state.JobID] = &sproto.JobFairShare{
+					Workspace:      workspace.name,
+					Username:       user.name,
+					WorkspaceSlots: workspace.state.offered,
+					UserSlots:      user.state.offered,
+					JobSlots:       state.offered,
+				}
+			}
+		}
+	}
+	return shares
+}
+
 func calculateSmallestAllocatableTask(state *groupState) (smallest *sproto.AllocateRequest) {
 	for _, req := range state.pendingReqs {
 		if smallest == nil || req.SlotsNeeded < smallest.SlotsNeeded {
@@ -1,7 +1,14 @@
 package agentrm
 
 import (
+	"fmt"
 	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/model"
 )
 
 func TestFairShareMaxSlots(t *testing.T) {
@@ -442,3 +449,99 @@ func TestFairShareBlocklistEqual(t *testing.T) {
 	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
 	assertEqualToRelease(t, taskList, toRelease, expectedToRelease)
 }
+
+func TestFairShareHierarchicalWorkspaces(t *testing.T) {
+	agents := []*MockAgent{
+		{ID: "agent", Slots: 8},
+	}
+	groups := []*MockGroup{
+		{ID: "group1", Weight: 1},
+		{ID: "group2", Weight: 1},
+		{ID: "group3", Weight: 1},
+		{ID: "group4", Weight: 1},
+		{ID: "group5", Weight: 1},
+	}
+	var tasks []*MockTask
+	for _, group := range groups[:4] {
+		for i := 0; i < 4; i++ {
+			tasks = append(tasks, &MockTask{
+				ID: model.AllocationID(fmt.Sprintf("%s-task%d", group.ID, i)), Group: group,
+				SlotsNeeded: 1, Workspace: "crowded", Username: "alice",
+			})
+		}
+	}
+	for i := 0; i < 8; i++ {
+		tasks = append(tasks, &MockTask{
+			ID: model.AllocationID(fmt.Sprintf("group5-task%d", i)), Group: groups[4],
+			SlotsNeeded: 1, Workspace: "quiet", Username: "bob",
+		})
+	}
+
+	// Without hierarchy, every job gets the same share, so the crowded workspace gets most slots.
+	taskList, groupMap, agentMap := setupSchedulerStates(t, tasks, groups, agents)
+	toAllocate, _ := fairshareSchedule(taskList, groupMap, agentMap, BestFit, false)
+	quiet := 0
+	for _, req := range toAllocate {
+		if req.Workspace == "quiet" {
+			quiet++
+		}
+	}
+	assert.Equal(t, len(toAllocate), 8)
+	assert.Assert(t, quiet < 4, "quiet workspace got %d slots", quiet)
+
+	// With it, each workspace gets half of the slots, no matter how many jobs it runs.
+	expectedToAllocate := []*MockTask{tasks[0], tasks[4], tasks[8], tasks[12]}
+	expectedToAllocate = append(expectedToAllocate, tasks[16:20]...)
+
+	taskList, groupMap, agentMap = setupSchedulerStates(t, tasks, groups, agents)
+	toAllocate, toRelease, shares := hierarchicalFairshareSchedule(
+		taskList, groupMap, agentMap, BestFit, false, &config.HierarchicalFairShareConfig{},
+	)
+	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
+	assertEqualToRelease(t, taskList, toRelease, []*MockTask{})
+	assert.DeepEqual(t, shares["group1"], &sproto.JobFairShare{
+		Workspace: "crowded", Username: "alice", WorkspaceSlots: 4, UserSlots: 4, JobSlots: 1,
+	})
+	assert.DeepEqual(t, shares["group5"], &sproto.JobFairShare{
+		Workspace: "quiet", Username: "bob", WorkspaceSlots: 4, UserSlots: 4, JobSlots: 4,
+	})
+}
+
+func TestFairShareHierarchicalWeights(t *testing.T) {
+	agents := []*MockAgent{
+		{ID: "agent", Slots: 8},
+	}
+	groups := []*MockGroup{
+		{ID: "group1", Weight: 1},
+		{ID: "group2", Weight: 1},
+		{ID: "group3", Weight: 1},
+	}
+	var tasks []*MockTask
+	for i, group := range groups {
+		username := "alice"
+		if i == 2 {
+			username = "bob"
+		}
+		for j := 0; j < 8; j++ {
+			tasks = append(tasks, &MockTask{
+				ID: model.AllocationID(fmt.Sprintf("%s-task%d", group.ID, j)), Group: group,
+				SlotsNeeded: 1, Workspace: "workspace", Username: username,
+			})
+		}
+	}
+
+	// Bob has three times the weight of Alice, so Alice's two jobs share two slots.
+	expectedToAllocate := []*MockTask{tasks[0], tasks[8]}
+	expectedToAllocate = append(expectedToAllocate, tasks[16:22]...)
+
+	taskList, groupMap, agentMap := setupSchedulerStates(t, tasks, groups, agents)
+	toAllocate, toRelease, shares := hierarchicalFairshareSchedule(
+		taskList, groupMap, agentMap, BestFit, false,
+		&config.HierarchicalFairShareConfig{UserWeights: map[string]float64{"bob": 3}},
+	)
+	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
+	assertEqualToRelease(t, taskList, toRelease, []*MockTask{})
+	assert.Equal(t, shares["group1"].UserSlots, 2)
+	assert.Equal(t, shares["group3"].UserSlots, 6)
+	assert.Equal(t, shares["group3"].WorkspaceSlots, 8)
+}
@@ -3,6 +3,7 @@ package agentrm
 import (
 	"fmt"
 	"slices"
+	"time"
 
 	"github.com/determined-ai/determined/master/internal/sproto"
 )
@@ -32,6 +33,19 @@ func agentSlotUnusedSatisfied(_ *sproto.AllocateRequest, agent *agentState) bool
 	return agent.numUsedSlots() == 0
 }
 
+func agentReservationSatisfied(req *sproto.AllocateRequest, agent *agentState) bool {
+	return req.SlotsNeeded == 0 || agent.reservedFor == "" || agent.reservedFor == req.JobID
+}
+
+// agentMaintenanceSatisfied keeps tasks off agents whose next maintenance window is within the
+// resource pool's maintenance_scheduling_horizon unless their time limits end before it starts.
+func agentMaintenanceSatisfied(req *sproto.AllocateRequest, agent *agentState) bool {
+	if agent.maintenanceStart.IsZero() {
+		return true
+	}
+	return req.TimeLimit > 0 && !time.Now().Add(req.TimeLimit).After(agent.maintenanceStart)
+}
+
 // Soft Constraints
 
 // BestFit returns a float affinity score between 0 and 1 for the affinity between the task and
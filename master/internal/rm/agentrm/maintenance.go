@@ -0,0 +1,233 @@
+package agentrm
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/internal/api"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/rm/rmevents"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+// DefaultMaintenanceCheckpointLead is how long before a maintenance window preemptible jobs on the
+// agent are checkpointed, unless the window says otherwise.
+const DefaultMaintenanceCheckpointLead = 10 * time.Minute
+
+// DefaultMaintenanceSchedulingHorizon is how long before a maintenance window the agent only runs
+// jobs whose time limits end before the window, unless the resource pool says otherwise.
+const DefaultMaintenanceSchedulingHorizon = 24 * time.Hour
+
+// ScheduleMaintenance adds a maintenance window to the agent.
+func (a *agent) ScheduleMaintenance(
+	req *apiv1.ScheduleAgentMaintenanceRequest,
+) (*apiv1.ScheduleAgentMaintenanceResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	w := model.AgentMaintenanceWindow{
+		AgentID:               string(a.id),
+		StartTime:             req.StartTime.AsTime(),
+		EndTime:               req.EndTime.AsTime(),
+		Reason:                req.Reason,
+		CheckpointLeadSeconds: int(DefaultMaintenanceCheckpointLead / time.Second),
+	}
+	if req.CheckpointLeadSeconds != nil {
+		w.CheckpointLeadSeconds = int(*req.CheckpointLeadSeconds)
+	}
+	if err := db.AddAgentMaintenanceWindow(context.TODO(), &w); err != nil {
+		return nil, err
+	}
+	a.syslog.Infof("scheduled maintenance window %d from %s to %s", w.ID, w.StartTime, w.EndTime)
+
+	a.maintenanceWindows = append(a.maintenanceWindows, w)
+	sort.SliceStable(a.maintenanceWindows, func(i, j int) bool {
+		return a.maintenanceWindows[i].StartTime.Before(a.maintenanceWindows[j].StartTime)
+	})
+	a.updateMaintenance(time.Now())
+	return &apiv1.ScheduleAgentMaintenanceResponse{Window: w.ToProto()}, nil
+}
+
+// CancelMaintenance removes a maintenance window of the agent.
+func (a *agent) CancelMaintenance(
+	req *apiv1.CancelAgentMaintenanceRequest,
+) (*apiv1.CancelAgentMaintenanceResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	err := db.DeleteAgentMaintenanceWindow(context.TODO(), string(a.id), int(req.WindowId))
+	switch {
+	case errors.Is(err, db.ErrNotFound):
+		return nil, api.NotFoundErrs("maintenance window", fmt.Sprint(req.WindowId), true)
+	case err != nil:
+		return nil, err
+	}
+	a.syslog.Infof("canceled maintenance window %d", req.WindowId)
+
+	a.maintenanceWindows = slices.DeleteFunc(a.maintenanceWindows,
+		func(w model.AgentMaintenanceWindow) bool { return w.ID == int(req.WindowId) })
+	a.updateMaintenance(time.Now())
+	return &apiv1.CancelAgentMaintenanceResponse{}, nil
+}
+
+// loadMaintenanceWindows loads the agent's maintenance windows that have not ended yet.
+func (a *agent) loadMaintenanceWindows() {
+	windows, err := db.AgentMaintenanceWindows(context.TODO(), string(a.id), time.Now())
+	if err != nil {
+		a.syslog.WithError(err).Error("failed to load maintenance windows")
+		return
+	}
+	a.maintenanceWindows = windows
+	a.updateMaintenance(time.Now())
+}
+
+func (a *agent) handleMaintenanceTimer() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.updateMaintenance(time.Now())
+}
+
+// updateMaintenance moves the agent in and out of its maintenance windows as of now, checkpoints
+// preemptible jobs ahead of the next window, and sets a timer for the next change. Agents that
+// are not started or are reconnecting are updated once they are back.
+func (a *agent) updateMaintenance(now time.Time) {
+	if a.maintenanceTimer != nil {
+		a.maintenanceTimer.Stop()
+		a.maintenanceTimer = nil
+	}
+	a.maintenanceWindows = slices.DeleteFunc(a.maintenanceWindows,
+		func(w model.AgentMaintenanceWindow) bool { return !w.EndTime.After(now) })
+	if !a.started || a.awaitingReconnect || a.agentState == nil {
+		return
+	}
+
+	// Windows may overlap, so the agent is in maintenance until the last started one ends.
+	var current, next *model.AgentMaintenanceWindow
+	for i := range a.maintenanceWindows {
+		w := &a.maintenanceWindows[i]
+		switch {
+		case w.StartTime.After(now):
+			if next == nil {
+				next = w
+			}
+		case current == nil || w.EndTime.After(current.EndTime):
+			current = w
+		}
+	}
+
+	changed := false
+	switch {
+	case current != nil && !a.agentState.inMaintenance:
+		a.enterMaintenance(*current)
+		changed = true
+	case current == nil && a.agentState.inMaintenance:
+		a.exitMaintenance()
+		changed = true
+	}
+
+	var nextStart time.Time
+	if next != nil {
+		if !a.restrictTime(*next).After(now) {
+			nextStart = next.StartTime
+		}
+		if !next.CheckpointTime().After(now) && a.checkpointedWindow != next.ID {
+			a.checkpointedWindow = next.ID
+			a.checkpointForMaintenance(*next)
+		}
+	}
+	if !a.agentState.maintenanceStart.Equal(nextStart) {
+		a.agentState.maintenanceStart = nextStart
+		changed = true
+	}
+
+	var wake time.Time
+	for _, t := range a.maintenanceChanges(current, next) {
+		if t.After(now) && (wake.IsZero() || t.Before(wake)) {
+			wake = t
+		}
+	}
+	if !wake.IsZero() {
+		a.maintenanceTimer = time.AfterFunc(wake.Sub(now), a.handleMaintenanceTimer)
+	}
+	if changed {
+		a.notifyListeners()
+	}
+}
+
+func (a *agent) maintenanceChanges(current, next *model.AgentMaintenanceWindow) []time.Time {
+	var changes []time.Time
+	if current != nil {
+		changes = append(changes, current.EndTime)
+	}
+	if next != nil {
+		changes = append(changes, a.restrictTime(*next), next.CheckpointTime(), next.StartTime)
+	}
+	return changes
+}
+
+// restrictTime is when the agent starts to only run jobs that end before the window, which is
+// no later than when preemptible jobs are checkpointed, so they are not rescheduled onto it.
+func (a *agent) restrictTime(w model.AgentMaintenanceWindow) time.Time {
+	t := w.StartTime.Add(-a.maintenanceHorizon)
+	if checkpoint := w.CheckpointTime(); checkpoint.Before(t) {
+		return checkpoint
+	}
+	return t
+}
+
+// enterMaintenance disables the agent, killing what still runs on it, and stashes its state to
+// return to afterward.
+func (a *agent) enterMaintenance(w model.AgentMaintenanceWindow) {
+	a.syslog.Infof("starting maintenance window %d until %s: %s", w.ID, w.EndTime, w.Reason)
+	a.agentState.inMaintenance = true
+	a.agentState.preMaintenanceEnabled = a.agentState.enabled
+	a.agentState.preMaintenanceDraining = a.agentState.draining
+
+	a.agentState.disable(false)
+	a.agentState.patchAllSlotsState(patchAllSlotsState{
+		enabled: &a.agentState.enabled,
+		drain:   &a.agentState.draining,
+	})
+	for _, aID := range a.agentState.containerAllocation {
+		rmevents.Publish(aID, &sproto.ReleaseResources{
+			Reason:    "agent maintenance",
+			ForceKill: true,
+		})
+	}
+}
+
+// exitMaintenance returns the agent to its state from before the maintenance window.
+func (a *agent) exitMaintenance() {
+	a.syslog.Info("ending maintenance")
+	a.agentState.inMaintenance = false
+	if a.agentState.preMaintenanceEnabled {
+		a.agentState.enable()
+	} else {
+		a.agentState.disable(a.agentState.preMaintenanceDraining)
+	}
+	a.agentState.patchAllSlotsState(patchAllSlotsState{
+		enabled: &a.agentState.enabled,
+		drain:   &a.agentState.draining,
+	})
+}
+
+// checkpointForMaintenance preempts the preemptible allocations on the agent, so they checkpoint
+// and are rescheduled elsewhere before the window starts.
+func (a *agent) checkpointForMaintenance(w model.AgentMaintenanceWindow) {
+	a.syslog.Infof("checkpointing preemptible allocations for maintenance window %d at %s",
+		w.ID, w.StartTime)
+	for _, aID := range a.agentState.containerAllocation {
+		rmevents.Publish(aID, &sproto.ReleaseResources{
+			Reason:      fmt.Sprintf("agent maintenance at %s", w.StartTime.Format(time.RFC3339)),
+			PreemptOnly: true,
+		})
+	}
+}
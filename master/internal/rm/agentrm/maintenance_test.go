@@ -0,0 +1,195 @@
+package agentrm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/aproto"
+	"github.com/determined-ai/determined/master/pkg/device"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/syncx/queue"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func newMaintenanceTestAgent(t *testing.T, windows ...model.AgentMaintenanceWindow) *agent {
+	state := newAgentState(aproto.ID("agent"), 0)
+	a := &agent{
+		syslog:             logrus.WithField("component", "agent"),
+		id:                 "agent",
+		agentUpdates:       queue.New[agentUpdatedEvent](),
+		started:            true,
+		agentState:         state,
+		maintenanceWindows: windows,
+		maintenanceHorizon: DefaultMaintenanceSchedulingHorizon,
+	}
+	state.handler = a
+	for i := 0; i < 2; i++ {
+		state.slotStates[device.ID(i)] = &slot{
+			device:  device.Device{ID: device.ID(i)},
+			enabled: slotEnabled{agentEnabled: true, userEnabled: true},
+		}
+		state.updateSlotDeviceView(device.ID(i))
+	}
+	t.Cleanup(func() {
+		if a.maintenanceTimer != nil {
+			a.maintenanceTimer.Stop()
+		}
+	})
+	return a
+}
+
+func TestAgentMaintenanceSatisfied(t *testing.T) {
+	agent := newFakeAgentState(t, "agent", 1, 0, 0, 0)
+	require.True(t, agentMaintenanceSatisfied(&sproto.AllocateRequest{}, agent))
+
+	agent.maintenanceStart = time.Now().Add(time.Hour)
+	require.False(t, agentMaintenanceSatisfied(&sproto.AllocateRequest{}, agent),
+		"tasks without time limits may not finish before the window")
+	require.True(t, agentMaintenanceSatisfied(
+		&sproto.AllocateRequest{TimeLimit: 30 * time.Minute}, agent))
+	require.False(t, agentMaintenanceSatisfied(
+		&sproto.AllocateRequest{TimeLimit: 2 * time.Hour}, agent))
+}
+
+func TestUpdateMaintenance(t *testing.T) {
+	now := time.Now()
+	a := newMaintenanceTestAgent(t, model.AgentMaintenanceWindow{
+		ID:                    1,
+		StartTime:             now.Add(time.Hour),
+		EndTime:               now.Add(2 * time.Hour),
+		CheckpointLeadSeconds: 600,
+	})
+
+	a.updateMaintenance(now)
+	require.False(t, a.agentState.inMaintenance)
+	require.Equal(t, now.Add(time.Hour), a.agentState.maintenanceStart)
+	require.Zero(t, a.checkpointedWindow)
+	require.NotNil(t, a.maintenanceTimer)
+
+	a.updateMaintenance(now.Add(55 * time.Minute))
+	require.False(t, a.agentState.inMaintenance)
+	require.Equal(t, 1, a.checkpointedWindow)
+
+	a.updateMaintenance(now.Add(time.Hour))
+	require.True(t, a.agentState.inMaintenance)
+	require.True(t, a.summarize().InMaintenance)
+	require.False(t, a.agentState.enabled)
+	require.Zero(t, a.agentState.numSlots())
+	require.True(t, a.agentState.maintenanceStart.IsZero())
+
+	a.updateMaintenance(now.Add(2 * time.Hour))
+	require.False(t, a.agentState.inMaintenance)
+	require.True(t, a.agentState.enabled)
+	require.Equal(t, 2, a.agentState.numSlots())
+	require.Empty(t, a.maintenanceWindows)
+	require.Nil(t, a.maintenanceTimer)
+}
+
+func TestUpdateMaintenanceKeepsDisabledAgentDisabled(t *testing.T) {
+	now := time.Now()
+	a := newMaintenanceTestAgent(t, model.AgentMaintenanceWindow{
+		ID:        1,
+		StartTime: now,
+		EndTime:   now.Add(time.Hour),
+	})
+	a.agentState.disable(true)
+
+	a.updateMaintenance(now)
+	require.True(t, a.agentState.inMaintenance)
+	require.False(t, a.agentState.draining)
+
+	a.updateMaintenance(now.Add(time.Hour))
+	require.False(t, a.agentState.inMaintenance)
+	require.False(t, a.agentState.enabled)
+	require.True(t, a.agentState.draining)
+}
+
+func TestUpdateMaintenanceWhileReconnecting(t *testing.T) {
+	now := time.Now()
+	a := newMaintenanceTestAgent(t, model.AgentMaintenanceWindow{
+		ID:        1,
+		StartTime: now,
+		EndTime:   now.Add(time.Hour),
+	})
+	a.awaitingReconnect = true
+
+	a.updateMaintenance(now)
+	require.False(t, a.agentState.inMaintenance, "reconnecting agents are updated once they are back")
+
+	a.awaitingReconnect = false
+	a.updateMaintenance(now)
+	require.True(t, a.agentState.inMaintenance)
+}
+
+func TestUpdateMaintenanceHorizon(t *testing.T) {
+	now := time.Now()
+	start := now.Add(2 * DefaultMaintenanceSchedulingHorizon)
+	a := newMaintenanceTestAgent(t, model.AgentMaintenanceWindow{
+		ID:        1,
+		StartTime: start,
+		EndTime:   start.Add(time.Hour),
+	})
+
+	a.updateMaintenance(now)
+	require.True(t, a.agentState.maintenanceStart.IsZero(),
+		"windows beyond the horizon do not restrict scheduling yet")
+	require.NotNil(t, a.maintenanceTimer)
+
+	a.updateMaintenance(start.Add(-DefaultMaintenanceSchedulingHorizon))
+	require.Equal(t, start, a.agentState.maintenanceStart)
+}
+
+func TestRestoreDuringMaintenance(t *testing.T) {
+	now := time.Now()
+	window := model.AgentMaintenanceWindow{
+		ID:        1,
+		StartTime: now,
+		EndTime:   now.Add(time.Hour),
+	}
+	a := newMaintenanceTestAgent(t, window)
+	a.updateMaintenance(now)
+	require.True(t, a.agentState.inMaintenance)
+	require.False(t, a.agentState.enabled)
+
+	// The master restarts during the window and restores the agent from its snapshot.
+	state, err := newAgentStateFromSnapshot(*a.agentState.snapshot())
+	require.NoError(t, err)
+	require.True(t, state.enabled, "snapshots save the state from before the window")
+	restored := newMaintenanceTestAgent(t, window)
+	restored.agentState = state
+	state.handler = restored
+
+	restored.updateMaintenance(now.Add(time.Minute))
+	require.True(t, restored.agentState.inMaintenance)
+	require.False(t, restored.agentState.enabled)
+
+	restored.updateMaintenance(now.Add(time.Hour))
+	require.False(t, restored.agentState.inMaintenance)
+	require.True(t, restored.agentState.enabled)
+	require.Equal(t, 2, restored.agentState.numSlots())
+}
+
+func TestEnableAndDisableAgentDuringMaintenance(t *testing.T) {
+	now := time.Now()
+	a := newMaintenanceTestAgent(t, model.AgentMaintenanceWindow{
+		ID:        1,
+		StartTime: now,
+		EndTime:   now.Add(time.Hour),
+	})
+	a.updateMaintenance(now)
+
+	_, err := a.EnableAgent(&apiv1.EnableAgentRequest{})
+	require.ErrorIs(t, err, errInMaintenance)
+
+	_, err = a.DisableAgent(&apiv1.DisableAgentRequest{Drain: true})
+	require.NoError(t, err)
+	require.False(t, a.agentState.draining, "the agent stays disabled during the window")
+
+	a.updateMaintenance(now.Add(time.Hour))
+	require.False(t, a.agentState.enabled, "the agent stays disabled after the window")
+	require.True(t, a.agentState.draining)
+}
@@ -32,6 +32,9 @@ type MockTask struct {
 	// Any test that set this to false is half wrong. It is used as a proxy to oversubscribe agents.
 	ContainerStarted  bool
 	JobSubmissionTime time.Time
+	TimeLimit         time.Duration
+	Workspace         string
+	Username          string
 
 	BlockedNodes []string
 }
@@ -60,11 +63,14 @@ func MockTaskToAllocateRequest(mockTask *MockTask) *sproto.AllocateRequest {
 		JobID:         model.JobID(jobID),
 		SlotsNeeded:   mockTask.SlotsNeeded,
 		IsUserVisible: true,
+		Workspace:     mockTask.Workspace,
+		Username:      mockTask.Username,
 		Preemption: sproto.PreemptionConfig{
 			Preemptible: !mockTask.NonPreemptible,
 		},
 		JobSubmissionTime: jobSubmissionTime,
 		BlockedNodes:      mockTask.BlockedNodes,
+		TimeLimit:         mockTask.TimeLimit,
 	}
 	return req
 }
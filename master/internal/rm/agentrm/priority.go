@@ -3,6 +3,7 @@ package agentrm
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -18,13 +19,18 @@ import (
 type priorityScheduler struct {
 	preemptionEnabled      bool
 	allowHeterogeneousFits bool
+	backfill               *config.BackfillConfig
+	// now returns the current time; it defaults to time.Now and is overridden in tests.
+	now func() time.Time
 }
 
 // NewPriorityScheduler creates a new scheduler that schedules tasks via priority.
 func NewPriorityScheduler(config *config.SchedulerConfig) Scheduler {
+	priority := config.PriorityConfig()
 	return &priorityScheduler{
-		preemptionEnabled:      config.Priority.Preemption,
+		preemptionEnabled:      priority.Preemption,
 		allowHeterogeneousFits: config.AllowHeterogeneousFits,
+		backfill:               priority.Backfill,
 	}
 }
 
@@ -79,7 +85,9 @@ func (p priorityScheduler) prioritySchedule(
 // prioritySchedulerWithFilter defines the logic of each scheduling circle.
 // 1. Schedule pending tasks without preemption.
 // 2. Search if preempting any lower-priority tasks can make space.
-// 3. Back-fill lower-priority pending tasks if there are no tasks to preempt.
+// 3. Back-fill lower-priority pending tasks if there are no tasks to preempt. In backfill mode,
+// the first task that cannot be scheduled holds a reservation and lower-priority tasks are only
+// started if they do not delay it.
 func (p priorityScheduler) prioritySchedulerWithFilter(
 	taskList *tasklist.TaskList,
 	groups map[model.JobID]*tasklist.Group,
@@ -102,21 +110,35 @@ func (p priorityScheduler) prioritySchedulerWithFilter(
 	)
 
 	localAgentsState := deepCopyAgents(agents)
+	placed := make(map[model.AllocationID][]*fittingState)
 
 	// If there exist any tasks that cannot be scheduled, all the tasks of lower priorities
-	// can only be backfilled if they are preemptible.
+	// can only be backfilled if they are preemptible, or, in backfill mode, if they do not delay
+	// the reservation of the first task that cannot be scheduled.
 	backfilling := false
+	var reservation *backfillReservation
 
 	for _, priority := range getOrderedPriorities(priorityToPendingTasksMap) {
 		allocationRequests := priorityToPendingTasksMap[priority]
 		log.Debugf("processing priority %d with %d pending tasks (backfilling: %v)",
 			priority, len(allocationRequests), backfilling)
 
-		successfulAllocations, unSuccessfulAllocations := p.trySchedulingPendingTasksInPriority(
-			allocationRequests,
-			localAgentsState,
-			fittingMethod,
-		)
+		var successfulAllocations, unSuccessfulAllocations []*sproto.AllocateRequest
+		if backfilling && p.backfill != nil {
+			successfulAllocations, unSuccessfulAllocations = p.tryBackfillingPendingTasksInPriority(
+				allocationRequests,
+				localAgentsState,
+				fittingMethod,
+				reservation,
+			)
+		} else {
+			successfulAllocations, unSuccessfulAllocations = p.trySchedulingPendingTasksInPriority(
+				allocationRequests,
+				localAgentsState,
+				fittingMethod,
+				placed,
+			)
+		}
 
 		// Only start tasks if there are no tasks of higher priorities to preempt.
 		if len(toRelease) == 0 {
@@ -125,6 +147,12 @@ func (p priorityScheduler) prioritySchedulerWithFilter(
 					log.Debugf("scheduled task: %s", allocatedTask.Name)
 					toAllocate = append(toAllocate, allocatedTask)
 				}
+			} else if p.backfill != nil {
+				for _, allocatedTask := range successfulAllocations {
+					log.Debugf("scheduled task via backfilling: %s", allocatedTask.Name)
+					allocatedTask.State = sproto.SchedulingStateScheduledBackfilled
+					toAllocate = append(toAllocate, allocatedTask)
+				}
 			} else if p.preemptionEnabled {
 				for _, allocatedTask := range successfulAllocations {
 					if !allocatedTask.Preemption.Preemptible {
@@ -139,6 +167,7 @@ func (p priorityScheduler) prioritySchedulerWithFilter(
 
 		// Scheduling the tasks of lower priority than the current one is considered to
 		// back-filling.
+		blocked := !backfilling && len(unSuccessfulAllocations) > 0
 		if len(unSuccessfulAllocations) > 0 {
 			backfilling = true
 		}
@@ -186,6 +215,23 @@ func (p priorityScheduler) prioritySchedulerWithFilter(
 				}
 			}
 		}
+
+		// Lower-priority tasks are not started while tasks are being preempted, so there is
+		// nothing to reserve in that case.
+		if blocked && p.backfill != nil && len(toRelease) == 0 {
+			reservation = p.reserve(
+				taskList,
+				unSuccessfulAllocations[0],
+				localAgentsState,
+				fittingMethod,
+				priorityToScheduledTaskMap,
+				placed,
+			)
+			if reservation != nil {
+				log.Debugf("reserved slots for task %s at %s",
+					unSuccessfulAllocations[0].Name, reservation.start)
+			}
+		}
 	}
 
 	toReleaseSlice := make([]model.AllocationID, 0, len(toRelease))
@@ -250,11 +296,12 @@ func (p priorityScheduler) trySchedulingTaskViaPreemption(
 
 // trySchedulingPendingTasksInPriority tries to schedule all the tasks in the
 // current priority. Note tasks are scheduled based on the order in which they
-// are listed.
+// are listed. The fits of the scheduled tasks are recorded in placed.
 func (p priorityScheduler) trySchedulingPendingTasksInPriority(
 	allocationRequests []*sproto.AllocateRequest,
 	agents map[aproto.ID]*agentState,
 	fittingMethod SoftConstraint,
+	placed map[model.AllocationID][]*fittingState,
 ) ([]*sproto.AllocateRequest, []*sproto.AllocateRequest) {
 	successfulAllocations := make([]*sproto.AllocateRequest, 0)
 	unSuccessfulAllocations := make([]*sproto.AllocateRequest, 0)
@@ -266,15 +313,198 @@ func (p priorityScheduler) trySchedulingPendingTasksInPriority(
 			continue
 		}
 		addTaskToAgents(fits)
+		placed[allocationRequest.AllocationID] = fits
 		successfulAllocations = append(successfulAllocations, allocationRequest)
 	}
 
 	return successfulAllocations, unSuccessfulAllocations
 }
 
+// tryBackfillingPendingTasksInPriority tries to schedule the tasks in the current priority while
+// a task of higher priority cannot be scheduled. A task is only scheduled if it can be preempted
+// later or if it does not delay the reservation of the blocked task. Tasks that fit but would
+// delay the reservation are neither scheduled nor returned as unsuccessful.
+func (p priorityScheduler) tryBackfillingPendingTasksInPriority(
+	allocationRequests []*sproto.AllocateRequest,
+	agents map[aproto.ID]*agentState,
+	fittingMethod SoftConstraint,
+	reservation *backfillReservation,
+) ([]*sproto.AllocateRequest, []*sproto.AllocateRequest) {
+	successfulAllocations := make([]*sproto.AllocateRequest, 0)
+	unSuccessfulAllocations := make([]*sproto.AllocateRequest, 0)
+
+	for _, allocationRequest := range allocationRequests {
+		fits := findFits(allocationRequest, agents, fittingMethod, p.allowHeterogeneousFits)
+		if len(fits) == 0 {
+			unSuccessfulAllocations = append(unSuccessfulAllocations, allocationRequest)
+			continue
+		}
+		preemptible := p.preemptionEnabled && allocationRequest.Preemption.Preemptible
+		if !preemptible {
+			if reservation == nil {
+				continue
+			}
+			end, ok := p.estimatedEnd(allocationRequest, reservation.now)
+			if !reservation.admit(fits, end, ok) {
+				log.Debugf("not backfilling task %s as it would delay the reservation",
+					allocationRequest.Name)
+				continue
+			}
+		}
+		addTaskToAgents(fits)
+		successfulAllocations = append(successfulAllocations, allocationRequest)
+	}
+
+	return successfulAllocations, unSuccessfulAllocations
+}
+
+// backfillReservation is the estimated start of the first task that cannot be scheduled, and the
+// estimated state of the agents at that time, with the reserved task placed on them.
+type backfillReservation struct {
+	now    time.Time
+	start  time.Time
+	agents map[aproto.ID]*agentState
+}
+
+// admit returns whether a task placed on the given fits now, which is estimated to end at end
+// (if bounded), does not delay the reservation. A task that ends before the reservation starts
+// is always admitted; any other task must also fit beside the reserved task, in which case its
+// slots are held in the reservation.
+func (r *backfillReservation) admit(fits []*fittingState, end time.Time, bounded bool) bool {
+	if bounded && !end.After(r.start) {
+		return true
+	}
+	for _, fit := range fits {
+		agent, ok := r.agents[fit.Agent.id]
+		if !ok || agent.numEmptySlots() < fit.Slots {
+			return false
+		}
+	}
+	for _, fit := range fits {
+		if _, err := r.agents[fit.Agent.id].allocateFreeDevices(fit.Slots, cproto.NewID()); err != nil {
+			panic(errors.Wrap(err, "can't add task to reservation"))
+		}
+	}
+	return true
+}
+
+// slotRelease is the estimated time at which an allocation releases its slots on each agent.
+type slotRelease struct {
+	end   time.Time
+	slots map[aproto.ID]int
+}
+
+// estimatedEnd returns when an allocation of the request started at start is estimated to end.
+// It returns false if the request has no time limit and there is no default.
+func (p priorityScheduler) estimatedEnd(
+	req *sproto.AllocateRequest, start time.Time,
+) (time.Time, bool) {
+	switch {
+	case req.TimeLimit > 0:
+		return start.Add(req.TimeLimit), true
+	case p.backfill != nil && p.backfill.DefaultTimeLimit != nil:
+		return start.Add(time.Duration(*p.backfill.DefaultTimeLimit)), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// reserve estimates the earliest time at which the request fits, by releasing the slots of the
+// running allocations and of the allocations scheduled in this pass in the order in which they
+// are estimated to end. Allocations without a start time or time limit are assumed to hold their
+// slots forever. It returns nil if the request does not fit before all those allocations end.
+func (p priorityScheduler) reserve(
+	taskList *tasklist.TaskList,
+	req *sproto.AllocateRequest,
+	agents map[aproto.ID]*agentState,
+	fittingMethod SoftConstraint,
+	priorityToScheduledTaskMap map[int][]*sproto.AllocateRequest,
+	placed map[model.AllocationID][]*fittingState,
+) *backfillReservation {
+	now := time.Now()
+	if p.now != nil {
+		now = p.now()
+	}
+
+	var releases []slotRelease
+	for _, scheduled := range priorityToScheduledTaskMap {
+		for _, scheduledReq := range scheduled {
+			allocated := taskList.Allocation(scheduledReq.AllocationID)
+			if allocated == nil || allocated.StartTime.IsZero() {
+				continue
+			}
+			end, ok := p.estimatedEnd(scheduledReq, allocated.StartTime)
+			if !ok {
+				continue
+			}
+			release := slotRelease{end: end, slots: make(map[aproto.ID]int)}
+			for _, resources := range allocated.Resources {
+				if container, ok := resources.(*containerResources); ok {
+					release.slots[container.agent.id] += len(container.devices)
+				}
+			}
+			releases = append(releases, release)
+		}
+	}
+	for allocationID, fits := range placed {
+		scheduledReq, ok := taskList.TaskByID(allocationID)
+		if !ok {
+			continue
+		}
+		end, ok := p.estimatedEnd(scheduledReq, now)
+		if !ok {
+			continue
+		}
+		release := slotRelease{end: end, slots: make(map[aproto.ID]int)}
+		for _, fit := range fits {
+			release.slots[fit.Agent.id] += fit.Slots
+		}
+		releases = append(releases, release)
+	}
+	sort.Slice(releases, func(i, j int) bool {
+		return releases[i].end.Before(releases[j].end)
+	})
+
+	shadow := make(map[aproto.ID]*agentState, len(agents))
+	for _, agent := range agents {
+		shadow[agent.id] = agent.deepCopy()
+	}
+	for _, release := range releases {
+		for agentID, slots := range release.slots {
+			if agent, ok := shadow[agentID]; ok {
+				releaseSlots(agent, slots)
+			}
+		}
+		if fits := findFits(req, shadow, fittingMethod, p.allowHeterogeneousFits); len(fits) > 0 {
+			addTaskToAgents(fits)
+			start := release.end
+			if start.Before(now) {
+				start = now
+			}
+			return &backfillReservation{now: now, start: start, agents: shadow}
+		}
+	}
+	return nil
+}
+
+// releaseSlots frees the given number of allocated slots on the agent. Slots on an agent are
+// interchangeable for fitting, so it does not matter which of them are freed.
+func releaseSlots(agent *agentState, slots int) {
+	for d, containerID := range agent.Devices {
+		if slots == 0 {
+			return
+		}
+		if containerID != nil {
+			agent.Devices[d] = nil
+			slots--
+		}
+	}
+}
+
 // sortTasksByPriorityAndPositionAndTimestamp sorts all pending and scheduled tasks
 // separately by priority. Within each priority, tasks are ordered
-// based on their queue position and then creation time.
+// based on their queue position and then creation time. Pending tasks that are held by a quota
+// are left out, so that they neither start nor block tasks of lower priority.
 func sortTasksByPriorityAndPositionAndTimestamp(
 	taskList *tasklist.TaskList,
 	groups map[model.JobID]*tasklist.Group,
@@ -300,7 +530,7 @@ func sortTasksByPriorityAndPositionAndTimestamp(
 				priorityToScheduledTaskMap[*priority],
 				req,
 			)
-		} else {
+		} else if !tasklist.QuotaHolds.Held(req.AllocationID) {
 			priorityToPendingTasksMap[*priority] = append(priorityToPendingTasksMap[*priority], req)
 		}
 	}
@@ -169,6 +169,42 @@ func TestPrioritySchedulingPreemptionDisabledHigherPriorityBlocksLowerPriority(t
 	}
 }
 
+func TestPrioritySchedulingQuotaHeldTaskDoesNotBlock(t *testing.T) {
+	lowerPriority := 50
+	higherPriority := 40
+
+	agents := []*MockAgent{
+		{ID: "agent1", Slots: 4},
+	}
+	groups := []*MockGroup{
+		{ID: "group1", Priority: &lowerPriority},
+		{ID: "group2", Priority: &higherPriority},
+	}
+	tasks := []*MockTask{
+		{ID: "task1", SlotsNeeded: 2, Group: groups[0]},
+		{ID: "task2", SlotsNeeded: 4, Group: groups[1]},
+	}
+
+	tasklist.QuotaHolds.Set(map[model.AllocationID]string{"task2": "quota exhausted"})
+	t.Cleanup(func() { tasklist.QuotaHolds.Set(nil) })
+
+	taskList, groupMap, agentMap := setupSchedulerStates(t, tasks, groups, agents)
+
+	p := &priorityScheduler{}
+	toAllocate, _ := p.prioritySchedule(taskList, groupMap,
+		make(map[model.JobID]decimal.Decimal), agentMap, BestFit)
+
+	expectedToAllocate := []*MockTask{tasks[0]}
+	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
+
+	tasklist.QuotaHolds.Set(nil)
+	toAllocate, _ = p.prioritySchedule(taskList, groupMap,
+		make(map[model.JobID]decimal.Decimal), agentMap, BestFit)
+
+	expectedToAllocate = []*MockTask{tasks[1]}
+	assertEqualToAllocate(t, toAllocate, expectedToAllocate)
+}
+
 func TestPrioritySchedulingPreemptionDisabledAddTasks(t *testing.T) {
 	lowerPriority := 50
 	higherPriority := 40
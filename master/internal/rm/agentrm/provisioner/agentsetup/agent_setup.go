@@ -42,6 +42,13 @@ type Provider interface {
 	Terminate(instanceIDs []string)
 }
 
+// SpotPricer is implemented by providers that can report the spot market price of their
+// instances.
+type SpotPricer interface {
+	// SpotPrice returns the current spot market price of an instance-hour.
+	SpotPrice() (float64, error)
+}
+
 // MustMakeAgentSetupScript generates the agent setup script.
 func MustMakeAgentSetupScript(config AgentSetupScriptConfig) []byte {
 	templateStr := string(etc.MustStaticFile(etc.AgentSetupScriptTemplateResource))
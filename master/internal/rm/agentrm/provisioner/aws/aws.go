@@ -60,6 +60,9 @@ func New(
 	//    "ec2:CancelSpotInstanceRequests",
 	//    "ec2:RequestSpotInstances",
 	//    "ec2:DescribeSpotInstanceRequests",
+	//    If recording the spot price of the instances for cost accounting, the following
+	//    permission will be required
+	//    "ec2:DescribeSpotPriceHistory",
 	// 2. Use a shared credentials file
 	//    In order to be able to connect to AWS, the credentials should be put in the
 	//    file `~/.aws/credential` in the format:
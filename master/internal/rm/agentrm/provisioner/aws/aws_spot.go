@@ -3,6 +3,7 @@ package aws
 import (
 	"encoding/base64"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -311,6 +312,40 @@ func (c *awsCluster) launchSpot(
 	return nil
 }
 
+// SpotPrice implements agentsetup.SpotPricer. It returns the average of the current spot prices
+// of the instance type across the availability zones of the region.
+func (c *awsCluster) SpotPrice() (float64, error) {
+	out, err := c.client.DescribeSpotPriceHistory(&ec2.DescribeSpotPriceHistoryInput{
+		InstanceTypes:       []*string{aws.String(c.config.InstanceType.Name())},
+		ProductDescriptions: []*string{aws.String("Linux/UNIX")},
+		StartTime:           aws.Time(time.Now()),
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "cannot describe spot price history")
+	}
+
+	// Starting the history now returns the current price of each availability zone.
+	latest := make(map[string]*ec2.SpotPrice)
+	for _, price := range out.SpotPriceHistory {
+		zone := aws.StringValue(price.AvailabilityZone)
+		if prev, ok := latest[zone]; !ok || aws.TimeValue(price.Timestamp).After(aws.TimeValue(prev.Timestamp)) {
+			latest[zone] = price
+		}
+	}
+	if len(latest) == 0 {
+		return 0, errors.Errorf("no spot price found for instance type %s", c.config.InstanceType.Name())
+	}
+	var total float64
+	for zone, price := range latest {
+		p, err := strconv.ParseFloat(aws.StringValue(price.SpotPrice), 64)
+		if err != nil {
+			return 0, errors.Wrapf(err, "cannot parse spot price of availability zone %s", zone)
+		}
+		total += p
+	}
+	return total / float64(len(latest)), nil
+}
+
 func (c *awsCluster) setTagsOnInstances(activeReqs *setOfSpotRequests) error {
 	instanceIDs := activeReqs.instanceIds()
 	if len(instanceIDs) == 0 {
@@ -1,6 +1,7 @@
 package provisioner
 
 import (
+	"context"
 	"crypto/tls"
 	"sync"
 	"time"
@@ -10,21 +11,25 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
+	"github.com/determined-ai/determined/master/internal/config"
 	"github.com/determined-ai/determined/master/internal/config/provconfig"
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/rm/agentrm/provisioner/agentsetup"
 	"github.com/determined-ai/determined/master/internal/rm/agentrm/provisioner/aws"
 	"github.com/determined-ai/determined/master/internal/rm/agentrm/provisioner/gcp"
 	"github.com/determined-ai/determined/master/internal/rm/agentrm/provisioner/scaledecider"
+	"github.com/determined-ai/determined/master/internal/rm/agentrm/provisioner/webhook"
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/internal/telemetry"
 	errInfo "github.com/determined-ai/determined/master/pkg/errors"
 	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
 )
 
 const (
 	actionCooldown      = 5 * time.Second
 	telemetryCooldown   = 90 * time.Second
+	spotPriceCooldown   = 10 * time.Minute
 	maxDisconnectPeriod = 10 * time.Minute
 )
 
@@ -39,22 +44,31 @@ const (
 //     which instances to terminate.
 //     2.1 It terminates instances if they stay idle for more than `maxIdleAgentPeriod` time.
 //     2.2 It checks recently launched instances and avoids provisioning more than needed.
+//     2.3 It keeps the instances of active warm pools and the instances that are needed for the
+//     demand that is forecast from the queue history.
 //  3. The instance providers take actions to launch/terminate instances.
 //  4. The rate limiter ensures telemetry does not get sent more frequently than every 90sec.
+//  5. If the resource pool tracks the spot price, the provisioner records the spot price of its
+//     instances every 10min.
 type Provisioner struct {
 	mu sync.Mutex
 
+	resourcePool     string
 	provider         agentsetup.Provider
 	scaleDecider     *scaledecider.ScaleDecider
 	telemetryLimiter *rate.Limiter
 	launchErr        *errInfo.StickyError
+	targetMin        *int
+	spotPricing      *config.PricingConfig
+	spotPriceLimiter *rate.Limiter
 
 	syslog *logrus.Entry
 }
 
 // New creates a new Provisioner.
 func New(
-	resourcePool string, config *provconfig.Config, cert *tls.Certificate, db db.DB,
+	resourcePool string, config *provconfig.Config, pricing *config.PricingConfig,
+	cert *tls.Certificate, db db.DB,
 ) (*Provisioner, error) {
 	if err := config.InitMasterAddress(); err != nil {
 		return nil, err
@@ -71,6 +85,11 @@ func New(
 		if cluster, err = gcp.New(resourcePool, config, cert); err != nil {
 			return nil, errors.Wrap(err, "cannot create a GCP cluster")
 		}
+	case config.Webhook != nil:
+		var err error
+		if cluster, err = webhook.New(resourcePool, config, cert); err != nil {
+			return nil, errors.Wrap(err, "cannot create a webhook cluster")
+		}
 	}
 
 	var launchErrorTimeout time.Duration
@@ -78,8 +97,14 @@ func New(
 		launchErrorTimeout = time.Duration(*config.LaunchErrorTimeout)
 	}
 
+	spotPricing := pricing
+	if pricing != nil && !pricing.SpotPrice {
+		spotPricing = nil
+	}
+
 	return &Provisioner{
-		provider: cluster,
+		resourcePool: resourcePool,
+		provider:     cluster,
 		scaleDecider: scaledecider.New(
 			resourcePool,
 			time.Duration(config.MaxIdleAgentPeriod),
@@ -87,10 +112,14 @@ func New(
 			maxDisconnectPeriod,
 			config.MinInstances,
 			config.MaxInstances,
+			config.WarmPools,
+			config.Forecast,
 			db,
 		),
 		telemetryLimiter: rate.NewLimiter(rate.Every(telemetryCooldown), 1),
 		launchErr:        errInfo.NewStickyError(launchErrorTimeout, config.LaunchErrorRetries),
+		spotPricing:      spotPricing,
+		spotPriceLimiter: rate.NewLimiter(rate.Every(spotPriceCooldown), 1),
 
 		syslog: logrus.WithField("component", "provisioner").
 			WithField("resource-pool", resourcePool),
@@ -178,9 +207,21 @@ func (p *Provisioner) Provision() {
 		}
 	}
 
-	if numToLaunch := p.scaleDecider.CalculateNumInstancesToLaunch(); numToLaunch > 0 {
-		p.syslog.Infof("decided to launch %d instances (type %s)",
-			numToLaunch, p.provider.InstanceType().Name())
+	if refreshed, err := p.scaleDecider.UpdateForecast(p.provider.SlotsPerInstance()); err != nil {
+		p.syslog.WithError(err).Error("cannot update demand forecast")
+	} else if refreshed {
+		p.syslog.Debug("updated demand forecast")
+	}
+
+	numToLaunch := p.scaleDecider.CalculateNumInstancesToLaunch()
+	decision := p.scaleDecider.LastLaunchDecision()
+	if p.targetMin == nil || *p.targetMin != decision.TargetMinInstances {
+		p.syslog.Infof("keeping at least %d instances: %s", decision.TargetMinInstances, decision)
+		p.targetMin = &decision.TargetMinInstances
+	}
+	if numToLaunch > 0 {
+		p.syslog.Infof("decided to launch %d instances (type %s): %s",
+			numToLaunch, p.provider.InstanceType().Name(), decision)
 		if err := p.launch(numToLaunch); err != nil {
 			p.syslog.WithError(err).Error("failure launching instances")
 		}
@@ -189,6 +230,32 @@ func (p *Provisioner) Provision() {
 	if p.telemetryLimiter.Allow() {
 		telemetry.ReportProvisionerTick(instances, p.provider.InstanceType().Name())
 	}
+
+	if p.spotPricing != nil && p.spotPriceLimiter.Allow() {
+		if err := p.recordSpotPrice(); err != nil {
+			p.syslog.WithError(err).Error("cannot record spot price")
+		}
+	}
+}
+
+// recordSpotPrice records the current spot price of a slot of the instances of the provider.
+func (p *Provisioner) recordSpotPrice() error {
+	pricer, ok := p.provider.(agentsetup.SpotPricer)
+	slots := p.provider.SlotsPerInstance()
+	if !ok || slots == 0 {
+		return nil
+	}
+	price, err := pricer.SpotPrice()
+	if err != nil {
+		return err
+	}
+	return db.RecordResourcePoolPrice(context.TODO(), &model.ResourcePoolPrice{
+		ResourcePool:  p.resourcePool,
+		SlotHourPrice: ptrs.Ptr(price / float64(slots)),
+		Currency:      p.spotPricing.Currency,
+		Source:        model.PriceSourceSpot,
+		EffectiveFrom: time.Now(),
+	})
 }
 
 func (p *Provisioner) launch(numToLaunch int) error {
@@ -198,6 +265,12 @@ func (p *Provisioner) launch(numToLaunch int) error {
 	return p.launchErr.SetError(p.provider.Launch(numToLaunch))
 }
 
+// LaunchDecision returns the most recent launching decision of the provisioner, or nil if it has
+// not made one yet. It does not wait for a running provisioning iteration.
+func (p *Provisioner) LaunchDecision() *sproto.LaunchDecision {
+	return p.scaleDecider.LastLaunchDecision()
+}
+
 // LaunchError returns the current launch error sent from the provider.
 func (p *Provisioner) LaunchError() error {
 	p.mu.Lock()
@@ -70,6 +70,8 @@ func newMockEnvironment(t *testing.T, setup *mockConfig) (*mockEnvironment, *Pro
 			setup.maxDisconnectPeriod,
 			setup.MinInstances,
 			setup.MaxInstances,
+			setup.WarmPools,
+			setup.Forecast,
 			nil,
 		),
 		telemetryLimiter: rate.NewLimiter(rate.Every(telemetryCooldown), 1),
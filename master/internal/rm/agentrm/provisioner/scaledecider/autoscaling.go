@@ -0,0 +1,84 @@
+package scaledecider
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/mathx"
+)
+
+// forecastRefreshPeriod is how often the demand forecast is refreshed from the queue history.
+const forecastRefreshPeriod = time.Minute
+
+// targetMinInstanceNum returns the number of instances to keep regardless of pending tasks and the
+// reason for it.
+func (s *ScaleDecider) targetMinInstanceNum(now time.Time) (int, string) {
+	target, reason := s.minInstanceNum, sproto.LaunchMinInstances
+	if warmPoolMin, _ := s.warmPoolMinInstanceNum(now); warmPoolMin > target {
+		target, reason = warmPoolMin, sproto.LaunchWarmPool
+	}
+	if s.forecastMinInstances > target {
+		target, reason = s.forecastMinInstances, sproto.LaunchDemandForecast
+	}
+	return mathx.Min(target, s.maxInstanceNum), reason
+}
+
+// warmPoolMinInstanceNum returns the largest minimum number of instances of the warm pools that
+// are active and the schedules of those warm pools.
+func (s *ScaleDecider) warmPoolMinInstanceNum(now time.Time) (int, []string) {
+	var minInstanceNum int
+	var active []string
+	for _, warmPool := range s.warmPools {
+		if !warmPool.Active(now) {
+			continue
+		}
+		active = append(active, warmPool.Schedule)
+		minInstanceNum = mathx.Max(minInstanceNum, warmPool.MinInstances)
+	}
+	return minInstanceNum, active
+}
+
+// UpdateForecast refreshes the demand forecast from the queue history of the resource pool if it
+// is enabled and was not refreshed recently. It returns whether the forecast was refreshed.
+func (s *ScaleDecider) UpdateForecast(slotsPerInstance int) (bool, error) {
+	s.mu.Lock()
+	forecast := s.forecast
+	now := time.Now()
+	if forecast == nil || forecast.Periods() == 0 || slotsPerInstance <= 0 ||
+		now.Before(s.forecastUpdated.Add(forecastRefreshPeriod)) {
+		s.mu.Unlock()
+		return false, nil
+	}
+	s.forecastUpdated = now
+	peakSlotDemand := s.peakSlotDemand
+	if peakSlotDemand == nil {
+		peakSlotDemand = db.PeakSlotDemand
+	}
+	s.mu.Unlock()
+
+	// For each past period, find the peak demand within the horizon from the same point of that
+	// period.
+	periods := forecast.Periods()
+	starts := make([]time.Time, 0, periods)
+	for i := 1; i <= periods; i++ {
+		starts = append(starts, now.Add(-time.Duration(i)*time.Duration(forecast.Period)))
+	}
+	demand, err := peakSlotDemand(context.TODO(), s.resourcePool, starts, time.Duration(forecast.Horizon))
+	if err != nil {
+		return false, err
+	}
+	var total int
+	for _, slots := range demand {
+		total += slots
+	}
+	forecastSlots := float64(total) / float64(periods)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.forecastSlots = forecastSlots
+	s.forecastMinInstances = int(math.Ceil(forecastSlots / float64(slotsPerInstance)))
+	return true, nil
+}
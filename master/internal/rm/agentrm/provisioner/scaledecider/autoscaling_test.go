@@ -0,0 +1,129 @@
+package scaledecider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+
+	"github.com/determined-ai/determined/master/internal/config/provconfig"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// alwaysActive is a warm pool that starts every minute and lasts for an hour.
+var alwaysActive = provconfig.WarmPoolConfig{
+	Schedule:     "* * * * *",
+	Duration:     model.Duration(time.Hour),
+	MinInstances: 3,
+}
+
+func TestWarmPoolActive(t *testing.T) {
+	workingHours := provconfig.WarmPoolConfig{
+		Schedule: "0 8 * * 1-5",
+		Duration: model.Duration(10 * time.Hour),
+	}
+	monday := time.Date(2024, 1, 8, 0, 0, 0, 0, time.Local)
+	for _, tc := range []struct {
+		at     time.Time
+		active bool
+	}{
+		{monday.Add(7 * time.Hour), false},
+		{monday.Add(8 * time.Hour), true},
+		{monday.Add(17 * time.Hour), true},
+		{monday.Add(18 * time.Hour), false},
+		{monday.Add(5*24*time.Hour + 9*time.Hour), false},
+	} {
+		assert.Equal(t, workingHours.Active(tc.at), tc.active, tc.at.String())
+	}
+}
+
+func TestCalculateNumInstancesToLaunchWarmPool(t *testing.T) {
+	s := ScaleDecider{
+		minInstanceNum: 1,
+		maxInstanceNum: 10,
+		warmPools:      []provconfig.WarmPoolConfig{alwaysActive},
+		instances: map[string]*model.Instance{
+			"instance1": {ID: "instance1", State: model.Running},
+		},
+	}
+	assert.Equal(t, s.CalculateNumInstancesToLaunch(), 2)
+
+	decision := s.LastLaunchDecision()
+	assert.Equal(t, decision.Reason, sproto.LaunchWarmPool)
+	assert.Equal(t, decision.TargetMinInstances, 3)
+	assert.DeepEqual(t, decision.ActiveWarmPools, []string{"* * * * *"})
+
+	// Pending tasks that need more instances than the warm pool take over.
+	s.desiredNewInstances = 5
+	assert.Equal(t, s.CalculateNumInstancesToLaunch(), 5)
+	assert.Equal(t, s.LastLaunchDecision().Reason, sproto.LaunchPendingTasks)
+
+	// The warm pool is limited by the maximum number of instances.
+	s.desiredNewInstances = 0
+	s.maxInstanceNum = 2
+	assert.Equal(t, s.CalculateNumInstancesToLaunch(), 1)
+	assert.Equal(t, s.LastLaunchDecision().TargetMinInstances, 2)
+}
+
+func TestFindInstancesToTerminateKeepsWarmPool(t *testing.T) {
+	s := ScaleDecider{
+		maxInstanceNum: 10,
+		warmPools:      []provconfig.WarmPoolConfig{alwaysActive},
+		instances: map[string]*model.Instance{
+			"instance1": {ID: "instance1", State: model.Running},
+			"instance2": {ID: "instance2", State: model.Running},
+			"instance3": {ID: "instance3", State: model.Running},
+			"instance4": {ID: "instance4", State: model.Running},
+		},
+		longIdle: newInstanceIDSet([]string{"instance1", "instance2", "instance3", "instance4"}),
+		idle:     make(map[string]time.Time),
+	}
+	decision := s.FindInstancesToTerminate()
+	assert.Equal(t, len(decision.InstanceIDs), 1)
+}
+
+func TestUpdateForecast(t *testing.T) {
+	now := time.Now()
+	var windows []time.Time
+	s := ScaleDecider{
+		maxInstanceNum: 10,
+		resourcePool:   "pool",
+		forecast: &provconfig.ForecastConfig{
+			Lookback: model.Duration(3 * 24 * time.Hour),
+			Period:   model.Duration(24 * time.Hour),
+			Horizon:  model.Duration(30 * time.Minute),
+		},
+		peakSlotDemand: func(
+			_ context.Context, pool string, starts []time.Time, length time.Duration,
+		) ([]int, error) {
+			assert.Equal(t, pool, "pool")
+			assert.Equal(t, length, 30*time.Minute)
+			windows = append(windows, starts...)
+			// 12, 8 and 4 slots were demanded at once on the past three days.
+			return []int{12, 8, 4}, nil
+		},
+	}
+
+	refreshed, err := s.UpdateForecast(4)
+	assert.NilError(t, err)
+	assert.Assert(t, refreshed)
+	assert.Equal(t, len(windows), 3)
+	for i, start := range windows {
+		day := time.Duration(i+1) * 24 * time.Hour
+		assert.Assert(t, !start.After(now.Add(-day).Add(time.Minute)))
+		assert.Assert(t, start.After(now.Add(-day).Add(-time.Minute)))
+	}
+	assert.Equal(t, s.forecastSlots, 8.0)
+	assert.Equal(t, s.forecastMinInstances, 2)
+
+	// The forecast is not refreshed again right away.
+	refreshed, err = s.UpdateForecast(4)
+	assert.NilError(t, err)
+	assert.Assert(t, !refreshed)
+	assert.Equal(t, len(windows), 3)
+
+	assert.Equal(t, s.CalculateNumInstancesToLaunch(), 2)
+	assert.Equal(t, s.LastLaunchDecision().Reason, sproto.LaunchDemandForecast)
+}
@@ -1,10 +1,12 @@
 package scaledecider
 
 import (
+	"context"
 	"sort"
 	"sync"
 	"time"
 
+	"github.com/determined-ai/determined/master/internal/config/provconfig"
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/sproto"
 	"github.com/determined-ai/determined/master/pkg/mathx"
@@ -24,6 +26,10 @@ import (
 //     a. The provider is starting up the instances.
 //     b. The instances are already running but agents on them are starting up.
 //     c. The agents are disconnected to the master due to misconfiguration or some unknown reason.
+//
+// Besides pending tasks, ScaleDecider keeps a minimum number of instances, which is the largest of
+// the configured minimum, the minimums of active warm pools, and the instances that are needed for
+// the demand that is forecast from the queue history.
 type ScaleDecider struct {
 	mu sync.Mutex
 
@@ -32,6 +38,8 @@ type ScaleDecider struct {
 	maxDisconnectPeriod time.Duration
 	minInstanceNum      int
 	maxInstanceNum      int
+	warmPools           []provconfig.WarmPoolConfig
+	forecast            *provconfig.ForecastConfig
 
 	instanceSnapshot       map[string]*model.Instance
 	connectedAgentSnapshot map[string]sproto.AgentSummary
@@ -47,6 +55,15 @@ type ScaleDecider struct {
 	longDisconnected map[string]bool
 	longIdle         map[string]bool
 
+	forecastUpdated      time.Time
+	forecastSlots        float64
+	forecastMinInstances int
+	lastLaunchDecision   *sproto.LaunchDecision
+	// peakSlotDemand fetches the demand history; it defaults to db.PeakSlotDemand.
+	peakSlotDemand func(
+		ctx context.Context, resourcePool string, starts []time.Time, length time.Duration,
+	) ([]int, error)
+
 	db           db.DB
 	resourcePool string
 }
@@ -58,6 +75,8 @@ func New(
 	maxDisconnectPeriod time.Duration,
 	minInstanceNum int,
 	maxInstanceNum int,
+	warmPools []provconfig.WarmPoolConfig,
+	forecast *provconfig.ForecastConfig,
 	db db.DB,
 ) *ScaleDecider {
 	return &ScaleDecider{
@@ -66,6 +85,8 @@ func New(
 		maxDisconnectPeriod:    maxDisconnectPeriod,
 		minInstanceNum:         minInstanceNum,
 		maxInstanceNum:         maxInstanceNum,
+		warmPools:              warmPools,
+		forecast:               forecast,
 		instanceSnapshot:       make(map[string]*model.Instance),
 		connectedAgentSnapshot: make(map[string]sproto.AgentSummary),
 		idleAgentSnapshot:      make(map[string]sproto.AgentSummary),
@@ -276,8 +297,9 @@ func (s *ScaleDecider) FindInstancesToTerminate() sproto.TerminateDecision {
 	}
 
 	// Terminate instances that are idle for a long time.
+	targetMinInstanceNum, _ := s.targetMinInstanceNum(time.Now())
 	for id := range s.longIdle {
-		if len(s.instances)-len(toTerminate) <= s.minInstanceNum {
+		if len(s.instances)-len(toTerminate) <= targetMinInstanceNum {
 			break
 		}
 		toTerminate[id] = sproto.TerminateLongIdleInstances
@@ -333,9 +355,44 @@ func (s *ScaleDecider) CalculateNumInstancesToLaunch() int {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	return mathx.Max(0, mathx.Clamp(
-		s.minInstanceNum-len(s.instances),
+	now := time.Now()
+	targetMinInstanceNum, reason := s.targetMinInstanceNum(now)
+	numToLaunch := mathx.Max(0, mathx.Clamp(
+		targetMinInstanceNum-len(s.instances),
 		s.desiredNewInstances-len(s.recentlyLaunched),
 		s.maxInstanceNum-len(s.instances),
 	))
+	if s.desiredNewInstances-len(s.recentlyLaunched) > targetMinInstanceNum-len(s.instances) {
+		reason = sproto.LaunchPendingTasks
+	}
+
+	warmPoolMinInstanceNum, activeWarmPools := s.warmPoolMinInstanceNum(now)
+	s.lastLaunchDecision = &sproto.LaunchDecision{
+		Time:                 now,
+		Instances:            len(s.instances),
+		RecentlyLaunched:     len(s.recentlyLaunched),
+		DesiredNewInstances:  s.desiredNewInstances,
+		MinInstances:         s.minInstanceNum,
+		WarmPoolMinInstances: warmPoolMinInstanceNum,
+		ActiveWarmPools:      activeWarmPools,
+		ForecastSlots:        s.forecastSlots,
+		ForecastMinInstances: s.forecastMinInstances,
+		TargetMinInstances:   targetMinInstanceNum,
+		MaxInstances:         s.maxInstanceNum,
+		InstancesToLaunch:    numToLaunch,
+		Reason:               reason,
+	}
+	return numToLaunch
+}
+
+// LastLaunchDecision returns the most recent launching decision, or nil if none was made yet.
+func (s *ScaleDecider) LastLaunchDecision() *sproto.LaunchDecision {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.lastLaunchDecision == nil {
+		return nil
+	}
+	decision := *s.lastLaunchDecision
+	return &decision
 }
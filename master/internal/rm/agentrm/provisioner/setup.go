@@ -6,6 +6,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
+	"github.com/determined-ai/determined/master/internal/config"
 	"github.com/determined-ai/determined/master/internal/config/provconfig"
 	"github.com/determined-ai/determined/master/internal/db"
 )
@@ -15,6 +16,7 @@ var syslog = logrus.WithField("component", "provisioner")
 // Setup initializes the provisioner.
 func Setup(
 	config *provconfig.Config,
+	pricing *config.PricingConfig,
 	resourcePool string,
 	cert *tls.Certificate,
 	db db.DB,
@@ -26,7 +28,10 @@ func Setup(
 	if config.GCP != nil {
 		syslog.Info("connecting to GCP")
 	}
-	provisioner, err := New(resourcePool, config, cert, db)
+	if config.Webhook != nil {
+		syslog.Info("connecting to webhook")
+	}
+	provisioner, err := New(resourcePool, config, pricing, cert, db)
 	if err != nil {
 		return nil, errors.Wrap(err, "error creating provisioner")
 	}
@@ -0,0 +1,229 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/master/internal/config/provconfig"
+	"github.com/determined-ai/determined/master/internal/rm/agentrm/provisioner/agentsetup"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// webhookCluster manages instances through a user-provided HTTP API. The API must serve:
+//  1. GET {url}/instances?resource_pool={pool}, which lists the instances of the resource pool.
+//  2. POST {url}/instances, which launches instances that run the agent setup script.
+//  3. POST {url}/instances/terminate, which terminates instances by ID.
+type webhookCluster struct {
+	config        *provconfig.WebhookClusterConfig
+	resourcePool  string
+	baseURL       string
+	startupScript string
+
+	client *http.Client
+
+	syslog *logrus.Entry
+}
+
+// instance is an instance as it is listed by the API.
+type instance struct {
+	ID         string    `json:"id"`
+	AgentName  string    `json:"agent_name"`
+	State      string    `json:"state"`
+	LaunchTime time.Time `json:"launch_time"`
+}
+
+type listResponse struct {
+	Instances []instance `json:"instances"`
+}
+
+type launchRequest struct {
+	ResourcePool  string            `json:"resource_pool"`
+	Count         int               `json:"count"`
+	InstanceType  string            `json:"instance_type"`
+	Slots         int               `json:"slots"`
+	StartupScript string            `json:"startup_script"`
+	Metadata      map[string]string `json:"metadata"`
+}
+
+type terminateRequest struct {
+	ResourcePool string   `json:"resource_pool"`
+	InstanceIDs  []string `json:"instance_ids"`
+}
+
+var webhookInstanceStates = map[string]model.InstanceState{
+	"starting":    model.Starting,
+	"running":     model.Running,
+	"stopping":    model.Stopping,
+	"stopped":     model.Stopped,
+	"terminating": model.Terminating,
+}
+
+// New creates a new webhook cluster.
+func New(
+	resourcePool string, config *provconfig.Config, cert *tls.Certificate,
+) (agentsetup.Provider, error) {
+	masterURL, err := url.Parse(config.MasterURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse master url")
+	}
+
+	startupScriptBase64 := base64.StdEncoding.EncodeToString([]byte(config.StartupScript))
+	containerScriptBase64 := base64.StdEncoding.EncodeToString(
+		[]byte(config.ContainerStartupScript),
+	)
+
+	var certBytes []byte
+	if masterURL.Scheme == agentsetup.SecureScheme && cert != nil {
+		for _, c := range cert.Certificate {
+			b := pem.EncodeToMemory(&pem.Block{
+				Type:  "CERTIFICATE",
+				Bytes: c,
+			})
+			certBytes = append(certBytes, b...)
+		}
+	}
+	masterCertBase64 := base64.StdEncoding.EncodeToString(certBytes)
+
+	startupScript := string(agentsetup.MustMakeAgentSetupScript(agentsetup.AgentSetupScriptConfig{
+		MasterHost:                   masterURL.Hostname(),
+		MasterPort:                   masterURL.Port(),
+		MasterCertName:               config.MasterCertName,
+		SlotType:                     config.Webhook.SlotType(),
+		AgentNetwork:                 config.AgentDockerNetwork,
+		AgentDockerRuntime:           config.AgentDockerRuntime,
+		AgentDockerImage:             config.AgentDockerImage,
+		AgentReconnectAttempts:       config.AgentReconnectAttempts,
+		AgentReconnectBackoff:        config.AgentReconnectBackoff,
+		StartupScriptBase64:          startupScriptBase64,
+		ContainerStartupScriptBase64: containerScriptBase64,
+		MasterCertBase64:             masterCertBase64,
+		AgentID:                      config.Webhook.AgentID,
+		ResourcePool:                 resourcePool,
+	}))
+
+	return &webhookCluster{
+		config:        config.Webhook,
+		resourcePool:  resourcePool,
+		baseURL:       strings.TrimSuffix(config.Webhook.URL, "/"),
+		startupScript: startupScript,
+		client:        &http.Client{Timeout: time.Duration(config.Webhook.Timeout)},
+		syslog:        logrus.WithField("webhook-cluster", resourcePool),
+	}, nil
+}
+
+func (c *webhookCluster) InstanceType() model.InstanceType {
+	return c.config.InstanceType
+}
+
+func (c *webhookCluster) SlotsPerInstance() int {
+	return c.config.SlotsPerInstance()
+}
+
+func (c *webhookCluster) List() ([]*model.Instance, error) {
+	path := "/instances?resource_pool=" + url.QueryEscape(c.resourcePool)
+	var resp listResponse
+	if err := c.do(http.MethodGet, path, nil, &resp); err != nil {
+		return nil, errors.Wrap(err, "cannot list webhook instances")
+	}
+
+	res := make([]*model.Instance, 0, len(resp.Instances))
+	for _, inst := range resp.Instances {
+		state, ok := webhookInstanceStates[strings.ToLower(inst.State)]
+		if !ok {
+			c.syslog.Errorf("unknown instance state for instance %v: %v", inst.ID, inst.State)
+			state = model.Unknown
+		}
+		res = append(res, &model.Instance{
+			ID:         inst.ID,
+			LaunchTime: inst.LaunchTime,
+			AgentName:  inst.AgentName,
+			State:      state,
+		})
+	}
+	return res, nil
+}
+
+func (c *webhookCluster) Launch(instanceNum int) error {
+	if instanceNum <= 0 {
+		return nil
+	}
+	req := launchRequest{
+		ResourcePool:  c.resourcePool,
+		Count:         instanceNum,
+		InstanceType:  c.config.InstanceType.Name(),
+		Slots:         c.config.InstanceType.Slots(),
+		StartupScript: c.startupScript,
+		Metadata:      c.config.Metadata,
+	}
+	if err := c.do(http.MethodPost, "/instances", req, nil); err != nil {
+		c.syslog.WithError(err).Errorf("error launching webhook instances")
+		return err
+	}
+	c.syslog.Infof("launched %d webhook instances", instanceNum)
+	return nil
+}
+
+func (c *webhookCluster) Terminate(instances []string) {
+	if len(instances) == 0 {
+		return
+	}
+	req := terminateRequest{ResourcePool: c.resourcePool, InstanceIDs: instances}
+	if err := c.do(http.MethodPost, "/instances/terminate", req, nil); err != nil {
+		c.syslog.WithError(err).Errorf("cannot terminate webhook instances: %s", instances)
+		return
+	}
+	c.syslog.Infof("terminated %d webhook instances: %s", len(instances), instances)
+}
+
+// do sends a request to the API and decodes its response into out, if out is non-nil.
+func (c *webhookCluster) do(method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return errors.Wrap(err, "cannot marshal request")
+		}
+		body = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, body)
+	if err != nil {
+		return errors.Wrap(err, "cannot create request")
+	}
+	for k, v := range c.config.Headers {
+		req.Header.Set(k, v)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s returned %s: %s", method, path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return errors.Wrap(err, "cannot decode response")
+	}
+	return nil
+}
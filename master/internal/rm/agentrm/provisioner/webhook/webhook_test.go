@@ -0,0 +1,99 @@
+package webhook
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/config/provconfig"
+	"github.com/determined-ai/determined/master/pkg/etc"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func newTestCluster(t *testing.T, handler http.HandlerFunc) *webhookCluster {
+	require.NoError(t, etc.SetRootPath("../../../../../static/srv/"))
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	webhookConfig := provconfig.DefaultWebhookClusterConfig()
+	webhookConfig.URL = server.URL + "/"
+	webhookConfig.Headers = map[string]string{"Authorization": "Bearer token"}
+	webhookConfig.AgentID = "$(cat /etc/instance-id)"
+	webhookConfig.Metadata = map[string]string{"flavor": "gpu.large"}
+	webhookConfig.InstanceType = provconfig.WebhookInstanceType{MachineType: "gpu.large", SlotNum: 4}
+
+	cluster, err := New("pool", &provconfig.Config{
+		MasterURL:     "http://master.example:8080",
+		StartupScript: "echo hello",
+		Webhook:       webhookConfig,
+	}, nil)
+	require.NoError(t, err)
+	return cluster.(*webhookCluster)
+}
+
+func TestWebhookList(t *testing.T) {
+	launched := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	cluster := newTestCluster(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, "/instances", r.URL.Path)
+		require.Equal(t, "pool", r.URL.Query().Get("resource_pool"))
+		require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewEncoder(w).Encode(listResponse{Instances: []instance{
+			{ID: "i-1", AgentName: "agent-1", State: "running", LaunchTime: launched},
+			{ID: "i-2", AgentName: "agent-2", State: "BOOTING", LaunchTime: launched},
+		}}))
+	})
+
+	instances, err := cluster.List()
+	require.NoError(t, err)
+	require.Equal(t, []*model.Instance{
+		{ID: "i-1", AgentName: "agent-1", State: model.Running, LaunchTime: launched},
+		{ID: "i-2", AgentName: "agent-2", State: model.Unknown, LaunchTime: launched},
+	}, instances)
+}
+
+func TestWebhookLaunch(t *testing.T) {
+	var received launchRequest
+	cluster := newTestCluster(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/instances", r.URL.Path)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	require.NoError(t, cluster.Launch(2))
+	require.Equal(t, "pool", received.ResourcePool)
+	require.Equal(t, 2, received.Count)
+	require.Equal(t, "gpu.large", received.InstanceType)
+	require.Equal(t, 4, received.Slots)
+	require.Equal(t, map[string]string{"flavor": "gpu.large"}, received.Metadata)
+	require.Contains(t, received.StartupScript, "master.example")
+	require.Contains(t, received.StartupScript, "$(cat /etc/instance-id)")
+}
+
+func TestWebhookLaunchError(t *testing.T) {
+	cluster := newTestCluster(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "quota exceeded", http.StatusServiceUnavailable)
+	})
+
+	err := cluster.Launch(1)
+	require.ErrorContains(t, err, "quota exceeded")
+}
+
+func TestWebhookTerminate(t *testing.T) {
+	var received terminateRequest
+	cluster := newTestCluster(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/instances/terminate", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	})
+
+	cluster.Terminate([]string{"i-1", "i-2"})
+	require.Equal(t, terminateRequest{ResourcePool: "pool", InstanceIDs: []string{"i-1", "i-2"}}, received)
+}
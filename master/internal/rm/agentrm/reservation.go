@@ -0,0 +1,160 @@
+package agentrm
+
+import (
+	"sort"
+	"time"
+
+	"github.com/determined-ai/determined/master/internal/rm/tasklist"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/aproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// agentReservation is a set of agents that the resource pool holds for a task that has waited
+// too long to be scheduled. The slots of reserved agents are not given to tasks of other jobs,
+// so that the agents drain until the reserved task fits on them.
+type agentReservation struct {
+	allocationID model.AllocationID
+	jobID        model.JobID
+	agents       []aproto.ID
+	slots        int
+	reservedTime time.Time
+}
+
+// toJobReservation returns the reservation as it is reported in the job queue.
+func (r *agentReservation) toJobReservation() *sproto.JobReservation {
+	agentIDs := make([]string, 0, len(r.agents))
+	for _, id := range r.agents {
+		agentIDs = append(agentIDs, string(id))
+	}
+	return &sproto.JobReservation{
+		AgentIDs:     agentIDs,
+		Slots:        r.slots,
+		ReservedTime: r.reservedTime,
+	}
+}
+
+// updateReservation reserves agents for the first pending task in the queue if it has waited
+// longer than the configured threshold and does not fit on the agents as they are, and marks the
+// reserved agents in the agent state cache. It must be called before each scheduling pass.
+func (rp *resourcePool) updateReservation(now time.Time) {
+	prev := rp.reservation
+	rp.reservation = nil
+
+	conf := rp.config.Scheduler.Reservation
+	if conf == nil {
+		return
+	}
+
+	req := rp.reservationCandidate()
+	if req == nil {
+		return
+	}
+	waitStart := req.RequestTime
+	if waitStart.IsZero() {
+		waitStart = req.JobSubmissionTime
+	}
+	if now.Sub(waitStart) < time.Duration(conf.WaitThreshold) {
+		return
+	}
+	if fits := findFits(
+		req, rp.agentStatesCache, rp.fittingMethod, rp.config.Scheduler.AllowHeterogeneousFits,
+	); len(fits) > 0 {
+		return
+	}
+
+	reservation := rp.reserveAgents(req, prev)
+	if reservation == nil {
+		rp.syslog.Debugf("no agents can be reserved for %s", req.Name)
+		return
+	}
+	if prev != nil && prev.allocationID == req.AllocationID {
+		reservation.reservedTime = prev.reservedTime
+	} else {
+		reservation.reservedTime = now
+		rp.syslog.Infof("reserving agents %v for %s after waiting %s",
+			reservation.agents, req.Name, now.Sub(waitStart).Round(time.Second))
+	}
+	for _, id := range reservation.agents {
+		rp.agentStatesCache[id].reservedFor = req.JobID
+	}
+	rp.reservation = reservation
+}
+
+// reservationCandidate returns the first task in the queue that needs slots and is neither
+// scheduled nor held by a quota, or nil if there is none.
+func (rp *resourcePool) reservationCandidate() *sproto.AllocateRequest {
+	var reqs []*sproto.AllocateRequest
+	if rp.config.Scheduler.PriorityConfig() != nil {
+		reqs = tasklist.SortTasksWithPosition(rp.taskList, rp.groups, rp.queuePositions, false)
+	} else {
+		for it := rp.taskList.Iterator(); it.Next(); {
+			reqs = append(reqs, it.Value())
+		}
+	}
+
+	for _, req := range reqs {
+		if req.SlotsNeeded > 0 && !rp.taskList.IsScheduled(req.AllocationID) &&
+			!tasklist.QuotaHolds.Held(req.AllocationID) {
+			return req
+		}
+	}
+	return nil
+}
+
+// reserveAgents picks the agents to hold for the request: the fewest agents, preferring the ones
+// that are already reserved for it and then the ones that are closest to being drained, on which
+// the request fits once they are drained. It returns nil if the request does not fit even if
+// every agent is drained.
+func (rp *resourcePool) reserveAgents(
+	req *sproto.AllocateRequest, prev *agentReservation,
+) *agentReservation {
+	held := make(map[aproto.ID]bool)
+	if prev != nil && prev.allocationID == req.AllocationID {
+		for _, id := range prev.agents {
+			held[id] = true
+		}
+	}
+
+	var candidates []*agentState
+	for _, agent := range rp.agentStatesCache {
+		if agent.enabled && !agent.draining && agentPermittedSatisfied(req, agent) {
+			candidates = append(candidates, agent)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		switch {
+		case held[a.id] != held[b.id]:
+			return held[a.id]
+		case a.numUsedSlots() != b.numUsedSlots():
+			return a.numUsedSlots() < b.numUsedSlots()
+		default:
+			return a.id < b.id
+		}
+	})
+
+	drained := make(map[aproto.ID]*agentState, len(candidates))
+	for _, agent := range candidates {
+		copied := agent.deepCopy()
+		for d := range copied.Devices {
+			copied.Devices[d] = nil
+		}
+		drained[copied.id] = copied
+
+		fits := findFits(req, drained, rp.fittingMethod, rp.config.Scheduler.AllowHeterogeneousFits)
+		if len(fits) == 0 {
+			continue
+		}
+		reservation := &agentReservation{allocationID: req.AllocationID, jobID: req.JobID}
+		for _, fit := range fits {
+			reservation.agents = append(reservation.agents, fit.Agent.id)
+			reservation.slots += fit.Slots
+		}
+		sort.Slice(reservation.agents, func(i, j int) bool {
+			return reservation.agents[i] < reservation.agents[j]
+		})
+		return reservation
+	}
+	return nil
+}
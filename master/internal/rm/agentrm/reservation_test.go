@@ -0,0 +1,129 @@
+package agentrm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/rm/tasklist"
+	"github.com/determined-ai/determined/master/internal/sproto"
+	"github.com/determined-ai/determined/master/pkg/aproto"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func setupReservationPool(t *testing.T, reservation *config.ReservationConfig) *resourcePool {
+	priority := 42
+	agents := []*MockAgent{
+		{ID: "agent1", Slots: 4},
+		{ID: "agent2", Slots: 4},
+		{ID: "agent3", Slots: 4},
+	}
+	groups := []*MockGroup{
+		{ID: "running1", Priority: &priority},
+		{ID: "running2", Priority: &priority},
+		{ID: "large", Priority: &priority},
+		{ID: "small", Priority: &priority},
+	}
+	submitted := time.Now().Add(-time.Hour)
+	// The large task needs two whole agents, but only agent3 is idle. agent2 is closer to being
+	// drained than agent1.
+	tasks := []*MockTask{
+		{
+			ID: "running1", Group: groups[0], SlotsNeeded: 3, AllocatedAgent: agents[0],
+			ContainerStarted: true, JobSubmissionTime: submitted,
+		},
+		{
+			ID: "running2", Group: groups[1], SlotsNeeded: 1, AllocatedAgent: agents[1],
+			ContainerStarted: true, JobSubmissionTime: submitted.Add(time.Second),
+		},
+		{
+			ID: "large", Group: groups[2], SlotsNeeded: 8,
+			JobSubmissionTime: submitted.Add(2 * time.Second),
+		},
+		{
+			ID: "small", Group: groups[3], SlotsNeeded: 3,
+			JobSubmissionTime: submitted.Add(3 * time.Second),
+		},
+	}
+
+	conf := &config.ResourcePoolConfig{
+		PoolName: "pool",
+		Scheduler: &config.SchedulerConfig{
+			Priority:      &config.PrioritySchedulerConfig{DefaultPriority: &priority},
+			FittingPolicy: best,
+			Reservation:   reservation,
+		},
+	}
+	scheduler, err := MakeScheduler(conf.Scheduler)
+	require.NoError(t, err)
+	rp := &resourcePool{
+		syslog:         logrus.WithField("component", "resource-pool"),
+		config:         conf,
+		scheduler:      scheduler,
+		fittingMethod:  BestFit,
+		queuePositions: tasklist.InitializeJobSortState(false),
+	}
+	rp.taskList, rp.groups, rp.agentStatesCache = setupSchedulerStates(t, tasks, groups, agents)
+	return rp
+}
+
+func TestReservationHoldsAgentsForWaitingTask(t *testing.T) {
+	rp := setupReservationPool(t, &config.ReservationConfig{
+		WaitThreshold: model.Duration(30 * time.Minute),
+	})
+	rp.updateReservation(time.Now())
+	require.NotNil(t, rp.reservation)
+	require.Equal(t, model.AllocationID("large"), rp.reservation.allocationID)
+	require.Equal(t, []aproto.ID{"agent2", "agent3"}, rp.reservation.agents)
+	require.Equal(t, 8, rp.reservation.slots)
+
+	// The small task would fit best on agent2, but that agent is held for the large task.
+	toAllocate, _ := rp.scheduler.Schedule(rp)
+	require.Empty(t, toAllocate)
+
+	jobQ := rp.GetJobQ()
+	require.Equal(t, &sproto.JobReservation{
+		AgentIDs:     []string{"agent2", "agent3"},
+		Slots:        8,
+		ReservedTime: rp.reservation.reservedTime,
+	}, jobQ["large"].Reservation)
+	require.Nil(t, jobQ["small"].Reservation)
+
+	// The reservation keeps its agents and time on later passes.
+	reservedTime := rp.reservation.reservedTime
+	rp.agentStatesCache = deepCopyAgents(rp.agentStatesCache)
+	for _, agent := range rp.agentStatesCache {
+		agent.reservedFor = ""
+	}
+	rp.updateReservation(reservedTime.Add(time.Minute))
+	require.Equal(t, []aproto.ID{"agent2", "agent3"}, rp.reservation.agents)
+	require.Equal(t, reservedTime, rp.reservation.reservedTime)
+}
+
+func TestReservationWaitThreshold(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		reservation *config.ReservationConfig
+	}{
+		{name: "disabled"},
+		{
+			name: "below threshold",
+			reservation: &config.ReservationConfig{
+				WaitThreshold: model.Duration(2 * time.Hour),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rp := setupReservationPool(t, tc.reservation)
+			rp.updateReservation(time.Now())
+			require.Nil(t, rp.reservation)
+
+			toAllocate, _ := rp.scheduler.Schedule(rp)
+			require.Len(t, toAllocate, 1)
+			require.Equal(t, model.AllocationID("small"), toAllocate[0].AllocationID)
+		})
+	}
+}
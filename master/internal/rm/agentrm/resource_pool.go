@@ -47,6 +47,8 @@ type resourcePool struct {
 	groups           map[model.JobID]*tasklist.Group
 	queuePositions   tasklist.JobSortState // secondary sort key based on job submission time
 	scalingInfo      *sproto.ScalingInfo
+	reservation      *agentReservation
+	quotaHolds       uint64 // version of the quota holds seen by the last scheduling pass
 
 	reschedule      bool
 	rescheduleTimer *time.Timer
@@ -106,7 +108,7 @@ func (rp *resourcePool) setupProvisioner() error {
 		rp.syslog.Infof("not enabling provisioner for resource pool: %s", rp.config.PoolName)
 		return nil
 	}
-	p, err := provisioner.Setup(rp.config.Provider, rp.config.PoolName, rp.cert, rp.db)
+	p, err := provisioner.Setup(rp.config.Provider, rp.config.Pricing, rp.config.PoolName, rp.cert, rp.db)
 	if err != nil {
 		return errors.Wrapf(err, "cannot create resource pool: %s", rp.config.PoolName)
 	}
@@ -311,7 +313,7 @@ func (rp *resourcePool) SetGroupPriority(msg sproto.SetGroupPriority) error {
 func (rp *resourcePool) setGroupPriority(msg sproto.SetGroupPriority) error {
 	g := rp.getOrCreateGroup(msg.JobID)
 	if (g.Priority != nil && *g.Priority == msg.Priority) ||
-		rp.config.Scheduler.Priority == nil {
+		rp.config.Scheduler.PriorityConfig() == nil {
 		return nil
 	}
 	rp.syslog.Infof("setting priority for group of %s to %d", msg.JobID, msg.Priority)
@@ -331,11 +333,11 @@ func (rp *resourcePool) getOrCreateGroup(jobID model.JobID) *tasklist.Group {
 	}
 	g := &tasklist.Group{JobID: jobID, Weight: 1}
 
-	if rp.config.Scheduler.Priority != nil {
-		if rp.config.Scheduler.Priority.DefaultPriority == nil {
+	if priority := rp.config.Scheduler.PriorityConfig(); priority != nil {
+		if priority.DefaultPriority == nil {
 			panic("default priority is not configured")
 		}
-		g.Priority = rp.config.Scheduler.Priority.DefaultPriority
+		g.Priority = priority.DefaultPriority
 	}
 
 	rp.groups[jobID] = g
@@ -345,6 +347,13 @@ func (rp *resourcePool) getOrCreateGroup(jobID model.JobID) *tasklist.Group {
 	return g
 }
 
+// requestReschedule schedules the resource pool on the next tick.
+func (rp *resourcePool) requestReschedule() {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	rp.reschedule = true
+}
+
 func (rp *resourcePool) schedulerTick() {
 	rp.mu.Lock()
 	defer rp.mu.Unlock()
@@ -357,6 +366,10 @@ func (rp *resourcePool) schedulerTick() {
 			}
 		}
 	}
+	if v := tasklist.QuotaHolds.Version(); v != rp.quotaHolds {
+		rp.quotaHolds = v
+		rp.reschedule = true
+	}
 	if rp.reschedule {
 		rp.syslog.Trace("scheduling")
 		rp.agentStatesCache = rp.agentService.list(rp.config.PoolName)
@@ -365,6 +378,7 @@ func (rp *resourcePool) schedulerTick() {
 		}()
 
 		rp.pruneTaskList()
+		rp.updateReservation(time.Now())
 		toAllocate, toRelease := rp.scheduler.Schedule(rp)
 		if len(toAllocate) > 0 || len(toRelease) > 0 {
 			rp.syslog.
@@ -464,6 +478,7 @@ func (rp *resourcePool) allocateResources(req *sproto.AllocateRequest) bool {
 		ResourcePool:      rp.config.PoolName,
 		Resources:         sprotoResources,
 		JobSubmissionTime: req.JobSubmissionTime,
+		StartTime:         time.Now().UTC(),
 	}
 	rp.taskList.AddAllocation(req.AllocationID, &allocated)
 	rmevents.Publish(req.AllocationID, allocated.Clone())
@@ -604,6 +619,15 @@ func (rp *resourcePool) GetResourceSummary() resourceSummary {
 	return rp.resourceSummaryFromAgentStates(rp.agentStatesCache)
 }
 
+// LaunchDecision returns the most recent launching decision of the provisioner of the resource
+// pool, or nil if the resource pool has no provisioner or it has not made a decision yet.
+func (rp *resourcePool) LaunchDecision() *sproto.LaunchDecision {
+	if rp.provisioner == nil {
+		return nil
+	}
+	return rp.provisioner.LaunchDecision()
+}
+
 func (rp *resourcePool) CapacityCheck(msg sproto.CapacityCheck) (sproto.CapacityCheckResponse, error) {
 	rp.mu.Lock()
 	defer rp.mu.Unlock()
@@ -640,6 +664,14 @@ func (rp *resourcePool) CapacityCheck(msg sproto.CapacityCheck) (sproto.Capacity
 	case rp.config.Provider.GCP != nil:
 		totalSlots = rp.config.Provider.MaxInstances * rp.config.Provider.GCP.SlotsPerInstance()
 
+		for id, a := range rp.agentStatesCache {
+			if blockedNodeSet.Contains(string(id)) {
+				totalSlots -= len(a.slotStates)
+			}
+		}
+	case rp.config.Provider.Webhook != nil:
+		totalSlots = rp.config.Provider.MaxInstances * rp.config.Provider.Webhook.SlotsPerInstance()
+
 		for id, a := range rp.agentStatesCache {
 			if blockedNodeSet.Contains(string(id)) {
 				totalSlots -= len(a.slotStates)
@@ -677,7 +709,14 @@ func (rp *resourcePool) GetJobQStats() *jobv1.QueueStats {
 func (rp *resourcePool) GetJobQ() map[model.JobID]*sproto.RMJobInfo {
 	rp.mu.Lock()
 	defer rp.mu.Unlock()
-	return rp.scheduler.JobQInfo(rp)
+
+	jobQ := rp.scheduler.JobQInfo(rp)
+	if rp.reservation != nil {
+		if jobInfo, ok := jobQ[rp.reservation.jobID]; ok {
+			jobInfo.Reservation = rp.reservation.toJobReservation()
+		}
+	}
+	return jobQ
 }
 
 func (rp *resourcePool) JobStopped(jobID model.JobID) {
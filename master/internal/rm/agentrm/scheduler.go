@@ -28,7 +28,9 @@ func MakeScheduler(conf *config.SchedulerConfig) (Scheduler, error) {
 		return NewPriorityScheduler(conf), nil
 	case config.FairShareScheduling:
 		log.Warn("Fair-Share Scheduler has been deprecated, please update master config to use Priority Scheduler.")
-		return NewFairShareScheduler(), nil
+		return NewFairShareScheduler(conf.FairShare), nil
+	case config.ExternalScheduling:
+		return NewExternalScheduler(conf)
 	case config.RoundRobinScheduling:
 		log.Error("Round Robin Scheduler has been removed, please update master config to use Priority Scheduler.")
 		log.Info("Priority Scheduler with all priorities equal will have the same behavior as a Round Robin Scheduler.")
@@ -2,6 +2,7 @@ package agentrm
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
@@ -564,3 +565,201 @@ func TestRoundRobinResourcePoolDeprecation(t *testing.T) {
 	_, err := MakeScheduler(conf.Scheduler)
 	require.Error(t, err)
 }
+
+// backfillSimTask is a task in a scheduling simulation: it is submitted at submit and runs for
+// duration once it is scheduled. Each task is its own job.
+type backfillSimTask struct {
+	id        string
+	priority  int
+	slots     int
+	timeLimit time.Duration
+	submit    time.Duration
+	duration  time.Duration
+}
+
+// simulateScheduling runs the priority scheduler against a virtual clock until every task has
+// finished, and returns the offset from the start of the simulation at which each task started.
+func simulateScheduling(
+	t *testing.T,
+	p *priorityScheduler,
+	mockAgents []*MockAgent,
+	tasks []backfillSimTask,
+) map[model.AllocationID]time.Duration {
+	epoch := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := epoch
+	p.now = func() time.Time { return now }
+
+	mockGroups := make([]*MockGroup, 0, len(tasks))
+	for _, task := range tasks {
+		mockGroups = append(mockGroups, &MockGroup{ID: task.id, Priority: &task.priority})
+	}
+	taskList, groups, agents := setupSchedulerStates(t, nil, mockGroups, mockAgents)
+
+	pending := make(map[model.AllocationID]backfillSimTask, len(tasks))
+	for _, task := range tasks {
+		pending[model.AllocationID(task.id)] = task
+	}
+	running := make(map[model.AllocationID]time.Time)
+	starts := make(map[model.AllocationID]time.Duration)
+
+	for step := 0; ; step++ {
+		require.Less(t, step, 10*len(tasks), "simulation did not converge")
+
+		// Finish the tasks that are done and submit the tasks that are due.
+		for id, end := range running {
+			if end.After(now) {
+				continue
+			}
+			for _, resources := range taskList.Allocation(id).Resources {
+				container := resources.(*containerResources)
+				container.agent.deallocateContainer(container.containerID)
+			}
+			taskList.RemoveTaskByID(id)
+			delete(running, id)
+		}
+		for id, task := range pending {
+			if submit := epoch.Add(task.submit); !submit.After(now) {
+				taskList.AddTask(&sproto.AllocateRequest{
+					AllocationID:      id,
+					JobID:             model.JobID(task.id),
+					Name:              task.id,
+					SlotsNeeded:       task.slots,
+					JobSubmissionTime: submit,
+					TimeLimit:         task.timeLimit,
+				})
+				delete(pending, id)
+			}
+		}
+
+		toAllocate, toRelease := p.prioritySchedule(
+			taskList, groups, make(map[model.JobID]decimal.Decimal), agents, BestFit,
+		)
+		require.Empty(t, toRelease)
+		for _, req := range toAllocate {
+			fits := findFits(req, agents, BestFit, false)
+			require.NotEmpty(t, fits, "scheduled task %s does not fit", req.AllocationID)
+			allocated := &sproto.ResourcesAllocated{
+				ID:                req.AllocationID,
+				Resources:         map[sproto.ResourcesID]sproto.Resources{},
+				JobSubmissionTime: req.JobSubmissionTime,
+				StartTime:         now,
+			}
+			for _, fit := range fits {
+				containerID := cproto.NewID()
+				devices, err := fit.Agent.allocateFreeDevices(fit.Slots, containerID)
+				require.NoError(t, err)
+				allocated.Resources[sproto.ResourcesID(containerID)] = &containerResources{
+					req:         req,
+					agent:       fit.Agent,
+					containerID: containerID,
+					devices:     devices,
+				}
+			}
+			taskList.AddAllocation(req.AllocationID, allocated)
+			starts[req.AllocationID] = now.Sub(epoch)
+		}
+		for _, task := range tasks {
+			id := model.AllocationID(task.id)
+			if _, ok := running[id]; !ok && taskList.IsScheduled(id) {
+				running[id] = now.Add(task.duration)
+			}
+		}
+
+		// Advance the clock to the next completion or submission.
+		var next time.Time
+		for _, end := range running {
+			if next.IsZero() || end.Before(next) {
+				next = end
+			}
+		}
+		for _, task := range pending {
+			if submit := epoch.Add(task.submit); next.IsZero() || submit.Before(next) {
+				next = submit
+			}
+		}
+		if next.IsZero() {
+			require.Len(t, starts, len(tasks), "simulation stalled with pending tasks")
+			return starts
+		}
+		now = next
+	}
+}
+
+func TestPriorityBackfillSimulation(t *testing.T) {
+	agents := []*MockAgent{{ID: "agent", Slots: 4}}
+	// A two-slot task holds half of the agent for two hours when a four-slot high-priority task
+	// arrives, followed by lower-priority tasks of different lengths.
+	tasks := []backfillSimTask{
+		{id: "running", priority: 50, slots: 2, timeLimit: 2 * time.Hour, duration: 2 * time.Hour},
+		{
+			id: "large", priority: 10, slots: 4, timeLimit: time.Hour,
+			submit: time.Minute, duration: time.Hour,
+		},
+		{
+			id: "short", priority: 50, slots: 2, timeLimit: time.Hour,
+			submit: 2 * time.Minute, duration: 30 * time.Minute,
+		},
+		{
+			id: "long", priority: 50, slots: 2, timeLimit: 3 * time.Hour,
+			submit: 3 * time.Minute, duration: 3 * time.Hour,
+		},
+		{id: "unbounded", priority: 50, slots: 2, submit: 4 * time.Minute, duration: time.Hour},
+	}
+
+	strict := simulateScheduling(t, &priorityScheduler{}, agents, tasks)
+	require.Equal(t, map[model.AllocationID]time.Duration{
+		"running":   0,
+		"large":     2 * time.Hour,
+		"short":     3 * time.Hour,
+		"long":      3 * time.Hour,
+		"unbounded": 3*time.Hour + 30*time.Minute,
+	}, strict)
+
+	// Only the short task fits in the gap before the large task's reservation; the others
+	// would delay it.
+	backfill := simulateScheduling(t, &priorityScheduler{backfill: &config.BackfillConfig{}},
+		agents, tasks)
+	require.Equal(t, map[model.AllocationID]time.Duration{
+		"running":   0,
+		"large":     2 * time.Hour,
+		"short":     2 * time.Minute,
+		"long":      3 * time.Hour,
+		"unbounded": 3 * time.Hour,
+	}, backfill)
+}
+
+func TestPriorityBackfillSimulationExtraSlots(t *testing.T) {
+	agents := []*MockAgent{{ID: "agent1", Slots: 4}, {ID: "agent2", Slots: 2}}
+	// No task sets a time limit. The running task holds most of the first agent, which the large
+	// task needs. Once the large task has a reservation there, the two-slot task can run
+	// indefinitely on the second agent, while the one-slot task only fits next to the running
+	// task and would delay the reservation.
+	tasks := []backfillSimTask{
+		{id: "running", priority: 50, slots: 3, duration: time.Hour},
+		{id: "large", priority: 10, slots: 4, submit: time.Minute, duration: time.Hour},
+		{id: "fits", priority: 50, slots: 2, submit: 2 * time.Minute, duration: 4 * time.Hour},
+		{id: "delays", priority: 50, slots: 1, submit: 3 * time.Minute, duration: time.Hour},
+	}
+
+	// Without a default time limit, the end of the running task cannot be estimated, so there
+	// is no reservation and nothing is backfilled.
+	noEstimate := simulateScheduling(t, &priorityScheduler{backfill: &config.BackfillConfig{}},
+		agents, tasks)
+	require.Equal(t, map[model.AllocationID]time.Duration{
+		"running": 0,
+		"large":   time.Hour,
+		"fits":    time.Hour,
+		"delays":  2 * time.Hour,
+	}, noEstimate)
+
+	defaultTimeLimit := model.Duration(time.Hour)
+	backfill := simulateScheduling(t, &priorityScheduler{
+		backfill: &config.BackfillConfig{DefaultTimeLimit: &defaultTimeLimit},
+	}, agents, tasks)
+	require.Equal(t, map[model.AllocationID]time.Duration{
+		"running": 0,
+		"large":   time.Hour,
+		"fits":    2 * time.Minute,
+		"delays":  2 * time.Hour,
+	}, backfill)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/determined-ai/determined/master/internal/config"
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/proto/pkg/jobv1"
@@ -51,3 +52,23 @@ func FetchAvgQueuedTime(pool string) (
 	})
 	return res, nil
 }
+
+// RecordResourcePoolPrice records the configured slot-hour price of a resource pool, so that costs
+// can be attributed to its allocations. If the resource pool has no price anymore, the removal is
+// recorded so that the last price does not stay in effect. The prices of resource pools that track
+// the spot price are recorded by their provisioners instead.
+func RecordResourcePoolPrice(ctx context.Context, pool config.ResourcePoolConfig) error {
+	if pool.Pricing != nil && pool.Pricing.SpotPrice {
+		return nil
+	}
+	price := &model.ResourcePoolPrice{
+		ResourcePool:  pool.PoolName,
+		Source:        model.PriceSourceConfig,
+		EffectiveFrom: time.Now(),
+	}
+	if slotHourPrice, ok := pool.SlotHourPrice(); ok {
+		price.SlotHourPrice = &slotHourPrice
+		price.Currency = pool.Pricing.Currency
+	}
+	return db.RecordResourcePoolPrice(ctx, price)
+}
@@ -2238,6 +2238,18 @@ func (m *DispatcherResourceManager) DisableSlot(*apiv1.DisableSlotRequest,
 	return nil, errNotSupportedOnHpcCluster
 }
 
+// ScheduleAgentMaintenance implements rm.ResourceManager.
+func (m *DispatcherResourceManager) ScheduleAgentMaintenance(*apiv1.ScheduleAgentMaintenanceRequest,
+) (*apiv1.ScheduleAgentMaintenanceResponse, error) {
+	return nil, errNotSupportedOnHpcCluster
+}
+
+// CancelAgentMaintenance implements rm.ResourceManager.
+func (m *DispatcherResourceManager) CancelAgentMaintenance(*apiv1.CancelAgentMaintenanceRequest,
+) (*apiv1.CancelAgentMaintenanceResponse, error) {
+	return nil, errNotSupportedOnHpcCluster
+}
+
 // SmallerValueIsHigherPriority returns true if smaller priority values indicate a higher priority level.
 func (m *DispatcherResourceManager) SmallerValueIsHigherPriority() (bool, error) {
 	return false, fmt.Errorf("priority not implemented")
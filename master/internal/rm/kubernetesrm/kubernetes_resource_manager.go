@@ -115,6 +115,10 @@ func New(
 	}
 
 	for _, poolConfig := range k.poolsConfig {
+		if err := rm.RecordResourcePoolPrice(context.TODO(), poolConfig); err != nil {
+			return nil, err
+		}
+
 		maxSlotsPerPod := 0
 		if m := k.config.MaxSlotsPerPod; m != nil {
 			maxSlotsPerPod = *m
@@ -721,6 +725,20 @@ func (k ResourceManager) DisableSlot(
 	return nil, rmerrors.ErrNotSupported
 }
 
+// ScheduleAgentMaintenance is not supported, since Kubernetes nodes are drained with Kubernetes.
+func (k ResourceManager) ScheduleAgentMaintenance(
+	req *apiv1.ScheduleAgentMaintenanceRequest,
+) (*apiv1.ScheduleAgentMaintenanceResponse, error) {
+	return nil, rmerrors.ErrNotSupported
+}
+
+// CancelAgentMaintenance is not supported, since Kubernetes nodes are drained with Kubernetes.
+func (k ResourceManager) CancelAgentMaintenance(
+	req *apiv1.CancelAgentMaintenanceRequest,
+) (*apiv1.CancelAgentMaintenanceResponse, error) {
+	return nil, rmerrors.ErrNotSupported
+}
+
 // SmallerValueIsHigherPriority returns true if smaller priority values indicate a higher priority level.
 func (k *ResourceManager) SmallerValueIsHigherPriority() (bool, error) {
 	return false, nil
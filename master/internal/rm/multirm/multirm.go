@@ -364,6 +364,32 @@ func (m *MultiRMRouter) DisableSlot(req *apiv1.DisableSlotRequest) (*apiv1.Disab
 	return m.rms[resolvedRMName].DisableSlot(req)
 }
 
+// ScheduleAgentMaintenance routes a ScheduleAgentMaintenance request to the specified resource
+// manager & agent.
+func (m *MultiRMRouter) ScheduleAgentMaintenance(req *apiv1.ScheduleAgentMaintenanceRequest) (
+	*apiv1.ScheduleAgentMaintenanceResponse, error,
+) {
+	resolvedRMName, err := m.getRMName(rm.ResourcePoolName(req.AgentId))
+	if err != nil {
+		return nil, err
+	}
+
+	return m.rms[resolvedRMName].ScheduleAgentMaintenance(req)
+}
+
+// CancelAgentMaintenance routes a CancelAgentMaintenance request to the specified resource
+// manager & agent.
+func (m *MultiRMRouter) CancelAgentMaintenance(req *apiv1.CancelAgentMaintenanceRequest) (
+	*apiv1.CancelAgentMaintenanceResponse, error,
+) {
+	resolvedRMName, err := m.getRMName(rm.ResourcePoolName(req.AgentId))
+	if err != nil {
+		return nil, err
+	}
+
+	return m.rms[resolvedRMName].CancelAgentMaintenance(req)
+}
+
 // DefaultNamespace is the default namespace used within a given Kubernetes RpM's Kubernetes cluster.
 func (m *MultiRMRouter) DefaultNamespace(clusterName string) (*string, error) {
 	if len(clusterName) == 0 {
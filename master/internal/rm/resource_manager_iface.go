@@ -52,6 +52,12 @@ type ResourceManager interface {
 	GetSlot(*apiv1.GetSlotRequest) (*apiv1.GetSlotResponse, error)
 	EnableSlot(*apiv1.EnableSlotRequest) (*apiv1.EnableSlotResponse, error)
 	DisableSlot(*apiv1.DisableSlotRequest) (*apiv1.DisableSlotResponse, error)
+	ScheduleAgentMaintenance(
+		*apiv1.ScheduleAgentMaintenanceRequest,
+	) (*apiv1.ScheduleAgentMaintenanceResponse, error)
+	CancelAgentMaintenance(
+		*apiv1.CancelAgentMaintenanceRequest,
+	) (*apiv1.CancelAgentMaintenanceResponse, error)
 	HealthCheck() []model.ResourceManagerHealth
 
 	// Kubernetes Namespaces and Quotas.
@@ -0,0 +1,52 @@
+package tasklist
+
+import (
+	"maps"
+	"sync"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// QuotaHolds is the set of pending allocations that would exceed a quota of their user or
+// workspace. Schedulers leave these allocations in the queue until they are released. It is kept
+// up to date by the quota enforcer of the config policies.
+var QuotaHolds = &Holds{}
+
+// Holds is a thread-safe set of held allocations and the reasons they are held.
+type Holds struct {
+	mu      sync.RWMutex
+	held    map[model.AllocationID]string
+	version uint64
+}
+
+// Set replaces the held allocations with the given ones.
+func (h *Holds) Set(held map[model.AllocationID]string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !maps.Equal(h.held, held) {
+		h.version++
+	}
+	h.held = held
+}
+
+// Version returns a number that changes whenever the held allocations change, so that schedulers
+// know when to reconsider them.
+func (h *Holds) Version() uint64 {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.version
+}
+
+// Reason returns why the allocation is held and whether it is held.
+func (h *Holds) Reason(id model.AllocationID) (string, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	reason, ok := h.held[id]
+	return reason, ok
+}
+
+// Held returns whether the allocation is held.
+func (h *Holds) Held(id model.AllocationID) bool {
+	_, ok := h.Reason(id)
+	return ok
+}
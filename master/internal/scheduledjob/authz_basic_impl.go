@@ -0,0 +1,58 @@
+package scheduledjob
+
+import (
+	"context"
+
+	"github.com/determined-ai/determined/master/internal/authz"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// ScheduledJobAuthZBasic is basic OSS controls.
+type ScheduledJobAuthZBasic struct{}
+
+// FilterScheduledJobs returns the scheduled jobs of the current user, or all of them if the user
+// is an admin.
+func (a *ScheduledJobAuthZBasic) FilterScheduledJobs(
+	ctx context.Context, curUser model.User, jobs []*ScheduledJob,
+) ([]*ScheduledJob, error) {
+	if curUser.Admin {
+		return jobs, nil
+	}
+	filtered := []*ScheduledJob{}
+	for _, j := range jobs {
+		if j.OwnerID == curUser.ID {
+			filtered = append(filtered, j)
+		}
+	}
+	return filtered, nil
+}
+
+// CanGetScheduledJob returns an error if the scheduled job is not owned by the current user and
+// the current user is not an admin.
+func (a *ScheduledJobAuthZBasic) CanGetScheduledJob(
+	ctx context.Context, curUser model.User, j *ScheduledJob,
+) error {
+	if !curUser.Admin && j.OwnerID != curUser.ID {
+		return authz.PermissionDeniedError{}.WithPrefix(
+			"non-admin users may not view other users' scheduled jobs",
+		)
+	}
+	return nil
+}
+
+// CanEditScheduledJob returns an error if the scheduled job is not owned by the current user and
+// the current user is not an admin.
+func (a *ScheduledJobAuthZBasic) CanEditScheduledJob(
+	ctx context.Context, curUser model.User, j *ScheduledJob,
+) error {
+	if !curUser.Admin && j.OwnerID != curUser.ID {
+		return authz.PermissionDeniedError{}.WithPrefix(
+			"non-admin users may not edit other users' scheduled jobs",
+		)
+	}
+	return nil
+}
+
+func init() {
+	AuthZProvider.Register("basic", &ScheduledJobAuthZBasic{})
+}
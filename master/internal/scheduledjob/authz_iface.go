@@ -0,0 +1,26 @@
+package scheduledjob
+
+import (
+	"context"
+
+	"github.com/determined-ai/determined/master/internal/authz"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// ScheduledJobAuthZ describes authz methods for scheduled jobs.
+type ScheduledJobAuthZ interface {
+	// GET /api/v1/scheduled-jobs
+	FilterScheduledJobs(
+		ctx context.Context, curUser model.User, jobs []*ScheduledJob,
+	) ([]*ScheduledJob, error)
+	// GET /api/v1/scheduled-jobs/:id
+	// GET /api/v1/scheduled-jobs/:id/runs
+	CanGetScheduledJob(ctx context.Context, curUser model.User, j *ScheduledJob) error
+	// DELETE /api/v1/scheduled-jobs/:id
+	// POST /api/v1/scheduled-jobs/:id/pause
+	// POST /api/v1/scheduled-jobs/:id/resume
+	CanEditScheduledJob(ctx context.Context, curUser model.User, j *ScheduledJob) error
+}
+
+// AuthZProvider is the authz registry for scheduled jobs.
+var AuthZProvider authz.AuthZProviderType[ScheduledJobAuthZ]
@@ -0,0 +1,38 @@
+package scheduledjob
+
+import (
+	"context"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// ScheduledJobAuthZPermissive is an authz provider that calls RBAC for side effects.
+type ScheduledJobAuthZPermissive struct{}
+
+// FilterScheduledJobs calls RBAC authz but enforces basic authz.
+func (a *ScheduledJobAuthZPermissive) FilterScheduledJobs(
+	ctx context.Context, curUser model.User, jobs []*ScheduledJob,
+) ([]*ScheduledJob, error) {
+	_, _ = (&ScheduledJobAuthZRBAC{}).FilterScheduledJobs(ctx, curUser, jobs)
+	return (&ScheduledJobAuthZBasic{}).FilterScheduledJobs(ctx, curUser, jobs)
+}
+
+// CanGetScheduledJob calls RBAC authz but enforces basic authz.
+func (a *ScheduledJobAuthZPermissive) CanGetScheduledJob(
+	ctx context.Context, curUser model.User, j *ScheduledJob,
+) error {
+	_ = (&ScheduledJobAuthZRBAC{}).CanGetScheduledJob(ctx, curUser, j)
+	return (&ScheduledJobAuthZBasic{}).CanGetScheduledJob(ctx, curUser, j)
+}
+
+// CanEditScheduledJob calls RBAC authz but enforces basic authz.
+func (a *ScheduledJobAuthZPermissive) CanEditScheduledJob(
+	ctx context.Context, curUser model.User, j *ScheduledJob,
+) error {
+	_ = (&ScheduledJobAuthZRBAC{}).CanEditScheduledJob(ctx, curUser, j)
+	return (&ScheduledJobAuthZBasic{}).CanEditScheduledJob(ctx, curUser, j)
+}
+
+func init() {
+	AuthZProvider.Register("permissive", &ScheduledJobAuthZPermissive{})
+}
@@ -0,0 +1,99 @@
+package scheduledjob
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/determined-ai/determined/master/internal/authz"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/rbac"
+	"github.com/determined-ai/determined/master/internal/rbac/audit"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/rbacv1"
+)
+
+// ScheduledJobAuthZRBAC is the RBAC implementation of the ScheduledJobAuthZ interface. Scheduled
+// jobs require the same permissions in their workspaces as the experiments or commands that they
+// launch.
+type ScheduledJobAuthZRBAC struct{}
+
+// viewPermission returns the permission to view the jobs that a scheduled job launches.
+func viewPermission(t Type) rbacv1.PermissionType {
+	if t == TypeExperiment {
+		return rbacv1.PermissionType_PERMISSION_TYPE_VIEW_EXPERIMENT_METADATA
+	}
+	return rbacv1.PermissionType_PERMISSION_TYPE_VIEW_NSC
+}
+
+// editPermission returns the permission to edit the jobs that a scheduled job launches.
+func editPermission(t Type) rbacv1.PermissionType {
+	if t == TypeExperiment {
+		return rbacv1.PermissionType_PERMISSION_TYPE_UPDATE_EXPERIMENT
+	}
+	return rbacv1.PermissionType_PERMISSION_TYPE_UPDATE_NSC
+}
+
+func checkForPermission(
+	ctx context.Context, curUser model.User, j *ScheduledJob, permission rbacv1.PermissionType,
+) (err error) {
+	fields := audit.ExtractLogFields(ctx)
+	fields["userID"] = curUser.ID
+	fields["permissionsRequired"] = []audit.PermissionWithSubject{
+		{
+			PermissionTypes: []rbacv1.PermissionType{permission},
+			SubjectType:     "scheduled job workspace",
+			SubjectIDs:      []string{fmt.Sprint(j.WorkspaceID)},
+		},
+	}
+	defer func() {
+		if err == nil || authz.IsPermissionDenied(err) {
+			fields["permissionGranted"] = !authz.IsPermissionDenied(err)
+			audit.Log(fields)
+		}
+	}()
+
+	workspaceID := int32(j.WorkspaceID)
+	return db.DoesPermissionMatch(ctx, curUser.ID, &workspaceID, permission)
+}
+
+// FilterScheduledJobs returns the scheduled jobs in workspaces where the user can view the jobs
+// that they launch.
+func (a *ScheduledJobAuthZRBAC) FilterScheduledJobs(
+	ctx context.Context, curUser model.User, jobs []*ScheduledJob,
+) ([]*ScheduledJob, error) {
+	scopes := make(map[Type]model.AccessScopeSet)
+	filtered := []*ScheduledJob{}
+	for _, j := range jobs {
+		if _, ok := scopes[j.Type]; !ok {
+			s, err := rbac.PermittedScopes(ctx, curUser, 0, viewPermission(j.Type))
+			if err != nil {
+				return nil, err
+			}
+			scopes[j.Type] = s
+		}
+		if scopes[j.Type][model.AccessScopeID(j.WorkspaceID)] {
+			filtered = append(filtered, j)
+		}
+	}
+	return filtered, nil
+}
+
+// CanGetScheduledJob checks if the user can view the jobs that the scheduled job launches in its
+// workspace.
+func (a *ScheduledJobAuthZRBAC) CanGetScheduledJob(
+	ctx context.Context, curUser model.User, j *ScheduledJob,
+) error {
+	return checkForPermission(ctx, curUser, j, viewPermission(j.Type))
+}
+
+// CanEditScheduledJob checks if the user can edit the jobs that the scheduled job launches in its
+// workspace.
+func (a *ScheduledJobAuthZRBAC) CanEditScheduledJob(
+	ctx context.Context, curUser model.User, j *ScheduledJob,
+) error {
+	return checkForPermission(ctx, curUser, j, editPermission(j.Type))
+}
+
+func init() {
+	AuthZProvider.Register("rbac", &ScheduledJobAuthZRBAC{})
+}
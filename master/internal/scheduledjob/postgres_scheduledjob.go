@@ -0,0 +1,118 @@
+package scheduledjob
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/db/bunutils"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+)
+
+func scheduledJobQuery(jobs any) *bun.SelectQuery {
+	return db.Bun().NewSelect().Model(jobs).
+		ColumnExpr("scheduled_job.*").
+		ColumnExpr("u.username").
+		Join("JOIN users u ON u.id = scheduled_job.owner_id")
+}
+
+// GetScheduledJob returns a scheduled job by ID. It returns db.ErrNotFound if there is none.
+func GetScheduledJob(ctx context.Context, id int) (*ScheduledJob, error) {
+	var j ScheduledJob
+	err := scheduledJobQuery(&j).Where("scheduled_job.id = ?", id).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, db.ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("getting scheduled job %d: %w", id, err)
+	}
+	return &j, nil
+}
+
+// GetScheduledJobs returns the scheduled jobs, optionally only those of a workspace.
+func GetScheduledJobs(ctx context.Context, workspaceID *int32) ([]*ScheduledJob, error) {
+	jobs := []*ScheduledJob{}
+	q := scheduledJobQuery(&jobs).Order("scheduled_job.id")
+	if workspaceID != nil {
+		q.Where("scheduled_job.workspace_id = ?", *workspaceID)
+	}
+	if err := q.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting scheduled jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+func getUnpausedScheduledJobs(ctx context.Context) ([]*ScheduledJob, error) {
+	jobs := []*ScheduledJob{}
+	if err := scheduledJobQuery(&jobs).Where("NOT scheduled_job.paused").Scan(ctx); err != nil {
+		return nil, fmt.Errorf("getting unpaused scheduled jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+func addScheduledJob(ctx context.Context, j *ScheduledJob) error {
+	if _, err := db.Bun().NewInsert().Model(j).ExcludeColumn("username").
+		Returning("id, created_at").Exec(ctx); err != nil {
+		return fmt.Errorf("adding scheduled job: %w", err)
+	}
+	return nil
+}
+
+func deleteScheduledJob(ctx context.Context, id int) error {
+	res, err := db.Bun().NewDelete().Model((*ScheduledJob)(nil)).Where("id = ?", id).Exec(ctx)
+	return db.MustHaveAffectedRows(res, err)
+}
+
+func setScheduledJobPaused(ctx context.Context, id int, paused bool) error {
+	res, err := db.Bun().NewUpdate().Model((*ScheduledJob)(nil)).
+		Set("paused = ?", paused).
+		Where("id = ?", id).
+		Exec(ctx)
+	return db.MustHaveAffectedRows(res, err)
+}
+
+func addRun(ctx context.Context, r *Run) error {
+	if _, err := db.Bun().NewInsert().Model(r).Exec(ctx); err != nil {
+		return fmt.Errorf("adding run of scheduled job %d: %w", r.ScheduledJobID, err)
+	}
+	return nil
+}
+
+// lastLaunchedRun returns the most recent run of a scheduled job that launched a job, or nil if
+// there is none.
+func lastLaunchedRun(ctx context.Context, scheduledJobID int) (*Run, error) {
+	var r Run
+	err := db.Bun().NewSelect().Model(&r).
+		Where("scheduled_job_id = ?", scheduledJobID).
+		Where("state = ?", RunStateLaunched).
+		Order("run_time DESC").
+		Limit(1).
+		Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("getting last run of scheduled job %d: %w", scheduledJobID, err)
+	}
+	return &r, nil
+}
+
+// GetRuns returns the runs of a scheduled job, most recent first.
+func GetRuns(
+	ctx context.Context, scheduledJobID int, offset, limit int,
+) ([]*Run, *apiv1.Pagination, error) {
+	runs := []*Run{}
+	q := db.Bun().NewSelect().Model(&runs).
+		Where("scheduled_job_id = ?", scheduledJobID).
+		Order("run_time DESC", "id DESC")
+	q, pagination, err := bunutils.Paginate(ctx, q, offset, limit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("paginating runs of scheduled job %d: %w", scheduledJobID, err)
+	}
+	if err := q.Scan(ctx); err != nil {
+		return nil, nil, fmt.Errorf("getting runs of scheduled job %d: %w", scheduledJobID, err)
+	}
+	return runs, pagination, nil
+}
@@ -0,0 +1,246 @@
+// Package scheduledjob launches experiments and commands on cron schedules, as their owners.
+package scheduledjob
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
+	"github.com/determined-ai/determined/proto/pkg/scheduledjobv1"
+)
+
+// Type is the kind of job that a scheduled job launches.
+type Type string
+
+const (
+	// TypeExperiment launches an experiment.
+	TypeExperiment Type = "EXPERIMENT"
+	// TypeCommand launches a command.
+	TypeCommand Type = "COMMAND"
+)
+
+// Proto returns a proto from a Type.
+func (t Type) Proto() scheduledjobv1.ScheduledJobType {
+	switch t {
+	case TypeExperiment:
+		return scheduledjobv1.ScheduledJobType_SCHEDULED_JOB_TYPE_EXPERIMENT
+	case TypeCommand:
+		return scheduledjobv1.ScheduledJobType_SCHEDULED_JOB_TYPE_COMMAND
+	default:
+		return scheduledjobv1.ScheduledJobType_SCHEDULED_JOB_TYPE_UNSPECIFIED
+	}
+}
+
+// OverlapPolicy is what a scheduled job does when it is due while the job of its previous run has
+// not finished.
+type OverlapPolicy string
+
+const (
+	// OverlapSkip skips the run.
+	OverlapSkip OverlapPolicy = "SKIP"
+	// OverlapQueue launches the job, which waits on the job of the previous run to finish.
+	OverlapQueue OverlapPolicy = "QUEUE"
+	// OverlapCancelPrevious kills the job of the previous run and launches the job.
+	OverlapCancelPrevious OverlapPolicy = "CANCEL_PREVIOUS"
+)
+
+// OverlapPolicyFromProto returns an OverlapPolicy from a proto.
+func OverlapPolicyFromProto(p scheduledjobv1.OverlapPolicy) OverlapPolicy {
+	switch p {
+	case scheduledjobv1.OverlapPolicy_OVERLAP_POLICY_QUEUE:
+		return OverlapQueue
+	case scheduledjobv1.OverlapPolicy_OVERLAP_POLICY_CANCEL_PREVIOUS:
+		return OverlapCancelPrevious
+	default:
+		return OverlapSkip
+	}
+}
+
+// Proto returns a proto from an OverlapPolicy.
+func (p OverlapPolicy) Proto() scheduledjobv1.OverlapPolicy {
+	switch p {
+	case OverlapSkip:
+		return scheduledjobv1.OverlapPolicy_OVERLAP_POLICY_SKIP
+	case OverlapQueue:
+		return scheduledjobv1.OverlapPolicy_OVERLAP_POLICY_QUEUE
+	case OverlapCancelPrevious:
+		return scheduledjobv1.OverlapPolicy_OVERLAP_POLICY_CANCEL_PREVIOUS
+	default:
+		return scheduledjobv1.OverlapPolicy_OVERLAP_POLICY_UNSPECIFIED
+	}
+}
+
+// RunState is the outcome of a run of a scheduled job.
+type RunState string
+
+const (
+	// RunStateLaunched means the job was launched.
+	RunStateLaunched RunState = "LAUNCHED"
+	// RunStateSkipped means the run was skipped since the job of the previous run had not finished.
+	RunStateSkipped RunState = "SKIPPED"
+	// RunStateFailed means the job could not be launched.
+	RunStateFailed RunState = "FAILED"
+)
+
+// Proto returns a proto from a RunState.
+func (s RunState) Proto() scheduledjobv1.ScheduledJobRunState {
+	switch s {
+	case RunStateLaunched:
+		return scheduledjobv1.ScheduledJobRunState_SCHEDULED_JOB_RUN_STATE_LAUNCHED
+	case RunStateSkipped:
+		return scheduledjobv1.ScheduledJobRunState_SCHEDULED_JOB_RUN_STATE_SKIPPED
+	case RunStateFailed:
+		return scheduledjobv1.ScheduledJobRunState_SCHEDULED_JOB_RUN_STATE_FAILED
+	default:
+		return scheduledjobv1.ScheduledJobRunState_SCHEDULED_JOB_RUN_STATE_UNSPECIFIED
+	}
+}
+
+// ScheduledJob corresponds to a row in the "scheduled_jobs" DB table.
+type ScheduledJob struct {
+	bun.BaseModel `bun:"table:scheduled_jobs"`
+
+	ID            int           `bun:"id,pk,autoincrement"`
+	Name          string        `bun:"name,notnull"`
+	Type          Type          `bun:"job_type,notnull"`
+	CronSpec      string        `bun:"cron_spec,notnull"`
+	OverlapPolicy OverlapPolicy `bun:"overlap_policy,notnull"`
+	// Request is the CreateExperimentRequest or LaunchCommandRequest of the job, in proto JSON.
+	Request     string       `bun:"request,type:jsonb,notnull"`
+	OwnerID     model.UserID `bun:"owner_id,notnull"`
+	Username    string       `bun:"username,scanonly"`
+	WorkspaceID int          `bun:"workspace_id,notnull"`
+	Paused      bool         `bun:"paused,notnull"`
+	CreatedAt   time.Time    `bun:"created_at,notnull,default:current_timestamp"`
+}
+
+// ExperimentRequest returns the request of a scheduled job that launches an experiment.
+func (j *ScheduledJob) ExperimentRequest() (*apiv1.CreateExperimentRequest, error) {
+	if j.Type != TypeExperiment {
+		return nil, fmt.Errorf("scheduled job %d does not launch an experiment", j.ID)
+	}
+	var req apiv1.CreateExperimentRequest
+	if err := protojson.Unmarshal([]byte(j.Request), &req); err != nil {
+		return nil, fmt.Errorf("parsing the request of scheduled job %d: %w", j.ID, err)
+	}
+	return &req, nil
+}
+
+// CommandRequest returns the request of a scheduled job that launches a command.
+func (j *ScheduledJob) CommandRequest() (*apiv1.LaunchCommandRequest, error) {
+	if j.Type != TypeCommand {
+		return nil, fmt.Errorf("scheduled job %d does not launch a command", j.ID)
+	}
+	var req apiv1.LaunchCommandRequest
+	if err := protojson.Unmarshal([]byte(j.Request), &req); err != nil {
+		return nil, fmt.Errorf("parsing the request of scheduled job %d: %w", j.ID, err)
+	}
+	return &req, nil
+}
+
+// ParseCronSpec parses a standard cron expression, which is in UTC unless it starts with CRON_TZ=
+// or TZ=.
+func ParseCronSpec(spec string) (cron.Schedule, error) {
+	return cron.ParseStandard(cronSpecWithLocation(spec))
+}
+
+func cronSpecWithLocation(spec string) string {
+	if strings.HasPrefix(spec, "CRON_TZ=") || strings.HasPrefix(spec, "TZ=") {
+		return spec
+	}
+	return "CRON_TZ=UTC " + spec
+}
+
+// NextRun returns when the scheduled job runs next after the given time, or nil if it is paused.
+func (j *ScheduledJob) NextRun(after time.Time) *time.Time {
+	if j.Paused {
+		return nil
+	}
+	schedule, err := ParseCronSpec(j.CronSpec)
+	if err != nil {
+		return nil
+	}
+	next := schedule.Next(after).UTC()
+	return &next
+}
+
+// Proto converts a scheduled job to its protobuf representation.
+func (j *ScheduledJob) Proto() *scheduledjobv1.ScheduledJob {
+	pb := &scheduledjobv1.ScheduledJob{
+		Id:            int32(j.ID),
+		Name:          j.Name,
+		Type:          j.Type.Proto(),
+		CronSpec:      j.CronSpec,
+		OverlapPolicy: j.OverlapPolicy.Proto(),
+		OwnerId:       int32(j.OwnerID),
+		Username:      j.Username,
+		WorkspaceId:   int32(j.WorkspaceID),
+		Paused:        j.Paused,
+		CreatedAt:     timestamppb.New(j.CreatedAt),
+	}
+	if next := j.NextRun(time.Now()); next != nil {
+		pb.NextRunTime = timestamppb.New(*next)
+	}
+	switch j.Type {
+	case TypeExperiment:
+		if req, err := j.ExperimentRequest(); err == nil {
+			pb.Config = req.Config
+			if req.Template != nil {
+				pb.TemplateName = *req.Template
+			}
+		}
+	case TypeCommand:
+		if req, err := j.CommandRequest(); err == nil {
+			if req.Config != nil {
+				if config, err := protojson.Marshal(req.Config); err == nil {
+					pb.Config = string(config)
+				}
+			}
+			pb.TemplateName = req.TemplateName
+		}
+	}
+	return pb
+}
+
+// Run corresponds to a row in the "scheduled_job_runs" DB table.
+type Run struct {
+	bun.BaseModel `bun:"table:scheduled_job_runs"`
+
+	ID             int           `bun:"id,pk,autoincrement"`
+	ScheduledJobID int           `bun:"scheduled_job_id,notnull"`
+	RunTime        time.Time     `bun:"run_time,notnull"`
+	State          RunState      `bun:"state,notnull"`
+	JobID          *model.JobID  `bun:"job_id"`
+	ExperimentID   *int          `bun:"experiment_id"`
+	TaskID         *model.TaskID `bun:"task_id"`
+	Message        string        `bun:"message,notnull"`
+}
+
+// Proto converts a run of a scheduled job to its protobuf representation.
+func (r *Run) Proto() *scheduledjobv1.ScheduledJobRun {
+	pb := &scheduledjobv1.ScheduledJobRun{
+		Id:             int32(r.ID),
+		ScheduledJobId: int32(r.ScheduledJobID),
+		RunTime:        timestamppb.New(r.RunTime),
+		State:          r.State.Proto(),
+		Message:        r.Message,
+	}
+	if r.JobID != nil {
+		pb.JobId = string(*r.JobID)
+	}
+	if r.ExperimentID != nil {
+		pb.ExperimentId = ptrs.Ptr(int32(*r.ExperimentID))
+	}
+	if r.TaskID != nil {
+		pb.TaskId = string(*r.TaskID)
+	}
+	return pb
+}
@@ -0,0 +1,126 @@
+package scheduledjob
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/proto/pkg/scheduledjobv1"
+)
+
+type fakeLauncher struct {
+	launched  [][]model.JobID
+	killed    []*Run
+	launchErr error
+}
+
+func (l *fakeLauncher) Launch(
+	_ context.Context, _ *ScheduledJob, dependsOn []model.JobID,
+) (*Run, error) {
+	if l.launchErr != nil {
+		return nil, l.launchErr
+	}
+	l.launched = append(l.launched, dependsOn)
+	return &Run{JobID: ptrs.Ptr(model.JobID("next"))}, nil
+}
+
+func (l *fakeLauncher) Kill(_ context.Context, _ *ScheduledJob, r *Run) error {
+	l.killed = append(l.killed, r)
+	return nil
+}
+
+func newTestService(outcome model.JobOutcome) (*Service, *fakeLauncher) {
+	l := &fakeLauncher{}
+	return &Service{
+		launcher: l,
+		outcomes: func(
+			_ context.Context, jobIDs []model.JobID,
+		) (map[model.JobID]model.JobOutcome, error) {
+			res := make(map[model.JobID]model.JobOutcome)
+			for _, jobID := range jobIDs {
+				res[jobID] = outcome
+			}
+			return res, nil
+		},
+	}, l
+}
+
+func TestLaunchOverlap(t *testing.T) {
+	ctx := context.Background()
+	prev := &Run{JobID: ptrs.Ptr(model.JobID("prev")), ExperimentID: ptrs.Ptr(1)}
+
+	t.Run("first run", func(t *testing.T) {
+		s, l := newTestService(model.JobOutcomeRunning)
+		r := s.launch(ctx, &ScheduledJob{OverlapPolicy: OverlapSkip}, nil)
+		require.Equal(t, RunStateLaunched, r.State)
+		require.Equal(t, model.JobID("next"), *r.JobID)
+		require.Equal(t, [][]model.JobID{nil}, l.launched)
+	})
+
+	t.Run("previous finished", func(t *testing.T) {
+		s, l := newTestService(model.JobOutcomeFailed)
+		r := s.launch(ctx, &ScheduledJob{OverlapPolicy: OverlapSkip}, prev)
+		require.Equal(t, RunStateLaunched, r.State)
+		require.Equal(t, [][]model.JobID{nil}, l.launched)
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		s, l := newTestService(model.JobOutcomeRunning)
+		r := s.launch(ctx, &ScheduledJob{OverlapPolicy: OverlapSkip}, prev)
+		require.Equal(t, RunStateSkipped, r.State)
+		require.Nil(t, r.JobID)
+		require.Empty(t, l.launched)
+	})
+
+	t.Run("queue", func(t *testing.T) {
+		s, l := newTestService(model.JobOutcomeRunning)
+		r := s.launch(ctx, &ScheduledJob{OverlapPolicy: OverlapQueue}, prev)
+		require.Equal(t, RunStateLaunched, r.State)
+		require.Equal(t, [][]model.JobID{{"prev"}}, l.launched)
+	})
+
+	t.Run("cancel previous", func(t *testing.T) {
+		s, l := newTestService(model.JobOutcomeRunning)
+		r := s.launch(ctx, &ScheduledJob{OverlapPolicy: OverlapCancelPrevious}, prev)
+		require.Equal(t, RunStateLaunched, r.State)
+		require.Equal(t, []*Run{prev}, l.killed)
+		require.Equal(t, [][]model.JobID{nil}, l.launched)
+	})
+
+	t.Run("launch failed", func(t *testing.T) {
+		s, l := newTestService(model.JobOutcomeSucceeded)
+		l.launchErr = errors.New("permission denied")
+		r := s.launch(ctx, &ScheduledJob{OverlapPolicy: OverlapSkip}, prev)
+		require.Equal(t, RunStateFailed, r.State)
+		require.Equal(t, "permission denied", r.Message)
+	})
+}
+
+func TestNextRun(t *testing.T) {
+	after := time.Date(2024, 11, 18, 10, 30, 0, 0, time.UTC)
+
+	j := &ScheduledJob{CronSpec: "0 2 * * *"}
+	require.Equal(t, time.Date(2024, 11, 19, 2, 0, 0, 0, time.UTC), *j.NextRun(after))
+
+	j.CronSpec = "CRON_TZ=America/New_York 0 2 * * *"
+	require.Equal(t, time.Date(2024, 11, 19, 7, 0, 0, 0, time.UTC), *j.NextRun(after))
+
+	j.Paused = true
+	require.Nil(t, j.NextRun(after))
+
+	_, err := ParseCronSpec("every night")
+	require.Error(t, err)
+}
+
+func TestOverlapPolicyProto(t *testing.T) {
+	for _, p := range []OverlapPolicy{OverlapSkip, OverlapQueue, OverlapCancelPrevious} {
+		require.Equal(t, p, OverlapPolicyFromProto(p.Proto()))
+	}
+	require.Equal(t, OverlapSkip,
+		OverlapPolicyFromProto(scheduledjobv1.OverlapPolicy_OVERLAP_POLICY_UNSPECIFIED))
+}
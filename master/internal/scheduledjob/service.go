@@ -0,0 +1,220 @@
+package scheduledjob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/job/dependency"
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+// Launcher launches and kills the jobs of scheduled jobs as their owners.
+type Launcher interface {
+	// Launch launches the job of a scheduled job, which waits on the given jobs to finish, and
+	// returns a run with the launched job.
+	Launch(ctx context.Context, j *ScheduledJob, dependsOn []model.JobID) (*Run, error)
+	// Kill kills the job launched by a run of a scheduled job.
+	Kill(ctx context.Context, j *ScheduledJob, r *Run) error
+}
+
+// Service runs scheduled jobs on their schedules.
+type Service struct {
+	mu       sync.Mutex
+	sched    gocron.Scheduler
+	jobs     map[int]uuid.UUID
+	launcher Launcher
+	syslog   *logrus.Entry
+
+	// outcomes looks up the outcomes of the jobs of previous runs.
+	outcomes func(ctx context.Context, jobIDs []model.JobID) (map[model.JobID]model.JobOutcome, error)
+}
+
+// DefaultService is the global scheduled job service singleton.
+var DefaultService *Service
+
+// SetDefaultService sets the global scheduled job service singleton.
+func SetDefaultService(s *Service) {
+	DefaultService = s
+}
+
+// NewService returns a new scheduled job service that launches jobs with the given launcher.
+func NewService(launcher Launcher) (*Service, error) {
+	sched, err := gocron.NewScheduler()
+	if err != nil {
+		return nil, fmt.Errorf("creating scheduled job scheduler: %w", err)
+	}
+	return &Service{
+		sched:    sched,
+		jobs:     make(map[int]uuid.UUID),
+		launcher: launcher,
+		syslog:   logrus.WithField("component", "scheduled-jobs"),
+		outcomes: dependency.JobOutcomes,
+	}, nil
+}
+
+// Start schedules the scheduled jobs that are not paused and starts the scheduler. Runs that were
+// due while the master was down are not made up.
+func (s *Service) Start(ctx context.Context) error {
+	jobs, err := getUnpausedScheduledJobs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, j := range jobs {
+		if err := s.schedule(j); err != nil {
+			s.syslog.WithError(err).WithField("scheduled-job-id", j.ID).Error("scheduling job")
+		}
+	}
+	s.sched.Start()
+	return nil
+}
+
+// Shutdown stops the scheduler.
+func (s *Service) Shutdown() error {
+	return s.sched.Shutdown()
+}
+
+// Add persists a scheduled job and schedules it unless it is paused.
+func (s *Service) Add(ctx context.Context, j *ScheduledJob) error {
+	if _, err := ParseCronSpec(j.CronSpec); err != nil {
+		return fmt.Errorf("invalid cron spec %q: %w", j.CronSpec, err)
+	}
+	if err := addScheduledJob(ctx, j); err != nil {
+		return err
+	}
+	if j.Paused {
+		return nil
+	}
+	return s.schedule(j)
+}
+
+// Delete unschedules a scheduled job and deletes it with its run history.
+func (s *Service) Delete(ctx context.Context, id int) error {
+	s.unschedule(id)
+	return deleteScheduledJob(ctx, id)
+}
+
+// SetPaused pauses or resumes the schedule of a scheduled job.
+func (s *Service) SetPaused(ctx context.Context, id int, paused bool) error {
+	if err := setScheduledJobPaused(ctx, id, paused); err != nil {
+		return err
+	}
+	if paused {
+		s.unschedule(id)
+		return nil
+	}
+	j, err := GetScheduledJob(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.schedule(j)
+}
+
+func (s *Service) schedule(j *ScheduledJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.jobs[j.ID]; ok {
+		return nil
+	}
+	job, err := s.sched.NewJob(
+		gocron.CronJob(cronSpecWithLocation(j.CronSpec), false),
+		gocron.NewTask(s.run, j.ID),
+		gocron.WithName(j.Name),
+		gocron.WithSingletonMode(gocron.LimitModeReschedule),
+	)
+	if err != nil {
+		return fmt.Errorf("scheduling job %d: %w", j.ID, err)
+	}
+	s.jobs[j.ID] = job.ID()
+	return nil
+}
+
+func (s *Service) unschedule(id int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobID, ok := s.jobs[id]
+	if !ok {
+		return
+	}
+	delete(s.jobs, id)
+	if err := s.sched.RemoveJob(jobID); err != nil {
+		s.syslog.WithError(err).WithField("scheduled-job-id", id).Warn("unscheduling job")
+	}
+}
+
+func (s *Service) run(id int) {
+	ctx := context.Background()
+	syslog := s.syslog.WithField("scheduled-job-id", id)
+
+	j, err := GetScheduledJob(ctx, id)
+	if errors.Is(err, db.ErrNotFound) {
+		s.unschedule(id)
+		return
+	} else if err != nil {
+		syslog.WithError(err).Error("getting scheduled job to run")
+		return
+	}
+	if j.Paused {
+		return
+	}
+
+	runTime := time.Now().UTC()
+	prev, err := lastLaunchedRun(ctx, id)
+	if err != nil {
+		syslog.WithError(err).Error("getting previous run of scheduled job")
+		return
+	}
+	r := s.launch(ctx, j, prev)
+	r.ScheduledJobID = id
+	r.RunTime = runTime
+	if err := addRun(ctx, r); err != nil {
+		syslog.WithError(err).Error("recording run of scheduled job")
+	}
+	syslog.WithField("state", r.State).Info("ran scheduled job")
+}
+
+// launch launches the job of a scheduled job according to its overlap policy with the job of its
+// previous run, and returns the run.
+func (s *Service) launch(ctx context.Context, j *ScheduledJob, prev *Run) *Run {
+	var dependsOn []model.JobID
+	if prev != nil && prev.JobID != nil {
+		outcomes, err := s.outcomes(ctx, []model.JobID{*prev.JobID})
+		if err != nil {
+			return &Run{State: RunStateFailed, Message: err.Error()}
+		}
+		if outcomes[*prev.JobID] == model.JobOutcomeRunning {
+			switch j.OverlapPolicy {
+			case OverlapQueue:
+				dependsOn = []model.JobID{*prev.JobID}
+			case OverlapCancelPrevious:
+				if err := s.launcher.Kill(ctx, j, prev); err != nil {
+					return &Run{
+						State:   RunStateFailed,
+						Message: fmt.Sprintf("killing job %s of the previous run: %s", *prev.JobID, err),
+					}
+				}
+			default:
+				return &Run{
+					State:   RunStateSkipped,
+					Message: fmt.Sprintf("job %s of the previous run has not finished", *prev.JobID),
+				}
+			}
+		}
+	}
+
+	r, err := s.launcher.Launch(ctx, j, dependsOn)
+	if err != nil {
+		return &Run{State: RunStateFailed, Message: err.Error()}
+	}
+	r.State = RunStateLaunched
+	return r
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/determined-ai/determined/master/internal/config"
 	"github.com/determined-ai/determined/master/internal/db"
 	"github.com/determined-ai/determined/master/internal/grpcutil"
+	"github.com/determined-ai/determined/master/internal/job/dependency"
 	"github.com/determined-ai/determined/master/internal/rm"
 	"github.com/determined-ai/determined/master/internal/rm/tasklist"
 	"github.com/determined-ai/determined/master/internal/sproto"
@@ -150,6 +151,7 @@ func getGenericTaskOnAllocationExit(
 			if err := tasklist.GroupPriorityChangeRegistry.Delete(jobID); err != nil {
 				syslog.WithError(err).Error("deleting group priority change registry")
 			}
+			dependency.DefaultService.JobFinished(jobID)
 			return
 		}
 		isPaused, err := db.IsPaused(ctx, taskID)
@@ -169,5 +171,83 @@ func getGenericTaskOnAllocationExit(
 		if err := tasklist.GroupPriorityChangeRegistry.Delete(jobID); err != nil {
 			syslog.WithError(err).Error("deleting group priority change registry")
 		}
+		dependency.DefaultService.JobFinished(jobID)
+	}
+}
+
+// getGenericTaskStartAllocation returns a function that starts the first allocation of a generic
+// task and persists the task's spec with it.
+func getGenericTaskStartAllocation(
+	pgDB *db.PgDB,
+	resourceManager rm.ResourceManager,
+	taskID model.TaskID,
+	jobID model.JobID,
+	spec *tasks.GenericTaskSpec,
+	logCtx logger.Context,
+	onAllocationExit func(*task.AllocationExited),
+) func(context.Context) error {
+	allocationID := model.AllocationID(fmt.Sprintf("%s.%d", taskID, 1))
+	isSingleNode := spec.GenericTaskConfig.Resources.IsSingleNode() != nil &&
+		*spec.GenericTaskConfig.Resources.IsSingleNode()
+	var timeLimit time.Duration
+	if spec.GenericTaskConfig.Resources.TimeLimit() != nil {
+		timeLimit = time.Duration(*spec.GenericTaskConfig.Resources.TimeLimit()) * time.Second
+	}
+	return func(ctx context.Context) error {
+		err := task.InsertNTSCAllocationWorkspaceRecord(ctx, allocationID, spec.WorkspaceID,
+			spec.Base.Workspace)
+		if err != nil {
+			return err
+		}
+		err = task.DefaultService.StartAllocation(logCtx, sproto.AllocateRequest{
+			AllocationID:      allocationID,
+			TaskID:            taskID,
+			JobID:             jobID,
+			JobSubmissionTime: spec.RegisteredTime,
+			IsUserVisible:     true,
+			Name:              fmt.Sprintf("Generic Task %s", taskID),
+			Workspace:         spec.Base.Workspace,
+			Username:          spec.Base.OwnerUsername(),
+
+			SlotsNeeded:  *spec.GenericTaskConfig.Resources.Slots(),
+			ResourcePool: spec.GenericTaskConfig.Resources.ResourcePool(),
+			FittingRequirements: sproto.FittingRequirements{
+				SingleAgent: isSingleNode,
+			},
+			TimeLimit: timeLimit,
+
+			Restore: false,
+		}, pgDB, resourceManager, spec, onAllocationExit)
+		if err != nil {
+			return err
+		}
+		return persistGenericTaskSpec(ctx, taskID, *spec, allocationID)
+	}
+}
+
+// getGenericTaskOnDependenciesResolved returns the callback of a generic task that waits on its
+// dependencies. It starts the allocation of the task once they are met, and cancels the task if
+// they can no longer be met.
+func getGenericTaskOnDependenciesResolved(
+	taskID model.TaskID,
+	jobID model.JobID,
+	logCtx logger.Context,
+	startAllocation func(context.Context) error,
+) func(error) {
+	return func(err error) {
+		syslog := logrus.WithField("component", "genericTask").WithFields(logCtx.Fields())
+		if err == nil {
+			if err = startAllocation(context.Background()); err == nil {
+				return
+			}
+			syslog.WithError(err).Error("starting generic task after its dependencies were met")
+		}
+		if err := db.SetCanceledState(taskID, time.Now().UTC()); err != nil {
+			syslog.WithError(err).Error("setting task to canceled state")
+		}
+		if err := tasklist.GroupPriorityChangeRegistry.Delete(jobID); err != nil {
+			syslog.WithError(err).Error("deleting group priority change registry")
+		}
+		dependency.DefaultService.JobFinished(jobID)
 	}
 }
@@ -3,6 +3,7 @@ package sproto
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/determined-ai/determined/master/pkg/aproto"
 	"github.com/determined-ai/determined/master/pkg/cproto"
@@ -94,3 +95,55 @@ func (t TerminateDecision) String() string {
 	}
 	return strings.Join(item, ",")
 }
+
+// Constant protocol for the reasons of launching instances.
+const (
+	// LaunchPendingTasks represents the reason for launching instances to fit pending tasks.
+	LaunchPendingTasks = "pending tasks"
+	// LaunchMinInstances represents the reason for launching instances to keep the configured
+	// minimum number of instances.
+	LaunchMinInstances = "min instances"
+	// LaunchWarmPool represents the reason for launching instances to keep the minimum number of
+	// instances of an active warm pool.
+	LaunchWarmPool = "warm pool"
+	// LaunchDemandForecast represents the reason for launching instances ahead of the demand that
+	// is forecast from the queue history.
+	LaunchDemandForecast = "demand forecast"
+)
+
+// LaunchDecision describes a launching decision and the inputs that it was made from.
+type LaunchDecision struct {
+	Time                time.Time
+	Instances           int
+	RecentlyLaunched    int
+	DesiredNewInstances int
+
+	MinInstances         int
+	WarmPoolMinInstances int
+	ActiveWarmPools      []string
+	ForecastSlots        float64
+	ForecastMinInstances int
+	// TargetMinInstances is the number of instances that are kept regardless of pending tasks:
+	// the largest of the minimums above, limited to MaxInstances.
+	TargetMinInstances int
+	MaxInstances       int
+
+	InstancesToLaunch int
+	Reason            string
+}
+
+// String returns a representative string.
+func (d LaunchDecision) String() string {
+	msg := fmt.Sprintf(
+		"reason: %s, instances: %d (%d starting), pending tasks need %d new instances, "+
+			"target minimum: %d (min instances: %d, warm pools: %d, forecast: %d for %.1f slots), "+
+			"max instances: %d",
+		d.Reason, d.Instances, d.RecentlyLaunched, d.DesiredNewInstances,
+		d.TargetMinInstances, d.MinInstances, d.WarmPoolMinInstances, d.ForecastMinInstances,
+		d.ForecastSlots, d.MaxInstances,
+	)
+	if len(d.ActiveWarmPools) > 0 {
+		msg += fmt.Sprintf(", active warm pools: %s", strings.Join(d.ActiveWarmPools, ", "))
+	}
+	return msg
+}
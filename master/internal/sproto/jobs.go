@@ -2,8 +2,10 @@ package sproto
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/shopspring/decimal"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/proto/pkg/jobv1"
@@ -32,6 +34,54 @@ type RMJobInfo struct { // rename ?
 	State          SchedulingState
 	RequestedSlots int
 	AllocatedSlots int
+	// Reservation is set if the resource pool holds agents for the job.
+	Reservation *JobReservation
+	// FairShare is set if the resource pool shares its slots across workspaces and users.
+	FairShare *JobFairShare
+}
+
+// JobFairShare describes the slots that hierarchical fair share offered to a job, its user and
+// its workspace.
+type JobFairShare struct {
+	Workspace      string
+	Username       string
+	WorkspaceSlots int
+	UserSlots      int
+	JobSlots       int
+}
+
+// Proto returns the proto representation of the fair share.
+func (f *JobFairShare) Proto() *jobv1.JobFairShare {
+	if f == nil {
+		return nil
+	}
+	return &jobv1.JobFairShare{
+		Workspace:      f.Workspace,
+		Username:       f.Username,
+		WorkspaceSlots: int32(f.WorkspaceSlots),
+		UserSlots:      int32(f.UserSlots),
+		JobSlots:       int32(f.JobSlots),
+	}
+}
+
+// JobReservation describes the agents that a resource pool holds for a job that has waited
+// too long to be scheduled.
+type JobReservation struct {
+	AgentIDs     []string
+	Slots        int
+	ReservedTime time.Time
+}
+
+// Proto returns the proto representation of the reservation.
+func (r *JobReservation) Proto() *jobv1.JobReservation {
+	if r == nil {
+		return nil
+	}
+	return &jobv1.JobReservation{
+		AgentIds:     r.AgentIDs,
+		Slots:        int32(r.Slots),
+		ReservedTime: timestamppb.New(r.ReservedTime),
+	}
 }
 
 // DeleteJob instructs the RM to clean up all metadata associated with a job external to
@@ -34,6 +34,10 @@ type (
 		IsUserVisible bool
 		State         SchedulingState
 		Name          string
+		// Workspace and Username identify who the allocation is for. The fair share scheduler
+		// shares slots across them in hierarchical mode.
+		Workspace string
+		Username  string
 
 		// Resource configuration.
 		SlotsNeeded         int
@@ -46,6 +50,9 @@ type (
 		ProxyPorts  []*ProxyPortConfig
 		Restore     bool
 		ProxyTLS    bool
+		// TimeLimit is how long the allocation is expected to run at most, or zero if unknown. The
+		// priority scheduler uses it to plan backfill; it is not enforced.
+		TimeLimit time.Duration
 
 		// Logging context of the allocation actor.
 		LogContext logger.Context
@@ -254,6 +261,8 @@ type (
 		Resources         ResourceList
 		JobSubmissionTime time.Time
 		Recovered         bool
+		// StartTime is when the resources were allocated, or zero if unknown.
+		StartTime time.Time
 	}
 	// PendingPreemption notifies the task actor that it should release
 	// resources due to a pending system-triggered preemption.
@@ -277,6 +286,9 @@ type (
 		// a preemption attempt instead of an immediate kill.
 		ForcePreemption bool
 		ForceKill       bool
+		// If specified as true (default false), only preemptible allocations are released, by
+		// preemption, and others keep running.
+		PreemptOnly bool
 	}
 	// ResourcesRuntimeInfo is all the information provided at runtime to make a task spec.
 	ResourcesRuntimeInfo struct {
@@ -311,6 +323,7 @@ func (ra ResourcesAllocated) Clone() *ResourcesAllocated {
 		Resources:         maps.Clone(ra.Resources),
 		JobSubmissionTime: ra.JobSubmissionTime,
 		Recovered:         ra.Recovered,
+		StartTime:         ra.StartTime,
 	}
 }
 
@@ -0,0 +1,283 @@
+package stream
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/stream"
+)
+
+const (
+	// ExperimentsDeleteKey specifies the key for delete experiments.
+	ExperimentsDeleteKey = "experiments_deleted"
+	// ExperimentsUpsertKey specifies the key for upsert experiments.
+	ExperimentsUpsertKey = "experiment"
+	// experimentChannel specifies the channel to listen to experiment events.
+	experimentChannel = "stream_experiment_chan"
+)
+
+// ExperimentMsg is a stream.Msg.
+//
+// determined:stream-gen source=server delete_msg=ExperimentsDeleted
+type ExperimentMsg struct {
+	bun.BaseModel `bun:"table:experiments"`
+
+	// immutable attributes
+	ID                   int     `bun:"id,pk" json:"id"`
+	JobID                string  `bun:"job_id" json:"job_id"`
+	ParentID             *int    `bun:"parent_id" json:"parent_id"`
+	Unmanaged            bool    `bun:"unmanaged" json:"unmanaged"`
+	ExternalExperimentID *string `bun:"external_experiment_id" json:"external_experiment_id"`
+
+	// mutable attributes
+	Name        string      `bun:"name,scanonly" json:"name"`
+	State       model.State `bun:"state" json:"state"`
+	Notes       string      `bun:"notes" json:"notes"`
+	Archived    bool        `bun:"archived" json:"archived"`
+	Progress    *float64    `bun:"progress" json:"progress"`
+	StartTime   time.Time   `bun:"start_time" json:"start_time"`
+	EndTime     *time.Time  `bun:"end_time" json:"end_time"`
+	OwnerID     *int        `bun:"owner_id" json:"owner_id"`
+	ProjectID   int         `bun:"project_id" json:"project_id"`
+	WorkspaceID int         `bun:"workspace_id,scanonly" json:"workspace_id"`
+	BestTrialID *int        `bun:"best_trial_id" json:"best_trial_id"`
+
+	// metadata
+	Seq int64 `bun:"seq" json:"seq"`
+}
+
+// SeqNum gets the SeqNum from an ExperimentMsg.
+func (em *ExperimentMsg) SeqNum() int64 {
+	return em.Seq
+}
+
+// GetID gets the ID from an ExperimentMsg.
+func (em *ExperimentMsg) GetID() int {
+	return em.ID
+}
+
+// UpsertMsg creates an Experiment stream upsert message.
+func (em *ExperimentMsg) UpsertMsg() *stream.UpsertMsg {
+	return &stream.UpsertMsg{
+		JSONKey: ExperimentsUpsertKey,
+		Msg:     em,
+	}
+}
+
+// DeleteMsg creates an Experiment stream delete message.
+func (em *ExperimentMsg) DeleteMsg() *stream.DeleteMsg {
+	deleted := strconv.Itoa(em.ID)
+	return &stream.DeleteMsg{
+		Key:     ExperimentsDeleteKey,
+		Deleted: deleted,
+	}
+}
+
+// ExperimentSubscriptionSpec is what a user submits to define an experiment subscription.
+//
+// determined:stream-gen source=client
+type ExperimentSubscriptionSpec struct {
+	WorkspaceIDs  []int `json:"workspace_ids"`
+	ProjectIDs    []int `json:"project_ids"`
+	ExperimentIDs []int `json:"experiment_ids"`
+	Since         int64 `json:"since"`
+}
+
+// selectExperimentMsgs creates a select query for ExperimentMsgs, including the name and
+// workspace id that are not stored as experiments columns.
+func selectExperimentMsgs(dest interface{}) *bun.SelectQuery {
+	return db.Bun().NewSelect().Model(dest).
+		ColumnExpr("?TableColumns").
+		ColumnExpr("experiment_msg.config->>'name' AS name").
+		ColumnExpr("p.workspace_id").
+		Join("JOIN projects p ON p.id = experiment_msg.project_id")
+}
+
+// createFilteredExperimentIDQuery creates a select query that
+// pulls all relevant experiment ids based on permission scope and
+// subscription spec filters.
+func createFilteredExperimentIDQuery(
+	globalAccess bool,
+	accessScopes []model.AccessScopeID,
+	spec ExperimentSubscriptionSpec,
+) *bun.SelectQuery {
+	q := db.Bun().NewSelect().
+		TableExpr("experiments e").
+		Column("e.id").
+		Join("JOIN projects p ON p.id = e.project_id").
+		OrderExpr("e.id ASC")
+
+	// add permission scope filter in event of non-global access
+	if !globalAccess {
+		q = permFilterQuery(q, accessScopes)
+	}
+
+	q.WhereGroup(" AND ", func(sq *bun.SelectQuery) *bun.SelectQuery {
+		if len(spec.ExperimentIDs) > 0 {
+			q.WhereOr("e.id in (?)", bun.In(spec.ExperimentIDs))
+		}
+		if len(spec.ProjectIDs) > 0 {
+			q.WhereOr("e.project_id in (?)", bun.In(spec.ProjectIDs))
+		}
+		if len(spec.WorkspaceIDs) > 0 {
+			q.WhereOr("p.workspace_id in (?)", bun.In(spec.WorkspaceIDs))
+		}
+		return q
+	})
+	return q
+}
+
+// ExperimentCollectStartupMsgs collects ExperimentMsg's that were missed prior to startup.
+// nolint: dupl
+func ExperimentCollectStartupMsgs(
+	ctx context.Context,
+	user model.User,
+	known string,
+	spec ExperimentSubscriptionSpec,
+) (
+	[]stream.MarshallableMsg, error,
+) {
+	var out []stream.MarshallableMsg
+
+	if len(spec.ExperimentIDs) == 0 && len(spec.ProjectIDs) == 0 && len(spec.WorkspaceIDs) == 0 {
+		// empty subscription: everything known should be returned as deleted
+		out = append(out, stream.DeleteMsg{
+			Key:     ExperimentsDeleteKey,
+			Deleted: known,
+		})
+		return out, nil
+	}
+	// step 0: get user's permitted access scopes
+	accessMap, err := AuthZProvider.Get().GetExperimentStreamableScopes(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	globalAccess, accessScopes := getStreamableScopes(accessMap)
+
+	// step 1: calculate all ids matching this subscription
+	createQuery := func() *bun.SelectQuery {
+		return createFilteredExperimentIDQuery(
+			globalAccess,
+			accessScopes,
+			spec,
+		)
+	}
+	missing, appeared, err := processQuery(ctx, createQuery, spec.Since, known, "e")
+	if err != nil {
+		return nil, fmt.Errorf("processing known: %w", err)
+	}
+
+	// step 2: hydrate appeared IDs into full ExperimentMsgs
+	var expMsgs []*ExperimentMsg
+	if len(appeared) > 0 {
+		query := selectExperimentMsgs(&expMsgs).Where("experiment_msg.id in (?)", bun.In(appeared))
+		if !globalAccess {
+			query = permFilterQuery(query, accessScopes)
+		}
+		err := query.Scan(ctx, &expMsgs)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			log.Errorf("error: %v\n", err)
+			return nil, err
+		}
+	}
+
+	// step 3: emit deletions and updates to the client
+	out = append(out, &stream.DeleteMsg{
+		Key:     ExperimentsDeleteKey,
+		Deleted: missing,
+	})
+	for _, msg := range expMsgs {
+		out = append(out, msg.UpsertMsg())
+	}
+	return out, nil
+}
+
+// ExperimentMakeFilter creates an ExperimentMsg filter based on the given ExperimentSubscriptionSpec.
+func ExperimentMakeFilter(spec *ExperimentSubscriptionSpec) (func(*ExperimentMsg) bool, error) {
+	// should this filter even run?
+	if len(spec.WorkspaceIDs) == 0 && len(spec.ProjectIDs) == 0 && len(spec.ExperimentIDs) == 0 {
+		return nil, errors.Errorf("invalid subscription spec arguments: %v %v %v",
+			spec.WorkspaceIDs, spec.ProjectIDs, spec.ExperimentIDs)
+	}
+
+	// create sets based on subscription spec
+	workspaceIDs := make(map[int]struct{})
+	for _, id := range spec.WorkspaceIDs {
+		if id <= 0 {
+			return nil, fmt.Errorf("invalid workspace id: %d", id)
+		}
+		workspaceIDs[id] = struct{}{}
+	}
+	projectIDs := make(map[int]struct{})
+	for _, id := range spec.ProjectIDs {
+		if id <= 0 {
+			return nil, fmt.Errorf("invalid project id: %d", id)
+		}
+		projectIDs[id] = struct{}{}
+	}
+	experimentIDs := make(map[int]struct{})
+	for _, id := range spec.ExperimentIDs {
+		if id <= 0 {
+			return nil, fmt.Errorf("invalid experiment id: %d", id)
+		}
+		experimentIDs[id] = struct{}{}
+	}
+
+	// return a closure around our copied maps
+	return func(msg *ExperimentMsg) bool {
+		if _, ok := experimentIDs[msg.ID]; ok {
+			return true
+		}
+		if _, ok := projectIDs[msg.ProjectID]; ok {
+			return true
+		}
+		if _, ok := workspaceIDs[msg.WorkspaceID]; ok {
+			return true
+		}
+		return false
+	}, nil
+}
+
+// ExperimentMakePermissionFilter returns a function that checks if an ExperimentMsg
+// is in scope of the user permissions.
+func ExperimentMakePermissionFilter(ctx context.Context, user model.User) (func(*ExperimentMsg) bool, error) {
+	accessScopeSet, err := AuthZProvider.Get().GetExperimentStreamableScopes(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case accessScopeSet[model.GlobalAccessScopeID]:
+		// user has global access for viewing experiments
+		return func(msg *ExperimentMsg) bool { return true }, nil
+	default:
+		return func(msg *ExperimentMsg) bool {
+			return accessScopeSet[model.AccessScopeID(msg.WorkspaceID)]
+		}, nil
+	}
+}
+
+// ExperimentMakeHydrator returns a function that gets properties of an experiment by
+// its id.
+func ExperimentMakeHydrator() func(*ExperimentMsg) (*ExperimentMsg, error) {
+	return func(msg *ExperimentMsg) (*ExperimentMsg, error) {
+		var saturatedMsg ExperimentMsg
+		query := selectExperimentMsgs(&saturatedMsg).Where("experiment_msg.id = ?", msg.GetID())
+		err := query.Scan(context.Background(), &saturatedMsg)
+		if err != nil && errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		} else if err != nil {
+			return nil, fmt.Errorf("error in experiment hydrator: %w", err)
+		}
+		return &saturatedMsg, nil
+	}
+}
@@ -24,9 +24,12 @@ type StartupMsg struct {
 //
 // Each field of a KnownKeySet is a comma-separated list of int64s and ranges like "a,b-c,d".
 type KnownKeySet struct {
-	Projects      string `json:"projects"`
-	Models        string `json:"models"`
-	ModelVersions string `json:"modelversions"`
+	Projects             string `json:"projects"`
+	Models               string `json:"models"`
+	ModelVersions        string `json:"modelversions"`
+	Experiments          string `json:"experiments"`
+	Trials               string `json:"trials"`
+	TrialMetricSummaries string `json:"trialmetricsummaries"`
 }
 
 // prepareWebsocketMessage converts the MarshallableMsg into a websocket.PreparedMessage.
@@ -29,10 +29,15 @@ type PublisherSet struct {
 	Projects      *stream.Publisher[*ProjectMsg]
 	Models        *stream.Publisher[*ModelMsg]
 	ModelVersions *stream.Publisher[*ModelVersionMsg]
-	bootemChan    chan struct{}
-	bootLock      sync.Mutex
-	readyCond     sync.Cond
-	ready         bool
+	Experiments   *stream.Publisher[*ExperimentMsg]
+	Trials        *stream.Publisher[*TrialMsg]
+
+	TrialMetricSummaries *stream.Publisher[*TrialMetricSummaryMsg]
+
+	bootemChan chan struct{}
+	bootLock   sync.Mutex
+	readyCond  sync.Cond
+	ready      bool
 }
 
 // NewPublisherSet constructor for PublisherSet.
@@ -43,8 +48,13 @@ func NewPublisherSet(dbAddress string) *PublisherSet {
 		Projects:      stream.NewPublisher[*ProjectMsg](ProjectMakeHydrator()),
 		Models:        stream.NewPublisher[*ModelMsg](ModelMakeHydrator()),
 		ModelVersions: stream.NewPublisher[*ModelVersionMsg](ModelVersionMakeHydrator()),
-		bootemChan:    make(chan struct{}),
-		readyCond:     *sync.NewCond(&lock),
+		Experiments:   stream.NewPublisher[*ExperimentMsg](ExperimentMakeHydrator()),
+		Trials:        stream.NewPublisher[*TrialMsg](TrialMakeHydrator()),
+		TrialMetricSummaries: stream.NewPublisher[*TrialMetricSummaryMsg](
+			TrialMetricSummaryMakeHydrator(),
+		),
+		bootemChan: make(chan struct{}),
+		readyCond:  *sync.NewCond(&lock),
 	}
 }
 
@@ -54,6 +64,10 @@ func (ps *PublisherSet) Start(ctx context.Context) error {
 		ps.Projects:      make(chan bool),
 		ps.Models:        make(chan bool),
 		ps.ModelVersions: make(chan bool),
+		ps.Experiments:   make(chan bool),
+		ps.Trials:        make(chan bool),
+
+		ps.TrialMetricSummaries: make(chan bool),
 	}
 
 	eg := errgroupx.WithContext(ctx)
@@ -102,6 +116,51 @@ func (ps *PublisherSet) Start(ctx context.Context) error {
 			return nil
 		},
 	)
+	eg.Go(
+		func(c context.Context) error {
+			err := publishLoop(
+				c,
+				ps.DBAddress,
+				experimentChannel,
+				ps.Experiments,
+				readyChannels[ps.Experiments],
+			)
+			if err != nil {
+				return fmt.Errorf("experiments publishLoop failed: %s", err.Error())
+			}
+			return nil
+		},
+	)
+	eg.Go(
+		func(c context.Context) error {
+			err := publishLoop(
+				c,
+				ps.DBAddress,
+				trialChannel,
+				ps.Trials,
+				readyChannels[ps.Trials],
+			)
+			if err != nil {
+				return fmt.Errorf("trials publishLoop failed: %s", err.Error())
+			}
+			return nil
+		},
+	)
+	eg.Go(
+		func(c context.Context) error {
+			err := publishLoop(
+				c,
+				ps.DBAddress,
+				trialMetricSummaryChannel,
+				ps.TrialMetricSummaries,
+				readyChannels[ps.TrialMetricSummaries],
+			)
+			if err != nil {
+				return fmt.Errorf("trial metric summaries publishLoop failed: %s", err.Error())
+			}
+			return nil
+		},
+	)
 
 	// wait for all publishers to become ready
 	eg.Go(
@@ -15,6 +15,10 @@ type SubscriptionSet struct {
 	Projects      *subscriptionState[*ProjectMsg, ProjectSubscriptionSpec]
 	Models        *subscriptionState[*ModelMsg, ModelSubscriptionSpec]
 	ModelVersions *subscriptionState[*ModelVersionMsg, ModelVersionSubscriptionSpec]
+	Experiments   *subscriptionState[*ExperimentMsg, ExperimentSubscriptionSpec]
+	Trials        *subscriptionState[*TrialMsg, TrialSubscriptionSpec]
+
+	TrialMetricSummaries *subscriptionState[*TrialMetricSummaryMsg, TrialMetricSummarySubscriptionSpec]
 }
 
 // subscriptionState contains per-type subscription state.
@@ -28,6 +32,10 @@ type SubscriptionSpecSet struct {
 	Projects     *ProjectSubscriptionSpec      `json:"projects"`
 	Models       *ModelSubscriptionSpec        `json:"models"`
 	ModelVersion *ModelVersionSubscriptionSpec `json:"modelversions"`
+	Experiments  *ExperimentSubscriptionSpec   `json:"experiments"`
+	Trials       *TrialSubscriptionSpec        `json:"trials"`
+
+	TrialMetricSummaries *TrialMetricSummarySubscriptionSpec `json:"trialmetricsummaries"`
 }
 
 // CollectStartupMsgsFunc collects messages that were missed prior to startup.
@@ -52,6 +60,11 @@ func NewSubscriptionSet(
 	var projectSubscriptionState *subscriptionState[*ProjectMsg, ProjectSubscriptionSpec]
 	var modelSubscriptionState *subscriptionState[*ModelMsg, ModelSubscriptionSpec]
 	var modelVersionSubscriptionState *subscriptionState[*ModelVersionMsg, ModelVersionSubscriptionSpec]
+	var experimentSubscriptionState *subscriptionState[*ExperimentMsg, ExperimentSubscriptionSpec]
+	var trialSubscriptionState *subscriptionState[*TrialMsg, TrialSubscriptionSpec]
+	var trialMetricSummarySubscriptionState *subscriptionState[
+		*TrialMetricSummaryMsg, TrialMetricSummarySubscriptionSpec,
+	]
 
 	if spec.Projects != nil {
 		projectSubscriptionState = &subscriptionState[*ProjectMsg, ProjectSubscriptionSpec]{
@@ -86,11 +99,50 @@ func NewSubscriptionSet(
 			ModelVersionCollectStartupMsgs,
 		}
 	}
+	if spec.Experiments != nil {
+		experimentSubscriptionState = &subscriptionState[*ExperimentMsg, ExperimentSubscriptionSpec]{
+			stream.NewSubscription(
+				streamer,
+				ps.Experiments,
+				newPermFilter(ctx, user, ExperimentMakePermissionFilter, &err),
+				newFilter(spec.Experiments, ExperimentMakeFilter, &err),
+			),
+			ExperimentCollectStartupMsgs,
+		}
+	}
+	if spec.Trials != nil {
+		trialSubscriptionState = &subscriptionState[*TrialMsg, TrialSubscriptionSpec]{
+			stream.NewSubscription(
+				streamer,
+				ps.Trials,
+				newPermFilter(ctx, user, TrialMakePermissionFilter, &err),
+				newFilter(spec.Trials, TrialMakeFilter, &err),
+			),
+			TrialCollectStartupMsgs,
+		}
+	}
+	if spec.TrialMetricSummaries != nil {
+		trialMetricSummarySubscriptionState = &subscriptionState[
+			*TrialMetricSummaryMsg, TrialMetricSummarySubscriptionSpec,
+		]{
+			stream.NewSubscription(
+				streamer,
+				ps.TrialMetricSummaries,
+				newPermFilter(ctx, user, TrialMetricSummaryMakePermissionFilter, &err),
+				newFilter(spec.TrialMetricSummaries, TrialMetricSummaryMakeFilter, &err),
+			),
+			TrialMetricSummaryCollectStartupMsgs,
+		}
+	}
 
 	return SubscriptionSet{
 		Projects:      projectSubscriptionState,
 		Models:        modelSubscriptionState,
 		ModelVersions: modelVersionSubscriptionState,
+		Experiments:   experimentSubscriptionState,
+		Trials:        trialSubscriptionState,
+
+		TrialMetricSummaries: trialMetricSummarySubscriptionState,
 	}, err
 }
 
@@ -124,6 +176,27 @@ func (ss *SubscriptionSet) Startup(ctx context.Context, user model.User, startup
 			sub.ModelVersion, ss.ModelVersions.Subscription.Streamer.PrepareFn,
 		)
 	}
+	if ss.Experiments != nil {
+		err = startup(
+			ctx, user, &msgs, err,
+			ss.Experiments, known.Experiments,
+			sub.Experiments, ss.Experiments.Subscription.Streamer.PrepareFn,
+		)
+	}
+	if ss.Trials != nil {
+		err = startup(
+			ctx, user, &msgs, err,
+			ss.Trials, known.Trials,
+			sub.Trials, ss.Trials.Subscription.Streamer.PrepareFn,
+		)
+	}
+	if ss.TrialMetricSummaries != nil {
+		err = startup(
+			ctx, user, &msgs, err,
+			ss.TrialMetricSummaries, known.TrialMetricSummaries,
+			sub.TrialMetricSummaries, ss.TrialMetricSummaries.Subscription.Streamer.PrepareFn,
+		)
+	}
 	return msgs, err
 }
 
@@ -167,4 +240,19 @@ func (ss *SubscriptionSet) UnregisterAll() {
 	if ss.Projects != nil {
 		ss.Projects.Subscription.Unregister()
 	}
+	if ss.Models != nil {
+		ss.Models.Subscription.Unregister()
+	}
+	if ss.ModelVersions != nil {
+		ss.ModelVersions.Subscription.Unregister()
+	}
+	if ss.Experiments != nil {
+		ss.Experiments.Subscription.Unregister()
+	}
+	if ss.Trials != nil {
+		ss.Trials.Subscription.Unregister()
+	}
+	if ss.TrialMetricSummaries != nil {
+		ss.TrialMetricSummaries.Subscription.Unregister()
+	}
 }
@@ -20,9 +20,12 @@ import (
 )
 
 const (
-	projects      = "projects"
-	models        = "models"
-	modelVersions = "modelversions"
+	projects             = "projects"
+	models               = "models"
+	modelVersions        = "modelversions"
+	experiments          = "experiments"
+	trials               = "trials"
+	trialMetricSummaries = "trialmetricsummaries"
 )
 
 func TestMockSocket(t *testing.T) {
@@ -184,6 +187,12 @@ func buildStartupMsg(
 			knownKeySet.Models = known
 		case modelVersions:
 			knownKeySet.ModelVersions = known
+		case experiments:
+			knownKeySet.Experiments = known
+		case trials:
+			knownKeySet.Trials = known
+		case trialMetricSummaries:
+			knownKeySet.TrialMetricSummaries = known
 		}
 	}
 
@@ -237,6 +246,44 @@ func buildStartupMsg(
 				UserIDs:         userIDs,
 				Since:           0,
 			}
+		case experiments:
+			var experimentIDs, projectIDs, workspaceIDs []int
+			if subscriptionIDs[experiments] != nil {
+				experimentIDs = subscriptionIDs[experiments].([]int)
+			}
+			if subscriptionIDs[projects] != nil {
+				projectIDs = subscriptionIDs[projects].([]int)
+			}
+			if subscriptionIDs["workspaces"] != nil {
+				workspaceIDs = subscriptionIDs["workspaces"].([]int)
+			}
+			subscriptionSpecSet.Experiments = &ExperimentSubscriptionSpec{
+				ExperimentIDs: experimentIDs,
+				ProjectIDs:    projectIDs,
+				WorkspaceIDs:  workspaceIDs,
+				Since:         0,
+			}
+		case trials, trialMetricSummaries:
+			var trialIDs, experimentIDs []int
+			if subscriptionIDs[trials] != nil {
+				trialIDs = subscriptionIDs[trials].([]int)
+			}
+			if subscriptionIDs[experiments] != nil {
+				experimentIDs = subscriptionIDs[experiments].([]int)
+			}
+			if subscriptionType == trials {
+				subscriptionSpecSet.Trials = &TrialSubscriptionSpec{
+					TrialIDs:      trialIDs,
+					ExperimentIDs: experimentIDs,
+					Since:         0,
+				}
+			} else {
+				subscriptionSpecSet.TrialMetricSummaries = &TrialMetricSummarySubscriptionSpec{
+					TrialIDs:      trialIDs,
+					ExperimentIDs: experimentIDs,
+					Since:         0,
+				}
+			}
 		}
 	}
 
@@ -650,3 +697,71 @@ func TestSubscribeModelVersion(t *testing.T) {
 	}
 	runUpdateTest(t, pgDB, testCases)
 }
+
+func TestSubscribeExperimentsAndTrials(t *testing.T) {
+	pgDB := initializeStreamDB(context.Background(), t)
+	exp := db.RequireMockExperiment(t, pgDB, db.RequireMockUser(t, pgDB))
+	trialID := db.RequireMockTrialID(t, pgDB, exp)
+
+	testCases := []updateTestCase{
+		{
+			startupCase: startupTestCase{
+				description: "startup test case for: subscribe to experiments, trials and metric summaries",
+				startupMsg: buildStartupMsg(
+					"1",
+					map[string]string{experiments: "", trials: "", trialMetricSummaries: ""},
+					map[string]map[string]interface{}{
+						experiments:          {"workspaces": []int{1}},
+						trials:               {experiments: []int{exp.ID}},
+						trialMetricSummaries: {experiments: []int{exp.ID}},
+					},
+				),
+				expectedUpserts: []string{
+					fmt.Sprintf("key: experiment, experiment_id: %d, project_id: 1, workspace_id: 1", exp.ID),
+					fmt.Sprintf("key: trial, trial_id: %d, experiment_id: %d, workspace_id: 1", trialID, exp.ID),
+					fmt.Sprintf(
+						"key: trialmetricsummary, trial_id: %d, experiment_id: %d, workspace_id: 1",
+						trialID, exp.ID,
+					),
+				},
+				expectedDeletions: []string{
+					"key: experiments_deleted, deleted: ",
+					"key: trials_deleted, deleted: ",
+					"key: trialmetricsummaries_deleted, deleted: ",
+				},
+			},
+			description: "reporting metrics only updates the metric summary",
+			queries: []streamdata.ExecutableQuery{
+				db.Bun().NewUpdate().Table("runs").
+					Set("summary_metrics = ?", `{"avg_metrics": {}}`).
+					Where("id = ?", trialID),
+			},
+			expectedUpserts: []string{
+				fmt.Sprintf(
+					"key: trialmetricsummary, trial_id: %d, experiment_id: %d, workspace_id: 1",
+					trialID, exp.ID,
+				),
+			},
+			expectedDeletions: []string{},
+		},
+		{
+			startupCase: startupTestCase{
+				description: "startup test case for: experiment falls out of a workspace subscription",
+				startupMsg: buildStartupMsg(
+					"2",
+					map[string]string{experiments: fmt.Sprint(exp.ID)},
+					map[string]map[string]interface{}{experiments: {"workspaces": []int{1}}},
+				),
+				expectedUpserts:   []string{},
+				expectedDeletions: []string{"key: experiments_deleted, deleted: "},
+			},
+			description: "moving an experiment to another workspace's project is a fallout",
+			queries: []streamdata.ExecutableQuery{
+				db.Bun().NewUpdate().Table("experiments").Set("project_id = ?", 2).Where("id = ?", exp.ID),
+			},
+			expectedUpserts:   []string{},
+			expectedDeletions: []string{fmt.Sprintf("key: experiments_deleted, deleted: %d", exp.ID)},
+		},
+	}
+	runUpdateTest(t, pgDB, testCases)
+}
@@ -49,6 +49,30 @@ func testPrepareFunc(i stream.MarshallableMsg) interface{} {
 				typedMsg.ModelID,
 				typedMsg.WorkspaceID,
 			)
+		case *ExperimentMsg:
+			return fmt.Sprintf(
+				"key: %s, experiment_id: %d, project_id: %d, workspace_id: %d",
+				ExperimentsUpsertKey,
+				typedMsg.ID,
+				typedMsg.ProjectID,
+				typedMsg.WorkspaceID,
+			)
+		case *TrialMsg:
+			return fmt.Sprintf(
+				"key: %s, trial_id: %d, experiment_id: %d, workspace_id: %d",
+				TrialsUpsertKey,
+				typedMsg.ID,
+				typedMsg.ExperimentID,
+				typedMsg.WorkspaceID,
+			)
+		case *TrialMetricSummaryMsg:
+			return fmt.Sprintf(
+				"key: %s, trial_id: %d, experiment_id: %d, workspace_id: %d",
+				TrialMetricSummariesUpsertKey,
+				typedMsg.ID,
+				typedMsg.ExperimentID,
+				typedMsg.WorkspaceID,
+			)
 		}
 	case *stream.DeleteMsg:
 		return fmt.Sprintf("key: %s, deleted: %s", msg.Key, msg.Deleted)
@@ -0,0 +1,268 @@
+package stream
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/stream"
+)
+
+const (
+	// TrialMetricSummariesDeleteKey specifies the key for delete trial metric summaries.
+	TrialMetricSummariesDeleteKey = "trialmetricsummaries_deleted"
+	// TrialMetricSummariesUpsertKey specifies the key for upsert trial metric summaries.
+	TrialMetricSummariesUpsertKey = "trialmetricsummary"
+	// trialMetricSummaryChannel specifies the channel to listen to trial metric summary events.
+	trialMetricSummaryChannel = "stream_trial_metric_summary_chan"
+)
+
+// TrialMetricSummaryMsg is a stream.Msg. It is keyed by trial id, and is sent separately from the
+// TrialMsg so that reporting metrics does not resend the rest of the trial.
+//
+// determined:stream-gen source=server delete_msg=TrialMetricSummariesDeleted
+type TrialMetricSummaryMsg struct {
+	bun.BaseModel `bun:"table:runs"`
+
+	// immutable attributes
+	ID           int `bun:"id,pk" json:"id"`
+	ExperimentID int `bun:"experiment_id" json:"experiment_id"`
+
+	// mutable attributes
+	ProjectID               int        `bun:"project_id" json:"project_id"`
+	WorkspaceID             int        `bun:"workspace_id,scanonly" json:"workspace_id"`
+	SummaryMetrics          JSONB      `bun:"summary_metrics,type:jsonb" json:"summary_metrics"`
+	SummaryMetricsTimestamp *time.Time `bun:"summary_metrics_timestamp" json:"summary_metrics_timestamp"`
+
+	// metadata
+	Seq int64 `bun:"summary_metrics_seq" json:"seq"`
+}
+
+// SeqNum gets the SeqNum from a TrialMetricSummaryMsg.
+func (tm *TrialMetricSummaryMsg) SeqNum() int64 {
+	return tm.Seq
+}
+
+// GetID gets the ID from a TrialMetricSummaryMsg.
+func (tm *TrialMetricSummaryMsg) GetID() int {
+	return tm.ID
+}
+
+// UpsertMsg creates a TrialMetricSummary stream upsert message.
+func (tm *TrialMetricSummaryMsg) UpsertMsg() *stream.UpsertMsg {
+	return &stream.UpsertMsg{
+		JSONKey: TrialMetricSummariesUpsertKey,
+		Msg:     tm,
+	}
+}
+
+// DeleteMsg creates a TrialMetricSummary stream delete message.
+func (tm *TrialMetricSummaryMsg) DeleteMsg() *stream.DeleteMsg {
+	deleted := strconv.Itoa(tm.ID)
+	return &stream.DeleteMsg{
+		Key:     TrialMetricSummariesDeleteKey,
+		Deleted: deleted,
+	}
+}
+
+// TrialMetricSummarySubscriptionSpec is what a user submits to define a trial metric summary
+// subscription.
+//
+// determined:stream-gen source=client
+type TrialMetricSummarySubscriptionSpec struct {
+	TrialIDs      []int `json:"trial_ids"`
+	ExperimentIDs []int `json:"experiment_ids"`
+	Since         int64 `json:"since"`
+}
+
+// selectTrialMetricSummaryMsgs creates a select query for TrialMetricSummaryMsgs, including the
+// workspace id of the trial's project.
+func selectTrialMetricSummaryMsgs(dest interface{}) *bun.SelectQuery {
+	return db.Bun().NewSelect().Model(dest).
+		ColumnExpr("?TableColumns").
+		ColumnExpr("p.workspace_id").
+		Join("JOIN projects p ON p.id = trial_metric_summary_msg.project_id")
+}
+
+// createFilteredTrialMetricSummaryIDQuery creates a select query that
+// pulls all relevant trial ids based on permission scope and
+// subscription spec filters.
+func createFilteredTrialMetricSummaryIDQuery(
+	globalAccess bool,
+	accessScopes []model.AccessScopeID,
+	spec TrialMetricSummarySubscriptionSpec,
+) *bun.SelectQuery {
+	// processQuery filters on a seq column, which is summary_metrics_seq for metric summaries.
+	q := db.Bun().NewSelect().
+		TableExpr("(SELECT id, experiment_id, project_id, summary_metrics_seq AS seq FROM runs) r").
+		Column("r.id").
+		Join("JOIN projects p ON p.id = r.project_id").
+		OrderExpr("r.id ASC")
+
+	// add permission scope filter in event of non-global access
+	if !globalAccess {
+		q = permFilterQuery(q, accessScopes)
+	}
+
+	q.WhereGroup(" AND ", func(sq *bun.SelectQuery) *bun.SelectQuery {
+		if len(spec.TrialIDs) > 0 {
+			q.WhereOr("r.id in (?)", bun.In(spec.TrialIDs))
+		}
+		if len(spec.ExperimentIDs) > 0 {
+			q.WhereOr("r.experiment_id in (?)", bun.In(spec.ExperimentIDs))
+		}
+		return q
+	})
+	return q
+}
+
+// TrialMetricSummaryCollectStartupMsgs collects TrialMetricSummaryMsg's that were missed prior
+// to startup.
+// nolint: dupl
+func TrialMetricSummaryCollectStartupMsgs(
+	ctx context.Context,
+	user model.User,
+	known string,
+	spec TrialMetricSummarySubscriptionSpec,
+) (
+	[]stream.MarshallableMsg, error,
+) {
+	var out []stream.MarshallableMsg
+
+	if len(spec.TrialIDs) == 0 && len(spec.ExperimentIDs) == 0 {
+		// empty subscription: everything known should be returned as deleted
+		out = append(out, stream.DeleteMsg{
+			Key:     TrialMetricSummariesDeleteKey,
+			Deleted: known,
+		})
+		return out, nil
+	}
+	// step 0: get user's permitted access scopes
+	accessMap, err := AuthZProvider.Get().GetExperimentStreamableScopes(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	globalAccess, accessScopes := getStreamableScopes(accessMap)
+
+	// step 1: calculate all ids matching this subscription
+	createQuery := func() *bun.SelectQuery {
+		return createFilteredTrialMetricSummaryIDQuery(
+			globalAccess,
+			accessScopes,
+			spec,
+		)
+	}
+	missing, appeared, err := processQuery(ctx, createQuery, spec.Since, known, "r")
+	if err != nil {
+		return nil, fmt.Errorf("processing known: %w", err)
+	}
+
+	// step 2: hydrate appeared IDs into full TrialMetricSummaryMsgs
+	var summaryMsgs []*TrialMetricSummaryMsg
+	if len(appeared) > 0 {
+		query := selectTrialMetricSummaryMsgs(&summaryMsgs).
+			Where("trial_metric_summary_msg.id in (?)", bun.In(appeared))
+		if !globalAccess {
+			query = permFilterQuery(query, accessScopes)
+		}
+		err := query.Scan(ctx, &summaryMsgs)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			log.Errorf("error: %v\n", err)
+			return nil, err
+		}
+	}
+
+	// step 3: emit deletions and updates to the client
+	out = append(out, &stream.DeleteMsg{
+		Key:     TrialMetricSummariesDeleteKey,
+		Deleted: missing,
+	})
+	for _, msg := range summaryMsgs {
+		out = append(out, msg.UpsertMsg())
+	}
+	return out, nil
+}
+
+// TrialMetricSummaryMakeFilter creates a TrialMetricSummaryMsg filter based on the given
+// TrialMetricSummarySubscriptionSpec.
+func TrialMetricSummaryMakeFilter(
+	spec *TrialMetricSummarySubscriptionSpec,
+) (func(*TrialMetricSummaryMsg) bool, error) {
+	// should this filter even run?
+	if len(spec.TrialIDs) == 0 && len(spec.ExperimentIDs) == 0 {
+		return nil, errors.Errorf("invalid subscription spec arguments: %v %v", spec.TrialIDs, spec.ExperimentIDs)
+	}
+
+	// create sets based on subscription spec
+	trialIDs := make(map[int]struct{})
+	for _, id := range spec.TrialIDs {
+		if id <= 0 {
+			return nil, fmt.Errorf("invalid trial id: %d", id)
+		}
+		trialIDs[id] = struct{}{}
+	}
+	experimentIDs := make(map[int]struct{})
+	for _, id := range spec.ExperimentIDs {
+		if id <= 0 {
+			return nil, fmt.Errorf("invalid experiment id: %d", id)
+		}
+		experimentIDs[id] = struct{}{}
+	}
+
+	// return a closure around our copied maps
+	return func(msg *TrialMetricSummaryMsg) bool {
+		if _, ok := trialIDs[msg.ID]; ok {
+			return true
+		}
+		if _, ok := experimentIDs[msg.ExperimentID]; ok {
+			return true
+		}
+		return false
+	}, nil
+}
+
+// TrialMetricSummaryMakePermissionFilter returns a function that checks if a
+// TrialMetricSummaryMsg is in scope of the user permissions.
+func TrialMetricSummaryMakePermissionFilter(
+	ctx context.Context, user model.User,
+) (func(*TrialMetricSummaryMsg) bool, error) {
+	accessScopeSet, err := AuthZProvider.Get().GetExperimentStreamableScopes(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case accessScopeSet[model.GlobalAccessScopeID]:
+		// user has global access for viewing experiments
+		return func(msg *TrialMetricSummaryMsg) bool { return true }, nil
+	default:
+		return func(msg *TrialMetricSummaryMsg) bool {
+			return accessScopeSet[model.AccessScopeID(msg.WorkspaceID)]
+		}, nil
+	}
+}
+
+// TrialMetricSummaryMakeHydrator returns a function that gets the metric summary of a trial by
+// its id.
+func TrialMetricSummaryMakeHydrator() func(*TrialMetricSummaryMsg) (*TrialMetricSummaryMsg, error) {
+	return func(msg *TrialMetricSummaryMsg) (*TrialMetricSummaryMsg, error) {
+		var saturatedMsg TrialMetricSummaryMsg
+		query := selectTrialMetricSummaryMsgs(&saturatedMsg).
+			Where("trial_metric_summary_msg.id = ?", msg.GetID())
+		err := query.Scan(context.Background(), &saturatedMsg)
+		if err != nil && errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		} else if err != nil {
+			return nil, fmt.Errorf("error in trial metric summary hydrator: %w", err)
+		}
+		return &saturatedMsg, nil
+	}
+}
@@ -0,0 +1,267 @@
+package stream
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/uptrace/bun"
+
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/stream"
+)
+
+const (
+	// TrialsDeleteKey specifies the key for delete trials.
+	TrialsDeleteKey = "trials_deleted"
+	// TrialsUpsertKey specifies the key for upsert trials.
+	TrialsUpsertKey = "trial"
+	// trialChannel specifies the channel to listen to trial events.
+	trialChannel = "stream_trial_chan"
+)
+
+// TrialMsg is a stream.Msg.
+//
+// determined:stream-gen source=server delete_msg=TrialsDeleted
+type TrialMsg struct {
+	bun.BaseModel `bun:"table:runs"`
+
+	// immutable attributes
+	ID           int `bun:"id,pk" json:"id"`
+	ExperimentID int `bun:"experiment_id" json:"experiment_id"`
+
+	// mutable attributes
+	ProjectID           int         `bun:"project_id" json:"project_id"`
+	WorkspaceID         int         `bun:"workspace_id,scanonly" json:"workspace_id"`
+	State               model.State `bun:"state" json:"state"`
+	StartTime           time.Time   `bun:"start_time" json:"start_time"`
+	EndTime             *time.Time  `bun:"end_time" json:"end_time"`
+	Restarts            int         `bun:"restarts" json:"restarts"`
+	TotalBatches        int         `bun:"total_batches" json:"total_batches"`
+	HParams             JSONB       `bun:"hparams,type:jsonb" json:"hparams"`
+	Tags                JSONB       `bun:"tags,type:jsonb" json:"tags"`
+	SearcherMetricValue *float64    `bun:"searcher_metric_value" json:"searcher_metric_value"`
+	BestValidationID    *int        `bun:"best_validation_id" json:"best_validation_id"`
+	LatestValidationID  *int        `bun:"latest_validation_id" json:"latest_validation_id"`
+	CheckpointCount     int         `bun:"checkpoint_count" json:"checkpoint_count"`
+	Archived            bool        `bun:"archived" json:"archived"`
+
+	// metadata
+	Seq int64 `bun:"seq" json:"seq"`
+}
+
+// SeqNum gets the SeqNum from a TrialMsg.
+func (tm *TrialMsg) SeqNum() int64 {
+	return tm.Seq
+}
+
+// GetID gets the ID from a TrialMsg.
+func (tm *TrialMsg) GetID() int {
+	return tm.ID
+}
+
+// UpsertMsg creates a Trial stream upsert message.
+func (tm *TrialMsg) UpsertMsg() *stream.UpsertMsg {
+	return &stream.UpsertMsg{
+		JSONKey: TrialsUpsertKey,
+		Msg:     tm,
+	}
+}
+
+// DeleteMsg creates a Trial stream delete message.
+func (tm *TrialMsg) DeleteMsg() *stream.DeleteMsg {
+	deleted := strconv.Itoa(tm.ID)
+	return &stream.DeleteMsg{
+		Key:     TrialsDeleteKey,
+		Deleted: deleted,
+	}
+}
+
+// TrialSubscriptionSpec is what a user submits to define a trial subscription.
+//
+// determined:stream-gen source=client
+type TrialSubscriptionSpec struct {
+	TrialIDs      []int `json:"trial_ids"`
+	ExperimentIDs []int `json:"experiment_ids"`
+	Since         int64 `json:"since"`
+}
+
+// selectTrialMsgs creates a select query for TrialMsgs, including the workspace id of the
+// trial's project.
+func selectTrialMsgs(dest interface{}) *bun.SelectQuery {
+	return db.Bun().NewSelect().Model(dest).
+		ColumnExpr("?TableColumns").
+		ColumnExpr("p.workspace_id").
+		Join("JOIN projects p ON p.id = trial_msg.project_id")
+}
+
+// createFilteredTrialIDQuery creates a select query that
+// pulls all relevant trial ids based on permission scope and
+// subscription spec filters.
+func createFilteredTrialIDQuery(
+	globalAccess bool,
+	accessScopes []model.AccessScopeID,
+	spec TrialSubscriptionSpec,
+) *bun.SelectQuery {
+	q := db.Bun().NewSelect().
+		TableExpr("runs r").
+		Column("r.id").
+		Join("JOIN projects p ON p.id = r.project_id").
+		OrderExpr("r.id ASC")
+
+	// add permission scope filter in event of non-global access
+	if !globalAccess {
+		q = permFilterQuery(q, accessScopes)
+	}
+
+	q.WhereGroup(" AND ", func(sq *bun.SelectQuery) *bun.SelectQuery {
+		if len(spec.TrialIDs) > 0 {
+			q.WhereOr("r.id in (?)", bun.In(spec.TrialIDs))
+		}
+		if len(spec.ExperimentIDs) > 0 {
+			q.WhereOr("r.experiment_id in (?)", bun.In(spec.ExperimentIDs))
+		}
+		return q
+	})
+	return q
+}
+
+// TrialCollectStartupMsgs collects TrialMsg's that were missed prior to startup.
+// nolint: dupl
+func TrialCollectStartupMsgs(
+	ctx context.Context,
+	user model.User,
+	known string,
+	spec TrialSubscriptionSpec,
+) (
+	[]stream.MarshallableMsg, error,
+) {
+	var out []stream.MarshallableMsg
+
+	if len(spec.TrialIDs) == 0 && len(spec.ExperimentIDs) == 0 {
+		// empty subscription: everything known should be returned as deleted
+		out = append(out, stream.DeleteMsg{
+			Key:     TrialsDeleteKey,
+			Deleted: known,
+		})
+		return out, nil
+	}
+	// step 0: get user's permitted access scopes
+	accessMap, err := AuthZProvider.Get().GetExperimentStreamableScopes(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	globalAccess, accessScopes := getStreamableScopes(accessMap)
+
+	// step 1: calculate all ids matching this subscription
+	createQuery := func() *bun.SelectQuery {
+		return createFilteredTrialIDQuery(
+			globalAccess,
+			accessScopes,
+			spec,
+		)
+	}
+	missing, appeared, err := processQuery(ctx, createQuery, spec.Since, known, "r")
+	if err != nil {
+		return nil, fmt.Errorf("processing known: %w", err)
+	}
+
+	// step 2: hydrate appeared IDs into full TrialMsgs
+	var trialMsgs []*TrialMsg
+	if len(appeared) > 0 {
+		query := selectTrialMsgs(&trialMsgs).Where("trial_msg.id in (?)", bun.In(appeared))
+		if !globalAccess {
+			query = permFilterQuery(query, accessScopes)
+		}
+		err := query.Scan(ctx, &trialMsgs)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			log.Errorf("error: %v\n", err)
+			return nil, err
+		}
+	}
+
+	// step 3: emit deletions and updates to the client
+	out = append(out, &stream.DeleteMsg{
+		Key:     TrialsDeleteKey,
+		Deleted: missing,
+	})
+	for _, msg := range trialMsgs {
+		out = append(out, msg.UpsertMsg())
+	}
+	return out, nil
+}
+
+// TrialMakeFilter creates a TrialMsg filter based on the given TrialSubscriptionSpec.
+func TrialMakeFilter(spec *TrialSubscriptionSpec) (func(*TrialMsg) bool, error) {
+	// should this filter even run?
+	if len(spec.TrialIDs) == 0 && len(spec.ExperimentIDs) == 0 {
+		return nil, errors.Errorf("invalid subscription spec arguments: %v %v", spec.TrialIDs, spec.ExperimentIDs)
+	}
+
+	// create sets based on subscription spec
+	trialIDs := make(map[int]struct{})
+	for _, id := range spec.TrialIDs {
+		if id <= 0 {
+			return nil, fmt.Errorf("invalid trial id: %d", id)
+		}
+		trialIDs[id] = struct{}{}
+	}
+	experimentIDs := make(map[int]struct{})
+	for _, id := range spec.ExperimentIDs {
+		if id <= 0 {
+			return nil, fmt.Errorf("invalid experiment id: %d", id)
+		}
+		experimentIDs[id] = struct{}{}
+	}
+
+	// return a closure around our copied maps
+	return func(msg *TrialMsg) bool {
+		if _, ok := trialIDs[msg.ID]; ok {
+			return true
+		}
+		if _, ok := experimentIDs[msg.ExperimentID]; ok {
+			return true
+		}
+		return false
+	}, nil
+}
+
+// TrialMakePermissionFilter returns a function that checks if a TrialMsg
+// is in scope of the user permissions. Trials are visible wherever their experiment is.
+func TrialMakePermissionFilter(ctx context.Context, user model.User) (func(*TrialMsg) bool, error) {
+	accessScopeSet, err := AuthZProvider.Get().GetExperimentStreamableScopes(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case accessScopeSet[model.GlobalAccessScopeID]:
+		// user has global access for viewing experiments
+		return func(msg *TrialMsg) bool { return true }, nil
+	default:
+		return func(msg *TrialMsg) bool {
+			return accessScopeSet[model.AccessScopeID(msg.WorkspaceID)]
+		}, nil
+	}
+}
+
+// TrialMakeHydrator returns a function that gets properties of a trial by
+// its id.
+func TrialMakeHydrator() func(*TrialMsg) (*TrialMsg, error) {
+	return func(msg *TrialMsg) (*TrialMsg, error) {
+		var saturatedMsg TrialMsg
+		query := selectTrialMsgs(&saturatedMsg).Where("trial_msg.id = ?", msg.GetID())
+		err := query.Scan(context.Background(), &saturatedMsg)
+		if err != nil && errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		} else if err != nil {
+			return nil, fmt.Errorf("error in trial hydrator: %w", err)
+		}
+		return &saturatedMsg, nil
+	}
+}
@@ -879,9 +879,12 @@ func (a *allocation) restoreResourceFailure(msg *sproto.ResourcesFailedError) {
 
 // releaseResources prompts the allocate to release resources.
 func (a *allocation) releaseResources(msg *sproto.ReleaseResources) {
-	if msg.ForceKill {
+	switch {
+	case msg.ForceKill:
 		a.tryExitOrKill(msg.Reason)
-	} else {
+	case msg.PreemptOnly && !a.req.Preemption.Preemptible:
+		a.syslog.WithField("reason", msg.Reason).Debug("ignoring release of non-preemptible allocation")
+	default:
 		a.tryExitOrTerminate(msg.Reason, msg.ForcePreemption)
 	}
 }
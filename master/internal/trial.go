@@ -308,6 +308,14 @@ func (t *trial) create() error {
 		int64(t.searcher.Create.TrialSeed),
 		t.taskSpec.LogRetentionDays,
 	)
+	if parent := t.searcher.Create.Checkpoint; parent != nil {
+		// Record which trial this one was copied from.
+		parentTrial, err := db.TrialByExperimentAndRequestID(context.TODO(), t.experimentID, parent.RequestID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to find parent trial %s", parent.RequestID)
+		}
+		m.ParentTrialID = &parentTrial.ID
+	}
 
 	err := t.addTask(context.TODO())
 	if err != nil {
@@ -400,6 +408,11 @@ func (t *trial) maybeAllocateTask() error {
 		preemptionTimeout = *t.config.PreemptionTimeout()
 	}
 
+	var timeLimit time.Duration
+	if t.config.Resources().TimeLimit() != nil {
+		timeLimit = time.Duration(*t.config.Resources().TimeLimit()) * time.Second
+	}
+
 	restoredAllocation, err := t.maybeRestoreAllocation()
 	if err != nil {
 		t.syslog.WithError(err).Warn("failed to restore trial allocation")
@@ -417,6 +430,8 @@ func (t *trial) maybeAllocateTask() error {
 			RequestTime:       time.Now().UTC(),
 			IsUserVisible:     true,
 			Name:              name,
+			Workspace:         t.taskSpec.Workspace,
+			Username:          t.taskSpec.OwnerUsername(),
 			SlotsNeeded:       t.config.Resources().SlotsPerTrial(),
 			ResourcePool:      t.config.Resources().ResourcePool(),
 			FittingRequirements: sproto.FittingRequirements{
@@ -426,7 +441,8 @@ func (t *trial) maybeAllocateTask() error {
 				Preemptible:     true,
 				TimeoutDuration: time.Duration(preemptionTimeout) * time.Second,
 			},
-			Restore: true,
+			Restore:   true,
+			TimeLimit: timeLimit,
 			ProxyPorts: sproto.NewProxyPortConfig(
 				tasks.TrialSpecProxyPorts(t.taskSpec, t.config), t.taskID),
 
@@ -464,6 +480,8 @@ func (t *trial) maybeAllocateTask() error {
 		JobSubmissionTime: t.jobSubmissionTime,
 		IsUserVisible:     true,
 		Name:              name,
+		Workspace:         t.taskSpec.Workspace,
+		Username:          t.taskSpec.OwnerUsername(),
 
 		SlotsNeeded:  t.config.Resources().SlotsPerTrial(),
 		ResourcePool: t.config.Resources().ResourcePool(),
@@ -476,6 +494,7 @@ func (t *trial) maybeAllocateTask() error {
 			TimeoutDuration: time.Duration(preemptionTimeout) * time.Second,
 		},
 		ProxyPorts: sproto.NewProxyPortConfig(tasks.TrialSpecProxyPorts(t.taskSpec, t.config), t.taskID),
+		TimeLimit:  timeLimit,
 
 		BlockedNodes: blockedNodes,
 	}
@@ -3,6 +3,7 @@ package webhooks
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -91,6 +92,17 @@ func (a *WebhooksAPIServer) PostWebhook(
 		)
 	}
 
+	if req.Webhook.PayloadTemplate != nil {
+		if err := validatePayloadTemplate(*req.Webhook.PayloadTemplate); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid payload template: %s", err)
+		}
+	}
+	if req.Webhook.WebhookType == webhookv1.WebhookType_WEBHOOK_TYPE_PAGERDUTY {
+		if _, err := pagerDutyRoutingKey(req.Webhook.Url); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%s", err)
+		}
+	}
+
 	for _, t := range req.Webhook.Triggers {
 		if t.TriggerType == webhookv1.TriggerType_TRIGGER_TYPE_TASK_LOG {
 			m := t.Condition.AsMap()
@@ -111,6 +123,12 @@ func (a *WebhooksAPIServer) PostWebhook(
 					regexConditionKey, m)
 			}
 		}
+		if t.TriggerType == webhookv1.TriggerType_TRIGGER_TYPE_METRIC_THRESHOLD_EXCEEDED {
+			if _, err := parseMetricThresholdCondition(t.Condition.AsMap()); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument,
+					"webhook metric threshold condition is invalid: %s", err)
+			}
+		}
 		if t.TriggerType == webhookv1.TriggerType_TRIGGER_TYPE_CUSTOM {
 			if req.Webhook.Mode != webhookv1.WebhookMode_WEBHOOK_MODE_SPECIFIC {
 				return nil, status.Errorf(codes.InvalidArgument,
@@ -120,7 +138,10 @@ func (a *WebhooksAPIServer) PostWebhook(
 		}
 	}
 
-	w := WebhookFromProto(req.Webhook)
+	w, err := WebhookFromProto(req.Webhook)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid webhook: %s", err)
+	}
 	if err := AddWebhook(ctx, &w); err != nil {
 		return nil, err
 	}
@@ -159,11 +180,13 @@ func (a *WebhooksAPIServer) TestWebhook(
 	eventID := uuid.New()
 	log.Infof("creating webhook payload for event %v", eventID)
 
-	var tReq *http.Request
-	switch webhook.WebhookType {
-	case WebhookTypeDefault:
-		t := time.Now().Unix()
-		p, perr := json.Marshal(EventPayload{
+	t := time.Now().Unix()
+	var p []byte
+	switch {
+	case webhook.PayloadTemplate != nil:
+		p, err = renderPayloadTemplate(*webhook.PayloadTemplate, samplePayload())
+	case webhook.WebhookType == WebhookTypeDefault:
+		p, err = json.Marshal(EventPayload{
 			ID:        uuid.New(),
 			Timestamp: t,
 			Type:      TriggerTypeStateChange,
@@ -174,18 +197,8 @@ func (a *WebhooksAPIServer) TestWebhook(
 				TestData: ptrs.Ptr("test"),
 			},
 		})
-		if perr != nil {
-			return nil, err
-		}
-
-		tr, rerr := generateWebhookRequest(ctx, webhook.URL, p, t)
-		if rerr != nil {
-			return nil, status.Errorf(codes.InvalidArgument,
-				"failed to create webhook request for event %v error : %v ", eventID, err)
-		}
-		tReq = tr
-	case WebhookTypeSlack:
-		slackMessage, serr := json.Marshal(SlackMessageBody{
+	case webhook.WebhookType == WebhookTypeSlack:
+		p, err = json.Marshal(SlackMessageBody{
 			Blocks: []SlackBlock{
 				{
 					Text: SlackField{
@@ -196,23 +209,31 @@ func (a *WebhooksAPIServer) TestWebhook(
 				},
 			},
 		})
-		if serr != nil {
-			return nil, err
-		}
-
-		tr, rerr := http.NewRequestWithContext(
-			ctx,
-			http.MethodPost,
-			webhook.URL,
-			bytes.NewBuffer(slackMessage),
-		)
-		if rerr != nil {
-			return nil, status.Errorf(codes.InvalidArgument,
-				"failed to create webhook request for event %v error : %v ", eventID, err)
-		}
-		tReq = tr
+	case webhook.WebhookType == WebhookTypeTeams, webhook.WebhookType == WebhookTypeDiscord,
+		webhook.WebhookType == WebhookTypePagerDuty:
+		p, err = generateMessagePayload(webhook, &eventMessage{
+			title:    "test",
+			color:    colorDefault,
+			severity: pagerDutySeverityInfo,
+		})
 	default:
-		panic("Unknown webhook type")
+		return nil, status.Errorf(codes.InvalidArgument,
+			"unknown webhook type %v for webhook %d", webhook.WebhookType, webhook.ID)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"failed to create webhook payload for event %v error: %v", eventID, err)
+	}
+
+	var tReq *http.Request
+	if webhook.WebhookType == WebhookTypeDefault || webhook.PayloadTemplate != nil {
+		tReq, err = generateWebhookRequest(ctx, webhook.URL, p, t)
+	} else {
+		tReq, err = http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewBuffer(p))
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"failed to create webhook request for event %v error : %v ", eventID, err)
 	}
 
 	log.Infof("creating webhook request for event %v", eventID)
@@ -232,7 +253,7 @@ func (a *WebhooksAPIServer) TestWebhook(
 		return nil, status.Errorf(codes.InvalidArgument,
 			"received error from webhook server for event %v error: %v ", eventID, resp.StatusCode)
 	}
-	return &apiv1.TestWebhookResponse{}, nil
+	return &apiv1.TestWebhookResponse{Completed: true, Payload: string(p)}, nil
 }
 
 // PostWebhookEventData handles data for custom trigger.
@@ -272,6 +293,16 @@ func (a *WebhooksAPIServer) PatchWebhook(
 	if err := authorizeEditRequest(ctx, webhook.Proto().WorkspaceId); err != nil {
 		return nil, err
 	}
+	if webhook.WebhookType == WebhookTypePagerDuty {
+		if _, err := pagerDutyRoutingKey(req.Webhook.GetUrl()); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%s", err)
+		}
+	}
+	if req.Webhook.GetPayloadTemplate() != "" {
+		if err := validatePayloadTemplate(req.Webhook.GetPayloadTemplate()); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid payload template: %s", err)
+		}
+	}
 
 	err = UpdateWebhook(
 		ctx,
@@ -286,3 +317,71 @@ func (a *WebhooksAPIServer) PatchWebhook(
 	}
 	return &apiv1.PatchWebhookResponse{}, nil
 }
+
+// GetWebhookDeliveries returns the delivery history of a webhook.
+func (a *WebhooksAPIServer) GetWebhookDeliveries(
+	ctx context.Context, req *apiv1.GetWebhookDeliveriesRequest,
+) (*apiv1.GetWebhookDeliveriesResponse, error) {
+	webhook, err := GetWebhook(ctx, int(req.WebhookId))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, api.NotFoundErrs("webhook", strconv.Itoa(int(req.WebhookId)), true)
+	} else if err != nil {
+		return nil, err
+	}
+	if err := authorizeEditRequest(ctx, webhook.Proto().WorkspaceId); err != nil {
+		return nil, err
+	}
+
+	ds, pagination, err := getDeliveries(ctx, webhook.ID, req.FailedOnly, int(req.Offset),
+		int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.GetWebhookDeliveriesResponse{Deliveries: ds.Proto(), Pagination: pagination}, nil
+}
+
+// RedeliverWebhookEvent queues a dead-lettered event of a webhook for delivery again.
+func (a *WebhooksAPIServer) RedeliverWebhookEvent(
+	ctx context.Context, req *apiv1.RedeliverWebhookEventRequest,
+) (*apiv1.RedeliverWebhookEventResponse, error) {
+	webhook, err := GetWebhook(ctx, int(req.WebhookId))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, api.NotFoundErrs("webhook", strconv.Itoa(int(req.WebhookId)), true)
+	} else if err != nil {
+		return nil, err
+	}
+	if err := authorizeEditRequest(ctx, webhook.Proto().WorkspaceId); err != nil {
+		return nil, err
+	}
+
+	n, err := redeliverEvents(ctx, webhook.ID, ptrs.Ptr(DeliveryID(req.DeliveryId)))
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, api.NotFoundErrs("dead-lettered webhook delivery",
+			strconv.Itoa(int(req.DeliveryId)), true)
+	}
+	return &apiv1.RedeliverWebhookEventResponse{}, nil
+}
+
+// RedeliverWebhookEvents queues all dead-lettered events of a webhook for delivery again.
+func (a *WebhooksAPIServer) RedeliverWebhookEvents(
+	ctx context.Context, req *apiv1.RedeliverWebhookEventsRequest,
+) (*apiv1.RedeliverWebhookEventsResponse, error) {
+	webhook, err := GetWebhook(ctx, int(req.WebhookId))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, api.NotFoundErrs("webhook", strconv.Itoa(int(req.WebhookId)), true)
+	} else if err != nil {
+		return nil, err
+	}
+	if err := authorizeEditRequest(ctx, webhook.Proto().WorkspaceId); err != nil {
+		return nil, err
+	}
+
+	n, err := redeliverEvents(ctx, webhook.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &apiv1.RedeliverWebhookEventsResponse{Redelivered: int32(n)}, nil
+}
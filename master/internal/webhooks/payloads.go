@@ -0,0 +1,327 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	conf "github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+const (
+	colorCompleted = "#13B670"
+	colorErrored   = "#DD5040"
+	colorDefault   = "#009bde"
+	colorWarning   = "#F7A000"
+
+	pagerDutySeverityCritical = "critical"
+	pagerDutySeverityError    = "error"
+	pagerDutySeverityWarning  = "warning"
+	pagerDutySeverityInfo     = "info"
+
+	// pagerDutyRoutingKeyParam is the webhook URL query parameter holding the integration key.
+	pagerDutyRoutingKeyParam = "routing_key"
+	pagerDutySource          = "determined-master"
+
+	// Discord and PagerDuty reject fields over these lengths.
+	maxMessageFieldLength = 1024
+	maxMessageTextLength  = 4000
+	maxDiscordTitleLength = 256
+)
+
+// messageField is a name and value pair shown with an eventMessage.
+type messageField struct {
+	name  string
+	value string
+}
+
+// eventMessage is a webhook event summarized for the chat and incident webhook types.
+type eventMessage struct {
+	title     string
+	text      string
+	url       string
+	urlText   string
+	color     string
+	severity  string
+	component string
+	fields    []messageField
+}
+
+// generateExperimentMessage summarizes an experiment event, the same way generateSlackPayload does.
+func generateExperimentMessage(
+	e model.Experiment, activeConfig expconf.ExperimentConfig, eventData *CustomTriggerData,
+	trialID *int, metricEvent *metricThresholdEvent,
+) *eventMessage {
+	m := &eventMessage{
+		text:      fmt.Sprintf("%v (#%v)", activeConfig.Name(), e.ID),
+		color:     colorDefault,
+		severity:  pagerDutySeverityInfo,
+		component: fmt.Sprintf("experiment-%d", e.ID),
+		urlText:   "View experiment",
+	}
+	if webUIBaseURL := conf.GetMasterConfig().Webhooks.BaseURL; webUIBaseURL != "" {
+		m.url = fmt.Sprintf("%v/det/experiments/%v/overview", webUIBaseURL, e.ID)
+	}
+
+	var mStatus string
+	switch e.State {
+	case model.CompletedState:
+		m.title = "Your experiment completed successfully"
+		m.color = colorCompleted
+		mStatus = "Completed"
+	case model.ErrorState:
+		m.title = "Your experiment has stopped with errors"
+		m.color = colorErrored
+		m.severity = pagerDutySeverityError
+		mStatus = "Errored"
+	default:
+		m.title = fmt.Sprintf("The status of your experiment is %s", e.State)
+		mStatus = string(e.State)
+	}
+
+	endTime := time.Now()
+	if e.EndTime != nil {
+		endTime = *e.EndTime
+	}
+	hours, minutes := math.Modf(endTime.Sub(e.StartTime).Hours())
+	m.fields = append(m.fields,
+		messageField{name: "Status", value: mStatus},
+		messageField{name: "Duration", value: fmt.Sprintf("%vh %vmin", hours, int(minutes*60))},
+	)
+	if wName := activeConfig.Workspace(); wName != "" {
+		m.fields = append(m.fields, messageField{name: "Workspace", value: wName})
+	}
+	if pName := activeConfig.Project(); pName != "" {
+		m.fields = append(m.fields, messageField{name: "Project", value: pName})
+	}
+	if trialID != nil && *trialID > 0 {
+		m.fields = append(m.fields, messageField{name: "Trial ID", value: strconv.Itoa(*trialID)})
+	}
+
+	if eventData != nil {
+		m.severity = logLevelToPagerDutySeverity(eventData.Level)
+		m.fields = append(m.fields,
+			messageField{name: "Level", value: eventData.Level},
+			messageField{name: "Title", value: eventData.Title},
+			messageField{name: "Description", value: eventData.Description},
+		)
+	}
+
+	if metricEvent != nil {
+		m.title = fmt.Sprintf("Metric %s (%s) reported %v, crossing the threshold %s %v",
+			metricEvent.metric.Name, metricEvent.metric.Group, metricEvent.metric.Value,
+			metricEvent.condition.Comparator, metricEvent.condition.Threshold)
+		m.color = colorWarning
+		m.severity = pagerDutySeverityWarning
+		m.fields = append(m.fields, messageField{
+			name:  "Steps completed",
+			value: strconv.Itoa(metricEvent.metric.StepsCompleted),
+		})
+	}
+
+	return m
+}
+
+// generateTaskLogMessage summarizes a task log event, the same way generateLogPatternSlackPayload does.
+func generateTaskLogMessage(
+	ctx context.Context, taskID model.TaskID, nodeName, regex, triggeringLog string,
+) (*eventMessage, error) {
+	task, err := db.TaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &eventMessage{
+		title:     "A log matched a webhook regex",
+		text:      triggeringLog,
+		color:     colorWarning,
+		severity:  pagerDutySeverityWarning,
+		component: string(taskID),
+		urlText:   "View full logs",
+		fields: []messageField{
+			{name: "Node", value: nodeName},
+			{name: "Regex", value: regex},
+		},
+	}
+	if task.TaskType == model.TaskTypeTrial {
+		trial, err := db.TrialByTaskID(ctx, taskID)
+		if err != nil {
+			return nil, err
+		}
+		m.fields = append(m.fields,
+			messageField{name: "Experiment ID", value: strconv.Itoa(trial.ExperimentID)},
+			messageField{name: "Trial ID", value: strconv.Itoa(trial.ID)},
+		)
+		if baseURL := conf.GetMasterConfig().Webhooks.BaseURL; baseURL != "" {
+			m.url = fmt.Sprintf("%s/det/experiments/%d/trials/%d/logs",
+				baseURL, trial.ExperimentID, trial.ID)
+		}
+	} else {
+		m.fields = append(m.fields,
+			messageField{name: "Task ID", value: string(taskID)},
+			messageField{name: "Task type", value: string(task.TaskType)},
+		)
+	}
+
+	return m, nil
+}
+
+// generateMessagePayload renders a summarized event for the chat and incident webhook types.
+func generateMessagePayload(w *Webhook, m *eventMessage) ([]byte, error) {
+	switch w.WebhookType {
+	case WebhookTypeTeams:
+		return generateTeamsPayload(m)
+	case WebhookTypeDiscord:
+		return generateDiscordPayload(m)
+	case WebhookTypePagerDuty:
+		return generatePagerDutyPayload(w.URL, m)
+	default:
+		return nil, fmt.Errorf("unknown webhook type %+v while generating message payload", w.WebhookType)
+	}
+}
+
+func generateTeamsPayload(m *eventMessage) ([]byte, error) {
+	facts := make([]TeamsFact, 0, len(m.fields))
+	for _, f := range m.fields {
+		facts = append(facts, TeamsFact{Name: f.name, Value: f.value})
+	}
+	card := TeamsMessageCard{
+		Type:       "MessageCard",
+		Context:    "http://schema.org/extensions",
+		ThemeColor: strings.TrimPrefix(m.color, "#"),
+		Summary:    m.title,
+		Title:      m.title,
+		Text:       truncateMessageText(m.text, maxMessageTextLength),
+		Sections:   []TeamsSection{{Facts: facts}},
+	}
+	if m.url != "" {
+		card.PotentialAction = []TeamsAction{{
+			Type:    "OpenUri",
+			Name:    m.urlText,
+			Targets: []TeamsActionTarget{{OS: "default", URI: m.url}},
+		}}
+	}
+
+	message, err := json.Marshal(card)
+	if err != nil {
+		return nil, fmt.Errorf("creating teams payload: %w", err)
+	}
+	return message, nil
+}
+
+func generateDiscordPayload(m *eventMessage) ([]byte, error) {
+	color, err := strconv.ParseInt(strings.TrimPrefix(m.color, "#"), 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("parsing discord embed color %s: %w", m.color, err)
+	}
+	fields := make([]DiscordField, 0, len(m.fields))
+	for _, f := range m.fields {
+		if f.value == "" {
+			// Discord rejects embeds with empty field values.
+			continue
+		}
+		fields = append(fields, DiscordField{
+			Name:   f.name,
+			Value:  truncateMessageText(f.value, maxMessageFieldLength),
+			Inline: true,
+		})
+	}
+
+	message, err := json.Marshal(DiscordMessage{
+		Embeds: []DiscordEmbed{{
+			Title:       truncateMessageText(m.title, maxDiscordTitleLength),
+			Description: truncateMessageText(m.text, maxMessageTextLength),
+			URL:         m.url,
+			Color:       int(color),
+			Fields:      fields,
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating discord payload: %w", err)
+	}
+	return message, nil
+}
+
+func generatePagerDutyPayload(webhookURL string, m *eventMessage) ([]byte, error) {
+	routingKey, err := pagerDutyRoutingKey(webhookURL)
+	if err != nil {
+		return nil, err
+	}
+	details := make(map[string]string, len(m.fields)+1)
+	for _, f := range m.fields {
+		details[f.name] = truncateMessageText(f.value, maxMessageFieldLength)
+	}
+	if m.text != "" {
+		details["Details"] = truncateMessageText(m.text, maxMessageTextLength)
+	}
+
+	event := PagerDutyEvent{
+		RoutingKey:  routingKey,
+		EventAction: "trigger",
+		Payload: PagerDutyPayload{
+			Summary:       truncateMessageText(m.title, maxMessageFieldLength),
+			Source:        pagerDutySource,
+			Severity:      m.severity,
+			Timestamp:     time.Now().UTC().Format(time.RFC3339),
+			Component:     m.component,
+			Class:         "determined",
+			CustomDetails: details,
+		},
+	}
+	if m.url != "" {
+		event.Links = []PagerDutyLink{{Href: m.url, Text: m.urlText}}
+	}
+
+	message, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("creating pagerduty payload: %w", err)
+	}
+	return message, nil
+}
+
+// pagerDutyRoutingKey returns the integration key PagerDuty webhooks carry in their URL.
+func pagerDutyRoutingKey(webhookURL string) (string, error) {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return "", fmt.Errorf("parsing pagerduty webhook url: %w", err)
+	}
+	routingKey := u.Query().Get(pagerDutyRoutingKeyParam)
+	if routingKey == "" {
+		return "", fmt.Errorf("pagerduty webhook url must set the '%s' query parameter",
+			pagerDutyRoutingKeyParam)
+	}
+	return routingKey, nil
+}
+
+func logLevelToPagerDutySeverity(level string) string {
+	switch level {
+	case model.LogLevelCritical:
+		return pagerDutySeverityCritical
+	case model.LogLevelError:
+		return pagerDutySeverityError
+	case model.LogLevelWarning:
+		return pagerDutySeverityWarning
+	default:
+		return pagerDutySeverityInfo
+	}
+}
+
+func truncateMessageText(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	cut := limit - len("...")
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "..."
+}
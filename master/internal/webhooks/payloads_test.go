@@ -0,0 +1,178 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+	"github.com/determined-ai/determined/proto/pkg/webhookv1"
+)
+
+func testEventMessage() *eventMessage {
+	return &eventMessage{
+		title:     "Your experiment has stopped with errors",
+		text:      "my-exp (#7)",
+		url:       "http://determined.ai/det/experiments/7/overview",
+		urlText:   "View experiment",
+		color:     colorErrored,
+		severity:  pagerDutySeverityError,
+		component: "experiment-7",
+		fields: []messageField{
+			{name: "Status", value: "Errored"},
+			{name: "Description", value: ""},
+		},
+	}
+}
+
+func TestWebhookTypeFromProto(t *testing.T) {
+	for proto, expected := range map[webhookv1.WebhookType]WebhookType{
+		webhookv1.WebhookType_WEBHOOK_TYPE_DEFAULT:   WebhookTypeDefault,
+		webhookv1.WebhookType_WEBHOOK_TYPE_SLACK:     WebhookTypeSlack,
+		webhookv1.WebhookType_WEBHOOK_TYPE_TEAMS:     WebhookTypeTeams,
+		webhookv1.WebhookType_WEBHOOK_TYPE_DISCORD:   WebhookTypeDiscord,
+		webhookv1.WebhookType_WEBHOOK_TYPE_PAGERDUTY: WebhookTypePagerDuty,
+	} {
+		actual, err := WebhookTypeFromProto(proto)
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+		require.Equal(t, proto, actual.Proto())
+	}
+
+	_, err := WebhookTypeFromProto(webhookv1.WebhookType_WEBHOOK_TYPE_UNSPECIFIED)
+	require.Error(t, err)
+	_, err = WebhookTypeFromProto(webhookv1.WebhookType(100))
+	require.Error(t, err)
+}
+
+func TestGenerateTeamsPayload(t *testing.T) {
+	p, err := generateMessagePayload(&Webhook{WebhookType: WebhookTypeTeams}, testEventMessage())
+	require.NoError(t, err)
+
+	var actual TeamsMessageCard
+	require.NoError(t, json.Unmarshal(p, &actual))
+	require.Equal(t, TeamsMessageCard{
+		Type:       "MessageCard",
+		Context:    "http://schema.org/extensions",
+		ThemeColor: "DD5040",
+		Summary:    "Your experiment has stopped with errors",
+		Title:      "Your experiment has stopped with errors",
+		Text:       "my-exp (#7)",
+		Sections: []TeamsSection{{Facts: []TeamsFact{
+			{Name: "Status", Value: "Errored"},
+			{Name: "Description", Value: ""},
+		}}},
+		PotentialAction: []TeamsAction{{
+			Type: "OpenUri",
+			Name: "View experiment",
+			Targets: []TeamsActionTarget{
+				{OS: "default", URI: "http://determined.ai/det/experiments/7/overview"},
+			},
+		}},
+	}, actual)
+}
+
+func TestGenerateDiscordPayload(t *testing.T) {
+	p, err := generateMessagePayload(&Webhook{WebhookType: WebhookTypeDiscord}, testEventMessage())
+	require.NoError(t, err)
+
+	var actual DiscordMessage
+	require.NoError(t, json.Unmarshal(p, &actual))
+	require.Equal(t, DiscordMessage{
+		Embeds: []DiscordEmbed{{
+			Title:       "Your experiment has stopped with errors",
+			Description: "my-exp (#7)",
+			URL:         "http://determined.ai/det/experiments/7/overview",
+			Color:       0xDD5040,
+			Fields:      []DiscordField{{Name: "Status", Value: "Errored", Inline: true}},
+		}},
+	}, actual)
+}
+
+func TestGeneratePagerDutyPayload(t *testing.T) {
+	w := &Webhook{
+		WebhookType: WebhookTypePagerDuty,
+		URL:         "https://events.pagerduty.com/v2/enqueue?routing_key=abc123",
+	}
+	p, err := generateMessagePayload(w, testEventMessage())
+	require.NoError(t, err)
+
+	var actual PagerDutyEvent
+	require.NoError(t, json.Unmarshal(p, &actual))
+	_, err = time.Parse(time.RFC3339, actual.Payload.Timestamp)
+	require.NoError(t, err)
+	actual.Payload.Timestamp = ""
+	require.Equal(t, PagerDutyEvent{
+		RoutingKey:  "abc123",
+		EventAction: "trigger",
+		Payload: PagerDutyPayload{
+			Summary:   "Your experiment has stopped with errors",
+			Source:    pagerDutySource,
+			Severity:  pagerDutySeverityError,
+			Component: "experiment-7",
+			Class:     "determined",
+			CustomDetails: map[string]string{
+				"Status":      "Errored",
+				"Description": "",
+				"Details":     "my-exp (#7)",
+			},
+		},
+		Links: []PagerDutyLink{{
+			Href: "http://determined.ai/det/experiments/7/overview",
+			Text: "View experiment",
+		}},
+	}, actual)
+
+	w.URL = "https://events.pagerduty.com/v2/enqueue"
+	_, err = generateMessagePayload(w, testEventMessage())
+	require.ErrorContains(t, err, pagerDutyRoutingKeyParam)
+}
+
+func TestGenerateExperimentMessage(t *testing.T) {
+	activeConfig := schemas.WithDefaults(expconf.ExperimentConfig{
+		RawName: expconf.Name{RawString: ptrs.Ptr("my-exp")},
+	})
+	start := time.Now().Add(-90 * time.Minute)
+	e := model.Experiment{ID: 7, State: model.ErrorState, StartTime: start}
+
+	m := generateExperimentMessage(e, activeConfig, nil, ptrs.Ptr(3), nil)
+	require.Equal(t, "Your experiment has stopped with errors", m.title)
+	require.Equal(t, "my-exp (#7)", m.text)
+	require.Equal(t, colorErrored, m.color)
+	require.Equal(t, pagerDutySeverityError, m.severity)
+	require.Contains(t, m.fields, messageField{name: "Trial ID", value: "3"})
+
+	e.State = model.ActiveState
+	m = generateExperimentMessage(e, activeConfig, &CustomTriggerData{
+		Title: "t", Description: "d", Level: model.LogLevelCritical,
+	}, nil, nil)
+	require.Equal(t, pagerDutySeverityCritical, m.severity)
+	require.Contains(t, m.fields, messageField{name: "Title", value: "t"})
+
+	m = generateExperimentMessage(e, activeConfig, nil, ptrs.Ptr(3), &metricThresholdEvent{
+		condition: &MetricThresholdCondition{
+			MetricName: "loss", Group: model.ValidationMetricGroup,
+			Comparator: ComparatorLessThan, Threshold: 0.5,
+		},
+		metric: &MetricPayload{
+			TrialID: 3, StepsCompleted: 10, Name: "loss",
+			Group: model.ValidationMetricGroup, Value: 0.25,
+		},
+	})
+	require.Equal(t, "Metric loss (validation) reported 0.25, crossing the threshold < 0.5", m.title)
+	require.Equal(t, pagerDutySeverityWarning, m.severity)
+	require.Contains(t, m.fields, messageField{name: "Steps completed", value: "10"})
+}
+
+func TestTruncateMessageText(t *testing.T) {
+	require.Equal(t, "short", truncateMessageText("short", 10))
+	require.Equal(t, "abcdefg...", truncateMessageText(strings.Repeat("abcdefghij", 2), 10))
+	// Multi-byte characters are never split.
+	require.Equal(t, "ééé...", truncateMessageText(strings.Repeat("é", 10), 10))
+}
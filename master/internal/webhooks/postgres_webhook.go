@@ -16,6 +16,7 @@ import (
 
 	conf "github.com/determined-ai/determined/master/internal/config"
 	"github.com/determined-ai/determined/master/internal/db"
+	"github.com/determined-ai/determined/master/internal/db/bunutils"
 	"github.com/determined-ai/determined/master/internal/experiment"
 	"github.com/determined-ai/determined/master/internal/logpattern"
 	"github.com/determined-ai/determined/master/internal/project"
@@ -24,6 +25,7 @@ import (
 	"github.com/determined-ai/determined/master/pkg/ptrs"
 	"github.com/determined-ai/determined/master/pkg/schemas"
 	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+	"github.com/determined-ai/determined/proto/pkg/apiv1"
 	"github.com/determined-ai/determined/proto/pkg/webhookv1"
 
 	"github.com/google/uuid"
@@ -34,10 +36,22 @@ type regexTriggers struct {
 	triggerIDToTrigger map[TriggerID]*Trigger
 }
 
+type metricTrigger struct {
+	condition *MetricThresholdCondition
+	trigger   *Trigger
+}
+
+// metricThresholdEvent is a reported metric that crossed the threshold of a trigger.
+type metricThresholdEvent struct {
+	condition *MetricThresholdCondition
+	metric    *MetricPayload
+}
+
 // WebhookManager manages webhooks.
 type WebhookManager struct {
 	mu                 sync.RWMutex
 	regexToTriggers    map[string]regexTriggers
+	idToMetricTrigger  map[TriggerID]metricTrigger
 	expToWebhookConfig map[int]*expconf.WebhooksConfigV0
 }
 
@@ -52,13 +66,15 @@ type CustomTriggerData struct {
 func New(ctx context.Context) (*WebhookManager, error) {
 	var triggers []*Trigger
 	if err := db.Bun().NewSelect().Model(&triggers).Relation("Webhook").
-		Where("trigger_type = ?", TriggerTypeTaskLog).
+		Where("trigger_type IN (?)",
+			bun.In([]TriggerType{TriggerTypeTaskLog, TriggerTypeMetricThresholdExceeded})).
 		Scan(ctx); err != nil {
-		return nil, fmt.Errorf("querying task logs triggers: %w", err)
+		return nil, fmt.Errorf("querying task logs and metric threshold triggers: %w", err)
 	}
 
 	m := &WebhookManager{
 		regexToTriggers:    make(map[string]regexTriggers),
+		idToMetricTrigger:  make(map[TriggerID]metricTrigger),
 		expToWebhookConfig: make(map[int]*expconf.WebhooksConfigV0),
 	}
 	if err := m.addTriggers(triggers); err != nil {
@@ -73,6 +89,14 @@ func (l *WebhookManager) addTriggers(triggers []*Trigger) error {
 	defer l.mu.Unlock()
 
 	for _, t := range triggers {
+		if t.TriggerType == TriggerTypeMetricThresholdExceeded {
+			condition, err := parseMetricThresholdCondition(t.Condition)
+			if err != nil {
+				return fmt.Errorf("parsing metric threshold trigger %d: %w", t.ID, err)
+			}
+			l.idToMetricTrigger[t.ID] = metricTrigger{condition: condition, trigger: t}
+			continue
+		}
 		if t.TriggerType != TriggerTypeTaskLog {
 			continue
 		}
@@ -106,6 +130,10 @@ func (l *WebhookManager) removeTriggers(triggers []*Trigger) error {
 	defer l.mu.Unlock()
 
 	for _, t := range triggers {
+		if t.TriggerType == TriggerTypeMetricThresholdExceeded {
+			delete(l.idToMetricTrigger, t.ID)
+			continue
+		}
 		if t.TriggerType != TriggerTypeTaskLog {
 			continue
 		}
@@ -128,6 +156,13 @@ func (l *WebhookManager) editTriggers(ts []*Trigger) error {
 	defer l.mu.Unlock()
 
 	for _, t := range ts {
+		if t.TriggerType == TriggerTypeMetricThresholdExceeded {
+			if mt, ok := l.idToMetricTrigger[t.ID]; ok {
+				mt.trigger.Webhook.URL = t.Webhook.URL
+				mt.trigger.Webhook.PayloadTemplate = t.Webhook.PayloadTemplate
+			}
+			continue
+		}
 		if t.TriggerType != TriggerTypeTaskLog {
 			continue
 		}
@@ -138,7 +173,9 @@ func (l *WebhookManager) editTriggers(ts []*Trigger) error {
 				"expected webhook trigger to have regex in condition instead got %v", t.Condition)
 		}
 
-		l.regexToTriggers[regex].triggerIDToTrigger[t.ID].Webhook.URL = t.Webhook.URL
+		cached := l.regexToTriggers[regex].triggerIDToTrigger[t.ID]
+		cached.Webhook.URL = t.Webhook.URL
+		cached.Webhook.PayloadTemplate = t.Webhook.PayloadTemplate
 	}
 	return nil
 }
@@ -248,6 +285,95 @@ func (l *WebhookManager) scanLogs(
 	return nil
 }
 
+func (l *WebhookManager) scanMetrics(
+	ctx context.Context, trialID, stepsCompleted int, group model.MetricGroup, metrics map[string]any,
+) error {
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	l.mu.RLock()
+	var matched []metricThresholdEvent
+	var triggers []*Trigger
+	for _, mt := range l.idToMetricTrigger {
+		if mt.condition.Group != group {
+			continue
+		}
+		value, ok := metrics[mt.condition.MetricName].(float64)
+		if !ok || !mt.condition.Exceeded(value) {
+			continue
+		}
+		matched = append(matched, metricThresholdEvent{
+			condition: mt.condition,
+			metric: &MetricPayload{
+				TrialID:        trialID,
+				StepsCompleted: stepsCompleted,
+				Name:           mt.condition.MetricName,
+				Group:          group,
+				Value:          value,
+			},
+		})
+		triggers = append(triggers, mt.trigger)
+	}
+	l.mu.RUnlock()
+	if len(matched) == 0 {
+		return nil
+	}
+
+	// A crossing is only reported once per trial, so skip triggers that already fired before
+	// loading the experiment.
+	var fired []TriggerID
+	triggerIDs := make([]TriggerID, len(triggers))
+	for i, t := range triggers {
+		triggerIDs[i] = t.ID
+	}
+	if err := db.Bun().NewSelect().Model((*webhookMetricThresholdTrigger)(nil)).
+		Column("trigger_id").
+		Where("trial_id = ?", trialID).
+		Where("trigger_id IN (?)", bun.In(triggerIDs)).
+		Scan(ctx, &fired); err != nil {
+		return fmt.Errorf("getting fired metric threshold triggers: %w", err)
+	}
+	if len(fired) == len(triggers) {
+		return nil
+	}
+
+	e, err := db.ExperimentByTrialID(ctx, trialID)
+	if err != nil {
+		return fmt.Errorf("getting experiment of trial %d: %w", trialID, err)
+	}
+	var expConfigBytes []byte
+	if err := db.Bun().NewSelect().Table("experiments").Column("config").
+		Where("id = ?", e.ID).Scan(ctx, &expConfigBytes); err != nil {
+		return fmt.Errorf("getting config of experiment %d: %w", e.ID, err)
+	}
+	activeConfig, err := expconf.ParseAnyExperimentConfigYAML(expConfigBytes)
+	if err != nil {
+		return fmt.Errorf("parsing config of experiment %d: %w", e.ID, err)
+	}
+	activeConfig = schemas.WithDefaults(activeConfig)
+
+	workspaceID, err := experiment.GetWorkspaceFromExperiment(ctx, e)
+	if err != nil {
+		return fmt.Errorf("get workspace id from experiment %d: %w", e.ID, err)
+	}
+	config, err := l.getWebhookConfig(ctx, &e.ID)
+	if err != nil {
+		return err
+	}
+
+	for i, t := range triggers {
+		if slices.Contains(fired, t.ID) || !matchWebhook(t, config, workspaceID, &e.ID) {
+			continue
+		}
+		if err := addMetricThresholdEvent(ctx, *e, activeConfig, t, &matched[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (l *WebhookManager) addWebhook(ctx context.Context, w *Webhook) error {
 	return db.Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
 		_, err := tx.NewInsert().Model(w).Exec(ctx)
@@ -342,7 +468,7 @@ func handleCustomTriggerData(ctx context.Context, data CustomTriggerData, experi
 				continue
 			}
 			err = generateEventForCustomTrigger(
-				ctx, &es, webhook.Triggers, webhook.WebhookType, webhook.URL, m.Experiment, activeConfig, data, trialID)
+				ctx, &es, webhook, m.Experiment, activeConfig, data, trialID)
 			if err != nil {
 				return fmt.Errorf("error genrating event for webhook with ID %d %+v: %w", webhookID, webhook, err)
 			}
@@ -358,7 +484,7 @@ func handleCustomTriggerData(ctx context.Context, data CustomTriggerData, experi
 				continue
 			}
 			err = generateEventForCustomTrigger(
-				ctx, &es, webhook.Triggers, webhook.WebhookType, webhook.URL, m.Experiment, activeConfig, data, trialID)
+				ctx, &es, webhook, m.Experiment, activeConfig, data, trialID)
 			if err != nil {
 				return fmt.Errorf("error genrating event %s %+v: %w", webhookName, webhook, err)
 			}
@@ -380,25 +506,23 @@ func handleCustomTriggerData(ctx context.Context, data CustomTriggerData, experi
 func generateEventForCustomTrigger(
 	ctx context.Context,
 	es *[]Event,
-	triggers Triggers,
-	webhookType WebhookType,
-	webhookURL string,
+	webhook *Webhook,
 	e model.Experiment,
 	activeConfig expconf.ExperimentConfig,
 	data CustomTriggerData,
 	trialID *int,
 ) error {
-	for _, t := range triggers {
+	for _, t := range webhook.Triggers {
 		if t.TriggerType != TriggerTypeCustom {
 			continue
 		}
 		p, err := generateEventPayload(
-			ctx, webhookType, e, activeConfig, e.State, TriggerTypeCustom, &data, trialID,
+			ctx, webhook, e, activeConfig, e.State, TriggerTypeCustom, &data, trialID, nil,
 		)
 		if err != nil {
 			return fmt.Errorf("error generating event payload: %w", err)
 		}
-		*es = append(*es, Event{Payload: p, URL: webhookURL})
+		*es = append(*es, Event{Payload: p, URL: webhook.URL, WebhookID: &webhook.ID})
 	}
 	return nil
 }
@@ -497,12 +621,12 @@ func ReportExperimentStateChanged(
 			continue
 		}
 		p, err := generateEventPayload(
-			ctx, t.Webhook.WebhookType, e, activeConfig, e.State, TriggerTypeStateChange, nil, nil,
+			ctx, t.Webhook, e, activeConfig, e.State, TriggerTypeStateChange, nil, nil, nil,
 		)
 		if err != nil {
 			return fmt.Errorf("error generating event payload: %w", err)
 		}
-		es = append(es, Event{Payload: p, URL: t.Webhook.URL})
+		es = append(es, Event{Payload: p, URL: t.Webhook.URL, WebhookID: &t.Webhook.ID})
 	}
 	if len(es) == 0 {
 		return nil
@@ -532,7 +656,7 @@ func addTaskLogEvent(ctx context.Context,
 	}
 
 	p, err := generateTaskLogPayload(
-		ctx, taskID, nodeName, regex, triggeringLog, trigger.Webhook.WebhookType)
+		ctx, taskID, nodeName, regex, triggeringLog, trigger.Webhook)
 	if err != nil {
 		return fmt.Errorf("generating task logs event: %w", err)
 	}
@@ -553,8 +677,9 @@ func addTaskLogEvent(ctx context.Context,
 		}
 
 		if _, err := db.Bun().NewInsert().Model(&Event{
-			Payload: p,
-			URL:     trigger.Webhook.URL,
+			Payload:   p,
+			URL:       trigger.Webhook.URL,
+			WebhookID: &trigger.Webhook.ID,
 		}).Exec(ctx); err != nil {
 			return fmt.Errorf("inserting task logs event trigger: %w", err)
 		}
@@ -572,31 +697,76 @@ func addTaskLogEvent(ctx context.Context,
 	return nil
 }
 
+func addMetricThresholdEvent(ctx context.Context,
+	e model.Experiment, activeConfig expconf.ExperimentConfig, trigger *Trigger,
+	metricEvent *metricThresholdEvent,
+) error {
+	defer func() {
+		if rec := recover(); rec != nil {
+			log.Errorf("uncaught error in adding metric threshold event: %v", rec)
+		}
+	}()
+
+	needToWake := false
+	if err := db.Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		res, err := tx.NewInsert().Model(&webhookMetricThresholdTrigger{
+			TrialID:   metricEvent.metric.TrialID,
+			TriggerID: trigger.ID,
+		}).On("CONFLICT (trial_id, trigger_id) DO NOTHING").Exec(ctx)
+		if err != nil {
+			return fmt.Errorf("inserting metric threshold event trigger: %w", err)
+		}
+		if rowsAffected, err := res.RowsAffected(); err != nil {
+			return fmt.Errorf("getting rows affected for webhook metric threshold triggers: %w", err)
+		} else if rowsAffected == 0 {
+			return nil
+		}
+
+		p, err := generateEventPayload(
+			ctx, trigger.Webhook, e, activeConfig, e.State,
+			TriggerTypeMetricThresholdExceeded, nil, &metricEvent.metric.TrialID, metricEvent,
+		)
+		if err != nil {
+			return fmt.Errorf("generating metric threshold event: %w", err)
+		}
+
+		if _, err := tx.NewInsert().Model(&Event{
+			Payload:   p,
+			URL:       trigger.Webhook.URL,
+			WebhookID: &trigger.Webhook.ID,
+		}).Exec(ctx); err != nil {
+			return fmt.Errorf("inserting metric threshold event: %w", err)
+		}
+
+		needToWake = true
+		return nil
+	}); err != nil {
+		return fmt.Errorf("adding webhook metric threshold trigger event: %w", err)
+	}
+
+	if needToWake {
+		singletonShipper.Wake()
+	}
+
+	return nil
+}
+
 func generateTaskLogPayload(
 	ctx context.Context,
 	taskID model.TaskID,
 	nodeName,
 	regex,
 	triggeringLog string,
-	wt WebhookType,
+	w *Webhook,
 ) ([]byte, error) {
-	switch wt {
+	if w.PayloadTemplate != nil {
+		return renderPayloadTemplate(*w.PayloadTemplate,
+			taskLogEventPayload(taskID, nodeName, regex, triggeringLog))
+	}
+
+	switch w.WebhookType {
 	case WebhookTypeDefault:
-		p, err := json.Marshal(EventPayload{
-			ID:        uuid.New(),
-			Type:      TriggerTypeTaskLog,
-			Timestamp: time.Now().Unix(),
-			Condition: Condition{
-				Regex: regex,
-			},
-			Data: EventData{
-				TaskLog: &TaskLogPayload{
-					TaskID:        taskID,
-					NodeName:      nodeName,
-					TriggeringLog: triggeringLog,
-				},
-			},
-		})
+		p, err := json.Marshal(taskLogEventPayload(taskID, nodeName, regex, triggeringLog))
 		if err != nil {
 			return nil, fmt.Errorf("marshaling json for log pattern payload: %w", err)
 		}
@@ -610,8 +780,36 @@ func generateTaskLogPayload(
 		}
 		return p, nil
 
+	case WebhookTypeTeams, WebhookTypeDiscord, WebhookTypePagerDuty:
+		m, err := generateTaskLogMessage(ctx, taskID, nodeName, regex, triggeringLog)
+		if err != nil {
+			return nil, err
+		}
+		return generateMessagePayload(w, m)
+
 	default:
-		return nil, fmt.Errorf("unknown webhook type %+v while generating log pattern payload", wt)
+		return nil, fmt.Errorf(
+			"unknown webhook type %+v while generating log pattern payload", w.WebhookType)
+	}
+}
+
+func taskLogEventPayload(
+	taskID model.TaskID, nodeName, regex, triggeringLog string,
+) EventPayload {
+	return EventPayload{
+		ID:        uuid.New(),
+		Type:      TriggerTypeTaskLog,
+		Timestamp: time.Now().Unix(),
+		Condition: Condition{
+			Regex: regex,
+		},
+		Data: EventData{
+			TaskLog: &TaskLogPayload{
+				TaskID:        taskID,
+				NodeName:      nodeName,
+				TriggeringLog: triggeringLog,
+			},
+		},
 	}
 }
 
@@ -675,49 +873,74 @@ func generateLogPatternSlackPayload(
 
 func generateEventPayload(
 	ctx context.Context,
-	wt WebhookType,
+	w *Webhook,
 	e model.Experiment,
 	activeConfig expconf.ExperimentConfig,
 	expState model.State,
 	tT TriggerType,
-	eventData *CustomTriggerData, trialID *int,
+	eventData *CustomTriggerData, trialID *int, metricEvent *metricThresholdEvent,
 ) ([]byte, error) {
-	switch wt {
+	if w.PayloadTemplate != nil {
+		return renderPayloadTemplate(*w.PayloadTemplate, experimentEventPayload(
+			e, activeConfig, expState, tT, eventData, trialID, metricEvent))
+	}
+
+	switch w.WebhookType {
 	case WebhookTypeDefault:
-		experiment := experimentToWebhookPayload(e, activeConfig)
-		if trialID != nil && *trialID > 0 {
-			experiment.TrialID = *trialID
-		}
-		pJSON, err := json.Marshal(EventPayload{
-			ID:        uuid.New(),
-			Type:      tT,
-			Timestamp: time.Now().Unix(),
-			Condition: Condition{
-				State: expState,
-			},
-			Data: EventData{
-				Experiment: experiment,
-				CustomData: eventData,
-			},
-		})
+		pJSON, err := json.Marshal(experimentEventPayload(
+			e, activeConfig, expState, tT, eventData, trialID, metricEvent))
 		if err != nil {
 			return nil, err
 		}
 		return pJSON, nil
 	case WebhookTypeSlack:
-		slackJSON, err := generateSlackPayload(ctx, e, activeConfig, eventData, trialID)
+		slackJSON, err := generateSlackPayload(ctx, e, activeConfig, eventData, trialID, metricEvent)
 		if err != nil {
 			return nil, err
 		}
 		return slackJSON, nil
+	case WebhookTypeTeams, WebhookTypeDiscord, WebhookTypePagerDuty:
+		return generateMessagePayload(
+			w, generateExperimentMessage(e, activeConfig, eventData, trialID, metricEvent))
 	default:
-		panic(fmt.Errorf("unknown webhook type: %+v", wt))
+		return nil, fmt.Errorf("unknown webhook type: %+v", w.WebhookType)
+	}
+}
+
+func experimentEventPayload(
+	e model.Experiment,
+	activeConfig expconf.ExperimentConfig,
+	expState model.State,
+	tT TriggerType,
+	eventData *CustomTriggerData, trialID *int, metricEvent *metricThresholdEvent,
+) EventPayload {
+	experiment := experimentToWebhookPayload(e, activeConfig)
+	if trialID != nil && *trialID > 0 {
+		experiment.TrialID = *trialID
+	}
+	condition := Condition{State: expState}
+	var metric *MetricPayload
+	if metricEvent != nil {
+		condition = Condition{MetricThresholdCondition: metricEvent.condition}
+		metric = metricEvent.metric
+	}
+	return EventPayload{
+		ID:        uuid.New(),
+		Type:      tT,
+		Timestamp: time.Now().Unix(),
+		Condition: condition,
+		Data: EventData{
+			Experiment: experiment,
+			CustomData: eventData,
+			Metric:     metric,
+		},
 	}
 }
 
 func generateSlackPayload(
 	ctx context.Context, e model.Experiment,
 	activeConfig expconf.ExperimentConfig, eventData *CustomTriggerData, trialID *int,
+	metricEvent *metricThresholdEvent,
 ) ([]byte, error) {
 	var status string
 	var eURL string
@@ -725,7 +948,7 @@ func generateSlackPayload(
 	var projectID int
 	var wID int
 	var w *model.Workspace
-	c := "#13B670"
+	c := colorCompleted
 	config := conf.GetMasterConfig()
 	wName := activeConfig.Workspace() // TODO(ET-288): This is incorrect on moves.
 	pName := activeConfig.Project()
@@ -770,7 +993,7 @@ func generateSlackPayload(
 		} else {
 			eURL = fmt.Sprintf("❌ %v (#%v)", activeConfig.Name(), e.ID)
 		}
-		c = "#DD5040"
+		c = colorErrored
 		mStatus = "Errored"
 	default:
 		status = fmt.Sprintf("The status of your experiment is %s", e.State)
@@ -780,10 +1003,17 @@ func generateSlackPayload(
 		} else {
 			eURL = fmt.Sprintf("%v (#%v)", activeConfig.Name(), e.ID)
 		}
-		c = "#009bde"
+		c = colorDefault
 		mStatus = string(e.State)
 	}
 
+	if metricEvent != nil {
+		status = fmt.Sprintf("Metric `%s` (%s) reported %v, crossing the threshold %s %v",
+			metricEvent.metric.Name, metricEvent.metric.Group, metricEvent.metric.Value,
+			metricEvent.condition.Comparator, metricEvent.condition.Threshold)
+		c = colorWarning
+	}
+
 	endTime := time.Now()
 	if e.EndTime != nil {
 		endTime = *e.EndTime
@@ -924,6 +1154,109 @@ WHERE q.id = webhook_events_queue.id RETURNING webhook_events_queue.*
 	return &eventBatch{tx: &tx, events: events}, nil
 }
 
+// recordDelivery records the outcome of delivering an event and, if delivery failed, keeps the
+// event as a dead letter so it can be redelivered later.
+func recordDelivery(ctx context.Context, tx bun.IDB, e Event, d *Delivery) error {
+	if e.WebhookID == nil {
+		// Events queued before deliveries were recorded, or whose webhook has since been deleted.
+		return nil
+	}
+	d.WebhookID = *e.WebhookID
+	d.URL = e.URL
+	d.DeliveredAt = time.Now().UTC()
+	if _, err := tx.NewInsert().Model(d).Exec(ctx); err != nil {
+		return fmt.Errorf("inserting webhook delivery: %w", err)
+	}
+	if d.Success {
+		return nil
+	}
+
+	if _, err := tx.NewInsert().Model(&deadLetter{
+		DeliveryID: d.ID,
+		WebhookID:  d.WebhookID,
+		Payload:    e.Payload,
+	}).Exec(ctx); err != nil {
+		return fmt.Errorf("inserting webhook dead letter: %w", err)
+	}
+	return nil
+}
+
+// getDeliveries returns a page of the deliveries of a webhook, most recent first.
+func getDeliveries(
+	ctx context.Context, webhookID WebhookID, failedOnly bool, offset, limit int,
+) (Deliveries, *apiv1.Pagination, error) {
+	ds := Deliveries{}
+	q := db.Bun().NewSelect().Model(&ds).
+		ColumnExpr("d.*").
+		ColumnExpr("dl.delivery_id IS NOT NULL AS dead_lettered").
+		Join("LEFT JOIN webhook_dead_letters dl ON dl.delivery_id = d.id").
+		Where("d.webhook_id = ?", webhookID).
+		Order("d.id DESC")
+	if failedOnly {
+		q = q.Where("NOT d.success")
+	}
+	q, pagination, err := bunutils.Paginate(ctx, q, offset, limit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("paginating deliveries of webhook %d: %w", webhookID, err)
+	}
+	if err := q.Scan(ctx); err != nil {
+		return nil, nil, fmt.Errorf("getting deliveries of webhook %d: %w", webhookID, err)
+	}
+	return ds, pagination, nil
+}
+
+// deleteExpiredDeliveries deletes the deliveries made before the given time, along with their dead
+// letters, and returns how many were deleted.
+func deleteExpiredDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	res, err := db.Bun().NewDelete().Model((*Delivery)(nil)).
+		Where("delivered_at < ?", before).
+		Exec(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("deleting webhook deliveries before %s: %w", before, err)
+	}
+	return res.RowsAffected()
+}
+
+// redeliverEvents moves the dead letters of a webhook back to the event queue, to be sent to the
+// current URL of the webhook. If deliveryID is set, only the event of that delivery is redelivered.
+func redeliverEvents(ctx context.Context, webhookID WebhookID, deliveryID *DeliveryID) (int, error) {
+	var es []Event
+	if err := db.Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var dls []deadLetter
+		q := tx.NewDelete().Model((*deadLetter)(nil)).
+			Where("webhook_id = ?", webhookID).
+			Returning("*")
+		if deliveryID != nil {
+			q = q.Where("delivery_id = ?", *deliveryID)
+		}
+		if _, err := q.Exec(ctx, &dls); err != nil {
+			return fmt.Errorf("deleting dead letters: %w", err)
+		}
+		if len(dls) == 0 {
+			return nil
+		}
+
+		var w Webhook
+		if err := tx.NewSelect().Model(&w).Where("id = ?", webhookID).Scan(ctx); err != nil {
+			return fmt.Errorf("getting webhook %d: %w", webhookID, err)
+		}
+		for _, dl := range dls {
+			es = append(es, Event{Payload: dl.Payload, URL: w.URL, WebhookID: &w.ID})
+		}
+		if _, err := tx.NewInsert().Model(&es).Exec(ctx); err != nil {
+			return fmt.Errorf("inserting redelivered events: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return 0, fmt.Errorf("redelivering events of webhook %d: %w", webhookID, err)
+	}
+
+	if len(es) > 0 {
+		singletonShipper.Wake()
+	}
+	return len(es), nil
+}
+
 // updateWebhook updates a webhook in the database.
 func (l *WebhookManager) updateWebhook(
 	ctx context.Context,
@@ -939,16 +1272,25 @@ func (l *WebhookManager) updateWebhook(
 	}
 
 	err = db.Bun().RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
-		_, err := tx.NewUpdate().Table("webhooks").
+		q := tx.NewUpdate().Table("webhooks").
 			Set("url = ?", p.Url).
-			Where("id = ?", webhookID).
-			Exec(ctx)
+			Where("id = ?", webhookID)
+		if p.PayloadTemplate != nil {
+			q = q.Set("payload_template = NULLIF(?, '')", *p.PayloadTemplate)
+		}
+		_, err := q.Exec(ctx)
 		if err != nil {
 			return fmt.Errorf("updating webhook %d: %w", webhookID, err)
 		}
 
 		for _, t := range ts {
 			t.Webhook.URL = p.Url
+			if p.PayloadTemplate != nil {
+				t.Webhook.PayloadTemplate = p.PayloadTemplate
+				if *p.PayloadTemplate == "" {
+					t.Webhook.PayloadTemplate = nil
+				}
+			}
 		}
 		if err := l.editTriggers(ts); err != nil {
 			return err
@@ -22,6 +22,7 @@ import (
 	"github.com/determined-ai/determined/master/pkg/ptrs"
 	"github.com/determined-ai/determined/master/pkg/schemas"
 	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+	"github.com/determined-ai/determined/proto/pkg/webhookv1"
 )
 
 const (
@@ -324,6 +325,180 @@ func TestWebhookScanLogs(t *testing.T) {
 	}
 }
 
+func TestWebhookScanMetrics(t *testing.T) {
+	ctx := context.Background()
+	clearWebhooksTables(ctx, t)
+
+	manager, err := New(ctx)
+	require.NoError(t, err)
+
+	workspaceID, _ := db.RequireMockWorkspaceID(t, pgDB, "")
+
+	lossBelow := func(threshold float64) Triggers {
+		return Triggers{{
+			TriggerType: TriggerTypeMetricThresholdExceeded,
+			Condition: map[string]any{
+				"metric_name": "loss",
+				"group":       model.ValidationMetricGroup.ToString(),
+				"comparator":  "<",
+				"threshold":   threshold,
+			},
+		}}
+	}
+	w0 := &Webhook{
+		WebhookType: WebhookTypeDefault,
+		URL:         uuid.New().String(),
+		Triggers:    lossBelow(0.5),
+		Mode:        WebhookModeWorkspace,
+	}
+	w1 := &Webhook{
+		WebhookType: WebhookTypeSlack,
+		URL:         uuid.New().String(),
+		Triggers:    lossBelow(0.1),
+		Mode:        WebhookModeWorkspace,
+	}
+	w2 := &Webhook{
+		WebhookType: WebhookTypeDefault,
+		URL:         uuid.New().String(),
+		Triggers: Triggers{{
+			TriggerType: TriggerTypeMetricThresholdExceeded,
+			Condition: map[string]any{
+				"metric_name": "loss",
+				"group":       model.TrainingMetricGroup.ToString(),
+				"comparator":  "<",
+				"threshold":   0.5,
+			},
+		}},
+		Mode: WebhookModeWorkspace,
+	}
+	w3 := &Webhook{
+		WebhookType: WebhookTypeDefault,
+		URL:         uuid.New().String(),
+		Triggers:    lossBelow(0.5),
+		Mode:        WebhookModeWorkspace,
+		WorkspaceID: ptrs.Ptr(int32(workspaceID)),
+	}
+
+	require.NoError(t, manager.addWebhook(ctx, w0))
+	require.NoError(t, manager.addWebhook(ctx, w1))
+	require.NoError(t, manager.addWebhook(ctx, w2))
+	require.NoError(t, manager.addWebhook(ctx, w3))
+
+	for _, shouldBounce := range []bool{false, true} {
+		clearWebhooksEvent(ctx, t)
+
+		if shouldBounce {
+			manager, err = New(ctx)
+			require.NoError(t, err)
+		}
+
+		user := db.RequireMockUser(t, pgDB)
+		exp := db.RequireMockExperiment(t, pgDB, user)
+		trial, _ := db.RequireMockTrial(t, pgDB, exp)
+
+		report := func(loss float64) {
+			require.NoError(t, manager.scanMetrics(ctx, trial.ID, 10, model.ValidationMetricGroup,
+				map[string]any{"loss": loss, "accuracy": "not a number"}))
+		}
+
+		report(0.7)
+		require.Zero(t, countEventsForURL(ctx, t, w0.URL))
+
+		report(0.3)
+		require.Equal(t, 1, countEventsForURL(ctx, t, w0.URL))
+		require.Zero(t, countEventsForURL(ctx, t, w1.URL))
+		require.Zero(t, countEventsForURL(ctx, t, w2.URL))
+		require.Zero(t, countEventsForURL(ctx, t, w3.URL))
+
+		// Crossings are only reported once per trial.
+		report(0.05)
+		require.Equal(t, 1, countEventsForURL(ctx, t, w0.URL))
+		require.Equal(t, 1, countEventsForURL(ctx, t, w1.URL))
+
+		var e Event
+		require.NoError(t, db.Bun().NewSelect().Model(&e).Where("url = ?", w0.URL).Scan(ctx))
+		var p EventPayload
+		require.NoError(t, json.Unmarshal(e.Payload, &p))
+		require.Equal(t, TriggerTypeMetricThresholdExceeded, p.Type)
+		require.Equal(t, ComparatorLessThan, p.Condition.Comparator)
+		require.Equal(t, 0.5, p.Condition.Threshold)
+		require.Equal(t, &MetricPayload{
+			TrialID:        trial.ID,
+			StepsCompleted: 10,
+			Name:           "loss",
+			Group:          model.ValidationMetricGroup,
+			Value:          0.3,
+		}, p.Data.Metric)
+		require.Equal(t, trial.ID, p.Data.Experiment.TrialID)
+	}
+
+	require.NoError(t, manager.deleteWebhook(ctx, w0.ID))
+	clearWebhooksEvent(ctx, t)
+
+	user := db.RequireMockUser(t, pgDB)
+	exp := db.RequireMockExperiment(t, pgDB, user)
+	trial, _ := db.RequireMockTrial(t, pgDB, exp)
+	require.NoError(t, manager.scanMetrics(ctx, trial.ID, 10, model.ValidationMetricGroup,
+		map[string]any{"loss": 0.01}))
+	require.Zero(t, countEventsForURL(ctx, t, w0.URL))
+	require.Equal(t, 1, countEventsForURL(ctx, t, w1.URL))
+}
+
+func TestWebhookPayloadTemplate(t *testing.T) {
+	ctx := context.Background()
+	clearWebhooksTables(ctx, t)
+
+	manager, err := New(ctx)
+	require.NoError(t, err)
+
+	regex := uuid.New().String()
+	w := &Webhook{
+		WebhookType:     WebhookTypeSlack,
+		URL:             uuid.New().String(),
+		Triggers:        Triggers{{TriggerType: TriggerTypeTaskLog, Condition: map[string]any{"regex": regex}}},
+		Mode:            WebhookModeWorkspace,
+		PayloadTemplate: ptrs.Ptr(`first {{.event_data.task_log.node_name}}`),
+	}
+	require.NoError(t, manager.addWebhook(ctx, w))
+
+	actual, err := GetWebhook(ctx, int(w.ID))
+	require.NoError(t, err)
+	require.Equal(t, w.PayloadTemplate, actual.PayloadTemplate)
+
+	scan := func() string {
+		clearWebhooksEvent(ctx, t)
+		user := db.RequireMockUser(t, pgDB)
+		exp := db.RequireMockExperiment(t, pgDB, user)
+		_, task := db.RequireMockTrial(t, pgDB, exp)
+		require.NoError(t, manager.scanLogs(ctx, []*model.TaskLog{
+			{TaskID: string(task.TaskID), AgentID: ptrs.Ptr("node-a"), Log: regex},
+		}, 0, nil))
+
+		var e Event
+		require.NoError(t, db.Bun().NewSelect().Model(&e).Where("url = ?", w.URL).Scan(ctx))
+		return string(e.Payload)
+	}
+	require.Equal(t, "first node-a", scan())
+
+	require.NoError(t, manager.updateWebhook(ctx, int32(w.ID), &webhookv1.PatchWebhook{
+		Url:             w.URL,
+		PayloadTemplate: ptrs.Ptr(`second {{.event_data.task_log.node_name}}`),
+	}))
+	require.Equal(t, "second node-a", scan())
+
+	require.NoError(t, manager.updateWebhook(ctx, int32(w.ID), &webhookv1.PatchWebhook{
+		Url:             w.URL,
+		PayloadTemplate: ptrs.Ptr(""),
+	}))
+	actual, err = GetWebhook(ctx, int(w.ID))
+	require.NoError(t, err)
+	require.Nil(t, actual.PayloadTemplate)
+
+	var slack SlackMessageBody
+	require.NoError(t, json.Unmarshal([]byte(scan()), &slack))
+	require.Len(t, slack.Blocks, 1)
+}
+
 func TestGenerateTaskLogPayload(t *testing.T) {
 	ctx := context.Background()
 
@@ -370,7 +545,7 @@ func testGenerateTaskLogPayloadTest(
 		}
 
 		payload, err := generateTaskLogPayload(
-			ctx, task.TaskID, "nodeA", "regexa", "trigA", webhookType)
+			ctx, task.TaskID, "nodeA", "regexa", "trigA", &Webhook{WebhookType: webhookType})
 		require.NoError(t, err)
 
 		if webhookType == WebhookTypeDefault {
@@ -847,6 +1022,8 @@ func clearWebhooksTables(ctx context.Context, t *testing.T) {
 	require.NoError(t, err)
 	_, err = db.Bun().NewDelete().Model((*webhookTaskLogTrigger)(nil)).Where("true").Exec(ctx)
 	require.NoError(t, err)
+	_, err = db.Bun().NewDelete().Model((*webhookMetricThresholdTrigger)(nil)).Where("true").Exec(ctx)
+	require.NoError(t, err)
 }
 
 func clearWebhooksEvent(ctx context.Context, t *testing.T) {
@@ -855,6 +1032,8 @@ func clearWebhooksEvent(ctx context.Context, t *testing.T) {
 	require.NoError(t, err)
 	_, err = db.Bun().NewDelete().Model((*webhookTaskLogTrigger)(nil)).Where("true").Exec(ctx)
 	require.NoError(t, err)
+	_, err = db.Bun().NewDelete().Model((*webhookMetricThresholdTrigger)(nil)).Where("true").Exec(ctx)
+	require.NoError(t, err)
 }
 
 func countEventsForURL(ctx context.Context, t *testing.T, url string) int {
@@ -10,6 +10,7 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -18,6 +19,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	conf "github.com/determined-ai/determined/master/internal/config"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
 )
 
 const (
@@ -27,6 +29,11 @@ const (
 	backoffAttempts = 2
 	backoffInterval = time.Second
 	backoffMax      = time.Minute
+
+	// maxResponseBodySize is how much of a response body is kept with a delivery.
+	maxResponseBodySize = 1024
+	// deliveryCleanupInterval is how often deliveries past their retention are deleted.
+	deliveryCleanupInterval = time.Hour
 )
 
 var singletonShipper *shipper
@@ -72,9 +79,38 @@ func newShipper() *shipper {
 		}()
 	}
 
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.cleanUpDeliveries(ctx)
+	}()
+
 	return s
 }
 
+// cleanUpDeliveries periodically deletes the deliveries, along with their dead-lettered events,
+// that are older than the delivery retention of the master config.
+func (s *shipper) cleanUpDeliveries(ctx context.Context) {
+	t := time.NewTicker(deliveryCleanupInterval)
+	defer t.Stop()
+	for {
+		if days := conf.GetMasterConfig().Webhooks.DeliveryRetentionDays; days >= 0 {
+			count, err := deleteExpiredDeliveries(ctx, time.Now().AddDate(0, 0, -days))
+			if err != nil {
+				s.log.WithError(err).Error("failed to delete expired webhook deliveries")
+			} else if count > 0 {
+				s.log.WithField("count", count).Info("deleted expired webhook deliveries")
+			}
+		}
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 // Wake attempts to wake the sender.
 func (s *shipper) Wake() {
 	select {
@@ -150,24 +186,30 @@ func (w *worker) shipBatch(ctx context.Context) (int, error) {
 		}
 	}()
 
+	ds := make([]Delivery, len(b.events))
 	var wg sync.WaitGroup
-	for _, e := range b.events {
+	for i, e := range b.events {
 		wg.Add(1)
-		go func(e Event) {
+		go func(e Event, d *Delivery) {
 			defer wg.Done()
 			if err := back.Retry(
-				func() error { return w.deliver(ctx, e) },
+				func() error { return w.deliver(ctx, e, d) },
 				backoff(),
 			); err != nil {
 				w.log.WithError(err).Error("failed to deliver webhook")
 			}
-		}(e)
+		}(e, &ds[i])
 	}
 	wg.Wait()
 	if err := ctx.Err(); err != nil {
 		return 0, err
 	}
 
+	for i, e := range b.events {
+		if err := recordDelivery(ctx, b.tx, e, &ds[i]); err != nil {
+			return 0, fmt.Errorf("recording delivery: %w", err)
+		}
+	}
 	if err := b.commit(); err != nil {
 		return 0, fmt.Errorf("consuming batch: %w", err)
 	}
@@ -181,10 +223,22 @@ func backoff() back.BackOff {
 	return back.WithMaxRetries(bf, backoffAttempts)
 }
 
-func (w *worker) deliver(ctx context.Context, e Event) error {
+// deliver makes one attempt to deliver an event, recording its outcome in d.
+func (w *worker) deliver(ctx context.Context, e Event, d *Delivery) (err error) {
+	d.Attempts++
+	d.StatusCode, d.ResponseBody, d.Error = nil, "", nil
+	start := time.Now()
+	defer func() {
+		d.LatencyMs = int(time.Since(start).Milliseconds())
+		d.Success = err == nil
+		if err != nil {
+			d.Error = ptrs.Ptr(err.Error())
+		}
+	}()
+
 	req, err := generateWebhookRequest(ctx, e.URL, e.Payload, time.Now().Unix())
 	if err != nil {
-		return err
+		return back.Permanent(err)
 	}
 
 	resp, err := w.cl.Do(req)
@@ -192,16 +246,24 @@ func (w *worker) deliver(ctx context.Context, e Event) error {
 		return fmt.Errorf("sending webhook request: %w", err)
 	}
 	defer func() {
-		if err = resp.Body.Close(); err != nil {
+		if err := resp.Body.Close(); err != nil {
 			w.log.WithError(err).Warn("failed to close response body")
 		}
 	}()
 
+	d.StatusCode = &resp.StatusCode
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBodySize))
+	if err != nil {
+		w.log.WithError(err).Warn("failed to read response body")
+	}
+	// Postgres text columns only hold valid UTF-8 without NUL bytes.
+	d.ResponseBody = strings.ToValidUTF8(strings.ReplaceAll(string(body), "\x00", ""), "")
+
 	switch {
 	case resp.StatusCode >= 500: //nolint: usestdlibvars
-		return fmt.Errorf("request returned %v: %w", resp.StatusCode, err)
+		return fmt.Errorf("request returned %v", resp.StatusCode)
 	case resp.StatusCode >= 400: //nolint: usestdlibvars
-		return back.Permanent(fmt.Errorf("request returned %v: %w", resp.StatusCode, err))
+		return back.Permanent(fmt.Errorf("request returned %v", resp.StatusCode))
 	default:
 		return nil
 	}
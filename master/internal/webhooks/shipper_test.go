@@ -8,6 +8,7 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"net/http/httptest"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -221,6 +222,107 @@ func TestShipper(t *testing.T) {
 	}
 }
 
+func TestDeliveries(t *testing.T) {
+	ctx := context.Background()
+	pgDB, closeDB := db.MustResolveTestPostgres(t)
+	defer closeDB()
+	db.MustMigrateTestPostgres(t, pgDB, db.MigrationsFromDB)
+	clearWebhooksTables(ctx, t)
+
+	var statusCode atomic.Int64
+	statusCode.Store(http.StatusBadRequest)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(statusCode.Load()))
+		_, err := w.Write([]byte("response body"))
+		require.NoError(t, err)
+	}))
+	defer srv.Close()
+
+	w := &Webhook{
+		URL: srv.URL,
+		Triggers: []*Trigger{{
+			TriggerType: TriggerTypeStateChange,
+			Condition:   map[string]interface{}{"state": model.CompletedState},
+		}},
+		WebhookType: WebhookTypeDefault,
+		Mode:        WebhookModeWorkspace,
+	}
+	require.NoError(t, AddWebhook(ctx, w))
+	_, err := db.Bun().NewInsert().Model(&Event{
+		URL: srv.URL, Payload: []byte(`{}`), WebhookID: &w.ID,
+	}).Exec(ctx)
+	require.NoError(t, err)
+
+	// Only wakes are needed from the shipper, batches are shipped by hand below.
+	singletonShipper = &shipper{wake: make(chan struct{}, 1)}
+	worker := newWorker(0)
+
+	t.Log("a rejected event is dead lettered")
+	n, err := worker.shipBatch(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	ds, _, err := getDeliveries(ctx, w.ID, false, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, ds, 1)
+	require.False(t, ds[0].Success)
+	require.Equal(t, http.StatusBadRequest, *ds[0].StatusCode)
+	require.Equal(t, "response body", ds[0].ResponseBody)
+	require.Equal(t, 1, ds[0].Attempts)
+	require.NotNil(t, ds[0].Error)
+	require.True(t, ds[0].DeadLettered)
+
+	t.Log("redelivering an unknown delivery does nothing")
+	n, err = redeliverEvents(ctx, w.ID, ptrs.Ptr(ds[0].ID+1))
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	t.Log("a redelivered event is sent again")
+	statusCode.Store(http.StatusOK)
+	n, err = redeliverEvents(ctx, w.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	n, err = worker.shipBatch(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	ds, _, err = getDeliveries(ctx, w.ID, false, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, ds, 2)
+	require.True(t, ds[0].Success)
+	require.Equal(t, http.StatusOK, *ds[0].StatusCode)
+	require.Nil(t, ds[0].Error)
+	require.False(t, ds[1].DeadLettered)
+
+	failed, _, err := getDeliveries(ctx, w.ID, true, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+	require.Equal(t, ds[1].ID, failed[0].ID)
+
+	page, pagination, err := getDeliveries(ctx, w.ID, false, 1, 1)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	require.Equal(t, ds[1].ID, page[0].ID)
+	require.Equal(t, int32(2), pagination.Total)
+
+	n, err = redeliverEvents(ctx, w.ID, nil)
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	t.Log("expired deliveries are deleted")
+	_, err = db.Bun().NewUpdate().Model((*Delivery)(nil)).
+		Set("delivered_at = ?", time.Now().AddDate(0, 0, -2)).
+		Where("id = ?", ds[1].ID).
+		Exec(ctx)
+	require.NoError(t, err)
+	deleted, err := deleteExpiredDeliveries(ctx, time.Now().AddDate(0, 0, -1))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+	ds, _, err = getDeliveries(ctx, w.ID, false, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, ds, 1)
+	require.True(t, ds[0].Success)
+}
+
 func scheduledWaitToDuration(factor int) time.Duration {
 	return 10 * time.Duration(factor) * time.Millisecond
 }
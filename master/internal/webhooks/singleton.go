@@ -6,6 +6,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/proto/pkg/trialv1"
 	"github.com/determined-ai/determined/proto/pkg/webhookv1"
 )
 
@@ -26,6 +27,18 @@ func ScanLogs(ctx context.Context, logs []*model.TaskLog, workspaceID model.Acce
 	return defaultManager.scanLogs(ctx, logs, workspaceID, expID)
 }
 
+// ReportTrialMetrics sends webhooks for trial metrics that cross a threshold. This should be called
+// wherever we add trial metrics.
+func ReportTrialMetrics(ctx context.Context, m *trialv1.TrialMetrics, group model.MetricGroup) error {
+	if defaultManager == nil {
+		log.Error("webhook manager is uninitialized")
+		return nil
+	}
+
+	return defaultManager.scanMetrics(ctx, int(m.TrialId), int(m.GetStepsCompleted()), group,
+		m.Metrics.AvgMetrics.AsMap())
+}
+
 // AddWebhook adds a Webhook and its Triggers to the DB.
 func AddWebhook(ctx context.Context, w *Webhook) error {
 	if defaultManager == nil {
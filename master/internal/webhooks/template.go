@@ -0,0 +1,133 @@
+package webhooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/template"
+
+	"github.com/google/uuid"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+// payloadTemplateFuncs are the functions available to webhook payload templates, on top of the
+// text/template builtins.
+var payloadTemplateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+func parsePayloadTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("payload").Funcs(payloadTemplateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parsing payload template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// payloadTemplateData returns the event as templates see it. Keys match the default webhook
+// payload, so `{{.event_data.experiment.name}}` renders the experiment name.
+func payloadTemplateData(p EventPayload) (map[string]any, error) {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling payload template data: %w", err)
+	}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	var data map[string]any
+	if err := d.Decode(&data); err != nil {
+		return nil, fmt.Errorf("unmarshaling payload template data: %w", err)
+	}
+	return data, nil
+}
+
+// renderPayloadTemplate renders a webhook payload template against an event.
+func renderPayloadTemplate(text string, p EventPayload) ([]byte, error) {
+	tmpl, err := parsePayloadTemplate(text)
+	if err != nil {
+		return nil, err
+	}
+	data, err := payloadTemplateData(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("rendering payload template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// validatePayloadTemplate checks that a payload template parses and renders against an event with
+// every field set, so references to keys that never exist are caught before any event is sent.
+func validatePayloadTemplate(text string) error {
+	tmpl, err := parsePayloadTemplate(text)
+	if err != nil {
+		return err
+	}
+	data, err := payloadTemplateData(samplePayload())
+	if err != nil {
+		return err
+	}
+	if err := tmpl.Option("missingkey=error").Execute(io.Discard, data); err != nil {
+		return fmt.Errorf("rendering payload template: %w", err)
+	}
+	return nil
+}
+
+// samplePayload returns an event with every field set, used to validate and preview templates.
+func samplePayload() EventPayload {
+	return EventPayload{
+		ID:        uuid.New(),
+		Type:      TriggerTypeStateChange,
+		Timestamp: 1700000000,
+		Condition: Condition{
+			State: model.CompletedState,
+			Regex: "(.*)error(.*)",
+			MetricThresholdCondition: &MetricThresholdCondition{
+				MetricName: "validation_loss",
+				Group:      model.ValidationMetricGroup,
+				Comparator: ComparatorLessThan,
+				Threshold:  0.05,
+			},
+		},
+		Data: EventData{
+			TestData: ptrs.Ptr("test"),
+			Experiment: &ExperimentPayload{
+				ID:            1,
+				State:         model.CompletedState,
+				Name:          expconf.Name{RawString: ptrs.Ptr("sample-experiment")},
+				Duration:      3600,
+				ResourcePool:  "default",
+				SlotsPerTrial: 1,
+				WorkspaceName: "Uncategorized",
+				ProjectName:   "Uncategorized",
+				TrialID:       1,
+			},
+			TaskLog: &TaskLogPayload{
+				TaskID:        "sample-task",
+				NodeName:      "sample-node",
+				TriggeringLog: "sample error log",
+			},
+			CustomData: &CustomTriggerData{
+				Title:       "sample title",
+				Description: "sample description",
+				Level:       model.LogLevelInfo,
+			},
+			Metric: &MetricPayload{
+				TrialID:        1,
+				StepsCompleted: 100,
+				Name:           "validation_loss",
+				Group:          model.ValidationMetricGroup,
+				Value:          0.04,
+			},
+		},
+	}
+}
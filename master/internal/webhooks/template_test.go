@@ -0,0 +1,55 @@
+package webhooks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func TestRenderPayloadTemplate(t *testing.T) {
+	p := taskLogEventPayload("task-1", "node-a", "err", "an error happened")
+
+	rendered, err := renderPayloadTemplate(
+		`{"text": "{{.event_type}} on {{.event_data.task_log.node_name}}", `+
+			`"log": {{json .event_data.task_log.triggering_log}}}`, p)
+	require.NoError(t, err)
+	require.JSONEq(t,
+		`{"text": "TASK_LOG on node-a", "log": "an error happened"}`, string(rendered))
+
+	// Numbers render as they appear in the default payload.
+	rendered, err = renderPayloadTemplate(
+		`{{.event_data.metric.steps_completed}} {{.event_data.metric.value}}`, EventPayload{
+			Data: EventData{Metric: &MetricPayload{
+				StepsCompleted: 12345678, Group: model.ValidationMetricGroup, Value: 0.25,
+			}},
+		})
+	require.NoError(t, err)
+	require.Equal(t, "12345678 0.25", string(rendered))
+
+	// Sections missing from an event do not fail rendering.
+	rendered, err = renderPayloadTemplate(
+		`{{with .event_data.experiment}}{{.name}}{{else}}none{{end}}`, p)
+	require.NoError(t, err)
+	require.Equal(t, "none", string(rendered))
+}
+
+func TestValidatePayloadTemplate(t *testing.T) {
+	for _, valid := range []string{
+		`plain text`,
+		`{{json .}}`,
+		`{{.event_data.experiment.name}} {{.condition.state}} {{.event_data.custom_data.title}}`,
+		`{{with .event_data.metric}}{{.name}}={{.value}}{{end}}`,
+	} {
+		require.NoError(t, validatePayloadTemplate(valid), valid)
+	}
+
+	for _, invalid := range []string{
+		`{{.event_data.experiment.nmae}}`,
+		`{{.event_data.experiment.name`,
+		`{{undefined_func .}}`,
+	} {
+		require.Error(t, validatePayloadTemplate(invalid), invalid)
+	}
+}
@@ -2,11 +2,15 @@ package webhooks
 
 import (
 	"fmt"
+	"math"
+	"time"
 
 	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/determined-ai/determined/master/pkg/model"
 	"github.com/determined-ai/determined/master/pkg/protoutils"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
 	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
 	"github.com/determined-ai/determined/proto/pkg/webhookv1"
 
@@ -29,30 +33,40 @@ func (ws Webhooks) Proto() []*webhookv1.Webhook {
 type Webhook struct {
 	bun.BaseModel `bun:"table:webhooks"`
 
-	ID          WebhookID   `bun:"id,pk,autoincrement"`
-	WebhookType WebhookType `bun:"webhook_type,notnull"`
-	URL         string      `bun:"url,notnull"`
-	Mode        WebhookMode `bun:"mode,notnull"`
-	WorkspaceID *int32      `bun:"workspace_id"`
-	Name        string      `bun:"name,notnull"`
+	ID              WebhookID   `bun:"id,pk,autoincrement"`
+	WebhookType     WebhookType `bun:"webhook_type,notnull"`
+	URL             string      `bun:"url,notnull"`
+	Mode            WebhookMode `bun:"mode,notnull"`
+	WorkspaceID     *int32      `bun:"workspace_id"`
+	Name            string      `bun:"name,notnull"`
+	PayloadTemplate *string     `bun:"payload_template"`
 
 	Triggers Triggers `bun:"rel:has-many,join:id=webhook_id"`
 }
 
 // WebhookFromProto returns a model Webhook from a proto definition.
-func WebhookFromProto(w *webhookv1.Webhook) Webhook {
+func WebhookFromProto(w *webhookv1.Webhook) (Webhook, error) {
 	var workspaceID *int32
 	if w.WorkspaceId != 0 {
 		workspaceID = &(w.WorkspaceId)
 	}
-	return Webhook{
-		URL:         w.Url,
-		Triggers:    TriggersFromProto(w.Triggers),
-		WebhookType: WebhookTypeFromProto(w.WebhookType),
-		Name:        w.Name,
-		WorkspaceID: workspaceID,
-		Mode:        WebhookModeFromProto(w.Mode),
+	webhookType, err := WebhookTypeFromProto(w.WebhookType)
+	if err != nil {
+		return Webhook{}, err
+	}
+	payloadTemplate := w.PayloadTemplate
+	if payloadTemplate != nil && *payloadTemplate == "" {
+		payloadTemplate = nil
 	}
+	return Webhook{
+		URL:             w.Url,
+		Triggers:        TriggersFromProto(w.Triggers),
+		WebhookType:     webhookType,
+		Name:            w.Name,
+		WorkspaceID:     workspaceID,
+		Mode:            WebhookModeFromProto(w.Mode),
+		PayloadTemplate: payloadTemplate,
+	}, nil
 }
 
 // Proto converts a webhook to its protobuf representation.
@@ -62,13 +76,14 @@ func (w *Webhook) Proto() *webhookv1.Webhook {
 		workspaceID = *(w.WorkspaceID)
 	}
 	return &webhookv1.Webhook{
-		Id:          int32(w.ID),
-		Url:         w.URL,
-		Triggers:    w.Triggers.Proto(),
-		WebhookType: w.WebhookType.Proto(),
-		Name:        w.Name,
-		Mode:        w.Mode.Proto(),
-		WorkspaceId: workspaceID,
+		Id:              int32(w.ID),
+		Url:             w.URL,
+		Triggers:        w.Triggers.Proto(),
+		WebhookType:     w.WebhookType.Proto(),
+		Name:            w.Name,
+		Mode:            w.Mode.Proto(),
+		WorkspaceId:     workspaceID,
+		PayloadTemplate: w.PayloadTemplate,
 	}
 }
 
@@ -122,6 +137,14 @@ type webhookTaskLogTrigger struct {
 	TriggerID TriggerID    `bun:"trigger_id"`
 }
 
+// Used for deduping metric threshold webhook events.
+type webhookMetricThresholdTrigger struct {
+	bun.BaseModel `bun:"table:webhook_metric_threshold_triggers"`
+
+	TrialID   int       `bun:"trial_id"`
+	TriggerID TriggerID `bun:"trigger_id"`
+}
+
 // TriggerFromProto returns a Trigger from a proto definition.
 func TriggerFromProto(t *webhookv1.Trigger) *Trigger {
 	return &Trigger{
@@ -166,6 +189,15 @@ const (
 
 	// WebhookTypeSlack represents a slack webhook.
 	WebhookTypeSlack WebhookType = "SLACK"
+
+	// WebhookTypeTeams represents a Microsoft Teams webhook.
+	WebhookTypeTeams WebhookType = "TEAMS"
+
+	// WebhookTypeDiscord represents a Discord webhook.
+	WebhookTypeDiscord WebhookType = "DISCORD"
+
+	// WebhookTypePagerDuty represents a PagerDuty Events API v2 webhook.
+	WebhookTypePagerDuty WebhookType = "PAGERDUTY"
 )
 
 const (
@@ -187,15 +219,20 @@ func WebhookModeFromProto(w webhookv1.WebhookMode) WebhookMode {
 }
 
 // WebhookTypeFromProto returns a WebhookType from a proto.
-func WebhookTypeFromProto(w webhookv1.WebhookType) WebhookType {
+func WebhookTypeFromProto(w webhookv1.WebhookType) (WebhookType, error) {
 	switch w {
 	case webhookv1.WebhookType_WEBHOOK_TYPE_DEFAULT:
-		return WebhookTypeDefault
+		return WebhookTypeDefault, nil
 	case webhookv1.WebhookType_WEBHOOK_TYPE_SLACK:
-		return WebhookTypeSlack
+		return WebhookTypeSlack, nil
+	case webhookv1.WebhookType_WEBHOOK_TYPE_TEAMS:
+		return WebhookTypeTeams, nil
+	case webhookv1.WebhookType_WEBHOOK_TYPE_DISCORD:
+		return WebhookTypeDiscord, nil
+	case webhookv1.WebhookType_WEBHOOK_TYPE_PAGERDUTY:
+		return WebhookTypePagerDuty, nil
 	default:
-		// TODO(???): prob don't panic
-		panic(fmt.Errorf("missing mapping for webhook type %s to SQL", w))
+		return "", fmt.Errorf("missing mapping for webhook type %s to SQL", w)
 	}
 }
 
@@ -223,6 +260,12 @@ func (w WebhookType) Proto() webhookv1.WebhookType {
 		return webhookv1.WebhookType_WEBHOOK_TYPE_DEFAULT
 	case WebhookTypeSlack:
 		return webhookv1.WebhookType_WEBHOOK_TYPE_SLACK
+	case WebhookTypeTeams:
+		return webhookv1.WebhookType_WEBHOOK_TYPE_TEAMS
+	case WebhookTypeDiscord:
+		return webhookv1.WebhookType_WEBHOOK_TYPE_DISCORD
+	case WebhookTypePagerDuty:
+		return webhookv1.WebhookType_WEBHOOK_TYPE_PAGERDUTY
 	default:
 		return webhookv1.WebhookType_WEBHOOK_TYPE_UNSPECIFIED
 	}
@@ -282,9 +325,75 @@ type WebhookEventID int
 type Event struct {
 	bun.BaseModel `bun:"table:webhook_events_queue"`
 
-	ID      WebhookEventID `bun:"id,pk,autoincrement"`
-	URL     string         `bun:"url,notnull"`
-	Payload []byte         `bun:"payload,notnull"`
+	ID        WebhookEventID `bun:"id,pk,autoincrement"`
+	URL       string         `bun:"url,notnull"`
+	Payload   []byte         `bun:"payload,notnull"`
+	WebhookID *WebhookID     `bun:"webhook_id"`
+}
+
+// DeliveryID is the type for Delivery IDs.
+type DeliveryID int
+
+// Delivery corresponds to a row in the "webhook_deliveries" DB table. It records the outcome of
+// the last attempt to deliver an event.
+type Delivery struct {
+	bun.BaseModel `bun:"table:webhook_deliveries,alias:d"`
+
+	ID           DeliveryID `bun:"id,pk,autoincrement"`
+	WebhookID    WebhookID  `bun:"webhook_id,notnull"`
+	URL          string     `bun:"url,notnull"`
+	Success      bool       `bun:"success,notnull"`
+	StatusCode   *int       `bun:"status_code"`
+	LatencyMs    int        `bun:"latency_ms,notnull"`
+	Attempts     int        `bun:"attempts,notnull"`
+	ResponseBody string     `bun:"response_body,notnull"`
+	Error        *string    `bun:"error"`
+	DeliveredAt  time.Time  `bun:"delivered_at,notnull"`
+
+	DeadLettered bool `bun:"dead_lettered,scanonly"`
+}
+
+// Proto converts a delivery to its protobuf representation.
+func (d *Delivery) Proto() *webhookv1.WebhookDelivery {
+	var statusCode *int32
+	if d.StatusCode != nil {
+		statusCode = ptrs.Ptr(int32(*d.StatusCode))
+	}
+	return &webhookv1.WebhookDelivery{
+		Id:           int32(d.ID),
+		WebhookId:    int32(d.WebhookID),
+		Url:          d.URL,
+		Success:      d.Success,
+		StatusCode:   statusCode,
+		LatencyMs:    int32(d.LatencyMs),
+		Attempts:     int32(d.Attempts),
+		ResponseBody: d.ResponseBody,
+		Error:        d.Error,
+		DeliveredAt:  timestamppb.New(d.DeliveredAt),
+		DeadLettered: d.DeadLettered,
+	}
+}
+
+// Deliveries is a slice of Delivery objects.
+type Deliveries []Delivery
+
+// Proto converts a slice of deliveries to its protobuf representation.
+func (ds Deliveries) Proto() []*webhookv1.WebhookDelivery {
+	out := make([]*webhookv1.WebhookDelivery, len(ds))
+	for i, d := range ds {
+		out[i] = d.Proto()
+	}
+	return out
+}
+
+// deadLetter corresponds to a row in the "webhook_dead_letters" DB table, an event that failed
+// to deliver and is kept until it is redelivered.
+type deadLetter struct {
+	bun.BaseModel `bun:"table:webhook_dead_letters"`
+
+	DeliveryID DeliveryID `bun:"delivery_id,pk"`
+	WebhookID  WebhookID  `bun:"webhook_id,notnull"`
+	Payload    []byte     `bun:"payload,notnull"`
 }
 
 // SlackMessageBody corresponds to an entire message as a Slack Block.
@@ -312,6 +421,89 @@ type SlackField struct {
 	Text string `json:"text"`
 }
 
+// TeamsMessageCard corresponds to a Microsoft Teams connector message card.
+type TeamsMessageCard struct {
+	Type            string         `json:"@type"`
+	Context         string         `json:"@context"`
+	ThemeColor      string         `json:"themeColor,omitempty"`
+	Summary         string         `json:"summary"`
+	Title           string         `json:"title,omitempty"`
+	Text            string         `json:"text,omitempty"`
+	Sections        []TeamsSection `json:"sections,omitempty"`
+	PotentialAction []TeamsAction  `json:"potentialAction,omitempty"`
+}
+
+// TeamsSection corresponds to a section of a Microsoft Teams message card.
+type TeamsSection struct {
+	Facts []TeamsFact `json:"facts,omitempty"`
+}
+
+// TeamsFact corresponds to a name and value pair in a Microsoft Teams message card section.
+type TeamsFact struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// TeamsAction corresponds to an action button of a Microsoft Teams message card.
+type TeamsAction struct {
+	Type    string              `json:"@type"`
+	Name    string              `json:"name"`
+	Targets []TeamsActionTarget `json:"targets"`
+}
+
+// TeamsActionTarget corresponds to the link opened by a Microsoft Teams message card action.
+type TeamsActionTarget struct {
+	OS  string `json:"os"`
+	URI string `json:"uri"`
+}
+
+// DiscordMessage corresponds to the body of a Discord webhook execution.
+type DiscordMessage struct {
+	Content string         `json:"content,omitempty"`
+	Embeds  []DiscordEmbed `json:"embeds,omitempty"`
+}
+
+// DiscordEmbed corresponds to a Discord rich embed.
+type DiscordEmbed struct {
+	Title       string         `json:"title,omitempty"`
+	Description string         `json:"description,omitempty"`
+	URL         string         `json:"url,omitempty"`
+	Color       int            `json:"color,omitempty"`
+	Fields      []DiscordField `json:"fields,omitempty"`
+}
+
+// DiscordField corresponds to a field in a Discord rich embed.
+type DiscordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+// PagerDutyEvent corresponds to a PagerDuty Events API v2 event.
+type PagerDutyEvent struct {
+	RoutingKey  string           `json:"routing_key"`
+	EventAction string           `json:"event_action"`
+	Payload     PagerDutyPayload `json:"payload"`
+	Links       []PagerDutyLink  `json:"links,omitempty"`
+}
+
+// PagerDutyPayload corresponds to the payload of a PagerDuty Events API v2 event.
+type PagerDutyPayload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"`
+	Timestamp     string            `json:"timestamp,omitempty"`
+	Component     string            `json:"component,omitempty"`
+	Class         string            `json:"class,omitempty"`
+	CustomDetails map[string]string `json:"custom_details,omitempty"`
+}
+
+// PagerDutyLink corresponds to a link attached to a PagerDuty Events API v2 event.
+type PagerDutyLink struct {
+	Href string `json:"href"`
+	Text string `json:"text,omitempty"`
+}
+
 // EventPayload respresents a webhook event.
 type EventPayload struct {
 	ID        uuid.UUID   `json:"event_id"`
@@ -321,12 +513,107 @@ type EventPayload struct {
 	Data      EventData   `json:"event_data"`
 }
 
-const regexConditionKey = "regex"
+const (
+	regexConditionKey       = "regex"
+	metricNameConditionKey  = "metric_name"
+	metricGroupConditionKey = "group"
+	comparatorConditionKey  = "comparator"
+	thresholdConditionKey   = "threshold"
+)
 
 // Condition represents a trigger condition.
 type Condition struct {
 	State model.State `json:"state,omitempty"`
 	Regex string      `json:"regex,omitempty"`
+	*MetricThresholdCondition
+}
+
+// Comparator is how a reported metric value is compared against a threshold.
+type Comparator string
+
+const (
+	// ComparatorGreaterThan triggers when the metric value is greater than the threshold.
+	ComparatorGreaterThan Comparator = ">"
+	// ComparatorGreaterThanOrEqual triggers when the metric value is at least the threshold.
+	ComparatorGreaterThanOrEqual Comparator = ">="
+	// ComparatorLessThan triggers when the metric value is less than the threshold.
+	ComparatorLessThan Comparator = "<"
+	// ComparatorLessThanOrEqual triggers when the metric value is at most the threshold.
+	ComparatorLessThanOrEqual Comparator = "<="
+)
+
+// MetricThresholdCondition is the condition of a METRIC_THRESHOLD_EXCEEDED trigger.
+type MetricThresholdCondition struct {
+	MetricName string            `json:"metric_name"`
+	Group      model.MetricGroup `json:"group"`
+	Comparator Comparator        `json:"comparator"`
+	Threshold  float64           `json:"threshold"`
+}
+
+// parseMetricThresholdCondition parses and validates the condition of a metric threshold trigger.
+func parseMetricThresholdCondition(c map[string]interface{}) (*MetricThresholdCondition, error) {
+	name, ok := c[metricNameConditionKey].(string)
+	if !ok || name == "" {
+		return nil, fmt.Errorf("metric threshold condition requires a string %q, got %v",
+			metricNameConditionKey, c[metricNameConditionKey])
+	}
+
+	group, ok := c[metricGroupConditionKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("metric threshold condition requires a string %q, got %v",
+			metricGroupConditionKey, c[metricGroupConditionKey])
+	}
+	if err := model.MetricGroup(group).Validate(); err != nil {
+		return nil, fmt.Errorf("metric threshold condition has invalid %q: %w",
+			metricGroupConditionKey, err)
+	}
+
+	comparator, _ := c[comparatorConditionKey].(string)
+	switch Comparator(comparator) {
+	case ComparatorGreaterThan, ComparatorGreaterThanOrEqual,
+		ComparatorLessThan, ComparatorLessThanOrEqual:
+	default:
+		return nil, fmt.Errorf("metric threshold condition %q must be one of >, >=, <, <=, got %v",
+			comparatorConditionKey, c[comparatorConditionKey])
+	}
+
+	var threshold float64
+	switch v := c[thresholdConditionKey].(type) {
+	case float64:
+		threshold = v
+	case int:
+		threshold = float64(v)
+	default:
+		return nil, fmt.Errorf("metric threshold condition requires a numeric %q, got %v",
+			thresholdConditionKey, c[thresholdConditionKey])
+	}
+	if math.IsNaN(threshold) || math.IsInf(threshold, 0) {
+		return nil, fmt.Errorf("metric threshold condition %q must be finite, got %v",
+			thresholdConditionKey, threshold)
+	}
+
+	return &MetricThresholdCondition{
+		MetricName: name,
+		Group:      model.MetricGroup(group),
+		Comparator: Comparator(comparator),
+		Threshold:  threshold,
+	}, nil
+}
+
+// Exceeded returns true if the value crosses the threshold.
+func (c MetricThresholdCondition) Exceeded(value float64) bool {
+	switch c.Comparator {
+	case ComparatorGreaterThan:
+		return value > c.Threshold
+	case ComparatorGreaterThanOrEqual:
+		return value >= c.Threshold
+	case ComparatorLessThan:
+		return value < c.Threshold
+	case ComparatorLessThanOrEqual:
+		return value <= c.Threshold
+	default:
+		return false
+	}
 }
 
 // EventData represents the event_data for a webhook event.
@@ -335,6 +622,7 @@ type EventData struct {
 	Experiment *ExperimentPayload `json:"experiment,omitempty"`
 	TaskLog    *TaskLogPayload    `json:"task_log,omitempty"`
 	CustomData *CustomTriggerData `json:"custom_data,omitempty"`
+	Metric     *MetricPayload     `json:"metric,omitempty"`
 }
 
 // ExperimentPayload is the webhook request representation of an experiment.
@@ -356,3 +644,12 @@ type TaskLogPayload struct {
 	NodeName      string       `json:"node_name"`
 	TriggeringLog string       `json:"triggering_log"`
 }
+
+// MetricPayload is the webhook request representation of a reported metric that crossed a threshold.
+type MetricPayload struct {
+	TrialID        int               `json:"trial_id"`
+	StepsCompleted int               `json:"steps_completed"`
+	Name           string            `json:"name"`
+	Group          model.MetricGroup `json:"group"`
+	Value          float64           `json:"value"`
+}
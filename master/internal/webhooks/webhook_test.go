@@ -0,0 +1,75 @@
+package webhooks
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+)
+
+func TestParseMetricThresholdCondition(t *testing.T) {
+	valid := map[string]interface{}{
+		"metric_name": "loss",
+		"group":       "validation",
+		"comparator":  "<=",
+		"threshold":   0.25,
+	}
+	c, err := parseMetricThresholdCondition(valid)
+	require.NoError(t, err)
+	require.Equal(t, &MetricThresholdCondition{
+		MetricName: "loss",
+		Group:      model.ValidationMetricGroup,
+		Comparator: ComparatorLessThanOrEqual,
+		Threshold:  0.25,
+	}, c)
+
+	for name, override := range map[string]map[string]interface{}{
+		"missing metric name": {"metric_name": nil},
+		"empty metric name":   {"metric_name": ""},
+		"missing group":       {"group": nil},
+		"invalid group":       {"group": "a.b"},
+		"unknown comparator":  {"comparator": "=="},
+		"string threshold":    {"threshold": "0.25"},
+		"nan threshold":       {"threshold": math.NaN()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			condition := map[string]interface{}{}
+			for k, v := range valid {
+				condition[k] = v
+			}
+			for k, v := range override {
+				if v == nil {
+					delete(condition, k)
+				} else {
+					condition[k] = v
+				}
+			}
+			_, err := parseMetricThresholdCondition(condition)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestMetricThresholdConditionExceeded(t *testing.T) {
+	cases := []struct {
+		comparator Comparator
+		value      float64
+		expected   bool
+	}{
+		{ComparatorGreaterThan, 1.5, true},
+		{ComparatorGreaterThan, 1.0, false},
+		{ComparatorGreaterThanOrEqual, 1.0, true},
+		{ComparatorGreaterThanOrEqual, 0.5, false},
+		{ComparatorLessThan, 0.5, true},
+		{ComparatorLessThan, 1.0, false},
+		{ComparatorLessThanOrEqual, 1.0, true},
+		{ComparatorLessThanOrEqual, 1.5, false},
+		{ComparatorLessThan, math.NaN(), false},
+	}
+	for _, tc := range cases {
+		c := MetricThresholdCondition{Comparator: tc.comparator, Threshold: 1.0}
+		require.Equal(t, tc.expected, c.Exceeded(tc.value), "%v %s 1.0", tc.value, tc.comparator)
+	}
+}
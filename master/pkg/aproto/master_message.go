@@ -50,6 +50,22 @@ type MasterMessage struct {
 	ContainerStateChanged *ContainerStateChanged
 	ContainerLog          *ContainerLog
 	ContainerStatsRecord  *ContainerStatsRecord
+	AgentHealthReport     *AgentHealthReport
+}
+
+// AgentHealthReport reports the results of the agent's periodic health checks.
+type AgentHealthReport struct {
+	// UnhealthyDevices lists the devices that failed health checks; the agent's other devices are
+	// healthy.
+	UnhealthyDevices []UnhealthyDevice
+	// DrainAgent asks the master to drain the agent when a device is unhealthy.
+	DrainAgent bool
+}
+
+// UnhealthyDevice is a device that failed a health check, with the reason it failed.
+type UnhealthyDevice struct {
+	Device device.Device
+	Reason string
 }
 
 // ContainerReattach is a struct describing containers that can be reattached.
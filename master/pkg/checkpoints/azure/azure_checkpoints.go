@@ -0,0 +1,175 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/docker/go-units"
+
+	"github.com/determined-ai/determined/master/pkg/checkpoints/archive"
+)
+
+// AzureDownloader implements downloading a checkpoint from Azure Blob Storage
+// and sends it to the client in an archive file.
+type AzureDownloader struct {
+	aw        archive.ArchiveWriter
+	client    *azblob.Client
+	container string
+	prefix    string
+	buffer    []byte
+	files     []archive.FileEntry
+}
+
+// DefaultDownloadPartSize is the default part size for downloading files from Azure.
+// This is the same as the default part size for S3.
+const DefaultDownloadPartSize = units.MiB * 5
+
+func (d *AzureDownloader) archiveDownload(ctx context.Context, path string, size int64) error {
+	resp, err := d.client.DownloadStream(ctx, d.container, d.prefix+path, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if err := d.aw.WriteHeader(path, size); err != nil {
+		return err
+	}
+	// Copy exactly size bytes, since the archive header was written with that size.
+	n, err := io.CopyBuffer(d.aw, io.LimitReader(resp.Body, size), d.buffer)
+	if err != nil {
+		return err
+	}
+	if n != size {
+		return fmt.Errorf("blob %s was %d bytes, expected %d", d.prefix+path, n, size)
+	}
+	return nil
+}
+
+// Download downloads the checkpoint.
+func (d *AzureDownloader) Download(ctx context.Context) error {
+	files, err := d.ListFiles(ctx)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := d.archiveDownload(ctx, file.Path, file.Size); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the underlying ArchiveWriter.
+func (d *AzureDownloader) Close() error {
+	return d.aw.Close()
+}
+
+// ListFiles lists the files in the checkpoint.
+func (d *AzureDownloader) ListFiles(ctx context.Context) ([]archive.FileEntry, error) {
+	if d.files != nil {
+		return d.files, nil
+	}
+	files := make([]archive.FileEntry, 0)
+
+	pager := d.client.NewListBlobsFlatPager(d.container, &azblob.ListBlobsFlatOptions{
+		Prefix: &d.prefix,
+	})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range page.Segment.BlobItems {
+			if item.Name == nil || strings.HasSuffix(*item.Name, "/") {
+				continue
+			}
+			var size int64
+			if item.Properties != nil && item.Properties.ContentLength != nil {
+				size = *item.Properties.ContentLength
+			}
+			files = append(files, archive.FileEntry{
+				Path: strings.TrimPrefix(*item.Name, d.prefix),
+				Size: size,
+			})
+		}
+	}
+	d.files = files
+	return d.files, nil
+}
+
+// SplitContainer splits the container of an Azure checkpoint storage config into the name of the
+// container and the path within it, e.g., "checkpoints/team-a" into "checkpoints" and "team-a".
+// This matches how checkpoints are uploaded to Azure.
+func SplitContainer(containerPath string) (string, string) {
+	name, path, _ := strings.Cut(strings.Trim(containerPath, "/"), "/")
+	return name, path
+}
+
+// NewAzureDownloader returns a new AzureDownloader. Either connectionString or accountURL must be
+// set. With accountURL, credential may be a SAS token or an account key; without credential, the
+// account URL is accessed anonymously.
+func NewAzureDownloader(
+	aw archive.ArchiveWriter,
+	containerName string,
+	prefix string,
+	connectionString *string,
+	accountURL *string,
+	credential *string,
+) (*AzureDownloader, error) {
+	prefix = strings.TrimLeft(prefix, "/")
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	client, err := newClient(connectionString, accountURL, credential)
+	if err != nil {
+		return nil, err
+	}
+	return &AzureDownloader{
+		aw:        aw,
+		client:    client,
+		container: containerName,
+		prefix:    prefix,
+		buffer:    make([]byte, DefaultDownloadPartSize),
+	}, nil
+}
+
+func newClient(connectionString, accountURL, credential *string) (*azblob.Client, error) {
+	switch {
+	case connectionString != nil && *connectionString != "":
+		return azblob.NewClientFromConnectionString(*connectionString, nil)
+	case accountURL == nil || *accountURL == "":
+		return nil, fmt.Errorf("either connection_string or account_url must be specified")
+	case credential == nil || *credential == "":
+		return azblob.NewClientWithNoCredential(*accountURL, nil)
+	}
+
+	// Like the Azure SDK for Python, treat credentials that parse as signed queries as SAS tokens
+	// and anything else as account keys.
+	if q, err := url.ParseQuery(strings.TrimPrefix(*credential, "?")); err == nil && q.Has("sig") {
+		u, err := url.Parse(*accountURL)
+		if err != nil {
+			return nil, fmt.Errorf("parsing account_url: %w", err)
+		}
+		u.RawQuery = q.Encode()
+		return azblob.NewClientWithNoCredential(u.String(), nil)
+	}
+	parts, err := azblob.ParseURL(*accountURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing account_url: %w", err)
+	}
+	accountName := parts.IPEndpointStyleInfo.AccountName
+	if accountName == "" {
+		accountName, _, _ = strings.Cut(parts.Host, ".")
+	}
+	cred, err := azblob.NewSharedKeyCredential(accountName, *credential)
+	if err != nil {
+		return nil, err
+	}
+	return azblob.NewClientWithSharedKeyCredential(*accountURL, cred, nil)
+}
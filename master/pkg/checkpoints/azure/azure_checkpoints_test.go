@@ -0,0 +1,296 @@
+package azure
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/checkpoints/archive"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+)
+
+const (
+	devAccount = "devstoreaccount1"
+	// devAccountKey is the well-known key of the Azurite development storage account.
+	devAccountKey = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/" +
+		"K1SZFPTOtr/KBHBeksoGMGw=="
+)
+
+// azuriteServer is a stand-in for Azurite, the local Azure Storage emulator, serving the Blob
+// Storage requests used to download checkpoints.
+type azuriteServer struct {
+	*httptest.Server
+	pageSize int
+
+	mu       sync.Mutex
+	blobs    map[string]string
+	requests []*http.Request
+}
+
+func newAzuriteServer(t *testing.T, blobs map[string]string) *azuriteServer {
+	s := &azuriteServer{blobs: maps.Clone(blobs), pageSize: 2}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *azuriteServer) connectionString() string {
+	return fmt.Sprintf(
+		"DefaultEndpointsProtocol=http;AccountName=%s;AccountKey=%s;BlobEndpoint=%s/%s;",
+		devAccount, devAccountKey, s.URL, devAccount)
+}
+
+func (s *azuriteServer) deleteBlob(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.blobs, name)
+}
+
+func (s *azuriteServer) takeRequests() []*http.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	requests := s.requests
+	s.requests = nil
+	return requests
+}
+
+func (s *azuriteServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r)
+	path := strings.TrimPrefix(r.URL.Path, "/"+devAccount+"/")
+	container, blob, _ := strings.Cut(path, "/")
+	if container != "checkpoints" {
+		http.Error(w, "ContainerNotFound", http.StatusNotFound)
+		return
+	}
+	if r.URL.Query().Get("comp") == "list" {
+		s.listBlobs(w, r)
+		return
+	}
+	content, ok := s.blobs[blob]
+	if !ok {
+		http.Error(w, "BlobNotFound", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	w.Header().Set("x-ms-blob-type", "BlockBlob")
+	_, _ = io.WriteString(w, content)
+}
+
+func (s *azuriteServer) listBlobs(w http.ResponseWriter, r *http.Request) {
+	type blob struct {
+		Name          string `xml:"Name"`
+		ContentLength int64  `xml:"Properties>Content-Length"`
+	}
+	type result struct {
+		XMLName    xml.Name `xml:"EnumerationResults"`
+		Blobs      []blob   `xml:"Blobs>Blob"`
+		NextMarker string   `xml:"NextMarker"`
+	}
+
+	var names []string
+	for name := range s.blobs {
+		if strings.HasPrefix(name, r.URL.Query().Get("prefix")) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	start := 0
+	if marker := r.URL.Query().Get("marker"); marker != "" {
+		start = sort.SearchStrings(names, marker)
+	}
+
+	var res result
+	for i := start; i < len(names); i++ {
+		if len(res.Blobs) == s.pageSize {
+			res.NextMarker = names[i]
+			break
+		}
+		res.Blobs = append(res.Blobs, blob{
+			Name:          names[i],
+			ContentLength: int64(len(s.blobs[names[i]])),
+		})
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	_ = xml.NewEncoder(w).Encode(res)
+}
+
+var testBlobs = map[string]string{
+	"team-a/ckpt-1/metadata.json":        `{"steps_completed": 100}`,
+	"team-a/ckpt-1/state/":               "",
+	"team-a/ckpt-1/state/model.pt":       strings.Repeat("weights", 1000),
+	"team-a/ckpt-1/state/optimizer.pt":   strings.Repeat("moments", 100),
+	"team-a/ckpt-10/metadata.json":       `{"steps_completed": 1000}`,
+	"team-b/ckpt-1/metadata.json":        `{"steps_completed": 1}`,
+	"team-a/ckpt-1-other/metadata.json":  `{}`,
+	"team-a/ckpt-1/state/nested/empty":   "",
+	"team-a/ckpt-1/state/nested/strange": "with spaces and / slashes",
+}
+
+var testFiles = func() []archive.FileEntry {
+	var files []archive.FileEntry
+	for _, path := range []string{
+		"metadata.json", "state/model.pt", "state/nested/empty", "state/nested/strange",
+		"state/optimizer.pt",
+	} {
+		files = append(files, archive.FileEntry{
+			Path: path,
+			Size: int64(len(testBlobs["team-a/ckpt-1/"+path])),
+		})
+	}
+	return files
+}()
+
+func newTestDownloader(
+	t *testing.T, s *azuriteServer, archiveType archive.ArchiveType,
+) (*AzureDownloader, *bytes.Buffer) {
+	var buf bytes.Buffer
+	aw, err := archive.NewArchiveWriter(&buf, archiveType)
+	require.NoError(t, err)
+	container, path := SplitContainer("/checkpoints/team-a/")
+	require.Equal(t, "checkpoints", container)
+	d, err := NewAzureDownloader(aw, container, path+"/ckpt-1", ptrs.Ptr(s.connectionString()),
+		nil, nil)
+	require.NoError(t, err)
+	return d, &buf
+}
+
+func TestAzureListFiles(t *testing.T) {
+	s := newAzuriteServer(t, testBlobs)
+	d, _ := newTestDownloader(t, s, archive.ArchiveTar)
+
+	files, err := d.ListFiles(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, testFiles, files)
+	requests := s.takeRequests()
+	require.Len(t, requests, 3, "blobs should be listed across pages")
+	require.True(t, strings.HasPrefix(requests[0].Header.Get("Authorization"),
+		"SharedKey "+devAccount))
+}
+
+func TestAzureDownloadTar(t *testing.T) {
+	s := newAzuriteServer(t, testBlobs)
+	d, buf := newTestDownloader(t, s, archive.ArchiveTar)
+
+	files, err := d.ListFiles(context.Background())
+	require.NoError(t, err)
+	contentLength, err := archive.DryRunLength(d.aw, files)
+	require.NoError(t, err)
+	require.NoError(t, d.Download(context.Background()))
+	require.NoError(t, d.Close())
+	require.Equal(t, contentLength, int64(buf.Len()))
+
+	tr := tar.NewReader(buf)
+	for _, file := range testFiles {
+		hdr, err := tr.Next()
+		require.NoError(t, err)
+		require.Equal(t, file.Path, hdr.Name)
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		require.Equal(t, testBlobs["team-a/ckpt-1/"+file.Path], string(content))
+	}
+	_, err = tr.Next()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestAzureDownloadZip(t *testing.T) {
+	s := newAzuriteServer(t, testBlobs)
+	d, buf := newTestDownloader(t, s, archive.ArchiveZip)
+
+	require.NoError(t, d.Download(context.Background()))
+	require.NoError(t, d.Close())
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	require.Len(t, zr.File, len(testFiles))
+	for i, file := range testFiles {
+		require.Equal(t, file.Path, zr.File[i].Name)
+		r, err := zr.File[i].Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.NoError(t, r.Close())
+		require.Equal(t, testBlobs["team-a/ckpt-1/"+file.Path], string(content))
+	}
+}
+
+func TestAzureDownloadMissingBlob(t *testing.T) {
+	s := newAzuriteServer(t, testBlobs)
+	d, _ := newTestDownloader(t, s, archive.ArchiveTar)
+
+	_, err := d.ListFiles(context.Background())
+	require.NoError(t, err)
+	s.deleteBlob("team-a/ckpt-1/state/model.pt")
+	require.Error(t, d.Download(context.Background()))
+}
+
+func TestAzureCredentials(t *testing.T) {
+	s := newAzuriteServer(t, testBlobs)
+	accountURL := s.URL + "/" + devAccount
+
+	_, err := NewAzureDownloader(nil, "checkpoints", "ckpt", nil, nil, nil)
+	require.ErrorContains(t, err, "either connection_string or account_url must be specified")
+
+	cases := []struct {
+		name       string
+		credential *string
+		check      func(*http.Request)
+	}{
+		{
+			name:       "account key",
+			credential: ptrs.Ptr(devAccountKey),
+			check: func(r *http.Request) {
+				require.True(t, strings.HasPrefix(r.Header.Get("Authorization"),
+					"SharedKey "+devAccount))
+			},
+		},
+		{
+			name:       "sas token",
+			credential: ptrs.Ptr("?sv=2021-08-06&ss=b&srt=co&sp=rl&sig=c2lnbmF0dXJl"),
+			check: func(r *http.Request) {
+				require.Empty(t, r.Header.Get("Authorization"))
+				require.Equal(t, "c2lnbmF0dXJl", r.URL.Query().Get("sig"))
+			},
+		},
+		{
+			name: "anonymous",
+			check: func(r *http.Request) {
+				require.Empty(t, r.Header.Get("Authorization"))
+				require.False(t, r.URL.Query().Has("sig"))
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s.takeRequests()
+			aw, err := archive.NewArchiveWriter(io.Discard, archive.ArchiveTar)
+			require.NoError(t, err)
+			d, err := NewAzureDownloader(aw, "checkpoints", "team-b/ckpt-1", nil, &accountURL,
+				tc.credential)
+			require.NoError(t, err)
+			files, err := d.ListFiles(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, []archive.FileEntry{
+				{Path: "metadata.json", Size: int64(len(testBlobs["team-b/ckpt-1/metadata.json"]))},
+			}, files)
+			requests := s.takeRequests()
+			require.NotEmpty(t, requests)
+			tc.check(requests[0])
+		})
+	}
+}
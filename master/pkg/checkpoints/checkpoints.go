@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/determined-ai/determined/master/pkg/checkpoints/archive"
+	"github.com/determined-ai/determined/master/pkg/checkpoints/azure"
 	"github.com/determined-ai/determined/master/pkg/checkpoints/gcs"
 	"github.com/determined-ai/determined/master/pkg/checkpoints/local"
 	"github.com/determined-ai/determined/master/pkg/checkpoints/s3"
@@ -55,6 +56,11 @@ func NewDownloader(
 		prefix := idPrefixRef(storage.Prefix())
 		return gcs.NewGCSDownloader(ctx, aw, storage.Bucket(), prefix)
 
+	case expconf.AzureConfig:
+		container, path := azure.SplitContainer(storage.Container())
+		return azure.NewAzureDownloader(aw, container, idPrefix(path),
+			storage.ConnectionString(), storage.AccountURL(), storage.Credential())
+
 	case expconf.SharedFSConfig:
 		pathPrefix, err := storage.PathInContainerOrHost()
 		if err != nil {
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/uptrace/bun"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
 	"github.com/determined-ai/determined/master/pkg/cproto"
 	"github.com/determined-ai/determined/master/pkg/device"
 	"github.com/determined-ai/determined/master/pkg/protoutils"
@@ -22,6 +25,9 @@ type AgentSummary struct {
 	Enabled        bool         `json:"enabled"`
 	Draining       bool         `json:"draining"`
 	Version        string       `json:"version"`
+
+	MaintenanceWindows []AgentMaintenanceWindow `json:"maintenance_windows"`
+	InMaintenance      bool                     `json:"in_maintenance"`
 }
 
 type slotStats map[string]*agentv1.DeviceStats
@@ -83,6 +89,10 @@ func (a AgentSummary) ToProto() *agentv1.Agent {
 			containers[sp.Container.Id] = sp.Container
 		}
 	}
+	windows := make([]*agentv1.MaintenanceWindow, 0, len(a.MaintenanceWindows))
+	for _, w := range a.MaintenanceWindows {
+		windows = append(windows, w.ToProto())
+	}
 
 	return &agentv1.Agent{
 		Id:             a.ID,
@@ -95,6 +105,9 @@ func (a AgentSummary) ToProto() *agentv1.Agent {
 		Enabled:        a.Enabled,
 		Draining:       a.Draining,
 		Version:        a.Version,
+		InMaintenance:  a.InMaintenance,
+
+		MaintenanceWindows: windows,
 	}
 }
 
@@ -111,6 +124,8 @@ type SlotSummary struct {
 	Enabled   bool              `json:"enabled"`
 	Container *cproto.Container `json:"container"`
 	Draining  bool              `json:"draining"`
+	// UnhealthyReason is why the agent's health checks found the device unhealthy, if they did.
+	UnhealthyReason string `json:"unhealthy_reason"`
 }
 
 // ToProto converts a SlotSummary to its protobuf representation.
@@ -121,6 +136,33 @@ func (s SlotSummary) ToProto() *agentv1.Slot {
 		Enabled:   s.Enabled,
 		Container: s.Container.ToProto(),
 		Draining:  s.Draining,
+
+		UnhealthyReason: s.UnhealthyReason,
+	}
+}
+
+// AgentHealthEvent records a device of an agent becoming unhealthy or healthy again.
+type AgentHealthEvent struct {
+	bun.BaseModel `bun:"table:agent_health_events"`
+
+	ID         int       `bun:"id,pk,autoincrement"`
+	AgentID    string    `bun:"agent_id,notnull"`
+	Time       time.Time `bun:"time,notnull"`
+	DeviceID   device.ID `bun:"device_id,notnull"`
+	DeviceUUID string    `bun:"device_uuid,notnull"`
+	Healthy    bool      `bun:"healthy,notnull"`
+	Reason     string    `bun:"reason,notnull"`
+}
+
+// ToProto converts an AgentHealthEvent to its protobuf representation.
+func (e *AgentHealthEvent) ToProto() *agentv1.AgentHealthEvent {
+	return &agentv1.AgentHealthEvent{
+		AgentId:    e.AgentID,
+		Time:       timestamppb.New(e.Time),
+		SlotId:     fmt.Sprint(e.DeviceID),
+		DeviceUuid: e.DeviceUUID,
+		Healthy:    e.Healthy,
+		Reason:     e.Reason,
 	}
 }
 
@@ -145,3 +187,33 @@ type AgentStats struct {
 func SortableSlotIndex(i int) string {
 	return fmt.Sprintf("%03d", i)
 }
+
+// AgentMaintenanceWindow is a time range in which an agent is unavailable.
+type AgentMaintenanceWindow struct {
+	bun.BaseModel `bun:"table:agent_maintenance_windows"`
+
+	ID        int       `bun:"id,pk,autoincrement"`
+	AgentID   string    `bun:"agent_id,notnull"`
+	StartTime time.Time `bun:"start_time,notnull"`
+	EndTime   time.Time `bun:"end_time,notnull"`
+	Reason    string    `bun:"reason,notnull"`
+	// CheckpointLeadSeconds is how long before the window preemptible jobs are checkpointed.
+	CheckpointLeadSeconds int `bun:"checkpoint_lead_seconds,notnull"`
+}
+
+// CheckpointTime returns when preemptible jobs on the agent are checkpointed for the window.
+func (w AgentMaintenanceWindow) CheckpointTime() time.Time {
+	return w.StartTime.Add(-time.Duration(w.CheckpointLeadSeconds) * time.Second)
+}
+
+// ToProto converts an AgentMaintenanceWindow to its protobuf representation.
+func (w AgentMaintenanceWindow) ToProto() *agentv1.MaintenanceWindow {
+	return &agentv1.MaintenanceWindow{
+		Id:                    int32(w.ID),
+		AgentId:               w.AgentID,
+		StartTime:             timestamppb.New(w.StartTime),
+		EndTime:               timestamppb.New(w.EndTime),
+		Reason:                w.Reason,
+		CheckpointLeadSeconds: int32(w.CheckpointLeadSeconds),
+	}
+}
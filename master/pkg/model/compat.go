@@ -42,6 +42,7 @@ func (r ResourcesConfig) ToExpconf() expconf.ResourcesConfig {
 		RawPriority:       r.Priority,
 		RawDevices:        r.Devices.ToExpconf(),
 		RawIsSingleNode:   r.IsSingleNode,
+		RawTimeLimit:      r.TimeLimit,
 	})
 }
 
@@ -452,6 +452,7 @@ type Trial struct {
 	EndTime               *time.Time     `db:"end_time"`
 	HParams               map[string]any `db:"hparams" bun:"hparams"`
 	WarmStartCheckpointID *int           `db:"warm_start_checkpoint_id"`
+	ParentTrialID         *int           `db:"parent_trial_id"`
 	Seed                  int64          `db:"seed"`
 	TotalBatches          int            `db:"total_batches"`
 	ExternalTrialID       *string        `db:"external_trial_id"`
@@ -474,6 +475,7 @@ func (t *Trial) ToRunAndTrialV2(experimentsProjectID int) (*Run, *TrialV2) {
 		EndTime:               t.EndTime,
 		HParams:               t.HParams,
 		WarmStartCheckpointID: t.WarmStartCheckpointID,
+		ParentRunID:           t.ParentTrialID,
 		TotalBatches:          t.TotalBatches,
 		ExternalRunID:         t.ExternalTrialID,
 		RestartID:             t.RunID,
@@ -513,6 +515,7 @@ type Run struct {
 	EndTime               *time.Time     `db:"end_time"`
 	HParams               map[string]any `db:"hparams" bun:"hparams"`
 	WarmStartCheckpointID *int           `db:"warm_start_checkpoint_id"`
+	ParentRunID           *int           `db:"parent_run_id"`
 	TotalBatches          int            `db:"total_batches"`
 	ExternalRunID         *string        `db:"external_run_id"`
 	RestartID             int            `db:"restart_id"`
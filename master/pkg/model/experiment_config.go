@@ -92,6 +92,7 @@ type ResourcesConfig struct {
 	ResourcePool   string       `json:"resource_pool"`
 	Priority       *int         `json:"priority,omitempty"`
 	IsSingleNode   *bool        `json:"is_single_node"`
+	TimeLimit      *int         `json:"time_limit,omitempty"`
 
 	Devices DevicesConfig `json:"devices"`
 }
@@ -165,6 +166,9 @@ func (r ResourcesConfig) Validate() []error {
 		check.GreaterThan(r.Weight, float64(0), "weight must be > 0"),
 	}
 	errs = append(errs, ValidatePrioritySetting(r.Priority)...)
+	if r.TimeLimit != nil {
+		errs = append(errs, check.GreaterThan(*r.TimeLimit, 0, "time_limit must be > 0"))
+	}
 	return errs
 }
 
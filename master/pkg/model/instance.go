@@ -1,8 +1,35 @@
 package model
 
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
 // InstanceStats stores the start/end status of instance.
 type InstanceStats struct {
 	ResourcePool string `db:"resource_pool"`
 	InstanceID   string `db:"instance_id"`
 	Slots        int    `db:"slots"`
 }
+
+// Sources of resource pool prices.
+const (
+	// PriceSourceConfig is the source of prices that are configured for a resource pool.
+	PriceSourceConfig = "config"
+	// PriceSourceSpot is the source of prices that are captured from the spot market.
+	PriceSourceSpot = "spot"
+)
+
+// ResourcePoolPrice is the price of a slot-hour of a resource pool from a point in time on.
+type ResourcePoolPrice struct {
+	bun.BaseModel `bun:"table:resource_pool_prices"`
+
+	ID           int    `bun:"id,pk,autoincrement"`
+	ResourcePool string `bun:"resource_pool,notnull"`
+	// SlotHourPrice is nil if the resource pool has no price from then on.
+	SlotHourPrice *float64  `bun:"slot_hour_price"`
+	Currency      string    `bun:"currency,notnull"`
+	Source        string    `bun:"source,notnull"`
+	EffectiveFrom time.Time `bun:"effective_from,notnull"`
+}
@@ -92,3 +92,43 @@ type Job struct {
 	OwnerID *UserID         `db:"owner_id" bun:"owner_id"`
 	QPos    decimal.Decimal `db:"q_position" bun:"q_position"`
 }
+
+// JobDependencyCondition is the condition that a job must meet for the jobs that depend on it to
+// start.
+type JobDependencyCondition string
+
+const (
+	// JobDependencySuccess is met when the job completes successfully.
+	JobDependencySuccess JobDependencyCondition = "SUCCESS"
+	// JobDependencyAnyExit is met when the job exits, whether it succeeds or not.
+	JobDependencyAnyExit JobDependencyCondition = "ANY_EXIT"
+)
+
+// JobDependencyConditionFromProto maps a jobv1.DependencyCondition to JobDependencyCondition.
+func JobDependencyConditionFromProto(c jobv1.DependencyCondition) JobDependencyCondition {
+	if c == jobv1.DependencyCondition_DEPENDENCY_CONDITION_ANY_EXIT {
+		return JobDependencyAnyExit
+	}
+	return JobDependencySuccess
+}
+
+// JobDependency is the model for a dependency of a job on another job in the database.
+type JobDependency struct {
+	bun.BaseModel `bun:"table:job_dependencies"`
+
+	JobID     JobID                  `bun:"job_id,pk"`
+	DependsOn JobID                  `bun:"depends_on,pk"`
+	Condition JobDependencyCondition `bun:"condition"`
+}
+
+// JobOutcome is how far a job has gotten, as far as the jobs that depend on it are concerned.
+type JobOutcome string
+
+const (
+	// JobOutcomeRunning is the outcome of a job that has not exited yet.
+	JobOutcomeRunning JobOutcome = "RUNNING"
+	// JobOutcomeSucceeded is the outcome of a job that completed successfully.
+	JobOutcomeSucceeded JobOutcome = "SUCCEEDED"
+	// JobOutcomeFailed is the outcome of a job that errored or was canceled.
+	JobOutcomeFailed JobOutcome = "FAILED"
+)
@@ -35,10 +35,31 @@ type ResourceConstraints struct {
 	MaxSlots *int `json:"max_slots"`
 }
 
+// QuotaLimits are limits on the cumulative and concurrent slot usage of the workloads of a user or
+// a workspace.
+type QuotaLimits struct {
+	// SlotHours is the number of slot-hours that may be used within the rolling Window.
+	SlotHours *float64  `json:"slot_hours"`
+	Window    *Duration `json:"window"`
+	// MaxConcurrentSlots is the number of slots that may be in use at the same time.
+	MaxConcurrentSlots *int `json:"max_concurrent_slots"`
+}
+
+// QuotaConstraints are quotas on the slot usage of workloads.
+// Submitted workloads whose user or workspace has exhausted its slot-hours are rejected, and
+// allocations that would exceed a quota are held in the queue until enough budget is available.
+type QuotaConstraints struct {
+	// User limits apply to the workloads of each user within the scope.
+	User *QuotaLimits `json:"user"`
+	// Workspace limits apply to all the workloads of each workspace within the scope.
+	Workspace *QuotaLimits `json:"workspace"`
+}
+
 // Constraints are non-overridable workload constraints.
 // Submitted workloads whose config's respective field(s) exceed defined constraints within a given
 // scope are rejected.
 type Constraints struct {
 	ResourceConstraints *ResourceConstraints `json:"resources"`
 	PriorityLimit       *int                 `json:"priority_limit"`
+	QuotaConstraints    *QuotaConstraints    `json:"quotas"`
 }
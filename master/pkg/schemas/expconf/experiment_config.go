@@ -212,6 +212,7 @@ type ResourcesConfigV0 struct {
 	RawResourcePool   *string  `json:"resource_pool"`
 	RawPriority       *int     `json:"priority"`
 	RawIsSingleNode   *bool    `json:"is_single_node"`
+	RawTimeLimit      *int     `json:"time_limit"`
 
 	RawDevices DevicesConfigV0 `json:"devices"`
 }
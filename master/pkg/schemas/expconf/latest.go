@@ -42,9 +42,12 @@ type (
 	RetentionPolicy           = RetentionPolicyConfigV0
 	S3Config                  = S3ConfigV0
 	SearcherConfig            = SearcherConfigV0
+	SearcherMetric            = SearcherMetricV0
 	SharedFSConfig            = SharedFSConfigV0
 	SingleConfig              = SingleConfigV0
 	SlurmConfig               = SlurmConfigV0
+	TPEConfig                 = TPEConfigV0
+	PBTConfig                 = PBTConfigV0
 	IntegrationsConfig        = IntegrationsConfigV0
 	PachydermConfig           = PachydermConfigV0
 	PachydermPachdConfig      = PachydermPachdConfigV0
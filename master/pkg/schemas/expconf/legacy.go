@@ -33,6 +33,16 @@ type LegacySearcher struct {
 	Name            string
 	Metric          string
 	SmallerIsBetter bool
+	// Metrics is only set for multi-objective searches.
+	Metrics []SearcherMetric
+}
+
+// Objectives returns the metrics that the searcher optimizes; see SearcherConfig.Objectives.
+func (l LegacySearcher) Objectives() []SearcherMetric {
+	if len(l.Metrics) > 0 {
+		return l.Metrics
+	}
+	return []SearcherMetric{{RawName: l.Metric, RawSmallerIsBetter: ptrs.Ptr(l.SmallerIsBetter)}}
 }
 
 func getCheckpointStorage(raw map[string]interface{}) (CheckpointStorageConfig, error) {
@@ -214,10 +224,25 @@ func getLegacySearcher(raw map[string]interface{}) (LegacySearcher, error) {
 		}
 	}
 
+	var tmetrics []SearcherMetric
+	if metrics, ok := tsearcher["metrics"]; ok && metrics != nil {
+		metricsBytes, err := json.Marshal(metrics)
+		if err != nil {
+			return LegacySearcher{}, errors.Wrap(err, "unable to remarshal searcher.metrics as json")
+		}
+		if err = json.Unmarshal(metricsBytes, &tmetrics); err != nil {
+			return LegacySearcher{}, errors.Wrap(err, "searcher.metrics is not a list of metrics")
+		}
+		for i := range tmetrics {
+			tmetrics[i] = schemas.WithDefaults(tmetrics[i])
+		}
+	}
+
 	return LegacySearcher{
 		Name:            tname,
 		Metric:          tmetric,
 		SmallerIsBetter: tsmallerIsBetter,
+		Metrics:         tmetrics,
 	}, nil
 }
 
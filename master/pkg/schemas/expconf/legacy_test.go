@@ -521,3 +521,30 @@ func TestLegacyConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestLegacySearcherMetrics(t *testing.T) {
+	searcher, err := getLegacySearcher(map[string]interface{}{
+		"searcher": map[string]interface{}{
+			"name":   "random",
+			"metric": "accuracy",
+			"metrics": []interface{}{
+				map[string]interface{}{"name": "accuracy", "smaller_is_better": false},
+				map[string]interface{}{"name": "latency"},
+			},
+		},
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, searcher.Objectives(), []SearcherMetric{
+		{RawName: "accuracy", RawSmallerIsBetter: ptrs.Ptr(false)},
+		{RawName: "latency", RawSmallerIsBetter: ptrs.Ptr(true)},
+	})
+
+	// Without metrics, the searcher metric is the only objective.
+	searcher, err = getLegacySearcher(map[string]interface{}{
+		"searcher": map[string]interface{}{"name": "single", "metric": "loss"},
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, searcher.Objectives(), []SearcherMetric{
+		{RawName: "loss", RawSmallerIsBetter: ptrs.Ptr(true)},
+	})
+}
@@ -149,8 +149,10 @@ func objectForURL(url string) interface{} {
 		"http://determined.ai/schemas/expconf/v0/searcher-async-halving.json",
 		"http://determined.ai/schemas/expconf/v0/searcher-custom.json",
 		"http://determined.ai/schemas/expconf/v0/searcher-grid.json",
+		"http://determined.ai/schemas/expconf/v0/searcher-pbt.json",
 		"http://determined.ai/schemas/expconf/v0/searcher-random.json",
-		"http://determined.ai/schemas/expconf/v0/searcher-single.json":
+		"http://determined.ai/schemas/expconf/v0/searcher-single.json",
+		"http://determined.ai/schemas/expconf/v0/searcher-tpe.json":
 		return &SearcherConfigV0{}
 	case "http://determined.ai/schemas/expconf/v0/checkpoint-storage.json":
 		return &CheckpointStorageConfigV0{}
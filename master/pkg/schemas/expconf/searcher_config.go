@@ -18,6 +18,8 @@ type SearcherConfigV0 struct {
 	RawSingleConfig       *SingleConfigV0       `union:"name,single" json:"-"`
 	RawRandomConfig       *RandomConfigV0       `union:"name,random" json:"-"`
 	RawGridConfig         *GridConfigV0         `union:"name,grid" json:"-"`
+	RawTPEConfig          *TPEConfigV0          `union:"name,tpe" json:"-"`
+	RawPBTConfig          *PBTConfigV0          `union:"name,pbt" json:"-"`
 	RawAsyncHalvingConfig *AsyncHalvingConfigV0 `union:"name,async_halving" json:"-"`
 	RawAdaptiveASHAConfig *AdaptiveASHAConfigV0 `union:"name,adaptive_asha" json:"-"`
 
@@ -29,10 +31,11 @@ type SearcherConfigV0 struct {
 	RawAdaptiveSimpleConfig *AdaptiveSimpleConfigV0 `union:"name,adaptive_simple" json:"-"`
 	RawCustomConfig         *CustomConfigV0         `union:"name,custom" json:"-"`
 
-	RawMetric               *string `json:"metric"`
-	RawSmallerIsBetter      *bool   `json:"smaller_is_better"`
-	RawSourceTrialID        *int    `json:"source_trial_id"`
-	RawSourceCheckpointUUID *string `json:"source_checkpoint_uuid"`
+	RawMetric               *string            `json:"metric"`
+	RawSmallerIsBetter      *bool              `json:"smaller_is_better"`
+	RawMetrics              []SearcherMetricV0 `json:"metrics"`
+	RawSourceTrialID        *int               `json:"source_trial_id"`
+	RawSourceCheckpointUUID *string            `json:"source_checkpoint_uuid"`
 }
 
 // Merge implements schemas.Mergeable.
@@ -54,6 +57,16 @@ func (s *SearcherConfigV0) UnmarshalJSON(data []byte) error {
 	return errors.Wrap(json.Unmarshal(data, DefaultParser(s)), "failed to parse searcher config")
 }
 
+// Objectives returns the metrics that the searcher optimizes: the configured metrics for a
+// multi-objective search, or else the searcher metric alone.
+func (s SearcherConfigV0) Objectives() []SearcherMetricV0 {
+	if len(s.Metrics()) > 0 {
+		return s.Metrics()
+	}
+	smallerIsBetter := s.SmallerIsBetter()
+	return []SearcherMetricV0{{RawName: s.Metric(), RawSmallerIsBetter: &smallerIsBetter}}
+}
+
 // AsLegacy converts a current ExperimentConfig to a (limited capacity) LegacySearcher.
 func (s SearcherConfigV0) AsLegacy() LegacySearcher {
 	var name string
@@ -64,6 +77,10 @@ func (s SearcherConfigV0) AsLegacy() LegacySearcher {
 		name = "random"
 	case s.RawGridConfig != nil:
 		name = "grid"
+	case s.RawTPEConfig != nil:
+		name = "tpe"
+	case s.RawPBTConfig != nil:
+		name = "pbt"
 	case s.RawAsyncHalvingConfig != nil:
 		name = "async_halving"
 	case s.RawAdaptiveASHAConfig != nil:
@@ -83,9 +100,18 @@ func (s SearcherConfigV0) AsLegacy() LegacySearcher {
 		Name:            name,
 		Metric:          s.Metric(),
 		SmallerIsBetter: s.SmallerIsBetter(),
+		Metrics:         s.Metrics(),
 	}
 }
 
+// SearcherMetricV0 is one of the metrics of a multi-objective search.
+//
+//go:generate ../gen.sh
+type SearcherMetricV0 struct {
+	RawName            string `json:"name"`
+	RawSmallerIsBetter *bool  `json:"smaller_is_better"`
+}
+
 // SingleConfigV0 configures a single trial.
 //
 //go:generate ../gen.sh
@@ -110,6 +136,31 @@ type GridConfigV0 struct {
 	RawMaxConcurrentTrials *int      `json:"max_concurrent_trials"`
 }
 
+// TPEConfigV0 configures a Tree-structured Parzen Estimator search.
+//
+//go:generate ../gen.sh
+type TPEConfigV0 struct {
+	RawMaxTrials           *int     `json:"max_trials"`
+	RawMaxConcurrentTrials *int     `json:"max_concurrent_trials"`
+	RawNumStartupTrials    *int     `json:"num_startup_trials"`
+	RawNumCandidates       *int     `json:"num_candidates"`
+	RawGamma               *float64 `json:"gamma"`
+	RawPriorWeight         *float64 `json:"prior_weight"`
+}
+
+// PBTConfigV0 configures a Population Based Training search.
+//
+//go:generate ../gen.sh
+type PBTConfigV0 struct {
+	RawPopulationSize      *int     `json:"population_size"`
+	RawNumRounds           *int     `json:"num_rounds"`
+	RawTimeMetric          *string  `json:"time_metric"`
+	RawTimePerRound        *int     `json:"time_per_round"`
+	RawTruncateFraction    *float64 `json:"truncate_fraction"`
+	RawResampleProbability *float64 `json:"resample_probability"`
+	RawPerturbFactor       *float64 `json:"perturb_factor"`
+}
+
 // AsyncHalvingConfigV0 configures asynchronous successive halving.
 //
 //go:generate ../gen.sh
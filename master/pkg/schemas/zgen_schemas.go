@@ -2098,6 +2098,14 @@ var (
             "minimum": 0,
             "default": 1
         },
+        "time_limit": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "minimum": 1,
+            "default": null
+        },
         "weight": {
             "type": [
                 "number",
@@ -2330,6 +2338,16 @@ var (
             ],
             "default": null
         },
+        "metrics": {
+            "type": [
+                "array",
+                "null"
+            ],
+            "default": null,
+            "items": {
+                "$ref": "http://determined.ai/schemas/expconf/v0/searcher-metric.json"
+            }
+        },
         "metric": {
             "type": [
                 "string",
@@ -2624,6 +2642,16 @@ var (
             ],
             "default": null
         },
+        "metrics": {
+            "type": [
+                "array",
+                "null"
+            ],
+            "default": null,
+            "items": {
+                "$ref": "http://determined.ai/schemas/expconf/v0/searcher-metric.json"
+            }
+        },
         "metric": {
             "type": [
                 "string",
@@ -2795,6 +2823,138 @@ var (
         ]
     }
 }
+`)
+	textSearcherMetricV0 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v0/searcher-metric.json",
+    "title": "SearcherMetric",
+    "type": "object",
+    "additionalProperties": false,
+    "required": [
+        "name"
+    ],
+    "properties": {
+        "name": {
+            "type": "string"
+        },
+        "smaller_is_better": {
+            "type": [
+                "boolean",
+                "null"
+            ],
+            "default": true
+        }
+    }
+}
+`)
+	textPBTConfigV0 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v0/searcher-pbt.json",
+    "title": "PBTConfig",
+    "type": "object",
+    "additionalProperties": false,
+    "required": [
+        "name"
+    ],
+    "eventuallyRequired": [
+        "population_size",
+        "num_rounds",
+        "time_metric",
+        "time_per_round",
+        "metric"
+    ],
+    "properties": {
+        "name": {
+            "const": "pbt"
+        },
+        "population_size": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": null,
+            "minimum": 1
+        },
+        "num_rounds": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": null,
+            "minimum": 1
+        },
+        "time_metric": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null
+        },
+        "time_per_round": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": null,
+            "minimum": 1
+        },
+        "truncate_fraction": {
+            "type": [
+                "number",
+                "null"
+            ],
+            "exclusiveMinimum": 0,
+            "maximum": 0.5,
+            "default": 0.2
+        },
+        "resample_probability": {
+            "type": [
+                "number",
+                "null"
+            ],
+            "minimum": 0,
+            "maximum": 1,
+            "default": 0.2
+        },
+        "perturb_factor": {
+            "type": [
+                "number",
+                "null"
+            ],
+            "minimum": 0,
+            "maximum": 1,
+            "default": 0.2
+        },
+        "metric": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null
+        },
+        "smaller_is_better": {
+            "type": [
+                "boolean",
+                "null"
+            ],
+            "default": true
+        },
+        "source_trial_id": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": null
+        },
+        "source_checkpoint_uuid": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null
+        }
+    }
+}
 `)
 	textRandomConfigV0 = []byte(`{
     "$schema": "http://json-schema.org/draft-07/schema#",
@@ -2838,6 +2998,16 @@ var (
             "default": null,
             "optionalRef": "http://determined.ai/schemas/expconf/v0/searcher-length.json"
         },
+        "metrics": {
+            "type": [
+                "array",
+                "null"
+            ],
+            "default": null,
+            "items": {
+                "$ref": "http://determined.ai/schemas/expconf/v0/searcher-metric.json"
+            }
+        },
         "metric": {
             "type": [
                 "string",
@@ -3013,6 +3183,103 @@ var (
         }
     }
 }
+`)
+	textTPEConfigV0 = []byte(`{
+    "$schema": "http://json-schema.org/draft-07/schema#",
+    "$id": "http://determined.ai/schemas/expconf/v0/searcher-tpe.json",
+    "title": "TPEConfig",
+    "type": "object",
+    "additionalProperties": false,
+    "required": [
+        "name"
+    ],
+    "eventuallyRequired": [
+        "max_trials",
+        "metric"
+    ],
+    "properties": {
+        "name": {
+            "const": "tpe"
+        },
+        "max_concurrent_trials": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "minimum": 0,
+            "default": 16
+        },
+        "max_trials": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": null,
+            "minimum": 1
+        },
+        "num_startup_trials": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "minimum": 1,
+            "default": 10
+        },
+        "num_candidates": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "minimum": 1,
+            "default": 24
+        },
+        "gamma": {
+            "type": [
+                "number",
+                "null"
+            ],
+            "exclusiveMinimum": 0,
+            "maximum": 1,
+            "default": 0.25
+        },
+        "prior_weight": {
+            "type": [
+                "number",
+                "null"
+            ],
+            "exclusiveMinimum": 0,
+            "default": 1.0
+        },
+        "metric": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null
+        },
+        "smaller_is_better": {
+            "type": [
+                "boolean",
+                "null"
+            ],
+            "default": true
+        },
+        "source_trial_id": {
+            "type": [
+                "integer",
+                "null"
+            ],
+            "default": null
+        },
+        "source_checkpoint_uuid": {
+            "type": [
+                "string",
+                "null"
+            ],
+            "default": null
+        }
+    }
+}
 `)
 	textSearcherConfigV0 = []byte(`{
     "$schema": "http://json-schema.org/draft-07/schema#",
@@ -3026,7 +3293,7 @@ var (
     },
     "then": {
         "union": {
-            "defaultMessage": "is not an object where object[\"name\"] is one of 'single', 'random', 'grid', 'custom', or 'adaptive_asha'",
+            "defaultMessage": "is not an object where object[\"name\"] is one of 'single', 'random', 'grid', 'tpe', 'pbt', 'custom', or 'adaptive_asha'",
             "items": [
                 {
                     "unionKey": "const:name=single",
@@ -3040,6 +3307,14 @@ var (
                     "unionKey": "const:name=grid",
                     "$ref": "http://determined.ai/schemas/expconf/v0/searcher-grid.json"
                 },
+                {
+                    "unionKey": "const:name=tpe",
+                    "$ref": "http://determined.ai/schemas/expconf/v0/searcher-tpe.json"
+                },
+                {
+                    "unionKey": "const:name=pbt",
+                    "$ref": "http://determined.ai/schemas/expconf/v0/searcher-pbt.json"
+                },
                 {
                     "unionKey": "const:name=adaptive_asha",
                     "$ref": "http://determined.ai/schemas/expconf/v0/searcher-adaptive-asha.json"
@@ -3079,16 +3354,35 @@ var (
     "properties": {
         "bracket_rungs": true,
         "divisor": true,
+        "gamma": true,
         "max_concurrent_trials": true,
         "max_length": true,
         "max_rungs": true,
         "max_time": true,
         "time_metric": true,
+        "time_per_round": true,
         "max_trials": true,
         "mode": true,
         "name": true,
+        "num_candidates": true,
+        "num_rounds": true,
         "num_rungs": true,
+        "num_startup_trials": true,
+        "perturb_factor": true,
+        "population_size": true,
+        "prior_weight": true,
+        "resample_probability": true,
         "stop_once": true,
+        "metrics": {
+            "type": [
+                "array",
+                "null"
+            ],
+            "default": null,
+            "items": {
+                "$ref": "http://determined.ai/schemas/expconf/v0/searcher-metric.json"
+            }
+        },
         "metric": {
             "type": [
                 "string",
@@ -3118,6 +3412,7 @@ var (
             "default": null
         },
         "budget": true,
+        "truncate_fraction": true,
         "train_stragglers": true,
         "unit": true
     }
@@ -3559,12 +3854,18 @@ var (
 
 	schemaSearcherLengthV0 interface{}
 
+	schemaSearcherMetricV0 interface{}
+
+	schemaPBTConfigV0 interface{}
+
 	schemaRandomConfigV0 interface{}
 
 	schemaSingleConfigV0 interface{}
 
 	schemaSyncHalvingConfigV0 interface{}
 
+	schemaTPEConfigV0 interface{}
+
 	schemaSearcherConfigV0 interface{}
 
 	schemaSecurityConfigV0 interface{}
@@ -4652,6 +4953,46 @@ func ParsedSearcherLengthV0() interface{} {
 	return schemaSearcherLengthV0
 }
 
+func ParsedSearcherMetricV0() interface{} {
+	cacheLock.RLock()
+	if schemaSearcherMetricV0 != nil {
+		cacheLock.RUnlock()
+		return schemaSearcherMetricV0
+	}
+	cacheLock.RUnlock()
+
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	if schemaSearcherMetricV0 != nil {
+		return schemaSearcherMetricV0
+	}
+	err := json.Unmarshal(textSearcherMetricV0, &schemaSearcherMetricV0)
+	if err != nil {
+		panic("invalid embedded json for SearcherMetricV0")
+	}
+	return schemaSearcherMetricV0
+}
+
+func ParsedPBTConfigV0() interface{} {
+	cacheLock.RLock()
+	if schemaPBTConfigV0 != nil {
+		cacheLock.RUnlock()
+		return schemaPBTConfigV0
+	}
+	cacheLock.RUnlock()
+
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	if schemaPBTConfigV0 != nil {
+		return schemaPBTConfigV0
+	}
+	err := json.Unmarshal(textPBTConfigV0, &schemaPBTConfigV0)
+	if err != nil {
+		panic("invalid embedded json for PBTConfigV0")
+	}
+	return schemaPBTConfigV0
+}
+
 func ParsedRandomConfigV0() interface{} {
 	cacheLock.RLock()
 	if schemaRandomConfigV0 != nil {
@@ -4712,6 +5053,26 @@ func ParsedSyncHalvingConfigV0() interface{} {
 	return schemaSyncHalvingConfigV0
 }
 
+func ParsedTPEConfigV0() interface{} {
+	cacheLock.RLock()
+	if schemaTPEConfigV0 != nil {
+		cacheLock.RUnlock()
+		return schemaTPEConfigV0
+	}
+	cacheLock.RUnlock()
+
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+	if schemaTPEConfigV0 != nil {
+		return schemaTPEConfigV0
+	}
+	err := json.Unmarshal(textTPEConfigV0, &schemaTPEConfigV0)
+	if err != nil {
+		panic("invalid embedded json for TPEConfigV0")
+	}
+	return schemaTPEConfigV0
+}
+
 func ParsedSearcherConfigV0() interface{} {
 	cacheLock.RLock()
 	if schemaSearcherConfigV0 != nil {
@@ -5033,12 +5394,18 @@ func schemaBytesMap() map[string][]byte {
 	cachedSchemaBytesMap[url] = textGridConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/searcher-length.json"
 	cachedSchemaBytesMap[url] = textSearcherLengthV0
+	url = "http://determined.ai/schemas/expconf/v0/searcher-metric.json"
+	cachedSchemaBytesMap[url] = textSearcherMetricV0
+	url = "http://determined.ai/schemas/expconf/v0/searcher-pbt.json"
+	cachedSchemaBytesMap[url] = textPBTConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/searcher-random.json"
 	cachedSchemaBytesMap[url] = textRandomConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/searcher-single.json"
 	cachedSchemaBytesMap[url] = textSingleConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/searcher-sync-halving.json"
 	cachedSchemaBytesMap[url] = textSyncHalvingConfigV0
+	url = "http://determined.ai/schemas/expconf/v0/searcher-tpe.json"
+	cachedSchemaBytesMap[url] = textTPEConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/searcher.json"
 	cachedSchemaBytesMap[url] = textSearcherConfigV0
 	url = "http://determined.ai/schemas/expconf/v0/security.json"
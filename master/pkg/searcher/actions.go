@@ -3,6 +3,8 @@ package searcher
 import (
 	"fmt"
 
+	"github.com/google/uuid"
+
 	"github.com/determined-ai/determined/master/pkg/model"
 
 	"github.com/determined-ai/determined/master/pkg/nprand"
@@ -19,12 +21,28 @@ type Create struct {
 	// TrialSeed must be a value between 0 and 2**31 - 1.
 	TrialSeed uint32       `json:"trial_seed"`
 	Hparams   HParamSample `json:"hparams"`
+	// Checkpoint, if set, warm starts the run from the latest checkpoint of another run.
+	Checkpoint *Checkpoint `json:"checkpoint,omitempty"`
+}
+
+// Checkpoint refers to the latest checkpoint of the run created by the Create with this request ID.
+type Checkpoint struct {
+	RequestID model.RequestID `json:"request_id"`
+	// UUID is the checkpoint that the run was warm started from, once it has been created, so that
+	// it restarts from the same checkpoint rather than a later one.
+	UUID *uuid.UUID `json:"uuid,omitempty"`
 }
 
 // searcherAction (Create) implements SearcherAction.
 func (Create) searcherAction() {}
 
 func (action Create) String() string {
+	if action.Checkpoint != nil {
+		return fmt.Sprintf(
+			"Create{TrialSeed: %d, Hparams: %v, RequestID: %d, Checkpoint: %d}",
+			action.TrialSeed, action.Hparams, action.RequestID, action.Checkpoint.RequestID,
+		)
+	}
 	return fmt.Sprintf(
 		"Create{TrialSeed: %d, Hparams: %v, RequestID: %d}",
 		action.TrialSeed, action.Hparams, action.RequestID,
@@ -42,6 +60,16 @@ func NewCreate(
 	}
 }
 
+// NewCreateFromCheckpoint initializes a new Create operation with a new request ID and the given
+// hyperparameters, warm started from the latest checkpoint of the run with the given request ID.
+func NewCreateFromCheckpoint(
+	rand *nprand.State, s HParamSample, parentID model.RequestID,
+) Create {
+	create := NewCreate(rand, s)
+	create.Checkpoint = &Checkpoint{RequestID: parentID}
+	return create
+}
+
 // Stop is a directive from the searcher to stop a run.
 type Stop struct {
 	RequestID model.RequestID `json:"request_id"`
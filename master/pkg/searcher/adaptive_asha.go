@@ -112,7 +112,7 @@ func makeBrackets(config expconf.AdaptiveASHAConfig) []bracket {
 	return brackets
 }
 
-func newAdaptiveASHASearch(config expconf.AdaptiveASHAConfig, smallerIsBetter bool, metric string) SearchMethod {
+func newAdaptiveASHASearch(config expconf.AdaptiveASHAConfig, objectives []expconf.SearcherMetric) SearchMethod {
 	brackets := makeBrackets(config)
 	methods := make([]SearchMethod, 0, len(brackets))
 	for _, bracket := range brackets {
@@ -125,7 +125,7 @@ func newAdaptiveASHASearch(config expconf.AdaptiveASHAConfig, smallerIsBetter bo
 			RawTimeMetric:          config.RawTimeMetric,
 			RawMaxTime:             config.RawMaxTime,
 		}
-		methods = append(methods, newAsyncHalvingStoppingSearch(c, smallerIsBetter, metric))
+		methods = append(methods, newAsyncHalvingStoppingSearch(c, objectives))
 	}
 
 	return newTournamentSearch(AdaptiveASHASearch, methods...)
@@ -17,11 +17,10 @@ import (
 // algorithm (ASHA) that early-stops worse performing trials rather than actively promoting better
 // performing trials. When a new validation metric is reported, the searcher decides if the run
 // should be stopped based on the ranking of the metric compared to other trials' metrics in the
-// same rung.
+// same rung. With several objectives, the metrics in a rung are ranked by non-dominated sorting.
 type asyncHalvingStoppingSearch struct {
 	expconf.AsyncHalvingConfig
-	SmallerIsBetter bool
-	Metric          string
+	Objectives []expconf.SearcherMetric
 	asyncHalvingSearchState
 }
 type (
@@ -35,9 +34,12 @@ type (
 		SearchMethodType SearchMethodType         `json:"search_method_type"`
 	}
 
+	// runMetric is the metric a run reported for a rung. Values holds every objective of a
+	// multi-objective search, negated if larger is better, and Metric is the first of them.
 	runMetric struct {
-		RequestID model.RequestID       `json:"request_id"`
-		Metric    model.ExtendedFloat64 `json:"metric"`
+		RequestID model.RequestID         `json:"request_id"`
+		Metric    model.ExtendedFloat64   `json:"metric"`
+		Values    []model.ExtendedFloat64 `json:"values,omitempty"`
 	}
 	rung struct {
 		UnitsNeeded uint64      `json:"units_needed"`
@@ -67,14 +69,13 @@ func makeRungs(numRungs int, divisor float64, maxLength uint64) []*rung {
 }
 
 func newAsyncHalvingStoppingSearch(
-	config expconf.AsyncHalvingConfig, smallerIsBetter bool, metric string,
+	config expconf.AsyncHalvingConfig, objectives []expconf.SearcherMetric,
 ) SearchMethod {
 	rungs := makeRungs(config.NumRungs(), config.Divisor(), config.Length().Units)
 
 	return &asyncHalvingStoppingSearch{
 		AsyncHalvingConfig: config,
-		SmallerIsBetter:    smallerIsBetter,
-		Metric:             metric,
+		Objectives:         objectives,
 		asyncHalvingSearchState: asyncHalvingSearchState{
 			Rungs:            rungs,
 			TrialRungs:       make(map[model.RequestID]int),
@@ -110,6 +111,33 @@ func (r *rung) insertMetric(requestID model.RequestID, metric float64) int {
 	return insertIndex
 }
 
+// insertObjectives adds the objective values of a completed validation to the rung and returns
+// the number of runs in the rung that rank strictly ahead of it by non-dominated sorting.
+func (r *rung) insertObjectives(requestID model.RequestID, values []float64) int {
+	insertIndex := r.insertMetric(requestID, values[0])
+	r.Metrics[insertIndex].Values = make([]model.ExtendedFloat64, 0, len(values))
+	for _, v := range values {
+		r.Metrics[insertIndex].Values = append(r.Metrics[insertIndex].Values, model.ExtendedFloat64(v))
+	}
+
+	points := make([][]float64, 0, len(r.Metrics))
+	for _, m := range r.Metrics {
+		point := make([]float64, 0, len(m.Values))
+		for _, v := range m.Values {
+			point = append(point, float64(v))
+		}
+		points = append(points, point)
+	}
+	fronts := NonDominatedSort(points)
+	rank := 0
+	for _, f := range fronts {
+		if f < fronts[insertIndex] {
+			rank++
+		}
+	}
+	return rank
+}
+
 // initialTrials specifies the initial trials that the search will create.
 // Since each run can only stop and create a new run, this effectively controls the degree of
 // parallelism of the search.
@@ -157,12 +185,12 @@ func (s *asyncHalvingStoppingSearch) trialExited(
 func (s *asyncHalvingStoppingSearch) validationCompleted(
 	ctx context, requestID model.RequestID, metrics map[string]interface{},
 ) ([]Action, error) {
-	timeStep, value, err := s.getMetric(metrics)
+	timeStep, values, err := s.getMetric(metrics)
 	if err != nil {
 		return nil, err
 	}
 
-	ops := s.doEarlyStopping(requestID, *timeStep, *value)
+	ops := s.doEarlyStopping(requestID, *timeStep, values)
 	allTrials := len(s.TrialRungs) - s.InvalidTrials
 	if len(ops) > 0 && allTrials < s.MaxTrials() {
 		create := NewCreate(ctx.rand, sampleAll(ctx.hparams, ctx.rand))
@@ -171,15 +199,20 @@ func (s *asyncHalvingStoppingSearch) validationCompleted(
 	return ops, nil
 }
 
-// getMetric reads the searcher metric and time step value from the reported validation metrics.
-func (s *asyncHalvingStoppingSearch) getMetric(metrics map[string]interface{}) (*uint64, *float64, error) {
-	searcherMetric, ok := metrics[s.Metric].(float64)
-
-	if !ok {
-		return nil, nil, fmt.Errorf("error parsing searcher metric (%s) from validation metrics: %v", s.Metric, metrics)
-	}
-	if !s.SmallerIsBetter {
-		searcherMetric *= -1
+// getMetric reads the value of each objective and the time step value from the reported
+// validation metrics. Objectives where larger is better are negated.
+func (s *asyncHalvingStoppingSearch) getMetric(metrics map[string]interface{}) (*uint64, []float64, error) {
+	values := make([]float64, 0, len(s.Objectives))
+	for _, objective := range s.Objectives {
+		value, ok := metrics[objective.Name()].(float64)
+		if !ok {
+			return nil, nil, fmt.Errorf(
+				"error parsing searcher metric (%s) from validation metrics: %v", objective.Name(), metrics)
+		}
+		if !objective.SmallerIsBetter() {
+			value *= -1
+		}
+		values = append(values, value)
 	}
 
 	unit := string(s.Length().Unit)
@@ -188,7 +221,7 @@ func (s *asyncHalvingStoppingSearch) getMetric(metrics map[string]interface{}) (
 		return nil, nil, fmt.Errorf("error parsing searcher time metric (%s) in validation metrics: %v", unit, metrics)
 	}
 
-	return ptrs.Ptr(uint64(stepNum)), &searcherMetric, nil
+	return ptrs.Ptr(uint64(stepNum)), values, nil
 }
 
 // doEarlyStopping handles early-stopping and record-keeping logic for a validation metric reported to the
@@ -196,7 +229,7 @@ func (s *asyncHalvingStoppingSearch) getMetric(metrics map[string]interface{}) (
 // If the metric qualifies the run for a rung but is not in the top 1/divisor trials for that rung,
 // doEarlyStopping will return a single `searcher.Stop` action. Otherwise, no actions will be returned.
 func (s *asyncHalvingStoppingSearch) doEarlyStopping(
-	requestID model.RequestID, timeStep uint64, metric float64,
+	requestID model.RequestID, timeStep uint64, values []float64,
 ) []Action {
 	rungIndex := s.TrialRungs[requestID]
 	var actions []Action
@@ -212,7 +245,12 @@ func (s *asyncHalvingStoppingSearch) doEarlyStopping(
 			return actions
 		}
 
-		insertIndex := rung.insertMetric(requestID, metric)
+		var insertIndex int
+		if len(values) > 1 {
+			insertIndex = rung.insertObjectives(requestID, values)
+		} else {
+			insertIndex = rung.insertMetric(requestID, values[0])
+		}
 
 		// If this is the top rung, close the run and exit.
 		if r == s.NumRungs()-1 {
@@ -278,7 +316,15 @@ func (s *asyncHalvingStoppingSearch) trialExitedEarly(
 	rungIndex := s.TrialRungs[requestID]
 	rung := s.Rungs[rungIndex]
 
-	rung.insertMetric(requestID, ashaExitedMetricValue)
+	if len(s.Objectives) > 1 {
+		exited := make([]float64, len(s.Objectives))
+		for i := range exited {
+			exited[i] = ashaExitedMetricValue
+		}
+		rung.insertObjectives(requestID, exited)
+	} else {
+		rung.insertMetric(requestID, ashaExitedMetricValue)
+	}
 
 	allTrials := len(s.TrialRungs) - s.InvalidTrials
 	if allTrials < s.MaxTrials() {
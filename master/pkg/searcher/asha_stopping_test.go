@@ -141,6 +141,27 @@ func TestInsertCompletedMetric(t *testing.T) {
 	}
 }
 
+func TestInsertObjectives(t *testing.T) {
+	r := rung{}
+	require.Equal(t, 0, r.insertObjectives(mockRequestID(1), []float64{1, 5}))
+	require.Equal(t, 0, r.insertObjectives(mockRequestID(2), []float64{5, 1}))
+	require.Equal(t, 0, r.insertObjectives(mockRequestID(3), []float64{3, 3}))
+	// Only the first point dominates this one, but the whole first front ranks ahead of it.
+	require.Equal(t, 3, r.insertObjectives(mockRequestID(4), []float64{2, 6}))
+	require.Equal(t, 4, r.insertObjectives(mockRequestID(5), []float64{6, 7}))
+
+	// Metrics stay sorted by the first objective.
+	var order []model.RequestID
+	for _, m := range r.Metrics {
+		order = append(order, m.RequestID)
+		require.Len(t, m.Values, 2)
+		require.Equal(t, m.Metric, m.Values[0])
+	}
+	require.Equal(t, []model.RequestID{
+		mockRequestID(1), mockRequestID(4), mockRequestID(3), mockRequestID(2), mockRequestID(5),
+	}, order)
+}
+
 func TestGetMetric(t *testing.T) {
 	cases := []struct {
 		metrics          map[string]interface{}
@@ -187,9 +208,10 @@ func TestGetMetric(t *testing.T) {
 
 	searcher := &asyncHalvingStoppingSearch{}
 	for _, c := range cases {
-		searcher.Metric = c.metricName
+		searcher.Objectives = []expconf.SearcherMetric{
+			{RawName: c.metricName, RawSmallerIsBetter: ptrs.Ptr(c.smallerIsBetter)},
+		}
 		searcher.RawTimeMetric = &c.timeMetricName
-		searcher.SmallerIsBetter = c.smallerIsBetter
 		searcher.RawMaxTime = ptrs.Ptr(10)
 		stepNum, searcherMetric, err := searcher.getMetric(c.metrics)
 		if c.expectedError != "" {
@@ -197,7 +219,7 @@ func TestGetMetric(t *testing.T) {
 		} else {
 			require.NoError(t, err, "got unexpected error %v: %v", err, c)
 			require.Equal(t, uint64(c.expectedTimeStep), *stepNum, "time step does not match")
-			require.InEpsilon(t, c.expectedMetric, *searcherMetric, 0.001, "searcher metric value doesn't match")
+			require.InEpsilon(t, c.expectedMetric, searcherMetric[0], 0.001, "searcher metric value doesn't match")
 		}
 	}
 }
@@ -384,7 +406,7 @@ func TestStopTrials(t *testing.T) {
 		searcher.AsyncHalvingConfig.RawDivisor = &c.divisor
 		numRungs := len(c.rungs)
 		searcher.AsyncHalvingConfig.RawNumRungs = &numRungs
-		ops := searcher.doEarlyStopping(c.metric.rID, c.metric.timeStep, c.metric.metric)
+		ops := searcher.doEarlyStopping(c.metric.rID, c.metric.timeStep, []float64{c.metric.metric})
 		require.Equal(t, c.expectedOps, ops)
 		require.Equal(t, c.expectedRungs, searcher.Rungs)
 		require.Equal(t, c.expectedRunRungs, searcher.TrialRungs)
@@ -449,3 +471,52 @@ func TestASHAStoppingSearchMethod(t *testing.T) {
 	require.Equal(t, 1, stoppedAt900)
 	require.Equal(t, 9, stoppedAt100)
 }
+
+func TestASHAStoppingMultiObjective(t *testing.T) {
+	config := schemas.WithDefaults(expconf.SearcherConfig{
+		RawMetric: ptrs.Ptr("accuracy"),
+		RawMetrics: []expconf.SearcherMetric{
+			{RawName: "accuracy", RawSmallerIsBetter: ptrs.Ptr(false)},
+			{RawName: "latency"},
+		},
+		RawAsyncHalvingConfig: &expconf.AsyncHalvingConfig{
+			RawNumRungs:            ptrs.Ptr(2),
+			RawDivisor:             ptrs.Ptr(2.0),
+			RawMaxTime:             ptrs.Ptr(200),
+			RawTimeMetric:          ptrs.Ptr("batches"),
+			RawMaxTrials:           ptrs.Ptr(3),
+			RawMaxConcurrentTrials: ptrs.Ptr(3),
+		},
+	})
+	hparams := expconf.Hyperparameters{
+		"x": expconf.Hyperparameter{RawIntHyperparameter: &expconf.IntHyperparameter{RawMaxval: 10}},
+	}
+	searcher := NewSearcher(0, NewSearchMethod(config), hparams)
+	actions, err := searcher.InitialTrials()
+	require.NoError(t, err)
+	require.Len(t, actions, 3)
+	var ids []model.RequestID
+	for _, a := range actions {
+		id := a.(Create).RequestID
+		ids = append(ids, id)
+		_, err := searcher.TrialCreated(id)
+		require.NoError(t, err)
+	}
+
+	validate := func(id model.RequestID, accuracy, latency float64) []Action {
+		actions, err := searcher.ValidationCompleted(id, map[string]interface{}{
+			"accuracy": accuracy, "latency": latency, "batches": 100.0,
+		})
+		require.NoError(t, err)
+		return actions
+	}
+	require.Empty(t, validate(ids[0], 0.9, 10))
+	// A less accurate but faster trial is on the Pareto front, so it continues.
+	require.Empty(t, validate(ids[1], 0.8, 5))
+	// A trial that is worse in both objectives is stopped.
+	require.Equal(t, []Action{NewStop(ids[2])}, validate(ids[2], 0.7, 20))
+
+	// A metric missing from the validation is an error.
+	_, err = searcher.ValidationCompleted(ids[0], map[string]interface{}{"accuracy": 0.9, "batches": 200.0})
+	require.ErrorContains(t, err, "error parsing searcher metric (latency)")
+}
@@ -0,0 +1,77 @@
+package searcher
+
+import (
+	"math"
+)
+
+// paretoValue treats NaN as the worst possible value, so that a point with a NaN objective never
+// dominates another point.
+func paretoValue(v float64) float64 {
+	if math.IsNaN(v) {
+		return math.Inf(1)
+	}
+	return v
+}
+
+// dominates returns whether the point a Pareto-dominates the point b: a is no worse than b in
+// every objective and strictly better in at least one. Smaller values are better.
+func dominates(a, b []float64) bool {
+	better := false
+	for i := range a {
+		av, bv := paretoValue(a[i]), paretoValue(b[i])
+		switch {
+		case av > bv:
+			return false
+		case av < bv:
+			better = true
+		}
+	}
+	return better
+}
+
+// NonDominatedSort ranks points by non-dominated sorting and returns the index of the Pareto front
+// of each point. Front 0 holds the points that no other point dominates, front 1 the points that
+// only points in front 0 dominate, and so on. Every point must have a value for each objective,
+// in the same order, where smaller values are better.
+func NonDominatedSort(points [][]float64) []int {
+	fronts := make([]int, len(points))
+	remaining := make([]int, len(points))
+	for i := range points {
+		remaining[i] = i
+	}
+
+	for front := 0; len(remaining) > 0; front++ {
+		var dominated []int
+		var current []int
+		for _, i := range remaining {
+			isDominated := false
+			for _, j := range remaining {
+				if dominates(points[j], points[i]) {
+					isDominated = true
+					break
+				}
+			}
+			if isDominated {
+				dominated = append(dominated, i)
+			} else {
+				current = append(current, i)
+			}
+		}
+		for _, i := range current {
+			fronts[i] = front
+		}
+		remaining = dominated
+	}
+	return fronts
+}
+
+// ParetoFront returns the indexes of the points that no other point dominates, in order.
+func ParetoFront(points [][]float64) []int {
+	var front []int
+	for i, f := range NonDominatedSort(points) {
+		if f == 0 {
+			front = append(front, i)
+		}
+	}
+	return front
+}
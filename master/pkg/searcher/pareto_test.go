@@ -0,0 +1,35 @@
+package searcher
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDominates(t *testing.T) {
+	require.True(t, dominates([]float64{1, 2}, []float64{2, 2}))
+	require.True(t, dominates([]float64{1, 2}, []float64{2, 3}))
+	require.False(t, dominates([]float64{1, 2}, []float64{1, 2}))
+	require.False(t, dominates([]float64{1, 3}, []float64{2, 2}))
+	// NaN is worse than any value.
+	require.True(t, dominates([]float64{1, 2}, []float64{1, math.NaN()}))
+	require.False(t, dominates([]float64{1, math.NaN()}, []float64{1, 2}))
+}
+
+func TestNonDominatedSort(t *testing.T) {
+	points := [][]float64{
+		{1, 5},
+		{5, 1},
+		{3, 3},
+		{2, 6},
+		{6, 2},
+		{3, 3},
+		{7, 7},
+	}
+	require.Equal(t, []int{0, 0, 0, 1, 1, 0, 2}, NonDominatedSort(points))
+	require.Equal(t, []int{0, 1, 2, 5}, ParetoFront(points))
+
+	require.Empty(t, NonDominatedSort(nil))
+	require.Empty(t, ParetoFront(nil))
+}
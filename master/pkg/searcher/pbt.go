@@ -0,0 +1,274 @@
+package searcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+
+	"github.com/determined-ai/determined/master/pkg/mathx"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/nprand"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+type (
+	// pbtTrial is a member of the population. Metrics holds the searcher metric the trial reported
+	// at the end of each round it trained for, negated if larger is better.
+	pbtTrial struct {
+		Hparams    HParamSample                  `json:"hparams"`
+		StartRound int                           `json:"start_round"`
+		Metrics    map[int]model.ExtendedFloat64 `json:"metrics"`
+	}
+	// pbtSearchState stores the state for PBT. Round is the number of rounds that have been
+	// ranked, and Population holds the trials that are training and have not been replaced.
+	pbtSearchState struct {
+		Round            int                           `json:"round"`
+		Population       map[model.RequestID]*pbtTrial `json:"population"`
+		SearchMethodType SearchMethodType              `json:"search_method_type"`
+	}
+	// pbtSearch implements Population Based Training. A fixed-size population of trials trains in
+	// rounds of the time metric. When every member has finished a round, the worst members are
+	// stopped and replaced with trials that continue from the latest checkpoints of the best
+	// members, with perturbed hyperparameters. Members that are not replaced keep training.
+	pbtSearch struct {
+		defaultSearchMethod
+		expconf.PBTConfig
+		SmallerIsBetter bool
+		Metric          string
+		pbtSearchState
+	}
+)
+
+func newPBTSearch(config expconf.PBTConfig, smallerIsBetter bool, metric string) SearchMethod {
+	return &pbtSearch{
+		PBTConfig:       config,
+		SmallerIsBetter: smallerIsBetter,
+		Metric:          metric,
+		pbtSearchState: pbtSearchState{
+			Population:       map[model.RequestID]*pbtTrial{},
+			SearchMethodType: PBTSearch,
+		},
+	}
+}
+
+func (s *pbtSearch) initialTrials(ctx context) ([]Action, error) {
+	var actions []Action
+	for trial := 0; trial < s.PopulationSize(); trial++ {
+		create := NewCreate(ctx.rand, sampleAll(ctx.hparams, ctx.rand))
+		s.Population[create.RequestID] = &pbtTrial{
+			Hparams: create.Hparams,
+			Metrics: map[int]model.ExtendedFloat64{},
+		}
+		actions = append(actions, create)
+	}
+	return actions, nil
+}
+
+// validationCompleted records the searcher metric of a trial for every round it has finished,
+// stops it after the last round, and acts on any rounds the whole population has finished.
+func (s *pbtSearch) validationCompleted(
+	ctx context, requestID model.RequestID, metrics map[string]interface{},
+) ([]Action, error) {
+	member, ok := s.Population[requestID]
+	if !ok {
+		// The trial was replaced and is stopping.
+		return nil, nil
+	}
+
+	value, ok := metrics[s.Metric].(float64)
+	if !ok {
+		return nil, fmt.Errorf("error parsing searcher metric (%s) from validation metrics: %v", s.Metric, metrics)
+	}
+	timeStep, ok := metrics[s.TimeMetric()].(float64)
+	if !ok {
+		return nil, fmt.Errorf(
+			"error parsing searcher time metric (%s) in validation metrics: %v", s.TimeMetric(), metrics)
+	}
+	switch {
+	case math.IsNaN(value):
+		value = math.Inf(1)
+	case !s.SmallerIsBetter:
+		value *= -1
+	}
+
+	var actions []Action
+	rounds := mathx.Min(int(timeStep)/s.TimePerRound(), s.NumRounds())
+	for round := member.StartRound; round < rounds; round++ {
+		if _, ok := member.Metrics[round]; ok {
+			continue
+		}
+		member.Metrics[round] = model.ExtendedFloat64(value)
+		if round == s.NumRounds()-1 {
+			actions = append(actions, NewStop(requestID))
+		}
+	}
+	return append(actions, s.exploit(ctx)...), nil
+}
+
+// exploit ranks every round that the whole population has finished. The worst TruncateFraction
+// of the population are stopped, and each is replaced with a copy of one of the best trials that
+// continues from that trial's latest checkpoint. The last round is not ranked.
+func (s *pbtSearch) exploit(ctx context) []Action {
+	var actions []Action
+	for s.Round < s.NumRounds()-1 && len(s.Population) > 0 {
+		var ranked []model.RequestID
+		for requestID, member := range s.Population {
+			if _, ok := member.Metrics[s.Round]; !ok {
+				return actions
+			}
+			ranked = append(ranked, requestID)
+		}
+		// Sort by metric and then by request ID, so that the ranking does not depend on map
+		// iteration order.
+		sort.Slice(ranked, func(i, j int) bool {
+			mi, mj := s.Population[ranked[i]].Metrics[s.Round], s.Population[ranked[j]].Metrics[s.Round]
+			if mi != mj {
+				return mi < mj
+			}
+			return bytes.Compare(ranked[i][:], ranked[j][:]) < 0
+		})
+
+		numReplaced := int(s.TruncateFraction() * float64(len(ranked)))
+		for i := 0; i < numReplaced; i++ {
+			parentID, replacedID := ranked[i], ranked[len(ranked)-numReplaced+i]
+			delete(s.Population, replacedID)
+			actions = append(actions, NewStop(replacedID))
+
+			create := NewCreateFromCheckpoint(ctx.rand, s.explore(ctx, s.Population[parentID].Hparams), parentID)
+			s.Population[create.RequestID] = &pbtTrial{
+				Hparams:    create.Hparams,
+				StartRound: s.Round + 1,
+				Metrics:    map[int]model.ExtendedFloat64{},
+			}
+			actions = append(actions, create)
+		}
+		s.Round++
+	}
+	return actions
+}
+
+// explore returns a perturbed copy of the hyperparameters of a trial that is being copied. Each
+// hyperparameter is resampled from its range with probability ResampleProbability. Otherwise
+// numeric values are scaled up or down by PerturbFactor and categorical values move to a
+// neighboring value.
+func (s *pbtSearch) explore(ctx context, hparams HParamSample) HParamSample {
+	result := make(HParamSample)
+	ctx.hparams.Each(func(name string, hp expconf.Hyperparameter) {
+		result[name] = s.exploreOne(ctx.rand, hp, hparams[name])
+	})
+	return result
+}
+
+func (s *pbtSearch) exploreOne(rand *nprand.State, hp expconf.Hyperparameter, value interface{}) interface{} {
+	switch {
+	case hp.RawConstHyperparameter != nil:
+		return hp.RawConstHyperparameter.Val()
+	case hp.RawNestedHyperparameter != nil:
+		values, _ := value.(map[string]interface{})
+		result := make(map[string]interface{})
+		expconf.Hyperparameters(*hp.RawNestedHyperparameter).Each(
+			func(name string, nested expconf.Hyperparameter) {
+				result[name] = s.exploreOne(rand, nested, values[name])
+			})
+		return result
+	}
+
+	if rand.UnitInterval() < s.ResampleProbability() {
+		return sampleOne(hp, rand)
+	}
+	increase := rand.UnitInterval() < 0.5
+	factor := 1 - s.PerturbFactor()
+	if increase {
+		factor = 1 + s.PerturbFactor()
+	}
+
+	// Hyperparameters restored from a snapshot have numbers decoded as float64.
+	var number float64
+	switch v := value.(type) {
+	case int:
+		number = float64(v)
+	case float64:
+		number = v
+	}
+
+	switch {
+	case hp.RawIntHyperparameter != nil:
+		p := hp.RawIntHyperparameter
+		old := int(math.Round(number))
+		perturbed := int(math.Round(number * factor))
+		// Always move small values by at least one, so that they are not stuck.
+		switch {
+		case perturbed == old && increase:
+			perturbed++
+		case perturbed == old:
+			perturbed--
+		}
+		return mathx.Clamp(p.Minval(), perturbed, p.Maxval())
+	case hp.RawDoubleHyperparameter != nil:
+		p := hp.RawDoubleHyperparameter
+		return mathx.Clamp(p.Minval(), number*factor, p.Maxval())
+	case hp.RawLogHyperparameter != nil:
+		p := hp.RawLogHyperparameter
+		return mathx.Clamp(math.Pow(p.Base(), p.Minval()), number*factor, math.Pow(p.Base(), p.Maxval()))
+	case hp.RawCategoricalHyperparameter != nil:
+		vals := hp.RawCategoricalHyperparameter.Vals()
+		for i, v := range vals {
+			if !reflect.DeepEqual(v, value) {
+				continue
+			}
+			if increase {
+				return vals[mathx.Min(i+1, len(vals)-1)]
+			}
+			return vals[mathx.Max(i-1, 0)]
+		}
+		return sampleOne(hp, rand)
+	default:
+		panic(fmt.Sprintf("unexpected hyperparameter type: %+v", hp))
+	}
+}
+
+// progress is the fraction of rounds that have been ranked, counting the population's progress
+// through the current round.
+func (s *pbtSearch) progress(map[model.RequestID]float64, map[model.RequestID]bool) float64 {
+	finished := 0
+	for _, member := range s.Population {
+		if _, ok := member.Metrics[s.Round]; ok {
+			finished++
+		}
+	}
+	round := float64(s.Round)
+	if len(s.Population) > 0 {
+		round += float64(finished) / float64(len(s.Population))
+	}
+	return round / float64(s.NumRounds())
+}
+
+// trialExitedEarly does not fail the search. The trial is dropped from the population once it
+// exits, and the rest of the population continues without it.
+func (s *pbtSearch) trialExitedEarly(context, model.RequestID, model.ExitedReason) ([]Action, error) {
+	return nil, nil
+}
+
+// trialExited drops a trial from the population, which may let the population finish a round.
+func (s *pbtSearch) trialExited(ctx context, requestID model.RequestID) ([]Action, error) {
+	delete(s.Population, requestID)
+	return s.exploit(ctx), nil
+}
+
+func (s *pbtSearch) Snapshot() (json.RawMessage, error) {
+	return json.Marshal(s.pbtSearchState)
+}
+
+func (s *pbtSearch) Restore(state json.RawMessage) error {
+	if state == nil {
+		return nil
+	}
+	return json.Unmarshal(state, &s.pbtSearchState)
+}
+
+func (s *pbtSearch) Type() SearchMethodType {
+	return s.SearchMethodType
+}
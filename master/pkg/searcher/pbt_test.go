@@ -0,0 +1,250 @@
+//nolint:exhaustruct
+package searcher
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/nprand"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+func pbtTestConfig(populationSize, numRounds int, truncateFraction float64) expconf.SearcherConfig {
+	return schemas.WithDefaults(expconf.SearcherConfig{
+		RawMetric: ptrs.Ptr("loss"),
+		RawPBTConfig: &expconf.PBTConfig{
+			RawPopulationSize:   ptrs.Ptr(populationSize),
+			RawNumRounds:        ptrs.Ptr(numRounds),
+			RawTimeMetric:       ptrs.Ptr("batches"),
+			RawTimePerRound:     ptrs.Ptr(100),
+			RawTruncateFraction: ptrs.Ptr(truncateFraction),
+		},
+	})
+}
+
+func pbtValidation(loss float64, batches int) map[string]interface{} {
+	return map[string]interface{}{"loss": loss, "batches": float64(batches)}
+}
+
+// splitActions returns the request IDs of the Stop actions and the Create actions.
+func splitActions(t *testing.T, actions []Action) ([]model.RequestID, []Create) {
+	var stops []model.RequestID
+	var creates []Create
+	for _, a := range actions {
+		switch a := a.(type) {
+		case Stop:
+			stops = append(stops, a.RequestID)
+		case Create:
+			creates = append(creates, a)
+		default:
+			t.Fatalf("unexpected action: %v", a)
+		}
+	}
+	return stops, creates
+}
+
+func TestPBTSearchMethod(t *testing.T) {
+	conf := pbtTestConfig(4, 3, 0.5)
+	hparams := tpeTestHyperparameters()
+	searcher := NewSearcher(0, NewSearchMethod(conf), hparams)
+
+	actions, err := searcher.InitialTrials()
+	require.NoError(t, err)
+	_, population := splitActions(t, actions)
+	require.Len(t, population, 4)
+	for _, c := range population {
+		require.Nil(t, c.Checkpoint)
+		_, err := searcher.TrialCreated(c.RequestID)
+		require.NoError(t, err)
+	}
+
+	// Nothing happens until the whole population has finished the round.
+	for i, c := range population[:3] {
+		actions, err := searcher.ValidationCompleted(c.RequestID, pbtValidation(float64(i), 100))
+		require.NoError(t, err)
+		require.Empty(t, actions)
+	}
+	actions, err = searcher.ValidationCompleted(population[3].RequestID, pbtValidation(3, 100))
+	require.NoError(t, err)
+
+	// The worst half is stopped and replaced with copies of the best half.
+	stops, creates := splitActions(t, actions)
+	require.Equal(t, []model.RequestID{population[2].RequestID, population[3].RequestID}, stops)
+	require.Len(t, creates, 2)
+	for i, c := range creates {
+		require.NotNil(t, c.Checkpoint)
+		require.Equal(t, population[i].RequestID, c.Checkpoint.RequestID)
+		require.Equal(t, "adam", c.Hparams["optimizer"].(map[string]interface{})["type"])
+		_, err := searcher.TrialCreated(c.RequestID)
+		require.NoError(t, err)
+	}
+	require.InDelta(t, 1.0/3, searcher.Progress(), 1e-9)
+
+	// Stopped trials no longer take part in the search.
+	for _, id := range stops {
+		actions, err := searcher.ValidationCompleted(id, pbtValidation(10, 200))
+		require.NoError(t, err)
+		require.Empty(t, actions)
+		actions, err = searcher.TrialExited(id)
+		require.NoError(t, err)
+		require.Empty(t, actions)
+	}
+
+	// In the second round, the copies do better than the trials they were copied from.
+	members := []model.RequestID{
+		population[0].RequestID, population[1].RequestID, creates[0].RequestID, creates[1].RequestID,
+	}
+	for i, id := range members {
+		actions, err = searcher.ValidationCompleted(id, pbtValidation(float64(10-i), 200))
+		require.NoError(t, err)
+	}
+	stops, creates = splitActions(t, actions)
+	require.Equal(t, []model.RequestID{members[1], members[0]}, stops)
+	require.Len(t, creates, 2)
+	require.Equal(t, members[3], creates[0].Checkpoint.RequestID)
+	require.Equal(t, members[2], creates[1].Checkpoint.RequestID)
+
+	// The last round is not ranked, and each trial is stopped once it finishes it.
+	for _, id := range stops {
+		_, err := searcher.TrialExited(id)
+		require.NoError(t, err)
+	}
+	members = []model.RequestID{members[2], members[3], creates[0].RequestID, creates[1].RequestID}
+	for _, c := range creates {
+		_, err := searcher.TrialCreated(c.RequestID)
+		require.NoError(t, err)
+	}
+	for i, id := range members {
+		actions, err := searcher.ValidationCompleted(id, pbtValidation(float64(i), 300))
+		require.NoError(t, err)
+		require.Equal(t, []Action{NewStop(id)}, actions)
+	}
+	for i, id := range members {
+		actions, err := searcher.TrialExited(id)
+		require.NoError(t, err)
+		if i < len(members)-1 {
+			require.Empty(t, actions)
+		} else {
+			require.Equal(t, []Action{Shutdown{}}, actions)
+		}
+	}
+}
+
+func TestPBTExploitEarlyExit(t *testing.T) {
+	conf := pbtTestConfig(3, 2, 0.5)
+	searcher := NewSearcher(0, NewSearchMethod(conf), tpeTestHyperparameters())
+
+	actions, err := searcher.InitialTrials()
+	require.NoError(t, err)
+	_, population := splitActions(t, actions)
+	for _, c := range population {
+		_, err := searcher.TrialCreated(c.RequestID)
+		require.NoError(t, err)
+	}
+
+	// A trial that exits early is dropped instead of failing the search, and the round is
+	// ranked once the rest of the population has finished it.
+	actions, err = searcher.TrialExitedEarly(population[0].RequestID, model.Errored)
+	require.NoError(t, err)
+	require.Empty(t, actions)
+	for i, c := range population[1:] {
+		actions, err = searcher.ValidationCompleted(c.RequestID, pbtValidation(float64(i), 150))
+		require.NoError(t, err)
+		require.Empty(t, actions)
+	}
+	actions, err = searcher.TrialExited(population[0].RequestID)
+	require.NoError(t, err)
+	stops, creates := splitActions(t, actions)
+	require.Equal(t, []model.RequestID{population[2].RequestID}, stops)
+	require.Len(t, creates, 1)
+	require.Equal(t, population[1].RequestID, creates[0].Checkpoint.RequestID)
+}
+
+func TestPBTExplore(t *testing.T) {
+	conf := pbtTestConfig(4, 3, 0.5)
+	conf.RawPBTConfig.RawResampleProbability = ptrs.Ptr(0.0)
+	conf.RawPBTConfig.RawPerturbFactor = ptrs.Ptr(0.5)
+	method := NewSearchMethod(conf).(*pbtSearch)
+	hparams := tpeTestHyperparameters()
+	ctx := context{rand: nprand.New(0), hparams: hparams}
+
+	parent := HParamSample{
+		"cat":       "b",
+		"double":    4.0,
+		"int":       1,
+		"optimizer": map[string]interface{}{"lr": 0.01, "type": "adam"},
+	}
+	// Numbers restored from a snapshot are float64.
+	restored := HParamSample{
+		"cat":       "b",
+		"double":    4.0,
+		"int":       1.0,
+		"optimizer": map[string]interface{}{"lr": 0.01, "type": "adam"},
+	}
+	for _, sample := range []HParamSample{parent, restored} {
+		for i := 0; i < 20; i++ {
+			child := method.explore(ctx, sample)
+			require.Contains(t, []interface{}{"a", "c"}, child["cat"])
+			require.Contains(t, []interface{}{2.0, 6.0}, child["double"])
+			// Small ints move by at least one, and values are clamped to the range.
+			require.Contains(t, []interface{}{1, 2}, child["int"])
+			optimizer := child["optimizer"].(map[string]interface{})
+			lr := optimizer["lr"].(float64)
+			require.True(t, math.Abs(lr-0.005) < 1e-12 || math.Abs(lr-0.015) < 1e-12, lr)
+			require.Equal(t, "adam", optimizer["type"])
+		}
+	}
+}
+
+func TestPBTSnapshotRestore(t *testing.T) {
+	conf := pbtTestConfig(4, 3, 0.25)
+	hparams := tpeTestHyperparameters()
+
+	original := NewSearcher(5, NewSearchMethod(conf), hparams)
+	actions, err := original.InitialTrials()
+	require.NoError(t, err)
+	_, population := splitActions(t, actions)
+	for i, c := range population[:3] {
+		_, err := original.TrialCreated(c.RequestID)
+		require.NoError(t, err)
+		_, err = original.ValidationCompleted(c.RequestID, pbtValidation(float64(i), 100))
+		require.NoError(t, err)
+	}
+	snapshot, err := original.Snapshot()
+	require.NoError(t, err)
+
+	restored := NewSearcher(5, NewSearchMethod(conf), hparams)
+	require.NoError(t, restored.Restore(snapshot))
+	resnapshot, err := restored.Snapshot()
+	require.NoError(t, err)
+	require.JSONEq(t, string(snapshot), string(resnapshot))
+
+	// Both searchers make the same replacement when the population finishes the round.
+	last := population[3].RequestID
+	expected, err := original.ValidationCompleted(last, pbtValidation(3, 100))
+	require.NoError(t, err)
+	actual, err := restored.ValidationCompleted(last, pbtValidation(3, 100))
+	require.NoError(t, err)
+	require.Len(t, actual, 2)
+	require.Equal(t, expected[0], actual[0])
+	expectedCreate, actualCreate := expected[1].(Create), actual[1].(Create)
+	require.Equal(t, expectedCreate.RequestID, actualCreate.RequestID)
+	require.Equal(t, expectedCreate.Checkpoint, actualCreate.Checkpoint)
+	require.InDelta(t, expectedCreate.Hparams["double"], actualCreate.Hparams["double"], 1e-9)
+}
+
+func TestPBTSimulate(t *testing.T) {
+	conf := pbtTestConfig(8, 3, 0.25)
+	summary, err := Simulate(conf, tpeTestHyperparameters())
+	require.NoError(t, err)
+	require.Equal(t, []TrialSummary{
+		{Count: 2, Unit: SearchUnit{Name: ptrs.Ptr("batches"), Value: ptrs.Ptr(int32(100))}},
+		{Count: 2, Unit: SearchUnit{Name: ptrs.Ptr("batches"), Value: ptrs.Ptr(int32(200))}},
+		{Count: 8, Unit: SearchUnit{Name: ptrs.Ptr("batches"), Value: ptrs.Ptr(int32(300))}},
+	}, summary.Trials)
+}
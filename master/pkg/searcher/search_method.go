@@ -54,6 +54,10 @@ const (
 	ASHASearch SearchMethodType = "asha"
 	// AdaptiveASHASearch is the SearchMethodType for an adaptive ASHA searcher.
 	AdaptiveASHASearch SearchMethodType = "adaptive_asha"
+	// TPESearch is the SearchMethodType for a Tree-structured Parzen Estimator searcher.
+	TPESearch SearchMethodType = "tpe"
+	// PBTSearch is the SearchMethodType for a Population Based Training searcher.
+	PBTSearch SearchMethodType = "pbt"
 )
 
 // NewSearchMethod returns a new search method for the provided searcher configuration.
@@ -65,10 +69,14 @@ func NewSearchMethod(c expconf.SearcherConfig) SearchMethod {
 		return newRandomSearch(*c.RawRandomConfig)
 	case c.RawGridConfig != nil:
 		return newGridSearch(*c.RawGridConfig)
+	case c.RawTPEConfig != nil:
+		return newTPESearch(*c.RawTPEConfig, c.SmallerIsBetter(), c.Metric())
+	case c.RawPBTConfig != nil:
+		return newPBTSearch(*c.RawPBTConfig, c.SmallerIsBetter(), c.Metric())
 	case c.RawAsyncHalvingConfig != nil:
-		return newAsyncHalvingStoppingSearch(*c.RawAsyncHalvingConfig, c.SmallerIsBetter(), c.Metric())
+		return newAsyncHalvingStoppingSearch(*c.RawAsyncHalvingConfig, c.Objectives())
 	case c.RawAdaptiveASHAConfig != nil:
-		return newAdaptiveASHASearch(*c.RawAdaptiveASHAConfig, c.SmallerIsBetter(), c.Metric())
+		return newAdaptiveASHASearch(*c.RawAdaptiveASHAConfig, c.Objectives())
 	default:
 		panic("no searcher type specified")
 	}
@@ -75,12 +75,38 @@ func Simulate(conf expconf.SearcherConfig, hparams expconf.Hyperparameters) (Sea
 		maxTrials := conf.RawRandomConfig.MaxTrials()
 		searchSummary.Trials = append(searchSummary.Trials, TrialSummary{Count: maxTrials, Unit: SearchUnit{MaxLength: true}})
 		return searchSummary, nil
+	case conf.RawTPEConfig != nil:
+		maxTrials := conf.RawTPEConfig.MaxTrials()
+		searchSummary.Trials = append(searchSummary.Trials, TrialSummary{Count: maxTrials, Unit: SearchUnit{MaxLength: true}})
+		return searchSummary, nil
 	case conf.RawGridConfig != nil:
 		hparamGrid := newHyperparameterGrid(hparams)
 		searchSummary.Trials = append(
 			searchSummary.Trials, TrialSummary{Count: len(hparamGrid), Unit: SearchUnit{MaxLength: true}},
 		)
 		return searchSummary, nil
+	case conf.RawPBTConfig != nil:
+		// Every ranked round stops the replaced trials, and the whole population trains for the
+		// last round.
+		pbtConfig := conf.RawPBTConfig
+		numReplaced := int(pbtConfig.TruncateFraction() * float64(pbtConfig.PopulationSize()))
+		for round := 1; round <= pbtConfig.NumRounds(); round++ {
+			count := numReplaced
+			if round == pbtConfig.NumRounds() {
+				count = pbtConfig.PopulationSize()
+			}
+			if count == 0 {
+				continue
+			}
+			searchSummary.Trials = append(searchSummary.Trials, TrialSummary{
+				Count: count,
+				Unit: SearchUnit{
+					Name:  ptrs.Ptr(pbtConfig.TimeMetric()),
+					Value: ptrs.Ptr(int32(round * pbtConfig.TimePerRound())),
+				},
+			})
+		}
+		return searchSummary, nil
 	case conf.RawAdaptiveASHAConfig != nil:
 		ashaConfig := conf.RawAdaptiveASHAConfig
 		brackets := makeBrackets(*ashaConfig)
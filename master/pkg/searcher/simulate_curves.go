@@ -0,0 +1,420 @@
+package searcher
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/nprand"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+	"github.com/determined-ai/determined/proto/pkg/experimentv1"
+)
+
+// LearningCurveModel is a family of synthetic learning curves for the searcher metric. A trial that
+// has trained for a fraction p of the maximum training length reports
+// asymptote + (InitialValue - asymptote) * exp(-Rate * p), plus Gaussian noise with standard
+// deviation NoiseStddev, where the asymptote of each trial is sampled uniformly between
+// MinAsymptote and MaxAsymptote. In a multi-objective search, every objective of a trial follows
+// its own curve, with its own asymptote and noise, so that trials trade the objectives off against
+// each other instead of ranking the same on all of them.
+type LearningCurveModel struct {
+	InitialValue float64
+	MinAsymptote float64
+	MaxAsymptote float64
+	Rate         float64
+	NoiseStddev  float64
+}
+
+// LearningCurveModelFromProto converts the protobuf representation of a LearningCurveModel.
+func LearningCurveModelFromProto(m *experimentv1.LearningCurveModel) LearningCurveModel {
+	return LearningCurveModel{
+		InitialValue: m.InitialValue,
+		MinAsymptote: m.MinAsymptote,
+		MaxAsymptote: m.MaxAsymptote,
+		Rate:         m.Rate,
+		NoiseStddev:  m.NoiseStddev,
+	}
+}
+
+func (m LearningCurveModel) validate() error {
+	switch {
+	case m.MinAsymptote > m.MaxAsymptote:
+		return errors.New("min_asymptote must not be greater than max_asymptote")
+	case m.Rate < 0:
+		return errors.New("rate must not be negative")
+	case m.NoiseStddev < 0:
+		return errors.New("noise_stddev must not be negative")
+	}
+	return nil
+}
+
+// value returns the metric of a trial with the given asymptote after a fraction of training.
+func (m LearningCurveModel) value(rand *nprand.State, asymptote, fraction float64) float64 {
+	value := asymptote + (m.InitialValue-asymptote)*math.Exp(-m.Rate*fraction)
+	if m.NoiseStddev > 0 {
+		value += m.NoiseStddev * sampleStandardNormal(rand)
+	}
+	return value
+}
+
+// SimulationDistribution summarizes a value across the runs of a simulation.
+type SimulationDistribution struct {
+	Mean float64
+	P10  float64
+	P50  float64
+	P90  float64
+}
+
+// Proto converts the SimulationDistribution to its protobuf representation.
+func (d SimulationDistribution) Proto() *experimentv1.SimulationDistribution {
+	return &experimentv1.SimulationDistribution{Mean: d.Mean, P10: d.P10, P50: d.P50, P90: d.P90}
+}
+
+func newSimulationDistribution(values []float64) SimulationDistribution {
+	if len(values) == 0 {
+		return SimulationDistribution{}
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	percentile := func(p float64) float64 {
+		return sorted[int(math.Round(p*float64(len(sorted)-1)))]
+	}
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+	return SimulationDistribution{
+		Mean: sum / float64(len(sorted)),
+		P10:  percentile(0.1),
+		P50:  percentile(0.5),
+		P90:  percentile(0.9),
+	}
+}
+
+// RungSimulation summarizes a rung of an ASHA bracket across the runs of a simulation.
+type RungSimulation struct {
+	Bracket            int
+	Rung               int
+	UnitsNeeded        uint64
+	ExpectedTrials     float64
+	ExpectedPromotions float64
+}
+
+// Proto converts the RungSimulation to its protobuf representation.
+func (r RungSimulation) Proto() *experimentv1.RungSimulation {
+	return &experimentv1.RungSimulation{
+		Bracket:            int32(r.Bracket),
+		Rung:               int32(r.Rung),
+		UnitsNeeded:        int32(r.UnitsNeeded),
+		ExpectedTrials:     r.ExpectedTrials,
+		ExpectedPromotions: r.ExpectedPromotions,
+	}
+}
+
+// SearchSimulation is the result of running a search many times against a LearningCurveModel.
+// ExpectedCompute is in slot-units: the time metric units every trial trains for, multiplied by
+// the slots per trial. TimeToBest is the time, in time metric units with trials training
+// concurrently, until the best validation of the search is reported.
+type SearchSimulation struct {
+	NumSimulations  int
+	ExpectedCompute float64
+	TimeToBest      SimulationDistribution
+	Rungs           []RungSimulation
+}
+
+// Proto converts the SearchSimulation to its protobuf representation.
+func (s SearchSimulation) Proto() *experimentv1.SearchSimulation {
+	rungs := make([]*experimentv1.RungSimulation, 0, len(s.Rungs))
+	for _, r := range s.Rungs {
+		rungs = append(rungs, r.Proto())
+	}
+	return &experimentv1.SearchSimulation{
+		NumSimulations:  int32(s.NumSimulations),
+		ExpectedCompute: s.ExpectedCompute,
+		TimeToBest:      s.TimeToBest.Proto(),
+		Rungs:           rungs,
+	}
+}
+
+// SimulateLearningCurves runs an ASHA or adaptive ASHA search numSimulations times, with every
+// trial reporting metrics from curves. Trials validate whenever they reach a rung, and all the
+// trials the searcher has created train concurrently at the same speed.
+func SimulateLearningCurves(
+	conf expconf.SearcherConfig, hparams expconf.Hyperparameters, curves LearningCurveModel,
+	slotsPerTrial, numSimulations int, seed uint32,
+) (SearchSimulation, error) {
+	if conf.RawAsyncHalvingConfig == nil && conf.RawAdaptiveASHAConfig == nil {
+		return SearchSimulation{}, errors.New(
+			"simulating learning curves is only supported for the async_halving and adaptive_asha searchers")
+	}
+	if numSimulations < 1 {
+		return SearchSimulation{}, errors.New("the number of simulations must be positive")
+	}
+	if err := curves.validate(); err != nil {
+		return SearchSimulation{}, errors.Wrap(err, "invalid learning curve model")
+	}
+
+	result := SearchSimulation{NumSimulations: numSimulations}
+	var timesToBest []float64
+	for i := 0; i < numSimulations; i++ {
+		run := newCurveSimulation(conf, hparams, curves, seed+uint32(i))
+		if err := run.run(); err != nil {
+			return SearchSimulation{}, errors.Wrapf(err, "simulation %d", i)
+		}
+		result.ExpectedCompute += float64(run.units*uint64(slotsPerTrial)) / float64(numSimulations)
+		timesToBest = append(timesToBest, run.timeToBest)
+
+		idx := 0
+		for b, search := range run.ashaSearches() {
+			for r, rung := range search.Rungs {
+				if i == 0 {
+					result.Rungs = append(result.Rungs, RungSimulation{
+						Bracket: b, Rung: r, UnitsNeeded: rung.UnitsNeeded,
+					})
+				}
+				result.Rungs[idx].ExpectedTrials += float64(len(rung.Metrics)) / float64(numSimulations)
+				// Every trial in the next rung was promoted from this one.
+				if r+1 < len(search.Rungs) {
+					promoted := len(search.Rungs[r+1].Metrics)
+					result.Rungs[idx].ExpectedPromotions += float64(promoted) / float64(numSimulations)
+				}
+				idx++
+			}
+		}
+	}
+	result.TimeToBest = newSimulationDistribution(timesToBest)
+	return result, nil
+}
+
+type (
+	// simulatedTrial is a trial in a curve simulation. It started training at time start, has
+	// trained for units, and validates next when it reaches the next checkpoint.
+	simulatedTrial struct {
+		requestID model.RequestID
+		start     float64
+		next      int
+		units     uint64
+		// asymptotes holds the asymptote of the curve of each objective.
+		asymptotes []float64
+		stopped    bool
+	}
+
+	// simulatedTrials is a heap of trials ordered by the time of their next validation.
+	simulatedTrials struct {
+		trials      []*simulatedTrial
+		checkpoints []uint64
+	}
+
+	curveSimulation struct {
+		conf        expconf.SearcherConfig
+		curves      LearningCurveModel
+		method      SearchMethod
+		searcher    *Searcher
+		rand        *nprand.State
+		maxUnits    uint64
+		timeMetric  string
+		trials      map[model.RequestID]*simulatedTrial
+		queue       *simulatedTrials
+		now         float64
+		units       uint64
+		best        float64
+		timeToBest  float64
+		hasBest     bool
+		shutdown    bool
+		checkpoints []uint64
+	}
+)
+
+func (h simulatedTrials) Len() int { return len(h.trials) }
+
+func (h simulatedTrials) Less(i, j int) bool {
+	ti, tj := h.trials[i], h.trials[j]
+	ni, nj := ti.start+float64(h.checkpoints[ti.next]), tj.start+float64(h.checkpoints[tj.next])
+	if ni != nj {
+		return ni < nj
+	}
+	return ti.start < tj.start
+}
+
+func (h simulatedTrials) Swap(i, j int) { h.trials[i], h.trials[j] = h.trials[j], h.trials[i] }
+
+func (h *simulatedTrials) Push(x interface{}) { h.trials = append(h.trials, x.(*simulatedTrial)) }
+
+func (h *simulatedTrials) Pop() interface{} {
+	last := h.trials[len(h.trials)-1]
+	h.trials = h.trials[:len(h.trials)-1]
+	return last
+}
+
+func newCurveSimulation(
+	conf expconf.SearcherConfig, hparams expconf.Hyperparameters, curves LearningCurveModel, seed uint32,
+) *curveSimulation {
+	var length expconf.Length
+	var rungUnits []uint64
+	switch {
+	case conf.RawAsyncHalvingConfig != nil:
+		c := conf.RawAsyncHalvingConfig
+		length = c.Length()
+		for _, r := range makeRungs(c.NumRungs(), c.Divisor(), length.Units) {
+			rungUnits = append(rungUnits, r.UnitsNeeded)
+		}
+	case conf.RawAdaptiveASHAConfig != nil:
+		c := conf.RawAdaptiveASHAConfig
+		length = c.Length()
+		for _, b := range makeBrackets(*c) {
+			for _, r := range makeRungs(b.numRungs, c.Divisor(), length.Units) {
+				rungUnits = append(rungUnits, r.UnitsNeeded)
+			}
+		}
+	}
+
+	// Validate at every rung of every bracket and at the end of training.
+	seen := map[uint64]bool{length.Units: true}
+	checkpoints := []uint64{length.Units}
+	for _, u := range rungUnits {
+		if !seen[u] {
+			seen[u] = true
+			checkpoints = append(checkpoints, u)
+		}
+	}
+	sort.Slice(checkpoints, func(i, j int) bool { return checkpoints[i] < checkpoints[j] })
+
+	method := NewSearchMethod(conf)
+	return &curveSimulation{
+		conf:        conf,
+		curves:      curves,
+		method:      method,
+		searcher:    NewSearcher(seed, method, hparams),
+		rand:        nprand.New(seed),
+		maxUnits:    length.Units,
+		timeMetric:  string(length.Unit),
+		trials:      map[model.RequestID]*simulatedTrial{},
+		queue:       &simulatedTrials{checkpoints: checkpoints},
+		checkpoints: checkpoints,
+	}
+}
+
+// ashaSearches returns the ASHA search of every bracket.
+func (s *curveSimulation) ashaSearches() []*asyncHalvingStoppingSearch {
+	switch method := s.method.(type) {
+	case *asyncHalvingStoppingSearch:
+		return []*asyncHalvingStoppingSearch{method}
+	case *tournamentSearch:
+		var searches []*asyncHalvingStoppingSearch
+		for _, sub := range method.subSearches {
+			searches = append(searches, sub.(*asyncHalvingStoppingSearch))
+		}
+		return searches
+	default:
+		panic(fmt.Sprintf("unexpected search method for a curve simulation: %T", s.method))
+	}
+}
+
+func (s *curveSimulation) run() error {
+	actions, err := s.searcher.InitialTrials()
+	if err != nil {
+		return err
+	}
+	if err := s.handle(actions); err != nil {
+		return err
+	}
+
+	for s.queue.Len() > 0 && !s.shutdown {
+		trial := heap.Pop(s.queue).(*simulatedTrial)
+		if trial.stopped {
+			continue
+		}
+		if err := s.validate(trial); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validate reports the next validation of a trial to the searcher.
+func (s *curveSimulation) validate(trial *simulatedTrial) error {
+	units := s.checkpoints[trial.next]
+	s.now = trial.start + float64(units)
+	trial.units = units
+
+	metrics := map[string]interface{}{s.timeMetric: float64(units)}
+	for i, objective := range s.conf.Objectives() {
+		value := s.curves.value(s.rand, trial.asymptotes[i], float64(units)/float64(s.maxUnits))
+		metrics[objective.Name()] = value
+		if i > 0 {
+			continue
+		}
+		// The best validation is the best value of the first objective.
+		if !objective.SmallerIsBetter() {
+			value *= -1
+		}
+		if !s.hasBest || value < s.best {
+			s.best, s.timeToBest, s.hasBest = value, s.now, true
+		}
+	}
+	actions, err := s.searcher.ValidationCompleted(trial.requestID, metrics)
+	if err != nil {
+		return err
+	}
+	if err := s.handle(actions); err != nil {
+		return err
+	}
+
+	if trial.stopped {
+		return nil
+	}
+	trial.next++
+	if trial.next == len(s.checkpoints) {
+		return s.exit(trial)
+	}
+	heap.Push(s.queue, trial)
+	return nil
+}
+
+func (s *curveSimulation) handle(actions []Action) error {
+	for _, action := range actions {
+		switch action := action.(type) {
+		case Create:
+			trial := &simulatedTrial{requestID: action.RequestID, start: s.now}
+			for range s.conf.Objectives() {
+				trial.asymptotes = append(trial.asymptotes,
+					s.rand.Uniform(s.curves.MinAsymptote, s.curves.MaxAsymptote))
+			}
+			s.trials[action.RequestID] = trial
+			heap.Push(s.queue, trial)
+			more, err := s.searcher.TrialCreated(action.RequestID)
+			if err != nil {
+				return err
+			}
+			if err := s.handle(more); err != nil {
+				return err
+			}
+		case Stop:
+			trial, ok := s.trials[action.RequestID]
+			if !ok || trial.stopped {
+				continue
+			}
+			if err := s.exit(trial); err != nil {
+				return err
+			}
+		case Shutdown:
+			s.shutdown = true
+		}
+	}
+	return nil
+}
+
+// exit stops a trial and accounts for the compute it used.
+func (s *curveSimulation) exit(trial *simulatedTrial) error {
+	trial.stopped = true
+	s.units += trial.units
+	actions, err := s.searcher.TrialExited(trial.requestID)
+	if err != nil {
+		return err
+	}
+	return s.handle(actions)
+}
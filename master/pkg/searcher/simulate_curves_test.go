@@ -0,0 +1,161 @@
+//nolint:exhaustruct
+package searcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+func curveTestHyperparameters() expconf.Hyperparameters {
+	return expconf.Hyperparameters{
+		"x": expconf.Hyperparameter{RawIntHyperparameter: &expconf.IntHyperparameter{RawMaxval: 10}},
+	}
+}
+
+var testCurves = LearningCurveModel{
+	InitialValue: 2.0,
+	MinAsymptote: 0.1,
+	MaxAsymptote: 1.0,
+	Rate:         3,
+	NoiseStddev:  0.05,
+}
+
+func TestSimulateLearningCurvesASHA(t *testing.T) {
+	conf := schemas.WithDefaults(expconf.SearcherConfig{
+		RawMetric: ptrs.Ptr("loss"),
+		RawAsyncHalvingConfig: &expconf.AsyncHalvingConfig{
+			RawNumRungs:            ptrs.Ptr(3),
+			RawDivisor:             ptrs.Ptr(3.0),
+			RawMaxTime:             ptrs.Ptr(900),
+			RawTimeMetric:          ptrs.Ptr("batches"),
+			RawMaxTrials:           ptrs.Ptr(9),
+			RawMaxConcurrentTrials: ptrs.Ptr(3),
+		},
+	})
+
+	sim, err := SimulateLearningCurves(conf, curveTestHyperparameters(), testCurves, 2, 50, 0)
+	require.NoError(t, err)
+	require.Equal(t, 50, sim.NumSimulations)
+	require.Len(t, sim.Rungs, 3)
+
+	// Every trial reports a metric for the first rung, and the trials in each rung were promoted
+	// from the rung below it.
+	require.InDelta(t, 9, sim.Rungs[0].ExpectedTrials, 1e-9)
+	var compute float64
+	for i, r := range sim.Rungs {
+		require.Equal(t, []uint64{100, 300, 900}[i], r.UnitsNeeded)
+		if i+1 < len(sim.Rungs) {
+			require.InDelta(t, sim.Rungs[i+1].ExpectedTrials, r.ExpectedPromotions, 1e-9)
+			require.Less(t, r.ExpectedPromotions, r.ExpectedTrials)
+			compute += (r.ExpectedTrials - r.ExpectedPromotions) * float64(r.UnitsNeeded)
+		} else {
+			require.Zero(t, r.ExpectedPromotions)
+			compute += r.ExpectedTrials * float64(r.UnitsNeeded)
+		}
+	}
+	// Each trial trains until the last rung it reaches, on 2 slots.
+	require.InDelta(t, 2*compute, sim.ExpectedCompute, 1e-6)
+
+	require.Greater(t, sim.TimeToBest.P10, 0.0)
+	require.LessOrEqual(t, sim.TimeToBest.P10, sim.TimeToBest.P50)
+	require.LessOrEqual(t, sim.TimeToBest.P50, sim.TimeToBest.P90)
+
+	// The simulation is reproducible for a seed.
+	again, err := SimulateLearningCurves(conf, curveTestHyperparameters(), testCurves, 2, 50, 0)
+	require.NoError(t, err)
+	require.Equal(t, sim, again)
+}
+
+func TestSimulateLearningCurvesAdaptiveASHA(t *testing.T) {
+	conf := schemas.WithDefaults(expconf.SearcherConfig{
+		RawMetric:          ptrs.Ptr("accuracy"),
+		RawSmallerIsBetter: ptrs.Ptr(false),
+		RawAdaptiveASHAConfig: &expconf.AdaptiveASHAConfig{
+			RawMaxRungs:   ptrs.Ptr(3),
+			RawMaxTime:    ptrs.Ptr(900),
+			RawDivisor:    ptrs.Ptr(3.0),
+			RawTimeMetric: ptrs.Ptr("batches"),
+			RawMaxTrials:  ptrs.Ptr(20),
+			RawMode:       ptrs.Ptr(expconf.StandardMode),
+		},
+	})
+	curves := LearningCurveModel{InitialValue: 0.1, MinAsymptote: 0.6, MaxAsymptote: 0.95, Rate: 3}
+
+	sim, err := SimulateLearningCurves(conf, curveTestHyperparameters(), curves, 1, 20, 7)
+	require.NoError(t, err)
+
+	// Standard mode with three rungs has brackets of three and two rungs.
+	var brackets []int
+	var trials float64
+	for _, r := range sim.Rungs {
+		if r.Rung == 0 {
+			brackets = append(brackets, r.Bracket)
+			trials += r.ExpectedTrials
+		}
+	}
+	require.Equal(t, []int{0, 1}, brackets)
+	require.Len(t, sim.Rungs, 5)
+	require.InDelta(t, 20, trials, 1e-9)
+	require.Greater(t, sim.ExpectedCompute, 0.0)
+}
+
+func TestSimulateLearningCurvesMultiObjective(t *testing.T) {
+	conf := schemas.WithDefaults(expconf.SearcherConfig{
+		RawMetric: ptrs.Ptr("loss"),
+		RawMetrics: []expconf.SearcherMetric{
+			{RawName: "loss"},
+			{RawName: "latency"},
+		},
+		RawAsyncHalvingConfig: &expconf.AsyncHalvingConfig{
+			RawNumRungs:            ptrs.Ptr(2),
+			RawDivisor:             ptrs.Ptr(3.0),
+			RawMaxTime:             ptrs.Ptr(300),
+			RawTimeMetric:          ptrs.Ptr("batches"),
+			RawMaxTrials:           ptrs.Ptr(9),
+			RawMaxConcurrentTrials: ptrs.Ptr(3),
+		},
+	})
+
+	run := newCurveSimulation(conf, curveTestHyperparameters(), testCurves, 0)
+	require.NoError(t, run.run())
+	rung := run.ashaSearches()[0].Rungs[0]
+	require.Len(t, rung.Metrics, 9)
+	// Every objective of a trial follows its own curve.
+	for _, m := range rung.Metrics {
+		require.Len(t, m.Values, 2)
+		require.NotEqual(t, m.Values[0], m.Values[1])
+	}
+}
+
+func TestSimulateLearningCurvesErrors(t *testing.T) {
+	asha := schemas.WithDefaults(expconf.SearcherConfig{
+		RawMetric: ptrs.Ptr("loss"),
+		RawAsyncHalvingConfig: &expconf.AsyncHalvingConfig{
+			RawNumRungs:   ptrs.Ptr(2),
+			RawMaxTime:    ptrs.Ptr(100),
+			RawTimeMetric: ptrs.Ptr("batches"),
+			RawMaxTrials:  ptrs.Ptr(4),
+		},
+	})
+	random := schemas.WithDefaults(expconf.SearcherConfig{
+		RawMetric:       ptrs.Ptr("loss"),
+		RawRandomConfig: &expconf.RandomConfig{RawMaxTrials: ptrs.Ptr(4)},
+	})
+	hparams := curveTestHyperparameters()
+
+	_, err := SimulateLearningCurves(random, hparams, testCurves, 1, 10, 0)
+	require.ErrorContains(t, err, "only supported for the async_halving and adaptive_asha searchers")
+
+	_, err = SimulateLearningCurves(asha, hparams, testCurves, 1, 0, 0)
+	require.ErrorContains(t, err, "must be positive")
+
+	invalid := testCurves
+	invalid.MinAsymptote = 5
+	_, err = SimulateLearningCurves(asha, hparams, invalid, 1, 10, 0)
+	require.ErrorContains(t, err, "min_asymptote must not be greater than max_asymptote")
+}
@@ -0,0 +1,467 @@
+package searcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/determined-ai/determined/master/pkg/mathx"
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/nprand"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+// tpeMaxRejections bounds the number of draws made when sampling from a truncated normal before
+// falling back to clamping the last draw into bounds.
+const tpeMaxRejections = 100
+
+type (
+	// tpeSearchState stores the state for TPE. Trial parameters are stored in the estimator's
+	// space (int and double values as-is, log values as exponents and categorical values as
+	// indices) so that they survive a snapshot unchanged. TrialMetrics holds the best searcher
+	// metric each trial has reported so far, negated if larger is better.
+	tpeSearchState struct {
+		CreatedTrials    int                                       `json:"created_trials"`
+		PendingTrials    int                                       `json:"pending_trials"`
+		TrialParams      map[model.RequestID]map[string]float64    `json:"trial_params"`
+		TrialMetrics     map[model.RequestID]model.ExtendedFloat64 `json:"trial_metrics"`
+		SearchMethodType SearchMethodType                          `json:"search_method_type"`
+	}
+	// tpeSearch implements the Tree-structured Parzen Estimator. After a number of randomly sampled
+	// startup trials, each new trial is chosen by splitting the finished observations into good and
+	// bad groups by searcher metric, fitting a density to each group for every hyperparameter, and
+	// picking the candidate drawn from the good density that maximizes the ratio of the two.
+	tpeSearch struct {
+		defaultSearchMethod
+		expconf.TPEConfig
+		SmallerIsBetter bool
+		Metric          string
+		tpeSearchState
+	}
+
+	// tpeParam is a single tunable hyperparameter, with nested hyperparameters flattened into
+	// dotted names.
+	tpeParam struct {
+		name string
+		path []string
+		hp   expconf.Hyperparameter
+	}
+
+	// parzenEstimator is a mixture of normal distributions truncated to [low, high].
+	parzenEstimator struct {
+		low, high float64
+		weights   []float64
+		mus       []float64
+		sigmas    []float64
+	}
+)
+
+func newTPESearch(config expconf.TPEConfig, smallerIsBetter bool, metric string) SearchMethod {
+	return &tpeSearch{
+		TPEConfig:       config,
+		SmallerIsBetter: smallerIsBetter,
+		Metric:          metric,
+		tpeSearchState: tpeSearchState{
+			TrialParams:      map[model.RequestID]map[string]float64{},
+			TrialMetrics:     map[model.RequestID]model.ExtendedFloat64{},
+			SearchMethodType: TPESearch,
+		},
+	}
+}
+
+func (s *tpeSearch) initialTrials(ctx context) ([]Action, error) {
+	var actions []Action
+	initialTrials := s.MaxTrials()
+	if s.MaxConcurrentTrials() > 0 {
+		initialTrials = mathx.Min(s.MaxTrials(), s.MaxConcurrentTrials())
+	}
+	for trial := 0; trial < initialTrials; trial++ {
+		actions = append(actions, s.create(ctx))
+	}
+	return actions, nil
+}
+
+// create proposes a new trial and records its parameters.
+func (s *tpeSearch) create(ctx context) Create {
+	params := tpeParams(ctx.hparams)
+	x := s.propose(ctx.rand, params)
+	create := NewCreate(ctx.rand, tpeSample(ctx.hparams, params, x))
+	s.TrialParams[create.RequestID] = x
+	s.CreatedTrials++
+	s.PendingTrials++
+	return create
+}
+
+func (s *tpeSearch) validationCompleted(
+	ctx context, requestID model.RequestID, metrics map[string]interface{},
+) ([]Action, error) {
+	value, ok := metrics[s.Metric].(float64)
+	if !ok {
+		return nil, fmt.Errorf("error parsing searcher metric (%s) from validation metrics: %v", s.Metric, metrics)
+	}
+	if math.IsNaN(value) {
+		return nil, nil
+	}
+	if !s.SmallerIsBetter {
+		value *= -1
+	}
+	if best, ok := s.TrialMetrics[requestID]; !ok || value < float64(best) {
+		s.TrialMetrics[requestID] = model.ExtendedFloat64(value)
+	}
+	return nil, nil
+}
+
+func (s *tpeSearch) progress(
+	trialProgress map[model.RequestID]float64,
+	trialsClosed map[model.RequestID]bool,
+) float64 {
+	// Progress is calculated the same way as for random search, since TPE also trains every
+	// trial to completion.
+	trialProgresses := 0.
+	for k, v := range trialProgress {
+		if trialsClosed[k] {
+			trialProgresses += 1.0
+		} else {
+			trialProgresses += v
+		}
+	}
+	return trialProgresses / float64(len(trialProgress))
+}
+
+// trialExitedEarly forgets a trial that exited with an InvalidHP, so that its parameters do not
+// inform later proposals, and replaces it once it is closed.
+func (s *tpeSearch) trialExitedEarly(
+	ctx context, requestID model.RequestID, exitedReason model.ExitedReason,
+) ([]Action, error) {
+	s.PendingTrials--
+	if exitedReason == model.InvalidHP || exitedReason == model.InitInvalidHP {
+		delete(s.TrialParams, requestID)
+		delete(s.TrialMetrics, requestID)
+		s.CreatedTrials--
+	}
+	return nil, nil
+}
+
+func (s *tpeSearch) trialExited(ctx context, requestID model.RequestID) ([]Action, error) {
+	s.PendingTrials--
+	var actions []Action
+	if s.CreatedTrials < s.MaxTrials() {
+		actions = append(actions, s.create(ctx))
+	}
+	return actions, nil
+}
+
+func (s *tpeSearch) Snapshot() (json.RawMessage, error) {
+	return json.Marshal(s.tpeSearchState)
+}
+
+func (s *tpeSearch) Restore(state json.RawMessage) error {
+	if state == nil {
+		return nil
+	}
+	return json.Unmarshal(state, &s.tpeSearchState)
+}
+
+func (s *tpeSearch) Type() SearchMethodType {
+	return s.SearchMethodType
+}
+
+// propose chooses the parameters of the next trial. It samples uniformly until enough trials have
+// reported a searcher metric.
+func (s *tpeSearch) propose(rand *nprand.State, params []tpeParam) map[string]float64 {
+	// Sort observations by metric and then by request ID, so that proposals only depend on the
+	// random state and not on map iteration order.
+	var observed []model.RequestID
+	for requestID := range s.TrialMetrics {
+		if _, ok := s.TrialParams[requestID]; ok {
+			observed = append(observed, requestID)
+		}
+	}
+	sort.Slice(observed, func(i, j int) bool {
+		mi, mj := s.TrialMetrics[observed[i]], s.TrialMetrics[observed[j]]
+		if mi != mj {
+			return mi < mj
+		}
+		return bytes.Compare(observed[i][:], observed[j][:]) < 0
+	})
+
+	x := make(map[string]float64, len(params))
+	if len(observed) < s.NumStartupTrials() {
+		for _, p := range params {
+			x[p.name] = p.sampleUniform(rand)
+		}
+		return x
+	}
+
+	numGood := mathx.Max(int(math.Ceil(s.Gamma()*float64(len(observed)))), 1)
+	for _, p := range params {
+		var good, bad []float64
+		for i, requestID := range observed {
+			v := s.TrialParams[requestID][p.name]
+			if i < numGood {
+				good = append(good, v)
+			} else {
+				bad = append(bad, v)
+			}
+		}
+		x[p.name] = p.sampleTPE(rand, good, bad, s.NumCandidates(), s.PriorWeight())
+	}
+	return x
+}
+
+// tpeParams returns the tunable hyperparameters, sorted by name. Const hyperparameters are left
+// out since there is nothing to learn about them.
+func tpeParams(h expconf.Hyperparameters) []tpeParam {
+	var params []tpeParam
+	var walk func(path []string, h map[string]expconf.Hyperparameter)
+	walk = func(path []string, h map[string]expconf.Hyperparameter) {
+		names := make([]string, 0, len(h))
+		for name := range h {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			hp := h[name]
+			p := append(append([]string{}, path...), name)
+			switch {
+			case hp.RawConstHyperparameter != nil:
+			case hp.RawNestedHyperparameter != nil:
+				walk(p, *hp.RawNestedHyperparameter)
+			default:
+				params = append(params, tpeParam{name: strings.Join(p, "."), path: p, hp: hp})
+			}
+		}
+	}
+	walk(nil, h)
+	return params
+}
+
+// tpeSample turns the estimator's parameters back into a hyperparameter sample, filling in
+// const hyperparameters.
+func tpeSample(h expconf.Hyperparameters, params []tpeParam, x map[string]float64) HParamSample {
+	var fill func(h map[string]expconf.Hyperparameter) map[string]interface{}
+	fill = func(h map[string]expconf.Hyperparameter) map[string]interface{} {
+		out := make(map[string]interface{}, len(h))
+		for name, hp := range h {
+			switch {
+			case hp.RawConstHyperparameter != nil:
+				out[name] = hp.RawConstHyperparameter.Val()
+			case hp.RawNestedHyperparameter != nil:
+				out[name] = fill(*hp.RawNestedHyperparameter)
+			}
+		}
+		return out
+	}
+	results := HParamSample(fill(h))
+
+	for _, p := range params {
+		m := map[string]interface{}(results)
+		for _, key := range p.path[:len(p.path)-1] {
+			m = m[key].(map[string]interface{})
+		}
+		m[p.path[len(p.path)-1]] = p.value(x[p.name])
+	}
+	return results
+}
+
+// bounds returns the range of a numeric hyperparameter in the estimator's space. Ints are widened
+// by half a step on each side so that the end values are as likely as the others.
+func (p tpeParam) bounds() (float64, float64) {
+	switch {
+	case p.hp.RawIntHyperparameter != nil:
+		return float64(p.hp.RawIntHyperparameter.Minval()) - 0.5, float64(p.hp.RawIntHyperparameter.Maxval()) + 0.5
+	case p.hp.RawDoubleHyperparameter != nil:
+		return p.hp.RawDoubleHyperparameter.Minval(), p.hp.RawDoubleHyperparameter.Maxval()
+	case p.hp.RawLogHyperparameter != nil:
+		return p.hp.RawLogHyperparameter.Minval(), p.hp.RawLogHyperparameter.Maxval()
+	default:
+		panic(fmt.Sprintf("unexpected hyperparameter type: %+v", p.hp))
+	}
+}
+
+// value converts a parameter from the estimator's space to a hyperparameter value.
+func (p tpeParam) value(x float64) interface{} {
+	switch {
+	case p.hp.RawIntHyperparameter != nil:
+		return int(p.roundInt(x))
+	case p.hp.RawDoubleHyperparameter != nil:
+		return x
+	case p.hp.RawLogHyperparameter != nil:
+		return math.Pow(p.hp.RawLogHyperparameter.Base(), x)
+	case p.hp.RawCategoricalHyperparameter != nil:
+		return p.hp.RawCategoricalHyperparameter.Vals()[int(x)]
+	default:
+		panic(fmt.Sprintf("unexpected hyperparameter type: %+v", p.hp))
+	}
+}
+
+// roundInt rounds a parameter of an int hyperparameter to the nearest value in its range. Values
+// in the widened bounds may round past the end values otherwise.
+func (p tpeParam) roundInt(x float64) float64 {
+	h := p.hp.RawIntHyperparameter
+	return mathx.Clamp(float64(h.Minval()), math.Round(x), float64(h.Maxval()))
+}
+
+// sampleUniform samples a parameter in the estimator's space with the same distribution as
+// random search.
+func (p tpeParam) sampleUniform(rand *nprand.State) float64 {
+	switch {
+	case p.hp.RawIntHyperparameter != nil:
+		h := p.hp.RawIntHyperparameter
+		return float64(h.Minval() + rand.Intn(h.Maxval()-h.Minval()+1))
+	case p.hp.RawCategoricalHyperparameter != nil:
+		return float64(rand.Intn(len(p.hp.RawCategoricalHyperparameter.Vals())))
+	default:
+		low, high := p.bounds()
+		return rand.Uniform(low, high)
+	}
+}
+
+// sampleTPE draws candidates from the density of the good observations and returns the one that
+// maximizes the ratio of the good density to the bad density.
+func (p tpeParam) sampleTPE(
+	rand *nprand.State, good, bad []float64, numCandidates int, priorWeight float64,
+) float64 {
+	var sample func() float64
+	var score func(x float64) float64
+
+	if h := p.hp.RawCategoricalHyperparameter; h != nil {
+		l := categoricalWeights(good, len(h.Vals()), priorWeight)
+		g := categoricalWeights(bad, len(h.Vals()), priorWeight)
+		sample = func() float64 { return float64(sampleWeighted(rand, l)) }
+		score = func(x float64) float64 { return math.Log(l[int(x)]) - math.Log(g[int(x)]) }
+	} else {
+		low, high := p.bounds()
+		l := newParzenEstimator(good, low, high, priorWeight)
+		g := newParzenEstimator(bad, low, high, priorWeight)
+		sample = func() float64 {
+			x := l.sample(rand)
+			if p.hp.RawIntHyperparameter != nil {
+				x = p.roundInt(x)
+			}
+			return x
+		}
+		score = func(x float64) float64 { return l.logPDF(x) - g.logPDF(x) }
+	}
+
+	best, bestScore := 0.0, math.Inf(-1)
+	for i := 0; i < numCandidates; i++ {
+		x := sample()
+		if sc := score(x); i == 0 || sc > bestScore {
+			best, bestScore = x, sc
+		}
+	}
+	return best
+}
+
+// categoricalWeights returns the normalized frequency of each category among the observations,
+// smoothed by a uniform prior.
+func categoricalWeights(observations []float64, numCategories int, priorWeight float64) []float64 {
+	weights := make([]float64, numCategories)
+	for i := range weights {
+		weights[i] = priorWeight / float64(numCategories)
+	}
+	for _, x := range observations {
+		weights[int(x)]++
+	}
+	total := priorWeight + float64(len(observations))
+	for i := range weights {
+		weights[i] /= total
+	}
+	return weights
+}
+
+// newParzenEstimator fits a mixture with one component per observation and a wide prior
+// component centered on the range. Each observation's bandwidth is the larger distance to its
+// neighbors, clipped so that no component is too narrow or wider than the range.
+func newParzenEstimator(observations []float64, low, high, priorWeight float64) parzenEstimator {
+	width := high - low
+	mus := append([]float64{}, observations...)
+	sort.Float64s(mus)
+
+	minSigma := width / math.Min(100, 1+float64(len(mus)))
+	sigmas := make([]float64, len(mus))
+	for i, mu := range mus {
+		left, right := mu-low, high-mu
+		if i > 0 {
+			left = mu - mus[i-1]
+		}
+		if i < len(mus)-1 {
+			right = mus[i+1] - mu
+		}
+		sigmas[i] = mathx.Clamp(minSigma, math.Max(left, right), width)
+	}
+
+	weights := make([]float64, len(mus))
+	total := priorWeight + float64(len(mus))
+	for i := range weights {
+		weights[i] = 1 / total
+	}
+
+	return parzenEstimator{
+		low:     low,
+		high:    high,
+		weights: append(weights, priorWeight/total),
+		mus:     append(mus, low+width/2),
+		sigmas:  append(sigmas, width),
+	}
+}
+
+func (pe parzenEstimator) sample(rand *nprand.State) float64 {
+	i := sampleWeighted(rand, pe.weights)
+	x := pe.mus[i]
+	for j := 0; j < tpeMaxRejections; j++ {
+		x = pe.mus[i] + pe.sigmas[i]*sampleStandardNormal(rand)
+		if x >= pe.low && x <= pe.high {
+			return x
+		}
+	}
+	return mathx.Clamp(pe.low, x, pe.high)
+}
+
+func (pe parzenEstimator) logPDF(x float64) float64 {
+	logPs := make([]float64, len(pe.mus))
+	maxLogP := math.Inf(-1)
+	for i := range pe.mus {
+		mu, sigma := pe.mus[i], pe.sigmas[i]
+		z := (x - mu) / sigma
+		mass := normalCDF((pe.high-mu)/sigma) - normalCDF((pe.low-mu)/sigma)
+		logPs[i] = math.Log(pe.weights[i]) - z*z/2 - math.Log(sigma*math.Sqrt(2*math.Pi)*mass)
+		maxLogP = math.Max(maxLogP, logPs[i])
+	}
+	sum := 0.0
+	for _, logP := range logPs {
+		sum += math.Exp(logP - maxLogP)
+	}
+	return maxLogP + math.Log(sum)
+}
+
+// sampleWeighted returns an index drawn with probability proportional to its weight.
+func sampleWeighted(rand *nprand.State, weights []float64) int {
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+	u := rand.UnitInterval() * total
+	for i, w := range weights {
+		if u < w {
+			return i
+		}
+		u -= w
+	}
+	return len(weights) - 1
+}
+
+// sampleStandardNormal draws from N(0, 1) with the Box-Muller transform.
+func sampleStandardNormal(rand *nprand.State) float64 {
+	u1 := 1 - rand.UnitInterval()
+	u2 := rand.UnitInterval()
+	return math.Sqrt(-2*math.Log(u1)) * math.Cos(2*math.Pi*u2)
+}
+
+func normalCDF(x float64) float64 {
+	return (1 + math.Erf(x/math.Sqrt2)) / 2
+}
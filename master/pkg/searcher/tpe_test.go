@@ -0,0 +1,194 @@
+//nolint:exhaustruct
+package searcher
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/determined-ai/determined/master/pkg/model"
+	"github.com/determined-ai/determined/master/pkg/nprand"
+	"github.com/determined-ai/determined/master/pkg/ptrs"
+	"github.com/determined-ai/determined/master/pkg/schemas"
+	"github.com/determined-ai/determined/master/pkg/schemas/expconf"
+)
+
+func tpeTestConfig(maxTrials, maxConcurrentTrials int) expconf.SearcherConfig {
+	return schemas.WithDefaults(expconf.SearcherConfig{
+		RawMetric: ptrs.Ptr("loss"),
+		RawTPEConfig: &expconf.TPEConfig{
+			RawMaxTrials:           ptrs.Ptr(maxTrials),
+			RawMaxConcurrentTrials: ptrs.Ptr(maxConcurrentTrials),
+			RawNumStartupTrials:    ptrs.Ptr(4),
+		},
+	})
+}
+
+func tpeTestHyperparameters() expconf.Hyperparameters {
+	nested := map[string]expconf.Hyperparameter{
+		"lr": {RawLogHyperparameter: &expconf.LogHyperparameter{
+			RawMinval: -4, RawMaxval: -1, RawBase: 10,
+		}},
+		"type": {RawConstHyperparameter: &expconf.ConstHyperparameter{RawVal: "adam"}},
+	}
+	return expconf.Hyperparameters{
+		"cat": {RawCategoricalHyperparameter: &expconf.CategoricalHyperparameter{
+			RawVals: []interface{}{"a", "b", "c"},
+		}},
+		"double":    {RawDoubleHyperparameter: &expconf.DoubleHyperparameter{RawMinval: 0, RawMaxval: 10}},
+		"int":       {RawIntHyperparameter: &expconf.IntHyperparameter{RawMinval: 1, RawMaxval: 8}},
+		"optimizer": {RawNestedHyperparameter: &nested},
+	}
+}
+
+func TestTPESearchMethod(t *testing.T) {
+	conf := tpeTestConfig(12, 2)
+	testSearchRunner := NewTestSearchRunner(t, conf, tpeTestHyperparameters())
+
+	// Simulate a search and verify every trial runs to completion with hparams in the space.
+	testSearchRunner.run(100, 10, false)
+	require.Len(t, testSearchRunner.trials, 12)
+	for _, tr := range testSearchRunner.trials {
+		require.False(t, tr.stopped)
+		require.Contains(t, []interface{}{"a", "b", "c"}, tr.hparams["cat"])
+		require.InDelta(t, 5, tr.hparams["double"].(float64), 5)
+		require.True(t, tr.hparams["int"].(int) >= 1 && tr.hparams["int"].(int) <= 8)
+		optimizer := tr.hparams["optimizer"].(map[string]interface{})
+		require.Equal(t, "adam", optimizer["type"])
+		lr := optimizer["lr"].(float64)
+		require.True(t, lr >= 1e-4 && lr <= 1e-1, lr)
+	}
+}
+
+// runTPE runs trials one at a time against a quadratic with its minimum at double=3, int=6 and
+// cat=c, and returns the hparams of every trial.
+func runTPE(t *testing.T, searcher *Searcher, numTrials int) []HParamSample {
+	actions, err := searcher.InitialTrials()
+	require.NoError(t, err)
+
+	var samples []HParamSample
+	for len(actions) > 0 && len(samples) < numTrials {
+		create, ok := actions[0].(Create)
+		require.True(t, ok, actions[0])
+		samples = append(samples, create.Hparams)
+		_, err = searcher.TrialCreated(create.RequestID)
+		require.NoError(t, err)
+
+		loss := math.Pow(create.Hparams["double"].(float64)-3, 2) +
+			math.Pow(float64(create.Hparams["int"].(int)-6), 2)
+		if create.Hparams["cat"] != "c" {
+			loss += 4
+		}
+		_, err = searcher.ValidationCompleted(create.RequestID, map[string]interface{}{"loss": loss})
+		require.NoError(t, err)
+
+		actions, err = searcher.TrialExited(create.RequestID)
+		require.NoError(t, err)
+	}
+	return samples
+}
+
+func TestTPEConverges(t *testing.T) {
+	conf := tpeTestConfig(60, 1)
+	hparams := tpeTestHyperparameters()
+	samples := runTPE(t, NewSearcher(0, NewSearchMethod(conf), hparams), 60)
+	require.Len(t, samples, 60)
+
+	// Random search would put the double about 2.9 from the optimum on average.
+	var distance float64
+	var bestCat int
+	for _, sample := range samples[40:] {
+		distance += math.Abs(sample["double"].(float64) - 3)
+		if sample["cat"] == "c" {
+			bestCat++
+		}
+	}
+	require.Less(t, distance/20, 1.5)
+	require.Greater(t, bestCat, 10)
+}
+
+func TestTPEReproducible(t *testing.T) {
+	conf := tpeTestConfig(20, 1)
+	hparams := tpeTestHyperparameters()
+	first := runTPE(t, NewSearcher(7, NewSearchMethod(conf), hparams), 20)
+	second := runTPE(t, NewSearcher(7, NewSearchMethod(conf), hparams), 20)
+	require.Equal(t, first, second)
+
+	other := runTPE(t, NewSearcher(8, NewSearchMethod(conf), hparams), 20)
+	require.NotEqual(t, first, other)
+}
+
+func TestTPESnapshotRestore(t *testing.T) {
+	conf := tpeTestConfig(20, 1)
+	hparams := tpeTestHyperparameters()
+
+	// Run past the startup trials, then snapshot and restore into a fresh searcher.
+	original := NewSearcher(3, NewSearchMethod(conf), hparams)
+	runTPE(t, original, 8)
+	snapshot, err := original.Snapshot()
+	require.NoError(t, err)
+
+	restored := NewSearcher(3, NewSearchMethod(conf), hparams)
+	require.NoError(t, restored.Restore(snapshot))
+	resnapshot, err := restored.Snapshot()
+	require.NoError(t, err)
+	require.JSONEq(t, string(snapshot), string(resnapshot))
+
+	// Both searchers propose the same trial when the last one exits.
+	var lastID model.RequestID
+	for id := range original.method.(*tpeSearch).TrialParams {
+		if !original.TrialIsClosed(id) {
+			lastID = id
+		}
+	}
+	for _, s := range []*Searcher{original, restored} {
+		_, err := s.ValidationCompleted(lastID, map[string]interface{}{"loss": 1.0})
+		require.NoError(t, err)
+	}
+	expected, err := original.TrialExited(lastID)
+	require.NoError(t, err)
+	actual, err := restored.TrialExited(lastID)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+	require.Len(t, actual, 1)
+}
+
+func TestTPEInvalidHP(t *testing.T) {
+	conf := tpeTestConfig(2, 2)
+	searcher := NewSearcher(0, NewSearchMethod(conf), tpeTestHyperparameters())
+	actions, err := searcher.InitialTrials()
+	require.NoError(t, err)
+	require.Len(t, actions, 2)
+	invalid := actions[0].(Create).RequestID
+	for _, a := range actions {
+		_, err := searcher.TrialCreated(a.(Create).RequestID)
+		require.NoError(t, err)
+	}
+
+	// An invalid trial is forgotten and replaced when it closes.
+	_, err = searcher.TrialExitedEarly(invalid, model.InvalidHP)
+	require.NoError(t, err)
+	require.NotContains(t, searcher.method.(*tpeSearch).TrialParams, invalid)
+	actions, err = searcher.TrialExited(invalid)
+	require.NoError(t, err)
+	require.Len(t, actions, 1)
+	require.IsType(t, Create{}, actions[0])
+}
+
+func TestTPEIntBounds(t *testing.T) {
+	p := tpeParam{hp: expconf.Hyperparameter{
+		RawIntHyperparameter: &expconf.IntHyperparameter{RawMinval: 1, RawMaxval: 8},
+	}}
+	low, high := p.bounds()
+	require.Equal(t, 1, p.value(low))
+	require.Equal(t, 8, p.value(high))
+
+	// Observations at the end values make samples near the widened bounds likely, and those must
+	// still round to values in the range.
+	rand := nprand.New(0)
+	for i := 0; i < 100; i++ {
+		x := p.sampleTPE(rand, []float64{1, 1, 8, 8}, []float64{4, 5}, 24, 1)
+		require.True(t, x >= 1 && x <= 8, x)
+	}
+}
@@ -133,18 +133,23 @@ func (t *TaskSpec) Clone() (*TaskSpec, error) {
 	return &res, nil
 }
 
+// OwnerUsername returns the username of the owner of the task, or an empty string if the task has
+// no owner.
+func (t *TaskSpec) OwnerUsername() string {
+	if t.Owner == nil {
+		return ""
+	}
+	return t.Owner.Username
+}
+
 // ResolveWorkDir resolves the work dir.
 func (t *TaskSpec) ResolveWorkDir() {
 	agentUser := ""
-	detUser := ""
 	if t.AgentUserGroup != nil {
 		agentUser = t.AgentUserGroup.User
 	}
-	if t.Owner != nil {
-		detUser = t.Owner.Username
-	}
 	workDir := strings.ReplaceAll(t.WorkDir, "$AGENT_USER", agentUser)
-	t.WorkDir = strings.ReplaceAll(workDir, "$DET_USER", detUser)
+	t.WorkDir = strings.ReplaceAll(workDir, "$DET_USER", t.OwnerUsername())
 }
 
 // Archives returns all the archives.
@@ -0,0 +1,5 @@
+CREATE TABLE webhook_metric_threshold_triggers (
+    trial_id integer NOT NULL REFERENCES runs(id) ON DELETE CASCADE,
+    trigger_id integer NOT NULL REFERENCES webhook_triggers(id) ON DELETE CASCADE,
+    PRIMARY KEY (trial_id, trigger_id)
+);
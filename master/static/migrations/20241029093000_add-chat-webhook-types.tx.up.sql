@@ -0,0 +1,14 @@
+ALTER TYPE webhook_type RENAME TO _webhook_type;
+
+CREATE TYPE webhook_type AS ENUM (
+  'DEFAULT',
+  'SLACK',
+  'TEAMS',
+  'DISCORD',
+  'PAGERDUTY'
+);
+
+ALTER TABLE webhooks ALTER COLUMN webhook_type
+    SET DATA TYPE webhook_type USING (webhook_type::text::webhook_type);
+
+DROP TYPE public._webhook_type;
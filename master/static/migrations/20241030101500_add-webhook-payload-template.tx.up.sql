@@ -0,0 +1,2 @@
+ALTER TABLE webhooks
+    ADD COLUMN payload_template text;
@@ -0,0 +1,23 @@
+ALTER TABLE webhook_events_queue
+    ADD COLUMN webhook_id integer REFERENCES webhooks(id) ON DELETE SET NULL;
+
+CREATE TABLE webhook_deliveries (
+    id SERIAL PRIMARY KEY,
+    webhook_id integer NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    url text NOT NULL,
+    success boolean NOT NULL,
+    status_code integer,
+    latency_ms integer NOT NULL,
+    attempts integer NOT NULL,
+    response_body text NOT NULL DEFAULT '',
+    error text,
+    delivered_at timestamptz NOT NULL DEFAULT now()
+);
+CREATE INDEX ix_webhook_deliveries_webhook_id ON webhook_deliveries(webhook_id);
+
+CREATE TABLE webhook_dead_letters (
+    delivery_id integer PRIMARY KEY REFERENCES webhook_deliveries(id) ON DELETE CASCADE,
+    webhook_id integer NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    payload bytea NOT NULL
+);
+CREATE INDEX ix_webhook_dead_letters_webhook_id ON webhook_dead_letters(webhook_id);
@@ -0,0 +1,10 @@
+-- sequences for tracking experiment, trial and trial metric summary event order
+CREATE SEQUENCE IF NOT EXISTS stream_experiment_seq START 1;
+CREATE SEQUENCE IF NOT EXISTS stream_trial_seq START 1;
+CREATE SEQUENCE IF NOT EXISTS stream_trial_metric_summary_seq START 1;
+
+ALTER TABLE experiments ADD COLUMN IF NOT EXISTS seq bigint DEFAULT 0;
+-- trials and their metric summaries are both stored on runs, but stream separately so that
+-- frequent metric reports don't resend the rest of the trial.
+ALTER TABLE runs ADD COLUMN IF NOT EXISTS seq bigint DEFAULT 0;
+ALTER TABLE runs ADD COLUMN IF NOT EXISTS summary_metrics_seq bigint DEFAULT 0;
@@ -0,0 +1,3 @@
+-- the run a searcher copied this run from, e.g. when population based training replaces a run
+-- with a copy of a better one.
+ALTER TABLE runs ADD COLUMN parent_run_id integer REFERENCES runs(id) ON DELETE SET NULL;
@@ -0,0 +1,13 @@
+-- The history of the price of a slot-hour of each resource pool, which is used to attribute costs
+-- to allocations.
+CREATE TABLE resource_pool_prices (
+    id SERIAL PRIMARY KEY,
+    resource_pool TEXT NOT NULL,
+    slot_hour_price DOUBLE PRECISION NOT NULL,
+    currency TEXT NOT NULL,
+    source TEXT NOT NULL,
+    effective_from TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ix_resource_pool_prices_resource_pool_effective_from
+    ON resource_pool_prices (resource_pool, effective_from);
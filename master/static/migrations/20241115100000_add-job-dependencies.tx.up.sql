@@ -0,0 +1,12 @@
+CREATE TYPE public.job_dependency_condition AS ENUM ('SUCCESS', 'ANY_EXIT');
+
+-- The jobs that must finish before a job starts. The upstream job is not a foreign key, so that a
+-- job that depends on a deleted job does not start.
+CREATE TABLE public.job_dependencies (
+    job_id text NOT NULL REFERENCES public.jobs(job_id) ON DELETE CASCADE,
+    depends_on text NOT NULL,
+    condition public.job_dependency_condition NOT NULL DEFAULT 'SUCCESS',
+    PRIMARY KEY (job_id, depends_on)
+);
+
+CREATE INDEX ix_job_dependencies_depends_on ON public.job_dependencies USING btree (depends_on);
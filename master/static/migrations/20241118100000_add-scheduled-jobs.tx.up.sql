@@ -0,0 +1,33 @@
+CREATE TYPE public.scheduled_job_type AS ENUM ('EXPERIMENT', 'COMMAND');
+CREATE TYPE public.scheduled_job_overlap_policy AS ENUM ('SKIP', 'QUEUE', 'CANCEL_PREVIOUS');
+CREATE TYPE public.scheduled_job_run_state AS ENUM ('LAUNCHED', 'SKIPPED', 'FAILED');
+
+-- The request of a scheduled job is the CreateExperimentRequest or LaunchCommandRequest that it
+-- launches, in its proto JSON representation.
+CREATE TABLE public.scheduled_jobs (
+    id serial PRIMARY KEY,
+    name text NOT NULL,
+    job_type public.scheduled_job_type NOT NULL,
+    cron_spec text NOT NULL,
+    overlap_policy public.scheduled_job_overlap_policy NOT NULL DEFAULT 'SKIP',
+    request jsonb NOT NULL,
+    owner_id integer NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    workspace_id integer NOT NULL REFERENCES public.workspaces(id) ON DELETE CASCADE,
+    paused boolean NOT NULL DEFAULT false,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+-- The launched job is not a foreign key, so that the history outlives deleted jobs.
+CREATE TABLE public.scheduled_job_runs (
+    id serial PRIMARY KEY,
+    scheduled_job_id integer NOT NULL REFERENCES public.scheduled_jobs(id) ON DELETE CASCADE,
+    run_time timestamptz NOT NULL DEFAULT now(),
+    state public.scheduled_job_run_state NOT NULL,
+    job_id text,
+    experiment_id integer,
+    task_id text,
+    message text NOT NULL DEFAULT ''
+);
+
+CREATE INDEX ix_scheduled_job_runs_scheduled_job_id_run_time
+    ON public.scheduled_job_runs USING btree (scheduled_job_id, run_time DESC);
@@ -0,0 +1,13 @@
+-- Agents are not a foreign key, since they are not persisted once they disconnect.
+CREATE TABLE public.agent_health_events (
+    id serial PRIMARY KEY,
+    agent_id text NOT NULL,
+    time timestamptz NOT NULL DEFAULT now(),
+    device_id integer NOT NULL,
+    device_uuid text NOT NULL DEFAULT '',
+    healthy boolean NOT NULL,
+    reason text NOT NULL DEFAULT ''
+);
+
+CREATE INDEX ix_agent_health_events_agent_id_time
+    ON public.agent_health_events USING btree (agent_id, time DESC);
@@ -0,0 +1,14 @@
+-- Agents are not a foreign key, since they are not persisted once they disconnect.
+CREATE TABLE public.agent_maintenance_windows (
+    id serial PRIMARY KEY,
+    agent_id text NOT NULL,
+    start_time timestamptz NOT NULL,
+    end_time timestamptz NOT NULL,
+    reason text NOT NULL DEFAULT '',
+    checkpoint_lead_seconds integer NOT NULL DEFAULT 600,
+    CHECK (end_time > start_time),
+    CHECK (checkpoint_lead_seconds >= 0)
+);
+
+CREATE INDEX ix_agent_maintenance_windows_agent_id_end_time
+    ON public.agent_maintenance_windows USING btree (agent_id, end_time);
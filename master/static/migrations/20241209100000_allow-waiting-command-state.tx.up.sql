@@ -0,0 +1,3 @@
+-- Commands and generic tasks that wait on their dependencies have no allocation yet, but are
+-- persisted so that they wait again after the master restarts.
+ALTER TABLE public.command_state ALTER COLUMN allocation_id DROP NOT NULL;
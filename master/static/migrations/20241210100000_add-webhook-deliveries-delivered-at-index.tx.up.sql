@@ -0,0 +1 @@
+CREATE INDEX ix_webhook_deliveries_delivered_at ON webhook_deliveries(delivered_at);
@@ -0,0 +1 @@
+ALTER TABLE resource_pool_prices ALTER COLUMN slot_hour_price DROP NOT NULL;
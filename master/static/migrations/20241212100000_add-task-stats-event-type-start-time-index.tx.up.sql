@@ -0,0 +1 @@
+CREATE INDEX ix_task_stats_event_type_start_time ON task_stats(event_type, start_time);
@@ -0,0 +1 @@
+CREATE INDEX ix_agent_health_events_time ON agent_health_events(time);
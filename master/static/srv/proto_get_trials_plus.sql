@@ -115,6 +115,7 @@ SELECT
   t.hparams,
   t.log_policy_matched,
   new_ckpt.uuid AS warm_start_checkpoint_uuid,
+  t.parent_trial_id,
   (
     SELECT tt.task_id FROM run_id_task_id tt
     JOIN tasks ta ON tt.task_id = ta.task_id
@@ -18,6 +18,10 @@ DROP FUNCTION IF EXISTS page_info CASCADE;
 DROP FUNCTION IF EXISTS proto_time CASCADE;
 DROP FUNCTION IF EXISTS retention_timestamp CASCADE;
 DROP FUNCTION IF EXISTS set_modified_time CASCADE;
+DROP FUNCTION IF EXISTS stream_experiment_change CASCADE;
+DROP FUNCTION IF EXISTS stream_experiment_change_by_project CASCADE;
+DROP FUNCTION IF EXISTS stream_experiment_notify CASCADE;
+DROP FUNCTION IF EXISTS stream_experiment_seq_modify CASCADE;
 DROP FUNCTION IF EXISTS stream_model_change CASCADE;
 DROP FUNCTION IF EXISTS stream_model_notify CASCADE;
 DROP FUNCTION IF EXISTS stream_model_seq_modify CASCADE;
@@ -29,6 +33,13 @@ DROP FUNCTION IF EXISTS stream_model_version_seq_modify_by_model CASCADE;
 DROP FUNCTION IF EXISTS stream_project_change CASCADE;
 DROP FUNCTION IF EXISTS stream_project_notify CASCADE;
 DROP FUNCTION IF EXISTS stream_project_seq_modify CASCADE;
+DROP FUNCTION IF EXISTS stream_trial_change CASCADE;
+DROP FUNCTION IF EXISTS stream_trial_change_by_project CASCADE;
+DROP FUNCTION IF EXISTS stream_trial_metric_summary_change CASCADE;
+DROP FUNCTION IF EXISTS stream_trial_metric_summary_notify CASCADE;
+DROP FUNCTION IF EXISTS stream_trial_metric_summary_seq_modify CASCADE;
+DROP FUNCTION IF EXISTS stream_trial_notify CASCADE;
+DROP FUNCTION IF EXISTS stream_trial_seq_modify CASCADE;
 DROP FUNCTION IF EXISTS try_float8_cast CASCADE;
 
 DROP AGGREGATE IF EXISTS jsonb_collect(jsonb);
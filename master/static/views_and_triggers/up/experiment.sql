@@ -0,0 +1,96 @@
+CREATE FUNCTION stream_experiment_change() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+DECLARE
+    old_workspace_id integer = NULL;
+    new_workspace_id integer = NULL;
+BEGIN
+    IF (TG_OP = 'INSERT') THEN
+        SELECT workspace_id INTO new_workspace_id FROM projects WHERE id = NEW.project_id;
+        PERFORM stream_experiment_notify(
+            NULL,
+            jsonb_build_object(
+                'id', NEW.id, 'project_id', NEW.project_id, 'workspace_id', new_workspace_id, 'seq', NEW.seq
+            )
+        );
+    ELSEIF (TG_OP = 'UPDATE') THEN
+        SELECT workspace_id INTO old_workspace_id FROM projects WHERE id = OLD.project_id;
+        SELECT workspace_id INTO new_workspace_id FROM projects WHERE id = NEW.project_id;
+        PERFORM stream_experiment_notify(
+            jsonb_build_object(
+                'id', OLD.id, 'project_id', OLD.project_id, 'workspace_id', old_workspace_id, 'seq', OLD.seq
+            ),
+            jsonb_build_object(
+                'id', NEW.id, 'project_id', NEW.project_id, 'workspace_id', new_workspace_id, 'seq', NEW.seq
+            )
+        );
+    ELSEIF (TG_OP = 'DELETE') THEN
+        SELECT workspace_id INTO old_workspace_id FROM projects WHERE id = OLD.project_id;
+        PERFORM stream_experiment_notify(
+            jsonb_build_object(
+                'id', OLD.id, 'project_id', OLD.project_id, 'workspace_id', old_workspace_id, 'seq', OLD.seq
+            ),
+            NULL
+        );
+        -- DELETEs trigger BEFORE, and must return a non-NULL value.
+        return OLD;
+    END IF;
+    return NULL;
+END;
+$$;
+
+CREATE TRIGGER stream_experiment_trigger_d BEFORE DELETE ON experiments FOR EACH ROW EXECUTE PROCEDURE stream_experiment_change();
+CREATE TRIGGER stream_experiment_trigger_iu AFTER INSERT OR UPDATE OF state, notes, config, start_time, end_time, parent_id, archived, owner_id, project_id, unmanaged, external_experiment_id, progress, job_id, best_trial_id ON experiments FOR EACH ROW EXECUTE PROCEDURE stream_experiment_change();
+
+-- Moving a project to another workspace moves its experiments with it, which subscribers by
+-- workspace see as a fallin or fallout.
+CREATE FUNCTION stream_experiment_change_by_project() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+DECLARE
+    f record;
+BEGIN
+    FOR f IN (
+        UPDATE experiments SET seq = nextval('stream_experiment_seq') WHERE project_id = NEW.id RETURNING id, seq
+    )
+    LOOP
+        PERFORM stream_experiment_notify(
+            jsonb_build_object('id', f.id, 'project_id', OLD.id, 'workspace_id', OLD.workspace_id, 'seq', f.seq),
+            jsonb_build_object('id', f.id, 'project_id', NEW.id, 'workspace_id', NEW.workspace_id, 'seq', f.seq)
+        );
+    END LOOP;
+    RETURN NEW;
+END;
+$$;
+CREATE TRIGGER stream_experiment_trigger_by_project AFTER UPDATE OF workspace_id ON projects FOR EACH ROW EXECUTE PROCEDURE stream_experiment_change_by_project();
+
+CREATE FUNCTION stream_experiment_notify(before jsonb, after jsonb) RETURNS integer
+    LANGUAGE plpgsql
+    AS $$
+DECLARE
+    output jsonb = NULL;
+BEGIN
+    IF before IS NOT NULL THEN
+        output = jsonb_object_agg('before', before);
+    END IF;
+    IF after IS NOT NULL THEN
+        IF output IS NULL THEN
+            output = jsonb_object_agg('after', after);
+        ELSE
+            output = output || jsonb_object_agg('after', after);
+        END IF;
+    END IF;
+    PERFORM pg_notify('stream_experiment_chan', output::text);
+return 0;
+END;
+$$;
+
+CREATE FUNCTION stream_experiment_seq_modify() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    NEW.seq = nextval('stream_experiment_seq');
+RETURN NEW;
+END;
+$$;
+CREATE TRIGGER stream_experiment_trigger_seq BEFORE INSERT OR UPDATE OF state, notes, config, start_time, end_time, parent_id, archived, owner_id, project_id, unmanaged, external_experiment_id, progress, job_id, best_trial_id ON experiments FOR EACH ROW EXECUTE PROCEDURE stream_experiment_seq_modify();
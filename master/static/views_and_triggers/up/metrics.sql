@@ -23,6 +23,7 @@ CREATE VIEW trials AS
     t.seed,
     r.experiment_id,
     r.warm_start_checkpoint_id,
+    r.parent_run_id AS parent_trial_id,
     r.runner_state,
     r.log_policy_matched,
     rm.metadata AS metadata
@@ -0,0 +1,201 @@
+-- Trials and trial metric summaries are both stored on runs, with their own sequence numbers and
+-- channels, so that reporting metrics does not resend every other trial attribute.
+CREATE FUNCTION stream_trial_change() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+DECLARE
+    old_workspace_id integer = NULL;
+    new_workspace_id integer = NULL;
+BEGIN
+    IF (TG_OP = 'INSERT') THEN
+        SELECT workspace_id INTO new_workspace_id FROM projects WHERE id = NEW.project_id;
+        PERFORM stream_trial_notify(
+            NULL,
+            jsonb_build_object(
+                'id', NEW.id, 'experiment_id', NEW.experiment_id, 'project_id', NEW.project_id,
+                'workspace_id', new_workspace_id, 'seq', NEW.seq
+            )
+        );
+    ELSEIF (TG_OP = 'UPDATE') THEN
+        SELECT workspace_id INTO old_workspace_id FROM projects WHERE id = OLD.project_id;
+        SELECT workspace_id INTO new_workspace_id FROM projects WHERE id = NEW.project_id;
+        PERFORM stream_trial_notify(
+            jsonb_build_object(
+                'id', OLD.id, 'experiment_id', OLD.experiment_id, 'project_id', OLD.project_id,
+                'workspace_id', old_workspace_id, 'seq', OLD.seq
+            ),
+            jsonb_build_object(
+                'id', NEW.id, 'experiment_id', NEW.experiment_id, 'project_id', NEW.project_id,
+                'workspace_id', new_workspace_id, 'seq', NEW.seq
+            )
+        );
+    ELSEIF (TG_OP = 'DELETE') THEN
+        SELECT workspace_id INTO old_workspace_id FROM projects WHERE id = OLD.project_id;
+        PERFORM stream_trial_notify(
+            jsonb_build_object(
+                'id', OLD.id, 'experiment_id', OLD.experiment_id, 'project_id', OLD.project_id,
+                'workspace_id', old_workspace_id, 'seq', OLD.seq
+            ),
+            NULL
+        );
+        -- DELETEs trigger BEFORE, and must return a non-NULL value.
+        return OLD;
+    END IF;
+    return NULL;
+END;
+$$;
+
+CREATE TRIGGER stream_trial_trigger_d BEFORE DELETE ON runs FOR EACH ROW EXECUTE PROCEDURE stream_trial_change();
+CREATE TRIGGER stream_trial_trigger_iu AFTER INSERT OR UPDATE OF experiment_id, project_id, state, start_time, end_time, restarts, total_batches, hparams, tags, searcher_metric_value, best_validation_id, latest_validation_id, checkpoint_count, archived ON runs FOR EACH ROW EXECUTE PROCEDURE stream_trial_change();
+
+CREATE FUNCTION stream_trial_notify(before jsonb, after jsonb) RETURNS integer
+    LANGUAGE plpgsql
+    AS $$
+DECLARE
+    output jsonb = NULL;
+BEGIN
+    IF before IS NOT NULL THEN
+        output = jsonb_object_agg('before', before);
+    END IF;
+    IF after IS NOT NULL THEN
+        IF output IS NULL THEN
+            output = jsonb_object_agg('after', after);
+        ELSE
+            output = output || jsonb_object_agg('after', after);
+        END IF;
+    END IF;
+    PERFORM pg_notify('stream_trial_chan', output::text);
+return 0;
+END;
+$$;
+
+CREATE FUNCTION stream_trial_seq_modify() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    NEW.seq = nextval('stream_trial_seq');
+RETURN NEW;
+END;
+$$;
+CREATE TRIGGER stream_trial_trigger_seq BEFORE INSERT OR UPDATE OF experiment_id, project_id, state, start_time, end_time, restarts, total_batches, hparams, tags, searcher_metric_value, best_validation_id, latest_validation_id, checkpoint_count, archived ON runs FOR EACH ROW EXECUTE PROCEDURE stream_trial_seq_modify();
+
+CREATE FUNCTION stream_trial_metric_summary_change() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+DECLARE
+    old_workspace_id integer = NULL;
+    new_workspace_id integer = NULL;
+BEGIN
+    IF (TG_OP = 'INSERT') THEN
+        SELECT workspace_id INTO new_workspace_id FROM projects WHERE id = NEW.project_id;
+        PERFORM stream_trial_metric_summary_notify(
+            NULL,
+            jsonb_build_object(
+                'id', NEW.id, 'experiment_id', NEW.experiment_id, 'project_id', NEW.project_id,
+                'workspace_id', new_workspace_id, 'seq', NEW.summary_metrics_seq
+            )
+        );
+    ELSEIF (TG_OP = 'UPDATE') THEN
+        SELECT workspace_id INTO old_workspace_id FROM projects WHERE id = OLD.project_id;
+        SELECT workspace_id INTO new_workspace_id FROM projects WHERE id = NEW.project_id;
+        PERFORM stream_trial_metric_summary_notify(
+            jsonb_build_object(
+                'id', OLD.id, 'experiment_id', OLD.experiment_id, 'project_id', OLD.project_id,
+                'workspace_id', old_workspace_id, 'seq', OLD.summary_metrics_seq
+            ),
+            jsonb_build_object(
+                'id', NEW.id, 'experiment_id', NEW.experiment_id, 'project_id', NEW.project_id,
+                'workspace_id', new_workspace_id, 'seq', NEW.summary_metrics_seq
+            )
+        );
+    ELSEIF (TG_OP = 'DELETE') THEN
+        SELECT workspace_id INTO old_workspace_id FROM projects WHERE id = OLD.project_id;
+        PERFORM stream_trial_metric_summary_notify(
+            jsonb_build_object(
+                'id', OLD.id, 'experiment_id', OLD.experiment_id, 'project_id', OLD.project_id,
+                'workspace_id', old_workspace_id, 'seq', OLD.summary_metrics_seq
+            ),
+            NULL
+        );
+        -- DELETEs trigger BEFORE, and must return a non-NULL value.
+        return OLD;
+    END IF;
+    return NULL;
+END;
+$$;
+
+CREATE TRIGGER stream_trial_metric_summary_trigger_d BEFORE DELETE ON runs FOR EACH ROW EXECUTE PROCEDURE stream_trial_metric_summary_change();
+CREATE TRIGGER stream_trial_metric_summary_trigger_iu AFTER INSERT OR UPDATE OF experiment_id, project_id, summary_metrics, summary_metrics_timestamp ON runs FOR EACH ROW EXECUTE PROCEDURE stream_trial_metric_summary_change();
+
+CREATE FUNCTION stream_trial_metric_summary_notify(before jsonb, after jsonb) RETURNS integer
+    LANGUAGE plpgsql
+    AS $$
+DECLARE
+    output jsonb = NULL;
+BEGIN
+    IF before IS NOT NULL THEN
+        output = jsonb_object_agg('before', before);
+    END IF;
+    IF after IS NOT NULL THEN
+        IF output IS NULL THEN
+            output = jsonb_object_agg('after', after);
+        ELSE
+            output = output || jsonb_object_agg('after', after);
+        END IF;
+    END IF;
+    PERFORM pg_notify('stream_trial_metric_summary_chan', output::text);
+return 0;
+END;
+$$;
+
+CREATE FUNCTION stream_trial_metric_summary_seq_modify() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    NEW.summary_metrics_seq = nextval('stream_trial_metric_summary_seq');
+RETURN NEW;
+END;
+$$;
+CREATE TRIGGER stream_trial_metric_summary_trigger_seq BEFORE INSERT OR UPDATE OF experiment_id, project_id, summary_metrics, summary_metrics_timestamp ON runs FOR EACH ROW EXECUTE PROCEDURE stream_trial_metric_summary_seq_modify();
+
+-- Moving a project to another workspace moves its trials with it, which subscribers by
+-- workspace see as a fallin or fallout.
+CREATE FUNCTION stream_trial_change_by_project() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+DECLARE
+    f record;
+BEGIN
+    FOR f IN (
+        UPDATE runs SET
+            seq = nextval('stream_trial_seq'),
+            summary_metrics_seq = nextval('stream_trial_metric_summary_seq')
+        WHERE project_id = NEW.id
+        RETURNING id, experiment_id, seq, summary_metrics_seq
+    )
+    LOOP
+        PERFORM stream_trial_notify(
+            jsonb_build_object(
+                'id', f.id, 'experiment_id', f.experiment_id, 'project_id', OLD.id,
+                'workspace_id', OLD.workspace_id, 'seq', f.seq
+            ),
+            jsonb_build_object(
+                'id', f.id, 'experiment_id', f.experiment_id, 'project_id', NEW.id,
+                'workspace_id', NEW.workspace_id, 'seq', f.seq
+            )
+        );
+        PERFORM stream_trial_metric_summary_notify(
+            jsonb_build_object(
+                'id', f.id, 'experiment_id', f.experiment_id, 'project_id', OLD.id,
+                'workspace_id', OLD.workspace_id, 'seq', f.summary_metrics_seq
+            ),
+            jsonb_build_object(
+                'id', f.id, 'experiment_id', f.experiment_id, 'project_id', NEW.id,
+                'workspace_id', NEW.workspace_id, 'seq', f.summary_metrics_seq
+            )
+        );
+    END LOOP;
+    RETURN NEW;
+END;
+$$;
+CREATE TRIGGER stream_trial_trigger_by_project AFTER UPDATE OF workspace_id ON projects FOR EACH ROW EXECUTE PROCEDURE stream_trial_change_by_project();
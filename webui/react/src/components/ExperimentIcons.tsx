@@ -24,6 +24,7 @@ const ExperimentIcons: React.FC<Props> = ({
       case JobState.SCHEDULED:
       case JobState.SCHEDULEDBACKFILLED:
       case JobState.QUEUED:
+      case JobState.WAITINGONDEPENDENCY:
       case RunState.Queued:
       case CommandState.Queued:
       case CommandState.Waiting:
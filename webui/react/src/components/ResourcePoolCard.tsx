@@ -87,6 +87,7 @@ export const PoolLogo: React.FC<{ type: V1ResourcePoolType }> = ({ type }) => {
       break;
     case V1ResourcePoolType.UNSPECIFIED:
     case V1ResourcePoolType.STATIC:
+    case V1ResourcePoolType.WEBHOOK:
       iconSrc = staticLogo;
       break;
   }
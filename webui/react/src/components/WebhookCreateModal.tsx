@@ -49,6 +49,18 @@ const typeOptions = [
     label: 'Slack',
     value: V1WebhookType.SLACK,
   },
+  {
+    label: 'Microsoft Teams',
+    value: V1WebhookType.TEAMS,
+  },
+  {
+    label: 'Discord',
+    value: V1WebhookType.DISCORD,
+  },
+  {
+    label: 'PagerDuty',
+    value: V1WebhookType.PAGERDUTY,
+  },
 ];
 const triggerOptions = [
   {
@@ -37,6 +37,7 @@ const jobStates: Array<JobState> = [
   JobState.QUEUED,
   JobState.SCHEDULED,
   JobState.SCHEDULEDBACKFILLED,
+  JobState.WAITINGONDEPENDENCY,
 ];
 export const killableRunStates: CompoundRunState[] = [
   ...activeStates,
@@ -109,6 +110,7 @@ export const V1ResourcePoolTypeToLabel: { [key in V1ResourcePoolType]: string }
   [V1ResourcePoolType.GCP]: 'GCP',
   [V1ResourcePoolType.STATIC]: 'Static',
   [V1ResourcePoolType.K8S]: 'Kubernetes',
+  [V1ResourcePoolType.WEBHOOK]: 'Webhook',
 };
 
 export const V1SchedulerTypeToLabel: { [key in V1SchedulerType]: string } = {
@@ -118,6 +120,7 @@ export const V1SchedulerTypeToLabel: { [key in V1SchedulerType]: string } = {
   [V1SchedulerType.ROUNDROBIN]: 'RoundRobin',
   [V1SchedulerType.SLURM]: 'Slurm',
   [V1SchedulerType.PBS]: 'PBS',
+  [V1SchedulerType.EXTERNAL]: 'External',
   [V1SchedulerType.UNSPECIFIED]: 'Unspecified',
 };
 
@@ -162,6 +165,7 @@ export const jobStateToLabel: { [key in JobState]: string } = {
   [JobState.SCHEDULED]: 'Scheduled',
   [JobState.SCHEDULEDBACKFILLED]: 'ScheduledBackfilled',
   [JobState.QUEUED]: 'Queued',
+  [JobState.WAITINGONDEPENDENCY]: 'Waiting on Dependency',
   [JobState.UNSPECIFIED]: 'Unspecified',
 };
 
@@ -81,6 +81,7 @@ const isActionableIcon = (state: CompoundRunState): boolean => {
     case JobState.SCHEDULED:
     case JobState.SCHEDULEDBACKFILLED:
     case JobState.QUEUED:
+    case JobState.WAITINGONDEPENDENCY:
     case RunState.Queued:
     case RunState.Starting:
     case RunState.Pulling:
@@ -77,6 +77,7 @@ function getCellStateFromExperimentState(expState: CompoundRunState) {
     case JobState.SCHEDULED:
     case JobState.SCHEDULEDBACKFILLED:
     case JobState.QUEUED:
+    case JobState.WAITINGONDEPENDENCY:
     case RunState.Queued: {
       return State.QUEUED;
     }
@@ -237,6 +237,7 @@ const JobQueue: React.FC<Props> = ({ rpStats, selectedRp, jobState }) => {
                   title: 'Queue',
                 };
               case Api.V1SchedulerType.PRIORITY:
+              case Api.V1SchedulerType.EXTERNAL:
               case Api.V1SchedulerType.KUBERNETES:
                 return {
                   ...col,
@@ -192,7 +192,10 @@ const ManageJobModalComponent: React.FC<Props> = ({
         onValuesChange={handleUpdateResourcePool}>
         <Form.Item
           extra="Priority is a whole number from 1 to 99 with 1 being the highest priority."
-          hidden={schedulerType !== api.V1SchedulerType.PRIORITY}
+          hidden={
+            schedulerType !== api.V1SchedulerType.PRIORITY &&
+            schedulerType !== api.V1SchedulerType.EXTERNAL
+          }
           label="Priority"
           name="priority">
           <Input addonAfter="out of 99" max={99} min={1} type="number" />
@@ -888,6 +888,9 @@ export const mapV1Webhook = (data: Sdk.V1Webhook): types.Webhook => {
         [Sdk.V1WebhookType.UNSPECIFIED]: 'Unspecified',
         [Sdk.V1WebhookType.DEFAULT]: 'Default',
         [Sdk.V1WebhookType.SLACK]: 'Slack',
+        [Sdk.V1WebhookType.TEAMS]: 'Microsoft Teams',
+        [Sdk.V1WebhookType.DISCORD]: 'Discord',
+        [Sdk.V1WebhookType.PAGERDUTY]: 'PagerDuty',
       }[data.webhookType] || 'Unspecified',
     workspaceId: data.workspaceId ?? 0,
   };
@@ -896,10 +899,18 @@ export const mapV1Webhook = (data: Sdk.V1Webhook): types.Webhook => {
 export const decodeJobStates = (
   states?: Sdk.Jobv1State[],
 ): Array<
-  'STATE_UNSPECIFIED' | 'STATE_QUEUED' | 'STATE_SCHEDULED' | 'STATE_SCHEDULED_BACKFILLED'
+  | 'STATE_UNSPECIFIED'
+  | 'STATE_QUEUED'
+  | 'STATE_SCHEDULED'
+  | 'STATE_SCHEDULED_BACKFILLED'
+  | 'STATE_WAITING_ON_DEPENDENCY'
 > => {
   return states as unknown as Array<
-    'STATE_UNSPECIFIED' | 'STATE_QUEUED' | 'STATE_SCHEDULED' | 'STATE_SCHEDULED_BACKFILLED'
+    | 'STATE_UNSPECIFIED'
+    | 'STATE_QUEUED'
+    | 'STATE_SCHEDULED'
+    | 'STATE_SCHEDULED_BACKFILLED'
+    | 'STATE_WAITING_ON_DEPENDENCY'
   >;
 };
 
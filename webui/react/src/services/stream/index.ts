@@ -1,4 +1,10 @@
-export type Streamable = 'projects' | 'experiments' | 'models' | 'modelversions';
+export type Streamable =
+  | 'projects'
+  | 'experiments'
+  | 'trials'
+  | 'trialmetricsummaries'
+  | 'models'
+  | 'modelversions';
 
 /* eslint-disable-next-line @typescript-eslint/no-explicit-any */
 export type StreamContent = any;
@@ -6,6 +12,8 @@ export type StreamContent = any;
 export const StreamEntityMap: Record<string, Streamable> = {
   experiment: 'experiments',
   project: 'projects',
+  trial: 'trials',
+  trialmetricsummary: 'trialmetricsummaries',
 };
 
 export abstract class StreamSpec {
@@ -1,9 +1,8 @@
 import WS from 'jest-websocket-mock';
 import { v4 as uuidv4 } from 'uuid';
 
-import { ExperimentSpec } from './experiments';
 import { Stream } from './stream';
-import { ProjectSpec } from './wire';
+import { ExperimentSpec, ProjectSpec } from './wire';
 
 const onUpsert = vi.fn();
 const onDelete = vi.fn();
@@ -16,7 +15,7 @@ const spec2ws = [1, 2];
 const spec3exp = [1, 2, 3];
 const spec1 = new ProjectSpec(spec1ws);
 const spec2 = new ProjectSpec(spec2ws);
-const spec3 = new ExperimentSpec(spec3exp);
+const spec3 = new ExperimentSpec([], [], spec3exp);
 
 const setup = () => {
   const server = genServer();
@@ -232,7 +231,9 @@ describe('stream', () => {
       subscribe: {
         experiments: {
           experiment_ids: spec3exp,
+          project_ids: [],
           since: 0,
+          workspace_ids: [],
         },
         projects: {
           project_ids: [],
@@ -266,7 +267,9 @@ describe('stream', () => {
       subscribe: {
         experiments: {
           experiment_ids: spec3exp,
+          project_ids: [],
           since: 5,
+          workspace_ids: [],
         },
         projects: {
           project_ids: [],
@@ -290,7 +293,9 @@ describe('stream', () => {
       subscribe: {
         experiments: {
           experiment_ids: spec3exp,
+          project_ids: [],
           since: 5,
+          workspace_ids: [],
         },
         projects: {
           project_ids: [],
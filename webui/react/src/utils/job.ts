@@ -34,6 +34,7 @@ export const jobTypeToCommandType = (jobType: JobType): CommandType | undefined
 
 export const orderedSchedulers = new Set<Api.V1SchedulerType>([
   Api.V1SchedulerType.PRIORITY,
+  Api.V1SchedulerType.EXTERNAL,
   Api.V1SchedulerType.KUBERNETES,
 ]);
 